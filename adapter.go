@@ -1,6 +1,9 @@
 package sheetkv
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // OperationType represents the type of operation
 type OperationType int
@@ -39,3 +42,118 @@ type Adapter interface {
 	// BatchUpdate performs multiple operations in a single request
 	BatchUpdate(ctx context.Context, operations []Operation) error
 }
+
+// IndexStore is implemented by adapters that can persist the secondary index
+// next to the data (for example in a hidden companion sheet)
+type IndexStore interface {
+	// SaveIndex replaces the persisted index
+	SaveIndex(ctx context.Context, index *Index) error
+
+	// LoadIndex returns the persisted index, or nil when none has been saved yet
+	LoadIndex(ctx context.Context) (*Index, error)
+}
+
+// RowLoader is implemented by adapters that can read individual rows without
+// loading the whole sheet
+type RowLoader interface {
+	// LoadRows retrieves the records stored at the given keys and the schema
+	LoadRows(ctx context.Context, keys []int) ([]*Record, []string, error)
+}
+
+// RowTailer is implemented by adapters that can read the rows after a key
+// without loading the whole sheet, for Client.Tail
+type RowTailer interface {
+	// LoadAfter retrieves the records stored after key and the schema
+	LoadAfter(ctx context.Context, key int) ([]*Record, []string, error)
+}
+
+// RevisionSource is implemented by adapters that can report a token which
+// changes whenever the spreadsheet is modified, by anyone
+type RevisionSource interface {
+	// Revision returns the current revision of the spreadsheet
+	Revision(ctx context.Context) (string, error)
+}
+
+// RecordAppender is implemented by adapters that can add rows after the last
+// row without rewriting the sheet
+type RecordAppender interface {
+	// AppendRecords appends the records, creating the header from schema when
+	// the sheet is empty; the keys of the records are ignored
+	AppendRecords(ctx context.Context, records []*Record, schema []string) error
+}
+
+// ValidationRuleSource is implemented by adapters that can read the
+// data-validation rules defined on the sheet
+type ValidationRuleSource interface {
+	// ValidationRules returns the enforceable rules per column
+	ValidationRules(ctx context.Context) ([]ColumnRule, error)
+}
+
+// CellCounter is implemented by adapters that can report the cells used by
+// the rest of the spreadsheet, so the client can watch a spreadsheet-wide
+// cell limit (see Config.CellLimit)
+type CellCounter interface {
+	// OtherCells returns the number of cells in the other tabs
+	OtherCells(ctx context.Context) (int, error)
+}
+
+// Limits describes the limits of a backend and how much of them is used.
+// Zero fields are unknown or do not apply to the backend.
+type Limits struct {
+	Rows           int   // Rows of the adapter's sheet, header included
+	Columns        int   // Columns of the adapter's sheet
+	RowLimit       int   // Rows a sheet can hold
+	ColumnLimit    int   // Columns a sheet can hold
+	Cells          int   // Cells in use across the spreadsheet or workbook
+	CellLimit      int   // Cells the spreadsheet or workbook can hold
+	FileSize       int64 // Size of the file in bytes
+	ReadQuota      int   // Read requests allowed per minute
+	ReadRemaining  int   // Estimated read requests left in the current minute
+	WriteQuota     int   // Write requests allowed per minute
+	WriteRemaining int   // Estimated write requests left in the current minute
+}
+
+// LimitsReporter is implemented by adapters that can report their backend's
+// limits and current usage, for capacity planning
+type LimitsReporter interface {
+	// Limits returns the limits and usage of the backend
+	Limits(ctx context.Context) (*Limits, error)
+}
+
+// Pinger is implemented by adapters that can check cheaply that their
+// backend is reachable, for health checks
+type Pinger interface {
+	// Ping returns an error when the backend cannot be reached
+	Ping(ctx context.Context) error
+}
+
+// Lease is a claim of one process on the right to write a sheet, see
+// Config.LeaseDuration
+type Lease struct {
+	Owner   string    // Identifies the holding process
+	Expires time.Time // When the lease lapses unless renewed
+}
+
+// LeaseStore is implemented by adapters that can keep a lease in the
+// spreadsheet (for example in a hidden companion sheet), so processes
+// writing the same sheet take turns instead of overwriting each other
+type LeaseStore interface {
+	// ReadLease returns the stored lease, or nil when none has been written
+	ReadLease(ctx context.Context) (*Lease, error)
+
+	// WriteLease replaces the stored lease; nil clears it
+	WriteLease(ctx context.Context, lease *Lease) error
+}
+
+// Locker serializes the saves of the processes writing one sheet, see
+// Config.Locker. The adapters provide locks of their backend, and locks of
+// services such as Redis or etcd can be plugged in.
+type Locker interface {
+	// Lock takes the lock before a save. It may wait while another process
+	// holds the lock, until ctx is done, or fail with an error wrapping
+	// ErrLocked.
+	Lock(ctx context.Context) error
+
+	// Unlock releases the lock after the save
+	Unlock(ctx context.Context) error
+}
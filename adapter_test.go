@@ -0,0 +1,169 @@
+package sheetkv_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// memoryAdapter is an in-memory Adapter used by the client tests
+type memoryAdapter struct {
+	mu        sync.Mutex
+	records   []*sheetkv.Record
+	schema    []string
+	loadDelay time.Duration
+	loadErr   error
+	saveErr   error
+	loads     int
+	saves     int
+	inFlight  int
+	maxFlight int
+}
+
+func newMemoryAdapter(schema []string, records ...*sheetkv.Record) *memoryAdapter {
+	return &memoryAdapter{schema: schema, records: records}
+}
+
+func (a *memoryAdapter) Load(ctx context.Context) ([]*sheetkv.Record, []string, error) {
+	a.mu.Lock()
+	a.loads++
+	a.inFlight++
+	if a.inFlight > a.maxFlight {
+		a.maxFlight = a.inFlight
+	}
+	delay, loadErr := a.loadDelay, a.loadErr
+	a.mu.Unlock()
+
+	defer func() {
+		a.mu.Lock()
+		a.inFlight--
+		a.mu.Unlock()
+	}()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+	if loadErr != nil {
+		return nil, nil, loadErr
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	records := make([]*sheetkv.Record, len(a.records))
+	for i, r := range a.records {
+		records[i] = cloneRecord(r)
+	}
+	schema := make([]string, len(a.schema))
+	copy(schema, a.schema)
+	return records, schema, nil
+}
+
+func (a *memoryAdapter) Save(ctx context.Context, records []*sheetkv.Record, schema []string, strategy sheetkv.SyncStrategy) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.saves++
+	if a.saveErr != nil {
+		return a.saveErr
+	}
+
+	a.records = make([]*sheetkv.Record, len(records))
+	for i, r := range records {
+		a.records[i] = cloneRecord(r)
+	}
+	a.schema = make([]string, len(schema))
+	copy(a.schema, schema)
+	return nil
+}
+
+func (a *memoryAdapter) BatchUpdate(ctx context.Context, operations []sheetkv.Operation) error {
+	return nil
+}
+
+func (a *memoryAdapter) saveCount() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.saves
+}
+
+func cloneRecord(r *sheetkv.Record) *sheetkv.Record {
+	c := &sheetkv.Record{Key: r.Key, Values: make(map[string]interface{}, len(r.Values))}
+	for k, v := range r.Values {
+		c.Values[k] = v
+	}
+	return c
+}
+
+// indexedAdapter is a memoryAdapter that also persists the index and can
+// load individual rows
+type indexedAdapter struct {
+	*memoryAdapter
+	index     *sheetkv.Index
+	rowLoads  int
+	indexSave int
+}
+
+func (a *indexedAdapter) SaveIndex(ctx context.Context, index *sheetkv.Index) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.index = index
+	a.indexSave++
+	return nil
+}
+
+func (a *indexedAdapter) LoadIndex(ctx context.Context) (*sheetkv.Index, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.index, nil
+}
+
+func (a *indexedAdapter) LoadRows(ctx context.Context, keys []int) ([]*sheetkv.Record, []string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.rowLoads++
+
+	var records []*sheetkv.Record
+	for _, key := range keys {
+		for _, r := range a.records {
+			if r.Key == key {
+				records = append(records, cloneRecord(r))
+			}
+		}
+	}
+	return records, a.schema, nil
+}
+
+// revisionAdapter is a memoryAdapter reporting a revision that increases on
+// every save, like a Drive file version
+type revisionAdapter struct {
+	*memoryAdapter
+	revision int
+}
+
+func (a *revisionAdapter) Save(ctx context.Context, records []*sheetkv.Record, schema []string, strategy sheetkv.SyncStrategy) error {
+	if err := a.memoryAdapter.Save(ctx, records, schema, strategy); err != nil {
+		return err
+	}
+	a.edit()
+	return nil
+}
+
+func (a *revisionAdapter) Revision(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return fmt.Sprintf("%d", a.revision), nil
+}
+
+// edit simulates a change made outside the client
+func (a *revisionAdapter) edit() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.revision++
+}
@@ -0,0 +1,167 @@
+// Package chaos wraps a sheetkv.Adapter to inject latency and failures, so
+// retry, rate-limit and conflict settings can be validated before pointing a
+// client at a production spreadsheet.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// ErrInjected is the default error of injected failures
+var ErrInjected = errors.New("chaos: injected failure")
+
+// Config describes the injected faults. Zero values inject nothing.
+type Config struct {
+	Latency          time.Duration // Delay of every call
+	Jitter           time.Duration // Random extra delay of every call, up to this
+	SlowFirstByte    time.Duration // Extra delay of the first call, like a cold connection
+	ErrorRate        float64       // Fraction of calls failing before they reach the adapter (0 to 1)
+	PartialWriteRate float64       // Fraction of saves and batch updates writing only part of their data, then failing (0 to 1)
+	Err              error         // Error of injected failures (default: ErrInjected)
+	Seed             int64         // Seed of the random faults, for reproducible runs (0: random)
+}
+
+// Adapter wraps an adapter, injecting the faults of its Config
+type Adapter struct {
+	inner    sheetkv.Adapter
+	config   Config
+	mu       sync.Mutex
+	rand     *rand.Rand
+	calls    int
+	injected int
+}
+
+var _ sheetkv.Adapter = (*Adapter)(nil)
+
+// New wraps inner with the faults of config
+func New(inner sheetkv.Adapter, config Config) (*Adapter, error) {
+	if config.ErrorRate < 0 || config.ErrorRate > 1 {
+		return nil, fmt.Errorf("error rate must be between 0 and 1, got %v", config.ErrorRate)
+	}
+	if config.PartialWriteRate < 0 || config.PartialWriteRate > 1 {
+		return nil, fmt.Errorf("partial write rate must be between 0 and 1, got %v", config.PartialWriteRate)
+	}
+	if config.Latency < 0 || config.Jitter < 0 || config.SlowFirstByte < 0 {
+		return nil, fmt.Errorf("delays must not be negative")
+	}
+	if config.Err == nil {
+		config.Err = ErrInjected
+	}
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	// #nosec G404 - faults do not need a cryptographic source
+	return &Adapter{inner: inner, config: config, rand: rand.New(rand.NewSource(seed))}, nil
+}
+
+// Calls returns the number of calls received
+func (a *Adapter) Calls() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.calls
+}
+
+// Injected returns the number of failures injected, partial writes included
+func (a *Adapter) Injected() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.injected
+}
+
+// Load retrieves the records of the wrapped adapter after the injected delay,
+// unless a failure is injected
+func (a *Adapter) Load(ctx context.Context) ([]*sheetkv.Record, []string, error) {
+	if err := a.before(ctx, "load"); err != nil {
+		return nil, nil, err
+	}
+	return a.inner.Load(ctx)
+}
+
+// Save saves through the wrapped adapter after the injected delay. A partial
+// write saves the first half of the records and fails.
+func (a *Adapter) Save(ctx context.Context, records []*sheetkv.Record, schema []string, strategy sheetkv.SyncStrategy) error {
+	if err := a.before(ctx, "save"); err != nil {
+		return err
+	}
+	if !a.partial() {
+		return a.inner.Save(ctx, records, schema, strategy)
+	}
+
+	written := len(records) / 2
+	if err := a.inner.Save(ctx, records[:written], schema, strategy); err != nil {
+		return err
+	}
+	return fmt.Errorf("save interrupted after %d of %d records: %w", written, len(records), a.config.Err)
+}
+
+// BatchUpdate applies the operations through the wrapped adapter after the
+// injected delay. A partial write applies the first half of the operations
+// and fails.
+func (a *Adapter) BatchUpdate(ctx context.Context, operations []sheetkv.Operation) error {
+	if err := a.before(ctx, "batch update"); err != nil {
+		return err
+	}
+	if !a.partial() {
+		return a.inner.BatchUpdate(ctx, operations)
+	}
+
+	applied := len(operations) / 2
+	if err := a.inner.BatchUpdate(ctx, operations[:applied]); err != nil {
+		return err
+	}
+	return fmt.Errorf("batch update interrupted after %d of %d operations: %w", applied, len(operations), a.config.Err)
+}
+
+// before waits for the delay of a call and returns the injected failure,
+// if any
+func (a *Adapter) before(ctx context.Context, action string) error {
+	a.mu.Lock()
+	a.calls++
+	delay := a.config.Latency
+	if a.config.Jitter > 0 {
+		delay += time.Duration(a.rand.Int63n(int64(a.config.Jitter) + 1))
+	}
+	if a.calls == 1 {
+		delay += a.config.SlowFirstByte
+	}
+	fail := a.config.ErrorRate > 0 && a.rand.Float64() < a.config.ErrorRate
+	if fail {
+		a.injected++
+	}
+	a.mu.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if fail {
+		return fmt.Errorf("%s: %w", action, a.config.Err)
+	}
+	return nil
+}
+
+// partial reports whether the current write is cut short
+func (a *Adapter) partial() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.config.PartialWriteRate <= 0 || a.rand.Float64() >= a.config.PartialWriteRate {
+		return false
+	}
+	a.injected++
+	return true
+}
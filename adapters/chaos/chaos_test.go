@@ -0,0 +1,170 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// memoryAdapter keeps the records saved to it
+type memoryAdapter struct {
+	mu      sync.Mutex
+	records []*sheetkv.Record
+	ops     []sheetkv.Operation
+}
+
+func (m *memoryAdapter) Load(ctx context.Context) ([]*sheetkv.Record, []string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.records, []string{"name"}, nil
+}
+
+func (m *memoryAdapter) Save(ctx context.Context, records []*sheetkv.Record, schema []string, strategy sheetkv.SyncStrategy) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = records
+	return nil
+}
+
+func (m *memoryAdapter) BatchUpdate(ctx context.Context, operations []sheetkv.Operation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ops = append(m.ops, operations...)
+	return nil
+}
+
+func records(n int) []*sheetkv.Record {
+	records := make([]*sheetkv.Record, n)
+	for i := range records {
+		records[i] = &sheetkv.Record{Key: i + 2, Values: map[string]interface{}{"name": "row"}}
+	}
+	return records
+}
+
+func TestNewValidatesConfig(t *testing.T) {
+	for _, config := range []Config{
+		{ErrorRate: -0.1},
+		{ErrorRate: 1.5},
+		{PartialWriteRate: 2},
+		{Latency: -time.Second},
+	} {
+		if _, err := New(&memoryAdapter{}, config); err == nil {
+			t.Errorf("New(%+v) should fail", config)
+		}
+	}
+}
+
+func TestPassThrough(t *testing.T) {
+	inner := &memoryAdapter{}
+	adapter, err := New(inner, Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if err := adapter.Save(ctx, records(3), []string{"name"}, sheetkv.SyncStrategyGapPreserving); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, _, err := adapter.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded) != 3 {
+		t.Errorf("expected 3 records, got %d", len(loaded))
+	}
+	if adapter.Calls() != 2 || adapter.Injected() != 0 {
+		t.Errorf("expected 2 calls and no faults, got %d and %d", adapter.Calls(), adapter.Injected())
+	}
+}
+
+func TestErrorRate(t *testing.T) {
+	inner := &memoryAdapter{}
+	adapter, err := New(inner, Config{ErrorRate: 1, Err: sheetkv.ErrQuotaExceeded})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = adapter.Save(context.Background(), records(2), []string{"name"}, sheetkv.SyncStrategyGapPreserving)
+	if !errors.Is(err, sheetkv.ErrQuotaExceeded) {
+		t.Errorf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if len(inner.records) != 0 {
+		t.Error("a failed call must not reach the adapter")
+	}
+
+	adapter, _ = New(inner, Config{ErrorRate: 0.5, Seed: 1})
+	failures := 0
+	for i := 0; i < 200; i++ {
+		if _, _, err := adapter.Load(context.Background()); errors.Is(err, ErrInjected) {
+			failures++
+		}
+	}
+	if failures < 60 || failures > 140 {
+		t.Errorf("expected about half of 200 loads to fail, got %d", failures)
+	}
+	if adapter.Injected() != failures {
+		t.Errorf("expected %d injected faults, got %d", failures, adapter.Injected())
+	}
+}
+
+func TestPartialWrite(t *testing.T) {
+	inner := &memoryAdapter{}
+	adapter, err := New(inner, Config{PartialWriteRate: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	err = adapter.Save(ctx, records(4), []string{"name"}, sheetkv.SyncStrategyGapPreserving)
+	if !errors.Is(err, ErrInjected) {
+		t.Errorf("expected ErrInjected, got %v", err)
+	}
+	if len(inner.records) != 2 {
+		t.Errorf("expected 2 of 4 records saved, got %d", len(inner.records))
+	}
+
+	ops := []sheetkv.Operation{
+		{Type: sheetkv.OpAdd, Record: records(1)[0]},
+		{Type: sheetkv.OpDelete, Record: records(1)[0]},
+	}
+	if err := adapter.BatchUpdate(ctx, ops); !errors.Is(err, ErrInjected) {
+		t.Errorf("expected ErrInjected, got %v", err)
+	}
+	if len(inner.ops) != 1 {
+		t.Errorf("expected 1 of 2 operations applied, got %d", len(inner.ops))
+	}
+}
+
+func TestLatency(t *testing.T) {
+	adapter, err := New(&memoryAdapter{}, Config{Latency: 20 * time.Millisecond, SlowFirstByte: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	start := time.Now()
+	if _, _, err := adapter.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("expected the first call to take 70ms, took %s", elapsed)
+	}
+
+	start = time.Now()
+	if _, _, err := adapter.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond || elapsed >= 70*time.Millisecond {
+		t.Errorf("expected the second call to take 20ms, took %s", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+	defer cancel()
+	if _, _, err := adapter.Load(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the delay to stop with the context, got %v", err)
+	}
+}
@@ -1,15 +1,32 @@
 package excel
 
 import (
+	"fmt"
 	"time"
 
 	sheetkv "github.com/ideamans/go-sheetkv"
+	"github.com/xuri/excelize/v2"
 )
 
 // Config holds configuration for Excel adapter
 type Config struct {
-	FilePath  string // Path to the Excel file
-	SheetName string // Name of the sheet to use
+	FilePath        string                // Path to the Excel file
+	SheetName       string                // Name of the sheet to use
+	IndexSheetName  string                // Hidden sheet holding the persisted index (default: _<SheetName>_index)
+	HeaderRow       int                   // Row holding the column names (default: 1); rows above it are left untouched
+	StartColumn     string                // First managed column, e.g. "C" (default: A); columns before it are left untouched
+	MaxColumns      int                   // Number of managed columns from StartColumn (0: unlimited); columns after them are left untouched
+	HeaderRows      int                   // Number of header rows, e.g. 2 for a group row above the field names (default: 1)
+	HeaderSeparator string                // Joins grouped header levels into column names (default: sheetkv.DefaultHeaderSeparator)
+	FormatHeader    bool                  // Freeze and bold the header and size the columns to their content on each save
+	FormulaColumns  []string              // Columns computed by formulas: loaded as computed values, never overwritten on save
+	ColumnAliases   sheetkv.ColumnAliases // Sheet header -> column name used in code
+	Whitespace      sheetkv.Whitespace    // Cleanup of the whitespace of loaded text, applied before numbers are parsed
+	BoolTokens      sheetkv.BoolTokens    // Additional texts read and written as booleans, e.g. "yes"/"no"
+	TypeInference   sheetkv.TypeInference // Columns loaded as text and conservative number parsing, so "00123" keeps its zeros
+	FloatFormats    sheetkv.FloatFormats  // Decimals and thousands separators of written floats per column (default: stored as is)
+	TimeColumns     []string              // Columns whose numbers are loaded as time.Time even without a date format, and whose RFC 3339 texts are saved as dates
+	ReopenFile      bool                  // Open and parse the file on every operation instead of keeping the workbook open between them
 }
 
 // Validate checks if the configuration is valid
@@ -20,9 +37,105 @@ func (c *Config) Validate() error {
 	if c.SheetName == "" {
 		return ErrMissingSheetName
 	}
+	if c.HeaderRow < 0 {
+		return ErrInvalidHeaderRow
+	}
+	if c.StartColumn != "" {
+		if _, err := excelize.ColumnNameToNumber(c.StartColumn); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidStartColumn, err)
+		}
+	}
+	if c.MaxColumns < 0 {
+		return ErrInvalidMaxColumns
+	}
+	if c.HeaderRows < 0 {
+		return ErrInvalidHeaderRows
+	}
 	return nil
 }
 
+// indexSheetName returns the name of the hidden index sheet
+func (c *Config) indexSheetName() string {
+	if c.IndexSheetName != "" {
+		return c.IndexSheetName
+	}
+	return "_" + c.SheetName + "_index"
+}
+
+// headerRow returns the 1-based row holding the column names
+func (c *Config) headerRow() int {
+	if c.HeaderRow > 0 {
+		return c.HeaderRow
+	}
+	return 1
+}
+
+// rowOf returns the sheet row storing key. Keys start at 2 for the first
+// row below the header, whatever the header row is.
+func (c *Config) rowOf(key int) int {
+	return key + c.lastHeaderRow() - 1
+}
+
+// headerRows returns the number of header rows
+func (c *Config) headerRows() int {
+	if c.HeaderRows > 1 {
+		return c.HeaderRows
+	}
+	return 1
+}
+
+// lastHeaderRow returns the 1-based row of the last header row
+func (c *Config) lastHeaderRow() int {
+	return c.headerRow() + c.headerRows() - 1
+}
+
+// startColumn returns the 1-based number of the first managed column
+func (c *Config) startColumn() int {
+	if c.StartColumn == "" {
+		return 1
+	}
+	n, _ := excelize.ColumnNameToNumber(c.StartColumn) // Checked by Validate
+	return n
+}
+
+// cell returns the name of the cell in the first managed column of row
+func (c *Config) cell(row int) string {
+	return fmt.Sprintf("%s%d", columnName(c.startColumn()), row)
+}
+
+// managed returns the cells of row within the managed columns
+func (c *Config) managed(row []string) []string {
+	start := c.startColumn() - 1
+	if start >= len(row) {
+		return []string{}
+	}
+	row = row[start:]
+	if c.MaxColumns > 0 && len(row) > c.MaxColumns {
+		row = row[:c.MaxColumns]
+	}
+	return row
+}
+
+// isFormulaColumn reports whether col is one of FormulaColumns
+func (c *Config) isFormulaColumn(col string) bool {
+	for _, formula := range c.FormulaColumns {
+		if formula == col {
+			return true
+		}
+	}
+	return false
+}
+
+// isTimeColumn reports whether col is one of TimeColumns
+func (c *Config) isTimeColumn(col string) bool {
+	for _, column := range c.TimeColumns {
+		if column == col {
+			return true
+		}
+	}
+	return false
+}
+
 // DefaultClientConfig returns the recommended default configuration for Excel
 func DefaultClientConfig() *sheetkv.Config {
 	return &sheetkv.Config{
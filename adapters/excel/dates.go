@@ -0,0 +1,112 @@
+package excel
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// dateNumFmts are the built-in number formats displaying dates or times
+var dateNumFmts = map[int]bool{
+	14: true, 15: true, 16: true, 17: true, 18: true, 19: true, 20: true, 21: true, 22: true,
+	27: true, 28: true, 29: true, 30: true, 31: true, 32: true, 33: true, 34: true, 35: true, 36: true,
+	45: true, 46: true, 47: true,
+	50: true, 51: true, 52: true, 53: true, 54: true, 55: true, 56: true, 57: true, 58: true,
+}
+
+// isDateFormat reports whether the custom number format code displays a
+// date or time, ignoring quoted text, escaped characters and brackets such
+// as colors or currencies
+func isDateFormat(code string) bool {
+	section, _, _ := strings.Cut(code, ";")
+	for i := 0; i < len(section); i++ {
+		switch c := section[i]; c {
+		case '"':
+			if end := strings.IndexByte(section[i+1:], '"'); end >= 0 {
+				i += end + 1
+			} else {
+				return false
+			}
+		case '\\', '_', '*':
+			i++ // The next character is literal or padding
+		case '[':
+			end := strings.IndexByte(section[i:], ']')
+			if end < 0 {
+				return false
+			}
+			switch strings.ToLower(section[i+1 : i+end]) {
+			case "h", "hh", "m", "mm", "s", "ss":
+				return true // Elapsed time
+			}
+			i += end
+		case 'y', 'Y', 'm', 'M', 'd', 'D', 'h', 'H', 's', 'S':
+			return true
+		}
+	}
+	return false
+}
+
+// dateCells detects the cells of a sheet holding dates, from their number
+// format or Config.TimeColumns
+type dateCells struct {
+	f        *excelize.File
+	config   *Config
+	date1904 bool
+	styles   map[int]bool // Style index -> displays a date
+}
+
+// newDateCells returns the date detection for sheet of f
+func (a *Adapter) newDateCells(f *excelize.File) *dateCells {
+	d := &dateCells{
+		f:      f,
+		config: a.config,
+		styles: make(map[int]bool),
+	}
+	if props, err := f.GetWorkbookProps(); err == nil && props.Date1904 != nil {
+		d.date1904 = *props.Date1904
+	}
+	return d
+}
+
+// isDateStyle reports whether the style index displays dates
+func (d *dateCells) isDateStyle(index int) bool {
+	if date, ok := d.styles[index]; ok {
+		return date
+	}
+	date := false
+	if style, err := d.f.GetStyle(index); err == nil {
+		if style.CustomNumFmt != nil {
+			date = isDateFormat(*style.CustomNumFmt)
+		} else {
+			date = dateNumFmts[style.NumFmt]
+		}
+	}
+	d.styles[index] = date
+	return date
+}
+
+// time returns the time held by cell of column col, false when the cell is
+// neither date-formatted nor in a time column, or does not hold a number
+func (d *dateCells) time(cell, col string) (time.Time, bool) {
+	if !d.config.isTimeColumn(col) {
+		index, err := d.f.GetCellStyle(d.config.SheetName, cell)
+		if err != nil || index == 0 || !d.isDateStyle(index) {
+			return time.Time{}, false
+		}
+	}
+	raw, err := d.f.GetCellValue(d.config.SheetName, cell, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return time.Time{}, false
+	}
+	serial, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	t, err := excelize.ExcelDateToTime(serial, d.date1904)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
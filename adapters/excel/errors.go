@@ -1,6 +1,14 @@
 package excel
 
-import "errors"
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ideamans/go-sheetkv"
+	"github.com/xuri/excelize/v2"
+)
 
 var (
 	// ErrMissingFilePath is returned when file path is not specified
@@ -9,9 +17,38 @@ var (
 	// ErrMissingSheetName is returned when sheet name is not specified
 	ErrMissingSheetName = errors.New("sheet name is required")
 
-	// ErrSheetNotFound is returned when the specified sheet doesn't exist
-	ErrSheetNotFound = errors.New("sheet not found")
+	// ErrSheetNotFound is returned when the specified sheet doesn't exist.
+	// It is sheetkv.ErrSheetNotFound.
+	ErrSheetNotFound = sheetkv.ErrSheetNotFound
 
 	// ErrInvalidFileFormat is returned when the file is not a valid Excel file
 	ErrInvalidFileFormat = errors.New("invalid Excel file format")
+
+	// ErrInvalidHeaderRow is returned when the header row is negative
+	ErrInvalidHeaderRow = errors.New("header row must be positive")
+
+	// ErrInvalidHeaderRows is returned when the number of header rows is negative
+	ErrInvalidHeaderRows = errors.New("header rows must not be negative")
+
+	// ErrInvalidStartColumn is returned when the start column is not a column name
+	ErrInvalidStartColumn = errors.New("invalid start column")
+
+	// ErrInvalidMaxColumns is returned when the number of managed columns is negative
+	ErrInvalidMaxColumns = errors.New("max columns must not be negative")
+
+	// ErrTooManyColumns is returned when the schema does not fit in the managed columns
+	ErrTooManyColumns = errors.New("too many columns")
 )
+
+// fileError wraps the error of accessing the workbook, adding
+// sheetkv.ErrUnauthorized when the file may not be read or written and
+// ErrInvalidFileFormat when it is not a workbook
+func fileError(action string, err error) error {
+	switch {
+	case errors.Is(err, os.ErrPermission):
+		return fmt.Errorf("failed to %s: %w: %w", action, sheetkv.ErrUnauthorized, err)
+	case errors.Is(err, zip.ErrFormat), errors.Is(err, excelize.ErrWorkbookFileFormat):
+		return fmt.Errorf("failed to %s: %w: %w", action, ErrInvalidFileFormat, err)
+	}
+	return fmt.Errorf("failed to %s: %w", action, err)
+}
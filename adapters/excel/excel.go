@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/ideamans/go-sheetkv"
 	"github.com/xuri/excelize/v2"
@@ -15,8 +16,11 @@ import (
 
 // Adapter implements the sheetkv.Adapter interface for Excel files
 type Adapter struct {
-	config *Config
-	mu     sync.RWMutex
+	config  *Config
+	mu      sync.Mutex     // Serializes operations, which share the workbook kept open
+	file    *excelize.File // Workbook kept open between operations, nil until the file is read
+	modTime time.Time      // Modification time of the file when file was read or written
+	size    int64          // Size of the file when file was read or written
 }
 
 // New creates a new Excel adapter with the given configuration
@@ -38,9 +42,9 @@ func New(config *Config) (*Adapter, error) {
 }
 
 // Load retrieves all records and schema from the Excel file
-func (a *Adapter) Load(ctx context.Context) ([]*sheetkv.Record, []string, error) {
-	a.mu.RLock()
-	defer a.mu.RUnlock()
+func (a *Adapter) Load(ctx context.Context) (_ []*sheetkv.Record, _ []string, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 
 	// Check if context is cancelled
 	select {
@@ -50,15 +54,15 @@ func (a *Adapter) Load(ctx context.Context) ([]*sheetkv.Record, []string, error)
 	}
 
 	// Open the Excel file
-	f, err := excelize.OpenFile(a.config.FilePath)
+	f, err := a.workbook()
 	if err != nil {
 		if os.IsNotExist(err) {
 			// File doesn't exist, return empty data
 			return []*sheetkv.Record{}, []string{}, nil
 		}
-		return nil, nil, fmt.Errorf("failed to open Excel file: %w", err)
+		return nil, nil, fileError("open Excel file", err)
 	}
-	defer f.Close()
+	defer a.release(f, &err)
 
 	// Check if sheet exists
 	sheetIndex, err := f.GetSheetIndex(a.config.SheetName)
@@ -76,20 +80,36 @@ func (a *Adapter) Load(ctx context.Context) ([]*sheetkv.Record, []string, error)
 		return nil, nil, fmt.Errorf("failed to get rows: %w", err)
 	}
 
-	if len(rows) == 0 {
+	headerIndex := a.config.headerRow() - 1
+	if len(rows) <= headerIndex {
 		return []*sheetkv.Record{}, []string{}, nil
 	}
 
-	// First row is the schema
-	schema := rows[0]
+	// Header rows are the schema
+	dataIndex := min(a.config.lastHeaderRow(), len(rows))
+	var schema []string
+	if a.config.headerRows() == 1 {
+		schema = a.config.managed(rows[headerIndex])
+	} else {
+		header := make([][]string, 0, dataIndex-headerIndex)
+		for _, row := range rows[headerIndex:dataIndex] {
+			header = append(header, a.config.managed(row))
+		}
+		schema = sheetkv.FlattenHeader(header, a.config.HeaderSeparator)
+	}
+	schema = a.config.ColumnAliases.Columns(schema)
 
 	// Convert rows to records
-	records := make([]*sheetkv.Record, 0, len(rows)-1)
-	for i := 1; i < len(rows); i++ {
-		row := rows[i]
+	dates := a.newDateCells(f)
+	records := make([]*sheetkv.Record, 0, len(rows)-dataIndex)
+	for i := dataIndex; i < len(rows); i++ {
+		row := a.config.managed(rows[i])
+		for len(a.config.FormulaColumns) > 0 && len(row) < len(schema) {
+			row = append(row, "") // Formula cells without a cached value may be trimmed
+		}
 
 		record := &sheetkv.Record{
-			Key:    i + 1, // Row number (1-based, but data starts from row 2)
+			Key:    i - dataIndex + 2, // Data starts at key 2, on the row after the header
 			Values: make(map[string]interface{}),
 		}
 
@@ -113,9 +133,31 @@ func (a *Adapter) Load(ctx context.Context) ([]*sheetkv.Record, []string, error)
 		} else {
 			// Map values to schema columns
 			for j, value := range row {
+				if j < len(schema) && a.config.isFormulaColumn(schema[j]) {
+					value = a.formulaValue(f, j, i+1, value)
+				}
+				if j < len(schema) && schema[j] != "" && value != "" {
+					cell, _ := excelize.CoordinatesToCellName(a.config.startColumn()+j, i+1)
+					if t, ok := dates.time(cell, schema[j]); ok {
+						record.Values[schema[j]] = t
+						continue
+					}
+					// Text columns keep the display text of numbers
+					if !a.config.isFormulaColumn(schema[j]) && a.config.TypeInference.Infers(schema[j], "") {
+						if typed, ok := a.typedValue(f, cell); ok {
+							record.Values[schema[j]] = typed
+							continue
+						}
+					}
+				}
 				if j < len(schema) && schema[j] != "" {
+					value = a.config.FloatFormats.Unformat(schema[j], a.config.Whitespace.Clean(value))
 					// Try to parse as number first
-					if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
+					if !a.config.TypeInference.Infers(schema[j], value) {
+						record.Values[schema[j]] = value
+					} else if b, ok := a.config.BoolTokens.Parse(schema[j], value); ok {
+						record.Values[schema[j]] = b
+					} else if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
 						// Check if it's an integer
 						if intVal := int64(floatVal); float64(intVal) == floatVal {
 							record.Values[schema[j]] = intVal
@@ -138,7 +180,7 @@ func (a *Adapter) Load(ctx context.Context) ([]*sheetkv.Record, []string, error)
 }
 
 // Save replaces all data in the Excel file with the provided records
-func (a *Adapter) Save(ctx context.Context, records []*sheetkv.Record, schema []string, strategy sheetkv.SyncStrategy) error {
+func (a *Adapter) Save(ctx context.Context, records []*sheetkv.Record, schema []string, strategy sheetkv.SyncStrategy) (err error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -152,22 +194,18 @@ func (a *Adapter) Save(ctx context.Context, records []*sheetkv.Record, schema []
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(a.config.FilePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
+		return fileError("create directory", err)
 	}
 
 	// Create a new Excel file or open existing one
-	var f *excelize.File
-	if _, err := os.Stat(a.config.FilePath); err == nil {
-		// File exists, open it
-		f, err = excelize.OpenFile(a.config.FilePath)
-		if err != nil {
-			return fmt.Errorf("failed to open Excel file: %w", err)
-		}
-	} else {
+	f, err := a.workbook()
+	if os.IsNotExist(err) {
 		// File doesn't exist, create new
 		f = excelize.NewFile()
+	} else if err != nil {
+		return fileError("open Excel file", err)
 	}
-	defer f.Close()
+	defer a.release(f, &err)
 
 	// Check if sheet exists, create if not
 	sheetIndex, err := f.GetSheetIndex(a.config.SheetName)
@@ -209,15 +247,30 @@ func (a *Adapter) Save(ctx context.Context, records []*sheetkv.Record, schema []
 		}
 	}
 
-	// Write schema (header row)
-	headerValues := make([]interface{}, len(schema))
-	for i, col := range schema {
-		headerValues[i] = col
+	if a.config.MaxColumns > 0 && len(schema) > a.config.MaxColumns {
+		return fmt.Errorf("%w: %d columns, at most %d", ErrTooManyColumns, len(schema), a.config.MaxColumns)
 	}
 
-	cell := "A1"
-	if err := f.SetSheetRow(a.config.SheetName, cell, &headerValues); err != nil {
-		return fmt.Errorf("failed to write header: %w", err)
+	// Write schema (header row)
+	headers := a.config.ColumnAliases.Headers(schema)
+	headerRows := [][]string{headers}
+	if a.config.headerRows() > 1 {
+		headerRows = sheetkv.SplitHeader(headers, a.config.headerRows(), a.config.HeaderSeparator)
+	}
+	merged := mergedCells(f, a.config.SheetName)
+	for l, row := range headerRows {
+		for i, col := range row {
+			cell, err := excelize.CoordinatesToCellName(a.config.startColumn()+i, a.config.headerRow()+l)
+			if err != nil {
+				return fmt.Errorf("failed to write header: %w", err)
+			}
+			if col == "" && merged[cell] {
+				continue // Clearing it would clear the label of the merged group
+			}
+			if err := f.SetCellValue(a.config.SheetName, cell, col); err != nil {
+				return fmt.Errorf("failed to write header: %w", err)
+			}
+		}
 	}
 
 	// Sort records by key
@@ -239,9 +292,9 @@ func (a *Adapter) Save(ctx context.Context, records []*sheetkv.Record, schema []
 				for i := range emptyRow {
 					emptyRow[i] = ""
 				}
-				cell := fmt.Sprintf("A%d", currentRow)
-				if err := f.SetSheetRow(a.config.SheetName, cell, &emptyRow); err != nil {
-					return fmt.Errorf("failed to write empty row %d: %w", currentRow, err)
+				cell := a.config.cell(a.config.rowOf(currentRow))
+				if err := a.writeRow(f, cell, schema, emptyRow); err != nil {
+					return fmt.Errorf("failed to write empty row %d: %w", a.config.rowOf(currentRow), err)
 				}
 				currentRow++
 			}
@@ -255,9 +308,9 @@ func (a *Adapter) Save(ctx context.Context, records []*sheetkv.Record, schema []
 					rowValues[i] = ""
 				}
 			}
-			cell := fmt.Sprintf("A%d", currentRow)
-			if err := f.SetSheetRow(a.config.SheetName, cell, &rowValues); err != nil {
-				return fmt.Errorf("failed to write row %d: %w", currentRow, err)
+			cell := a.config.cell(a.config.rowOf(currentRow))
+			if err := a.writeRow(f, cell, schema, rowValues); err != nil {
+				return fmt.Errorf("failed to write row %d: %w", a.config.rowOf(currentRow), err)
 			}
 			currentRow++
 		}
@@ -265,20 +318,20 @@ func (a *Adapter) Save(ctx context.Context, records []*sheetkv.Record, schema []
 		// Clear any remaining rows beyond the last record
 		// Find the max row that exists
 		if len(sortedRecords) > 0 {
-			lastKey := sortedRecords[len(sortedRecords)-1].Key
-			// Clear rows beyond lastKey
-			for row := lastKey + 1; row <= lastKey+100; row++ { // Clear up to 100 extra rows
+			lastRow := a.config.rowOf(sortedRecords[len(sortedRecords)-1].Key)
+			// Clear rows beyond lastRow
+			for row := lastRow + 1; row <= lastRow+100; row++ { // Clear up to 100 extra rows
 				emptyRow := make([]interface{}, len(schema))
 				for i := range emptyRow {
 					emptyRow[i] = ""
 				}
-				cell := fmt.Sprintf("A%d", row)
-				_ = f.SetSheetRow(a.config.SheetName, cell, &emptyRow) // Best effort
+				cell := a.config.cell(row)
+				_ = a.writeRow(f, cell, schema, emptyRow) // Best effort
 			}
 		}
 	} else {
-		// Compacting sync: write records sequentially starting from row 2
-		rowNum := 2
+		// Compacting sync: write records sequentially starting below the header
+		rowNum := a.config.rowOf(2)
 		for _, record := range sortedRecords {
 			rowValues := make([]interface{}, len(schema))
 			for i, col := range schema {
@@ -288,32 +341,44 @@ func (a *Adapter) Save(ctx context.Context, records []*sheetkv.Record, schema []
 					rowValues[i] = ""
 				}
 			}
-			cell := fmt.Sprintf("A%d", rowNum)
-			if err := f.SetSheetRow(a.config.SheetName, cell, &rowValues); err != nil {
+			cell := a.config.cell(rowNum)
+			if err := a.writeRow(f, cell, schema, rowValues); err != nil {
 				return fmt.Errorf("failed to write row %d: %w", rowNum, err)
 			}
 			rowNum++
 		}
 
 		// Clear remaining rows after compacting
-		totalRows := len(sortedRecords) + 1 // +1 for header
+		totalRows := len(sortedRecords) + a.config.lastHeaderRow() // Header and the rows above it
 		// Clear up to 100 rows beyond the data
 		for row := totalRows + 1; row <= totalRows+100; row++ {
 			emptyRow := make([]interface{}, len(schema))
 			for i := range emptyRow {
 				emptyRow[i] = ""
 			}
-			cell := fmt.Sprintf("A%d", row)
-			_ = f.SetSheetRow(a.config.SheetName, cell, &emptyRow) // Best effort
+			cell := a.config.cell(row)
+			_ = a.writeRow(f, cell, schema, emptyRow) // Best effort
 		}
 	}
 
-	// Save the file
-	if err := f.SaveAs(a.config.FilePath); err != nil {
-		return fmt.Errorf("failed to save Excel file: %w", err)
+	if len(a.config.FloatFormats) > 0 && len(sortedRecords) > 0 {
+		lastKey := len(sortedRecords) + 1
+		if strategy == sheetkv.SyncStrategyGapPreserving {
+			lastKey = sortedRecords[len(sortedRecords)-1].Key
+		}
+		if err := a.formatNumbers(f, schema, a.config.rowOf(lastKey)); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	if a.config.FormatHeader {
+		if err := a.formatHeader(f, sortedRecords, schema); err != nil {
+			return err
+		}
+	}
+
+	// Save the file
+	return a.saveWorkbook(f)
 }
 
 // BatchUpdate performs multiple operations in a single request
@@ -406,6 +471,35 @@ func (a *Adapter) BatchUpdate(ctx context.Context, operations []sheetkv.Operatio
 	return a.Save(ctx, newRecords, schema, sheetkv.SyncStrategyGapPreserving)
 }
 
+// mergedCells returns the cells covered by merged ranges of the sheet,
+// except their top-left cells
+func mergedCells(f *excelize.File, sheet string) map[string]bool {
+	cells := make(map[string]bool)
+	merges, err := f.GetMergeCells(sheet)
+	if err != nil {
+		return cells
+	}
+
+	for _, merge := range merges {
+		startCol, startRow, err1 := excelize.CellNameToCoordinates(merge.GetStartAxis())
+		endCol, endRow, err2 := excelize.CellNameToCoordinates(merge.GetEndAxis())
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		for col := startCol; col <= endCol; col++ {
+			for row := startRow; row <= endRow; row++ {
+				if col == startCol && row == startRow {
+					continue
+				}
+				if name, err := excelize.CoordinatesToCellName(col, row); err == nil {
+					cells[name] = true
+				}
+			}
+		}
+	}
+	return cells
+}
+
 // columnName converts a column number to Excel column name (1 -> A, 26 -> Z, 27 -> AA)
 func columnName(col int) string {
 	result := ""
@@ -416,3 +510,67 @@ func columnName(col int) string {
 	}
 	return result
 }
+
+// writeRow writes values from cell to the right, leaving the cells of
+// formula columns untouched
+func (a *Adapter) writeRow(f *excelize.File, cell string, schema []string, values []interface{}) error {
+	// Values are written as typed cells, booleans as the configured tokens,
+	// and the times of time columns stored by Record.SetTime as dates
+	for i, value := range values {
+		values[i] = nativeValue(value)
+		if i >= len(schema) {
+			continue
+		}
+		switch val := values[i].(type) {
+		case bool:
+			if token, ok := a.config.BoolTokens.Format(schema[i], val); ok {
+				values[i] = token
+			}
+		case string:
+			if a.config.isTimeColumn(schema[i]) {
+				if t, err := time.Parse(time.RFC3339, val); err == nil {
+					values[i] = t
+				}
+			}
+		}
+	}
+
+	if len(a.config.FormulaColumns) == 0 {
+		return f.SetSheetRow(a.config.SheetName, cell, &values)
+	}
+
+	col, row, err := excelize.CellNameToCoordinates(cell)
+	if err != nil {
+		return err
+	}
+	for i, value := range values {
+		if i < len(schema) && a.config.isFormulaColumn(schema[i]) {
+			continue
+		}
+		name, err := excelize.CoordinatesToCellName(col+i, row)
+		if err != nil {
+			return err
+		}
+		if err := f.SetCellValue(a.config.SheetName, name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formulaValue returns the computed value of the j-th managed cell of row.
+// The cached result may be missing or stale after a save, so the formula is
+// evaluated, falling back to the cached value for unsupported functions.
+func (a *Adapter) formulaValue(f *excelize.File, j, row int, cached string) string {
+	cell, err := excelize.CoordinatesToCellName(a.config.startColumn()+j, row)
+	if err != nil {
+		return cached
+	}
+	if formula, err := f.GetCellFormula(a.config.SheetName, cell); err != nil || formula == "" {
+		return cached
+	}
+	if value, err := f.CalcCellValue(a.config.SheetName, cell); err == nil {
+		return value
+	}
+	return cached
+}
@@ -2,11 +2,16 @@ package excel
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/ideamans/go-sheetkv"
+	"github.com/xuri/excelize/v2"
 )
 
 func TestNew(t *testing.T) {
@@ -505,6 +510,575 @@ func TestAdapter_SyncStrategies(t *testing.T) {
 	})
 }
 
+func TestAdapter_HeaderRow(t *testing.T) {
+	ctx := context.Background()
+	testFile := filepath.Join(t.TempDir(), "header.xlsx")
+
+	// A title and a note above the header
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", "Data")
+	f.SetCellValue("Data", "A1", "Member list")
+	f.SetCellValue("Data", "A2", "Do not edit the header")
+	f.SetSheetRow("Data", "A3", &[]interface{}{"name", "age"})
+	f.SetSheetRow("Data", "A4", &[]interface{}{"John", 30})
+	if err := f.SaveAs(testFile); err != nil {
+		t.Fatalf("SaveAs() error = %v", err)
+	}
+	f.Close()
+
+	adapter, err := New(&Config{FilePath: testFile, SheetName: "Data", HeaderRow: 3})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	records, schema, err := adapter.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(schema) != 2 || schema[0] != "name" {
+		t.Errorf("schema = %v, want [name age]", schema)
+	}
+	if len(records) != 1 || records[0].Key != 2 || records[0].Values["name"] != "John" {
+		t.Fatalf("records = %+v, want John at key 2", records)
+	}
+
+	records = append(records, &sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane", "age": int64(25)}})
+	if err := adapter.Save(ctx, records, schema, sheetkv.SyncStrategyCompacting); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	f, err = excelize.OpenFile(testFile)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	defer f.Close()
+
+	for cell, want := range map[string]string{"A1": "Member list", "A2": "Do not edit the header", "A3": "name", "A4": "John", "A5": "Jane"} {
+		if got, _ := f.GetCellValue("Data", cell); got != want {
+			t.Errorf("%s = %q, want %q", cell, got, want)
+		}
+	}
+
+	if _, err := New(&Config{FilePath: testFile, SheetName: "Data", HeaderRow: -1}); err != ErrInvalidHeaderRow {
+		t.Errorf("New() error = %v, want %v", err, ErrInvalidHeaderRow)
+	}
+}
+
+func TestAdapter_ColumnRange(t *testing.T) {
+	ctx := context.Background()
+	testFile := filepath.Join(t.TempDir(), "columns.xlsx")
+
+	// A note in column A and a computed column after the managed ones
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", "Data")
+	f.SetSheetRow("Data", "A1", &[]interface{}{"notes", "", "name", "age", "total"})
+	f.SetSheetRow("Data", "A2", &[]interface{}{"keep me", "", "John", 30})
+	f.SetCellFormula("Data", "E2", "D2*2")
+	if err := f.SaveAs(testFile); err != nil {
+		t.Fatalf("SaveAs() error = %v", err)
+	}
+	f.Close()
+
+	adapter, err := New(&Config{FilePath: testFile, SheetName: "Data", StartColumn: "C", MaxColumns: 2})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	records, schema, err := adapter.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(schema) != 2 || schema[0] != "name" || schema[1] != "age" {
+		t.Errorf("schema = %v, want [name age]", schema)
+	}
+	if len(records) != 1 || records[0].Values["name"] != "John" {
+		t.Fatalf("records = %+v, want John", records)
+	}
+
+	records[0].Values["age"] = int64(31)
+	if err := adapter.Save(ctx, records, schema, sheetkv.SyncStrategyGapPreserving); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	f, err = excelize.OpenFile(testFile)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	defer f.Close()
+
+	for cell, want := range map[string]string{"A2": "keep me", "C2": "John", "D2": "31", "E1": "total"} {
+		if got, _ := f.GetCellValue("Data", cell); got != want {
+			t.Errorf("%s = %q, want %q", cell, got, want)
+		}
+	}
+	if formula, _ := f.GetCellFormula("Data", "E2"); formula != "D2*2" {
+		t.Errorf("E2 formula = %q, want D2*2", formula)
+	}
+
+	if err := adapter.Save(ctx, records, []string{"name", "age", "email"}, sheetkv.SyncStrategyGapPreserving); !errors.Is(err, ErrTooManyColumns) {
+		t.Errorf("Save() error = %v, want %v", err, ErrTooManyColumns)
+	}
+	if _, err := New(&Config{FilePath: testFile, SheetName: "Data", StartColumn: "3"}); !errors.Is(err, ErrInvalidStartColumn) {
+		t.Errorf("New() error = %v, want %v", err, ErrInvalidStartColumn)
+	}
+}
+
+func TestAdapter_GroupedHeader(t *testing.T) {
+	ctx := context.Background()
+	testFile := filepath.Join(t.TempDir(), "grouped.xlsx")
+
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", "Data")
+	f.SetSheetRow("Data", "A1", &[]interface{}{"name", "address", ""})
+	f.SetSheetRow("Data", "A2", &[]interface{}{"", "city", "zip"})
+	f.SetSheetRow("Data", "A3", &[]interface{}{"John", "Tokyo", "100-0001"})
+	f.MergeCell("Data", "B1", "C1")
+	if err := f.SaveAs(testFile); err != nil {
+		t.Fatalf("SaveAs() error = %v", err)
+	}
+	f.Close()
+
+	adapter, err := New(&Config{FilePath: testFile, SheetName: "Data", HeaderRows: 2})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	records, schema, err := adapter.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if want := []string{"name", "address/city", "address/zip"}; !reflect.DeepEqual(schema, want) {
+		t.Errorf("schema = %v, want %v", schema, want)
+	}
+	if len(records) != 1 || records[0].Key != 2 || records[0].Values["address/city"] != "Tokyo" {
+		t.Fatalf("records = %+v, want Tokyo at key 2", records)
+	}
+
+	records = append(records, &sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane", "address/city": "Osaka"}})
+	if err := adapter.Save(ctx, records, schema, sheetkv.SyncStrategyCompacting); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	f, err = excelize.OpenFile(testFile)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	defer f.Close()
+
+	for cell, want := range map[string]string{"B1": "address", "B2": "city", "C2": "zip", "A4": "Jane", "B4": "Osaka"} {
+		if got, _ := f.GetCellValue("Data", cell); got != want {
+			t.Errorf("%s = %q, want %q", cell, got, want)
+		}
+	}
+	if merges, _ := f.GetMergeCells("Data"); len(merges) != 1 {
+		t.Errorf("merged cells = %v, want the group label kept merged", merges)
+	}
+}
+
+func TestAdapter_FormulaColumns(t *testing.T) {
+	ctx := context.Background()
+	testFile := filepath.Join(t.TempDir(), "formula.xlsx")
+
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", "Data")
+	f.SetSheetRow("Data", "A1", &[]interface{}{"price", "total"})
+	f.SetCellValue("Data", "A2", 10)
+	f.SetCellFormula("Data", "B2", "A2*2")
+	f.SetCellValue("Data", "A3", 20)
+	f.SetCellFormula("Data", "B3", "A3*2")
+	if err := f.SaveAs(testFile); err != nil {
+		t.Fatalf("SaveAs() error = %v", err)
+	}
+	f.Close()
+
+	adapter, err := New(&Config{FilePath: testFile, SheetName: "Data", FormulaColumns: []string{"total"}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	records, schema, err := adapter.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(records) != 2 || records[0].Values["total"] != int64(20) || records[1].Values["total"] != int64(40) {
+		t.Fatalf("records = %+v, want computed totals 20 and 40", records)
+	}
+
+	records[0].Values["price"] = int64(15)
+	records[0].Values["total"] = int64(999) // Stale or bogus values are not written
+	if err := adapter.Save(ctx, records, schema, sheetkv.SyncStrategyGapPreserving); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	f, err = excelize.OpenFile(testFile)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	defer f.Close()
+
+	for cell, want := range map[string]string{"B2": "A2*2", "B3": "A3*2"} {
+		if got, _ := f.GetCellFormula("Data", cell); got != want {
+			t.Errorf("%s formula = %q, want %q", cell, got, want)
+		}
+	}
+	if got, _ := f.GetCellValue("Data", "A2"); got != "15" {
+		t.Errorf("A2 = %q, want 15", got)
+	}
+
+	records, _, err = adapter.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if records[0].Values["total"] != int64(30) {
+		t.Errorf("total = %v, want 30 after recalculation", records[0].Values["total"])
+	}
+}
+
+func TestAdapter_ColumnAliases(t *testing.T) {
+	ctx := context.Background()
+	testFile := filepath.Join(t.TempDir(), "aliases.xlsx")
+
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", "Data")
+	f.SetSheetRow("Data", "A1", &[]interface{}{"氏名", "Join Date", "email"})
+	f.SetSheetRow("Data", "A2", &[]interface{}{"山田太郎", "2024-04-01", "taro@example.com"})
+	if err := f.SaveAs(testFile); err != nil {
+		t.Fatalf("SaveAs() error = %v", err)
+	}
+	f.Close()
+
+	adapter, err := New(&Config{
+		FilePath:      testFile,
+		SheetName:     "Data",
+		ColumnAliases: sheetkv.ColumnAliases{"氏名": "name", "Join Date": "joined_at"},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	records, schema, err := adapter.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if want := []string{"name", "joined_at", "email"}; !reflect.DeepEqual(schema, want) {
+		t.Errorf("schema = %v, want %v", schema, want)
+	}
+	if records[0].Values["name"] != "山田太郎" {
+		t.Errorf("name = %v, want 山田太郎", records[0].Values["name"])
+	}
+
+	if err := adapter.Save(ctx, records, schema, sheetkv.SyncStrategyCompacting); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	f, err = excelize.OpenFile(testFile)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	defer f.Close()
+	for cell, want := range map[string]string{"A1": "氏名", "B1": "Join Date", "C1": "email"} {
+		if got, _ := f.GetCellValue("Data", cell); got != want {
+			t.Errorf("%s = %q, want %q", cell, got, want)
+		}
+	}
+}
+
+func TestAdapter_Whitespace(t *testing.T) {
+	ctx := context.Background()
+	testFile := filepath.Join(t.TempDir(), "whitespace.xlsx")
+
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", "Data")
+	f.SetSheetRow("Data", "A1", &[]interface{}{"name", "age"})
+	f.SetSheetRow("Data", "A2", &[]interface{}{"　山田　　太郎 ", " 42 "})
+	if err := f.SaveAs(testFile); err != nil {
+		t.Fatalf("SaveAs() error = %v", err)
+	}
+	f.Close()
+
+	adapter, err := New(&Config{
+		FilePath:   testFile,
+		SheetName:  "Data",
+		Whitespace: sheetkv.Whitespace{Trim: true, Collapse: true, FullWidth: true},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	records, _, err := adapter.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := records[0].Values["name"]; got != "山田 太郎" {
+		t.Errorf("name = %q, want %q", got, "山田 太郎")
+	}
+	if got := records[0].Values["age"]; got != int64(42) {
+		t.Errorf("age = %#v, want int64(42) once trimmed", got)
+	}
+}
+
+func TestAdapter_BoolTokens(t *testing.T) {
+	ctx := context.Background()
+	testFile := filepath.Join(t.TempDir(), "bools.xlsx")
+
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", "Data")
+	f.SetSheetRow("Data", "A1", &[]interface{}{"task", "done", "count"})
+	f.SetSheetRow("Data", "A2", &[]interface{}{"write", "○", "1"})
+	f.SetSheetRow("Data", "A3", &[]interface{}{"review", "×", "0"})
+	if err := f.SaveAs(testFile); err != nil {
+		t.Fatalf("SaveAs() error = %v", err)
+	}
+	f.Close()
+
+	adapter, err := New(&Config{
+		FilePath:   testFile,
+		SheetName:  "Data",
+		BoolTokens: sheetkv.BoolTokens{True: []string{"○"}, False: []string{"×"}},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	records, schema, err := adapter.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if records[0].Values["done"] != true || records[1].Values["done"] != false {
+		t.Errorf("done = %v, %v, want true, false", records[0].Values["done"], records[1].Values["done"])
+	}
+	if records[0].Values["count"] != int64(1) {
+		t.Errorf("count = %#v, want int64(1)", records[0].Values["count"])
+	}
+
+	records[1].Values["done"] = true
+	if err := adapter.Save(ctx, records, schema, sheetkv.SyncStrategyCompacting); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	f, err = excelize.OpenFile(testFile)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	defer f.Close()
+	for cell, want := range map[string]string{"B2": "○", "B3": "○"} {
+		if got, _ := f.GetCellValue("Data", cell); got != want {
+			t.Errorf("%s = %q, want %q", cell, got, want)
+		}
+	}
+}
+
+func TestAdapter_TypeInference(t *testing.T) {
+	ctx := context.Background()
+	testFile := filepath.Join(t.TempDir(), "types.xlsx")
+
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", "Data")
+	f.SetSheetRow("Data", "A1", &[]interface{}{"zip", "phone", "qty", "flag"})
+	f.SetSheetRow("Data", "A2", &[]interface{}{"123", "09012345678", "42", "true"})
+	if err := f.SaveAs(testFile); err != nil {
+		t.Fatalf("SaveAs() error = %v", err)
+	}
+	f.Close()
+
+	adapter, err := New(&Config{
+		FilePath:      testFile,
+		SheetName:     "Data",
+		TypeInference: sheetkv.TypeInference{TextColumns: []string{"zip"}, Conservative: true},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	records, _, err := adapter.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := map[string]interface{}{"zip": "123", "phone": "09012345678", "qty": int64(42), "flag": "true"}
+	if !reflect.DeepEqual(records[0].Values, want) {
+		t.Errorf("Values = %#v, want %#v", records[0].Values, want)
+	}
+}
+
+func TestAdapter_FloatFormats(t *testing.T) {
+	ctx := context.Background()
+	testFile := filepath.Join(t.TempDir(), "floats.xlsx")
+
+	adapter, err := New(&Config{
+		FilePath:     testFile,
+		SheetName:    "Data",
+		FloatFormats: sheetkv.FloatFormats{"price": {Fixed: true, Decimals: 2, Thousands: true}},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	records := []*sheetkv.Record{
+		{Key: 2, Values: map[string]interface{}{"price": 1234.5678, "rate": 0.125}},
+	}
+	if err := adapter.Save(ctx, records, []string{"price", "rate"}, sheetkv.SyncStrategyCompacting); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	f, err := excelize.OpenFile(testFile)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	if got, _ := f.GetCellValue("Data", "A2"); got != "1,234.57" {
+		t.Errorf("A2 = %q, want %q", got, "1,234.57")
+	}
+	if got, _ := f.GetCellValue("Data", "A2", excelize.Options{RawCellValue: true}); got != "1234.5678" {
+		t.Errorf("raw A2 = %q, want the number kept", got)
+	}
+	f.Close()
+
+	loaded, _, err := adapter.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := loaded[0].Values["price"]; got != 1234.5678 {
+		t.Errorf("price = %#v, want 1234.5678 as stored", got)
+	}
+	if got := loaded[0].Values["rate"]; got != 0.125 {
+		t.Errorf("rate = %#v, want 0.125", got)
+	}
+}
+
+func TestAdapter_Dates(t *testing.T) {
+	ctx := context.Background()
+	testFile := filepath.Join(t.TempDir(), "dates.xlsx")
+
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", "Data")
+	f.SetSheetRow("Data", "A1", &[]interface{}{"due", "at", "serial", "count"})
+	f.SetSheetRow("Data", "A2", &[]interface{}{45658, 45658.75, 45659, 45660})
+	builtin, _ := f.NewStyle(&excelize.Style{NumFmt: 14})
+	custom := `yyyy"年"m"月"d"日"`
+	customStyle, _ := f.NewStyle(&excelize.Style{CustomNumFmt: &custom})
+	f.SetCellStyle("Data", "A2", "A2", builtin)
+	f.SetCellStyle("Data", "B2", "B2", customStyle)
+	if err := f.SaveAs(testFile); err != nil {
+		t.Fatalf("SaveAs() error = %v", err)
+	}
+	f.Close()
+
+	adapter, err := New(&Config{FilePath: testFile, SheetName: "Data", TimeColumns: []string{"serial"}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	records, schema, err := adapter.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := map[string]interface{}{
+		"due":    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		"at":     time.Date(2025, 1, 1, 18, 0, 0, 0, time.UTC),
+		"serial": time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		"count":  int64(45660),
+	}
+	if !reflect.DeepEqual(records[0].Values, want) {
+		t.Errorf("Values = %#v, want %#v", records[0].Values, want)
+	}
+
+	records = append(records, &sheetkv.Record{Key: 3})
+	records[1].SetTime("serial", time.Date(2025, 1, 3, 12, 0, 0, 0, time.UTC))
+	if err := adapter.Save(ctx, records, schema, sheetkv.SyncStrategyCompacting); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, _, err := adapter.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := loaded[0].Values["at"]; got != want["at"] {
+		t.Errorf("at = %#v after save, want %v", got, want["at"])
+	}
+	if got, want := loaded[1].Values["serial"], time.Date(2025, 1, 3, 12, 0, 0, 0, time.UTC); got != want {
+		t.Errorf("serial = %#v after save, want %v", got, want)
+	}
+}
+
+func TestAdapter_NativeTypes(t *testing.T) {
+	ctx := context.Background()
+	testFile := filepath.Join(t.TempDir(), "types.xlsx")
+
+	type price float64
+	adapter, err := New(&Config{FilePath: testFile, SheetName: "Data"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	records := []*sheetkv.Record{
+		{Key: 2, Values: map[string]interface{}{
+			"int":    7,
+			"float":  0.1,
+			"named":  price(12.5),
+			"number": json.Number("42"),
+			"bool":   true,
+			"text":   "00123",
+		}},
+	}
+	schema := []string{"int", "float", "named", "number", "bool", "text"}
+	if err := adapter.Save(ctx, records, schema, sheetkv.SyncStrategyCompacting); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	f, err := excelize.OpenFile(testFile)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	// A formula in the workbook computes with the written cells
+	f.SetCellFormula("Data", "G2", "A2+C2+D2")
+	if got, err := f.CalcCellValue("Data", "G2"); err != nil || got != "61.5" {
+		t.Errorf("A2+C2+D2 = %q, %v, want 61.5", got, err)
+	}
+	for cell, want := range map[string]excelize.CellType{"C2": excelize.CellTypeUnset, "E2": excelize.CellTypeBool, "F2": excelize.CellTypeSharedString} {
+		if got, _ := f.GetCellType("Data", cell); got != want {
+			t.Errorf("GetCellType(%s) = %v, want %v", cell, got, want)
+		}
+	}
+	f.Close()
+
+	loaded, _, err := adapter.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := map[string]interface{}{
+		"int":    int64(7),
+		"float":  0.1,
+		"named":  12.5,
+		"number": int64(42),
+		"bool":   true,
+		"text":   int64(123),
+	}
+	if !reflect.DeepEqual(loaded[0].Values, want) {
+		t.Errorf("Values = %#v, want %#v", loaded[0].Values, want)
+	}
+}
+
+func TestIsDateFormat(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"yyyy-mm-dd", true},
+		{"h:mm AM/PM", true},
+		{"[h]:mm", true},
+		{`yyyy"年"m"月"d"日"`, true},
+		{"0.00", false},
+		{"#,##0", false},
+		{"[Red]0.00", false},
+		{`[$¥-411]#,##0`, false},
+		{`0 "days"`, false},
+		{`0\d`, false},
+		{"0.00E+00", false},
+		{"General", false},
+	}
+	for _, tt := range tests {
+		if got := isDateFormat(tt.code); got != tt.want {
+			t.Errorf("isDateFormat(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
 func TestColumnName(t *testing.T) {
 	tests := []struct {
 		col  int
@@ -528,3 +1102,31 @@ func TestColumnName(t *testing.T) {
 		})
 	}
 }
+
+func TestAdapter_InvalidFile(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "notes.xlsx")
+	if err := os.WriteFile(testFile, []byte("not a workbook"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	adapter, err := New(&Config{FilePath: testFile, SheetName: "Data"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, _, err := adapter.Load(context.Background()); !errors.Is(err, ErrInvalidFileFormat) {
+		t.Errorf("Load() error = %v, want ErrInvalidFileFormat", err)
+	}
+	if err := adapter.Save(context.Background(), nil, []string{"name"}, sheetkv.SyncStrategyGapPreserving); !errors.Is(err, ErrInvalidFileFormat) {
+		t.Errorf("Save() error = %v, want ErrInvalidFileFormat", err)
+	}
+}
+
+func TestFileError(t *testing.T) {
+	err := fileError("open Excel file", &os.PathError{Op: "open", Path: "data.xlsx", Err: os.ErrPermission})
+	if !errors.Is(err, sheetkv.ErrUnauthorized) || !errors.Is(err, os.ErrPermission) {
+		t.Errorf("fileError() = %v, want ErrUnauthorized wrapping the permission error", err)
+	}
+	if !errors.Is(ErrSheetNotFound, sheetkv.ErrSheetNotFound) {
+		t.Error("ErrSheetNotFound should be sheetkv.ErrSheetNotFound")
+	}
+}
@@ -0,0 +1,97 @@
+package excel
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/ideamans/go-sheetkv"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	minColumnWidth = 8  // Default Excel column width
+	maxColumnWidth = 60 // Long texts wrap instead of widening the column further
+)
+
+// formatHeader freezes the header rows, makes them bold and sizes the
+// managed columns to their content. Excel has no auto-fit, so widths are
+// estimated from the number of characters.
+func (a *Adapter) formatHeader(f *excelize.File, records []*sheetkv.Record, schema []string) error {
+	if len(schema) == 0 {
+		return nil
+	}
+	sheet := a.config.SheetName
+	first := a.config.startColumn()
+
+	top, err := excelize.CoordinatesToCellName(1, a.config.lastHeaderRow()+1)
+	if err != nil {
+		return err
+	}
+	if err := f.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      a.config.lastHeaderRow(),
+		TopLeftCell: top,
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return fmt.Errorf("failed to freeze header: %w", err)
+	}
+
+	style, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return fmt.Errorf("failed to create header style: %w", err)
+	}
+	start, _ := excelize.CoordinatesToCellName(first, a.config.headerRow())
+	end, _ := excelize.CoordinatesToCellName(first+len(schema)-1, a.config.lastHeaderRow())
+	if err := f.SetCellStyle(sheet, start, end, style); err != nil {
+		return fmt.Errorf("failed to set header style: %w", err)
+	}
+
+	for i, col := range schema {
+		width := utf8.RuneCountInString(a.config.ColumnAliases.Header(col))
+		for _, record := range records {
+			if n := utf8.RuneCountInString(fmt.Sprintf("%v", record.Values[col])); n > width {
+				width = n
+			}
+		}
+		width = min(max(width+2, minColumnWidth), maxColumnWidth)
+
+		name := columnName(first + i)
+		if err := f.SetColWidth(sheet, name, name, float64(width)); err != nil {
+			return fmt.Errorf("failed to set column width: %w", err)
+		}
+	}
+	return nil
+}
+
+// formatNumbers applies the number formats of Config.FloatFormats to the
+// data rows of their columns, down to lastRow. Values stay numbers; only
+// their display changes.
+func (a *Adapter) formatNumbers(f *excelize.File, schema []string, lastRow int) error {
+	sheet := a.config.SheetName
+	first := a.config.startColumn()
+	styles := make(map[string]int)
+
+	for i, col := range schema {
+		format, ok := a.config.FloatFormats.For(col)
+		if !ok || format.NumberFormat() == "" {
+			continue
+		}
+		numFmt := format.NumberFormat()
+		style, ok := styles[numFmt]
+		if !ok {
+			var err error
+			style, err = f.NewStyle(&excelize.Style{CustomNumFmt: &numFmt})
+			if err != nil {
+				return fmt.Errorf("failed to create number style: %w", err)
+			}
+			styles[numFmt] = style
+		}
+
+		start, _ := excelize.CoordinatesToCellName(first+i, a.config.rowOf(2))
+		end, _ := excelize.CoordinatesToCellName(first+i, lastRow)
+		if err := f.SetCellStyle(sheet, start, end, style); err != nil {
+			return fmt.Errorf("failed to set number style: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package excel
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestAdapter_FormatHeader(t *testing.T) {
+	ctx := context.Background()
+	testFile := filepath.Join(t.TempDir(), "format.xlsx")
+
+	adapter, err := New(&Config{FilePath: testFile, SheetName: "Data", FormatHeader: true})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	records := []*sheetkv.Record{
+		{Key: 2, Values: map[string]interface{}{"name": "John", "comment": "A rather long comment about John"}},
+	}
+	if err := adapter.Save(ctx, records, []string{"name", "comment"}, sheetkv.SyncStrategyCompacting); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	f, err := excelize.OpenFile(testFile)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	defer f.Close()
+
+	panes, err := f.GetPanes("Data")
+	if err != nil {
+		t.Fatalf("GetPanes() error = %v", err)
+	}
+	if !panes.Freeze || panes.YSplit != 1 {
+		t.Errorf("panes = %+v, want the first row frozen", panes)
+	}
+
+	styleID, _ := f.GetCellStyle("Data", "B1")
+	style, err := f.GetStyle(styleID)
+	if err != nil {
+		t.Fatalf("GetStyle() error = %v", err)
+	}
+	if style.Font == nil || !style.Font.Bold {
+		t.Error("header should be bold")
+	}
+	if styleID, _ := f.GetCellStyle("Data", "A2"); styleID != 0 {
+		t.Error("data rows should keep the default style")
+	}
+
+	nameWidth, _ := f.GetColWidth("Data", "A")
+	commentWidth, _ := f.GetColWidth("Data", "B")
+	if nameWidth != minColumnWidth || commentWidth <= nameWidth {
+		t.Errorf("widths = %v, %v, want %v and a wider comment column", nameWidth, commentWidth, float64(minColumnWidth))
+	}
+}
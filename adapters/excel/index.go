@@ -0,0 +1,148 @@
+package excel
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// SaveIndex writes the secondary index to the hidden index sheet
+func (a *Adapter) SaveIndex(ctx context.Context, index *sheetkv.Index) (err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	f, err := a.workbook()
+	if err != nil {
+		return fileError("open Excel file", err)
+	}
+	defer a.release(f, &err)
+
+	// Recreate the sheet so that stale entries disappear
+	name := a.config.indexSheetName()
+	if idx, err := f.GetSheetIndex(name); err == nil && idx != -1 {
+		if err := f.DeleteSheet(name); err != nil {
+			return fmt.Errorf("failed to reset index sheet: %w", err)
+		}
+	}
+	if _, err := f.NewSheet(name); err != nil {
+		return fmt.Errorf("failed to create index sheet: %w", err)
+	}
+	if err := f.SetSheetVisible(name, false); err != nil {
+		return fmt.Errorf("failed to hide index sheet: %w", err)
+	}
+
+	header := []interface{}{"column", "value", "keys"}
+	if err := f.SetSheetRow(name, "A1", &header); err != nil {
+		return fmt.Errorf("failed to write index header: %w", err)
+	}
+	for i, entry := range index.Entries {
+		keys := make([]string, len(entry.Keys))
+		for j, key := range entry.Keys {
+			keys[j] = strconv.Itoa(key)
+		}
+		row := []interface{}{entry.Column, entry.Value, strings.Join(keys, ",")}
+		if err := f.SetSheetRow(name, fmt.Sprintf("A%d", i+2), &row); err != nil {
+			return fmt.Errorf("failed to write index row: %w", err)
+		}
+	}
+
+	// Keep the data sheet active
+	if dataIndex, err := f.GetSheetIndex(a.config.SheetName); err == nil && dataIndex != -1 {
+		f.SetActiveSheet(dataIndex)
+	}
+
+	return a.saveWorkbook(f)
+}
+
+// LoadIndex reads the secondary index from the hidden index sheet.
+// It returns nil when the file or the index sheet does not exist.
+func (a *Adapter) LoadIndex(ctx context.Context) (_ *sheetkv.Index, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	f, err := a.workbook()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fileError("open Excel file", err)
+	}
+	defer a.release(f, &err)
+
+	name := a.config.indexSheetName()
+	if idx, err := f.GetSheetIndex(name); err != nil || idx == -1 {
+		return nil, nil
+	}
+
+	rows, err := f.GetRows(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get index rows: %w", err)
+	}
+
+	index := &sheetkv.Index{}
+	seen := make(map[string]bool)
+	for i, row := range rows {
+		if i == 0 || len(row) < 3 {
+			continue // Header or malformed row
+		}
+
+		entry := sheetkv.IndexEntry{Column: row[0], Value: row[1]}
+		for _, part := range strings.Split(row[2], ",") {
+			if key, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+				entry.Keys = append(entry.Keys, key)
+			}
+		}
+
+		if !seen[entry.Column] {
+			seen[entry.Column] = true
+			index.Columns = append(index.Columns, entry.Column)
+		}
+		index.Entries = append(index.Entries, entry)
+	}
+
+	return index, nil
+}
+
+// LoadRows retrieves the records stored at the given keys.
+// The workbook is parsed as a whole, so this mainly exists for parity with
+// remote backends.
+func (a *Adapter) LoadRows(ctx context.Context, keys []int) ([]*sheetkv.Record, []string, error) {
+	records, schema, err := a.Load(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wanted := make(map[int]bool, len(keys))
+	for _, key := range keys {
+		wanted[key] = true
+	}
+
+	result := make([]*sheetkv.Record, 0, len(keys))
+	for _, record := range records {
+		if wanted[record.Key] {
+			result = append(result, record)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+
+	return result, schema, nil
+}
@@ -0,0 +1,99 @@
+package excel
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestAdapter_Index(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "test.xlsx")
+	adapter, err := New(&Config{FilePath: testFile, SheetName: "Users"})
+	if err != nil {
+		t.Fatalf("Failed to create adapter: %v", err)
+	}
+	ctx := context.Background()
+
+	t.Run("LoadIndex without file", func(t *testing.T) {
+		index, err := adapter.LoadIndex(ctx)
+		if err != nil || index != nil {
+			t.Errorf("LoadIndex() = %v, %v, want nil, nil", index, err)
+		}
+	})
+
+	records := []*sheetkv.Record{
+		{Key: 2, Values: map[string]interface{}{"email": "a@example.com", "dept": "Sales"}},
+		{Key: 3, Values: map[string]interface{}{"email": "b@example.com", "dept": "Eng"}},
+		{Key: 4, Values: map[string]interface{}{"email": "c@example.com", "dept": "Sales"}},
+	}
+	if err := adapter.Save(ctx, records, []string{"email", "dept"}, sheetkv.SyncStrategyGapPreserving); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	index := &sheetkv.Index{
+		Columns: []string{"dept"},
+		Entries: []sheetkv.IndexEntry{
+			{Column: "dept", Value: "Eng", Keys: []int{3}},
+			{Column: "dept", Value: "Sales", Keys: []int{2, 4}},
+		},
+	}
+
+	t.Run("SaveIndex and LoadIndex", func(t *testing.T) {
+		if err := adapter.SaveIndex(ctx, index); err != nil {
+			t.Fatalf("SaveIndex() error = %v", err)
+		}
+		// Saving twice must not leave stale entries behind
+		if err := adapter.SaveIndex(ctx, index); err != nil {
+			t.Fatalf("SaveIndex() error = %v", err)
+		}
+
+		got, err := adapter.LoadIndex(ctx)
+		if err != nil {
+			t.Fatalf("LoadIndex() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, index) {
+			t.Errorf("LoadIndex() = %+v, want %+v", got, index)
+		}
+	})
+
+	t.Run("Index sheet is hidden and data is untouched", func(t *testing.T) {
+		f, err := excelize.OpenFile(testFile)
+		if err != nil {
+			t.Fatalf("Failed to open file: %v", err)
+		}
+		defer f.Close()
+
+		visible, err := f.GetSheetVisible("_Users_index")
+		if err != nil {
+			t.Fatalf("GetSheetVisible() error = %v", err)
+		}
+		if visible {
+			t.Error("index sheet should be hidden")
+		}
+
+		loaded, _, err := adapter.Load(ctx)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if len(loaded) != 3 {
+			t.Errorf("Load() got %d records, want 3", len(loaded))
+		}
+	})
+
+	t.Run("LoadRows", func(t *testing.T) {
+		got, schema, err := adapter.LoadRows(ctx, index.Keys("dept", "Sales"))
+		if err != nil {
+			t.Fatalf("LoadRows() error = %v", err)
+		}
+		if !reflect.DeepEqual(schema, []string{"email", "dept"}) {
+			t.Errorf("LoadRows() schema = %v", schema)
+		}
+		if len(got) != 2 || got[0].Key != 2 || got[1].Key != 4 {
+			t.Errorf("LoadRows() = %v, want rows 2 and 4", got)
+		}
+	})
+}
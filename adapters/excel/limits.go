@@ -0,0 +1,61 @@
+package excel
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ideamans/go-sheetkv"
+	"github.com/xuri/excelize/v2"
+)
+
+// Limits returns the size of the file, the used range of the sheet and the
+// cells used by every sheet of the workbook; it implements
+// sheetkv.LimitsReporter. Excel limits each sheet rather than the workbook,
+// so CellLimit is zero.
+func (a *Adapter) Limits(ctx context.Context) (_ *sheetkv.Limits, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	limits := &sheetkv.Limits{
+		RowLimit:    excelize.TotalRows,
+		ColumnLimit: excelize.MaxColumns,
+	}
+
+	info, err := os.Stat(a.config.FilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return limits, nil
+		}
+		return nil, fmt.Errorf("failed to stat Excel file: %w", err)
+	}
+	limits.FileSize = info.Size()
+
+	f, err := a.workbook()
+	if err != nil {
+		return nil, fileError("open Excel file", err)
+	}
+	defer a.release(f, &err)
+
+	for _, sheet := range f.GetSheetList() {
+		rows, err := f.GetRows(sheet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rows: %w", err)
+		}
+		columns := 0
+		for _, row := range rows {
+			columns = max(columns, len(row))
+		}
+		limits.Cells += len(rows) * columns
+		if sheet == a.config.SheetName {
+			limits.Rows, limits.Columns = len(rows), columns
+		}
+	}
+	return limits, nil
+}
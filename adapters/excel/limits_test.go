@@ -0,0 +1,53 @@
+package excel
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestAdapter_Limits(t *testing.T) {
+	ctx := context.Background()
+	testFile := filepath.Join(t.TempDir(), "test.xlsx")
+	adapter, err := New(&Config{FilePath: testFile, SheetName: "Users"})
+	if err != nil {
+		t.Fatalf("Failed to create adapter: %v", err)
+	}
+
+	t.Run("Without file", func(t *testing.T) {
+		limits, err := adapter.Limits(ctx)
+		if err != nil {
+			t.Fatalf("Limits() error = %v", err)
+		}
+		if limits.FileSize != 0 || limits.Cells != 0 || limits.RowLimit != excelize.TotalRows {
+			t.Errorf("Limits() = %+v, want an empty file", limits)
+		}
+	})
+
+	t.Run("Used range", func(t *testing.T) {
+		records := []*sheetkv.Record{
+			{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30), "email": "john@example.com"}},
+			{Key: 3, Values: map[string]interface{}{"name": "Jane"}},
+		}
+		if err := adapter.Save(ctx, records, []string{"name", "age", "email"}, sheetkv.SyncStrategyGapPreserving); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+
+		limits, err := adapter.Limits(ctx)
+		if err != nil {
+			t.Fatalf("Limits() error = %v", err)
+		}
+		if limits.Rows != 3 || limits.Columns != 3 || limits.Cells < 9 {
+			t.Errorf("Limits() = %d x %d, %d cells, want 3 x 3, at least 9 cells", limits.Rows, limits.Columns, limits.Cells)
+		}
+		if limits.FileSize == 0 {
+			t.Error("FileSize = 0, want the size of the file")
+		}
+		if limits.ColumnLimit != excelize.MaxColumns {
+			t.Errorf("ColumnLimit = %d, want %d", limits.ColumnLimit, excelize.MaxColumns)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package excel
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// DefaultLockPoll is how often a FileLock retries a lock held by another
+// process
+const DefaultLockPoll = 50 * time.Millisecond
+
+// FileLock is a sheetkv.Locker holding an exclusive flock on a lock file,
+// so processes sharing a workbook take turns saving it. Lock waits while
+// another process holds the lock. It is supported on Linux, macOS and the
+// BSDs; elsewhere Lock fails.
+type FileLock struct {
+	path string
+	mu   sync.Mutex
+	file *os.File // Open lock file while locked
+}
+
+// NewFileLock creates a lock on the file at path, created when missing
+func NewFileLock(path string) *FileLock {
+	return &FileLock{path: path}
+}
+
+// Locker returns a lock on the file <FilePath>.lock next to the workbook,
+// for sheetkv.Config.Locker
+func (a *Adapter) Locker() *FileLock {
+	return NewFileLock(a.config.FilePath + ".lock")
+}
+
+// Lock takes the lock, waiting while another process holds it until ctx is
+// done
+func (l *FileLock) Lock(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file != nil {
+		return fmt.Errorf("lock %s is already held", l.path)
+	}
+
+	// #nosec G304 - the path of the lock file is chosen by the caller
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return fileError("open lock file", err)
+	}
+
+	for {
+		locked, err := tryLock(file)
+		if err != nil {
+			file.Close()
+			return fmt.Errorf("failed to lock %s: %w", l.path, err)
+		}
+		if locked {
+			l.file = file
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			file.Close()
+			return fmt.Errorf("%w: %s: %w", sheetkv.ErrLocked, l.path, ctx.Err())
+		case <-time.After(DefaultLockPoll):
+		}
+	}
+}
+
+// Unlock releases the lock
+func (l *FileLock) Unlock(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	file := l.file
+	l.file = nil
+
+	if err := unlockFile(file); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to unlock %s: %w", l.path, err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,26 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package excel
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// tryLock takes an exclusive flock on file without waiting, reporting
+// whether it was free
+func tryLock(file *os.File) (bool, error) {
+	// #nosec G115 - file descriptors fit in an int
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// unlockFile releases the flock on file
+func unlockFile(file *os.File) error {
+	// #nosec G115 - file descriptors fit in an int
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,18 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package excel
+
+import (
+	"errors"
+	"os"
+)
+
+// tryLock fails: flock is not available on this platform
+func tryLock(file *os.File) (bool, error) {
+	return false, errors.ErrUnsupported
+}
+
+// unlockFile fails: flock is not available on this platform
+func unlockFile(file *os.File) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,50 @@
+package excel
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestFileLock(t *testing.T) {
+	adapter, err := New(&Config{FilePath: filepath.Join(t.TempDir(), "test.xlsx"), SheetName: "Users"})
+	if err != nil {
+		t.Fatalf("Failed to create adapter: %v", err)
+	}
+	first, second := adapter.Locker(), adapter.Locker()
+	ctx := context.Background()
+
+	if err := first.Lock(ctx); err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+
+	// The other lock waits until its context is done
+	waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if err := second.Lock(waitCtx); !errors.Is(err, sheetkv.ErrLocked) {
+		t.Fatalf("Lock() error = %v, want ErrLocked", err)
+	}
+
+	// and takes the lock once released
+	locked := make(chan error, 1)
+	go func() { locked <- second.Lock(ctx) }()
+	time.Sleep(20 * time.Millisecond)
+	if err := first.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock() error = %v", err)
+	}
+	select {
+	case err := <-locked:
+		if err != nil {
+			t.Fatalf("Lock() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Lock() did not return after Unlock")
+	}
+	if err := second.Unlock(ctx); err != nil {
+		t.Errorf("Unlock() error = %v", err)
+	}
+}
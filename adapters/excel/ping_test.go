@@ -0,0 +1,38 @@
+package excel
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAdapter_Ping(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.xlsx")
+	if err := os.WriteFile(existing, []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{"existing file", existing, false},
+		{"not yet saved", filepath.Join(dir, "new.xlsx"), false},
+		{"missing directory", filepath.Join(dir, "missing", "data.xlsx"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter, err := New(&Config{FilePath: tt.path, SheetName: "Data"})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if err := adapter.Ping(ctx); (err != nil) != tt.wantErr {
+				t.Errorf("Ping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
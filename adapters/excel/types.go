@@ -0,0 +1,77 @@
+package excel
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// nativeValue returns v as one of the types excelize writes as a typed
+// cell. Named types, such as a type Price float64, and json.Number would
+// otherwise be written as text.
+func nativeValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil, string, bool, int64, float64, time.Time, time.Duration:
+		return v
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		if f, err := val.Float64(); err == nil {
+			return f
+		}
+		return val.String()
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint()
+	case reflect.Float32:
+		// Through the shortest text, so float32(0.1) is written as 0.1
+		f, _ := strconv.ParseFloat(strconv.FormatFloat(rv.Float(), 'g', -1, 32), 64)
+		return f
+	case reflect.Float64:
+		return rv.Float()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.String:
+		return rv.String()
+	}
+	return v
+}
+
+// typedValue returns the value of a number or boolean cell from its stored
+// value rather than its display text, false for text and formula cells
+func (a *Adapter) typedValue(f *excelize.File, cell string) (interface{}, bool) {
+	cellType, err := f.GetCellType(a.config.SheetName, cell)
+	if err != nil {
+		return nil, false
+	}
+	switch cellType {
+	case excelize.CellTypeUnset, excelize.CellTypeNumber, excelize.CellTypeBool:
+	default:
+		return nil, false
+	}
+
+	raw, err := f.GetCellValue(a.config.SheetName, cell, excelize.Options{RawCellValue: true})
+	if err != nil || raw == "" {
+		return nil, false
+	}
+	if cellType == excelize.CellTypeBool {
+		return raw == "1" || raw == "TRUE" || raw == "true", true
+	}
+	n, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, false
+	}
+	if i := int64(n); float64(i) == n {
+		return i, true
+	}
+	return n, true
+}
@@ -0,0 +1,82 @@
+package excel
+
+import (
+	"os"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// workbook returns the parsed workbook of the file. The workbook is kept
+// open between operations and parsed again only when the file changed on
+// disk since it was read or written, by its modification time or size. The
+// error satisfies os.IsNotExist when the file does not exist. Callers must
+// hold the lock and end the operation with release.
+func (a *Adapter) workbook() (*excelize.File, error) {
+	info, err := os.Stat(a.config.FilePath)
+	if err != nil {
+		a.discard()
+		return nil, err
+	}
+	if a.file != nil && info.ModTime().Equal(a.modTime) && info.Size() == a.size {
+		return a.file, nil
+	}
+
+	a.discard()
+	f, err := excelize.OpenFile(a.config.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	if !a.config.ReopenFile {
+		a.file, a.modTime, a.size = f, info.ModTime(), info.Size()
+	}
+	return f, nil
+}
+
+// saveWorkbook writes f to the file, keeping it open as the workbook of the
+// file
+func (a *Adapter) saveWorkbook(f *excelize.File) error {
+	if err := f.SaveAs(a.config.FilePath); err != nil {
+		return fileError("save Excel file", err)
+	}
+	if a.config.ReopenFile {
+		return nil
+	}
+	info, err := os.Stat(a.config.FilePath)
+	if err != nil {
+		return fileError("stat Excel file", err)
+	}
+	a.file, a.modTime, a.size = f, info.ModTime(), info.Size()
+	return nil
+}
+
+// release ends an operation on f. A failed operation may have left f
+// half-modified, so it is closed and the file parsed again next time, as
+// f is when it is not the workbook kept open.
+func (a *Adapter) release(f *excelize.File, err *error) {
+	if f == a.file && *err != nil {
+		a.discard()
+		return
+	}
+	if f != a.file {
+		f.Close()
+	}
+}
+
+// discard closes the workbook kept open
+func (a *Adapter) discard() {
+	if a.file != nil {
+		a.file.Close()
+		a.file, a.modTime, a.size = nil, time.Time{}, 0
+	}
+}
+
+// Close closes the workbook kept open between operations. The adapter stays
+// usable: the next operation reads the file again.
+func (a *Adapter) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.discard()
+	return nil
+}
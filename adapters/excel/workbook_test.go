@@ -0,0 +1,71 @@
+package excel
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestAdapter_KeepsWorkbookOpen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "test.xlsx")
+	adapter, _ := New(&Config{FilePath: path, SheetName: "Sheet1"})
+	defer adapter.Close()
+
+	records := []*sheetkv.Record{{Key: 2, Values: map[string]interface{}{"name": "John"}}}
+	if err := adapter.Save(ctx, records, []string{"name"}, sheetkv.SyncStrategyCompacting); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	saved := adapter.file
+	if _, _, err := adapter.Load(ctx); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if saved == nil || adapter.file != saved {
+		t.Fatal("Load() parsed the file written by the adapter again")
+	}
+
+	t.Run("External changes", func(t *testing.T) {
+		// Make sure the modification time moves on coarse file systems
+		time.Sleep(10 * time.Millisecond)
+		f, err := excelize.OpenFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.SetCellValue("Sheet1", "A3", "Jane, added by hand")
+		if err := f.SaveAs(path); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+
+		loaded, _, err := adapter.Load(ctx)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if len(loaded) != 2 || adapter.file == saved {
+			t.Errorf("Load() = %v, want the row added by hand", loaded)
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		if err := adapter.Close(); err != nil || adapter.file != nil {
+			t.Errorf("Close() error = %v, workbook kept = %v", err, adapter.file != nil)
+		}
+		if loaded, _, err := adapter.Load(ctx); err != nil || len(loaded) != 2 {
+			t.Errorf("Load() after Close = %v, %v", loaded, err)
+		}
+	})
+
+	t.Run("ReopenFile", func(t *testing.T) {
+		adapter, _ := New(&Config{FilePath: path, SheetName: "Sheet1", ReopenFile: true})
+		if _, _, err := adapter.Load(ctx); err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if adapter.file != nil {
+			t.Error("ReopenFile kept the workbook open")
+		}
+	})
+}
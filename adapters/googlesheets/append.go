@@ -0,0 +1,81 @@
+package googlesheets
+
+import (
+	"context"
+
+	"github.com/ideamans/go-sheetkv"
+	"google.golang.org/api/sheets/v4"
+)
+
+// AppendRecords adds rows after the last row of the sheet without rewriting
+// it. The header is created from schema when the sheet is empty, and columns
+// missing from an existing header are added to its end.
+func (a *SheetsAdaptor) AppendRecords(ctx context.Context, records []*sheetkv.Record, schema []string) error {
+	headerRange := a.rowsRange(a.header(), a.lastHeader())
+	a.requests.read()
+	resp, err := a.service.Spreadsheets.Values.Get(a.spreadsheetID, headerRange).Context(ctx).Do()
+	if err != nil {
+		return apiError("get header", err)
+	}
+
+	_, header := a.parseHeader(resp.Values)
+
+	// Extend the header with new columns
+	extended := header
+	for _, col := range schema {
+		if !containsColumn(extended, col) {
+			extended = append(extended, col)
+		}
+	}
+	for _, record := range records {
+		for col := range record.Values {
+			if !containsColumn(extended, col) {
+				extended = append(extended, col)
+			}
+		}
+	}
+
+	if len(extended) != len(header) {
+		a.requests.write()
+		_, err := a.service.Spreadsheets.Values.Update(a.spreadsheetID, a.cell(a.header()),
+			&sheets.ValueRange{Values: a.headerValues(extended)}).
+			ValueInputOption("RAW").
+			Context(ctx).
+			Do()
+		if err != nil {
+			return apiError("update header", err)
+		}
+	}
+
+	values := make([][]interface{}, 0, len(records))
+	for _, record := range records {
+		row := make([]interface{}, len(extended))
+		for i, col := range extended {
+			row[i] = a.sheetValue(col, record.Values[col])
+		}
+		values = append(values, row)
+	}
+
+	a.requests.write()
+	_, err = a.service.Spreadsheets.Values.Append(a.spreadsheetID, a.cell(a.header()),
+		&sheets.ValueRange{Values: values}).
+		ValueInputOption("RAW").
+		InsertDataOption("INSERT_ROWS").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return apiError("append rows", err)
+	}
+
+	return nil
+}
+
+// containsColumn reports whether schema contains col
+func containsColumn(schema []string, col string) bool {
+	for _, c := range schema {
+		if c == col {
+			return true
+		}
+	}
+	return false
+}
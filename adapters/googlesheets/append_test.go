@@ -0,0 +1,96 @@
+package googlesheets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestSheetsAdaptor_AppendRecords(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		header     [][]interface{}
+		wantHeader []interface{} // nil when the header must not be written
+		wantRow    []interface{}
+	}{
+		{
+			name:       "Empty sheet gets a header",
+			header:     nil,
+			wantHeader: []interface{}{"time", "op", "key"},
+			wantRow:    []interface{}{"t1", "add", float64(2)},
+		},
+		{
+			name:       "Existing header order is kept",
+			header:     [][]interface{}{{"key", "op", "time"}},
+			wantHeader: nil,
+			wantRow:    []interface{}{float64(2), "add", "t1"},
+		},
+		{
+			name:       "Missing columns are added",
+			header:     [][]interface{}{{"key", "op"}},
+			wantHeader: []interface{}{"key", "op", "time"},
+			wantRow:    []interface{}{float64(2), "add", "t1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotHeader []interface{}
+			var gotRows [][]interface{}
+			var insertOption string
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/v4/spreadsheets/test-id/values/audit!A1:ZZ1":
+					json.NewEncoder(w).Encode(map[string]interface{}{"values": tt.header})
+				case "/v4/spreadsheets/test-id/values/audit!A1":
+					var req sheets.ValueRange
+					json.NewDecoder(r.Body).Decode(&req)
+					gotHeader = req.Values[0]
+					json.NewEncoder(w).Encode(map[string]interface{}{})
+				case "/v4/spreadsheets/test-id/values/audit!A1:append":
+					var req sheets.ValueRange
+					json.NewDecoder(r.Body).Decode(&req)
+					gotRows = req.Values
+					insertOption = r.URL.Query().Get("insertDataOption")
+					json.NewEncoder(w).Encode(map[string]interface{}{})
+				default:
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			adaptor, err := NewSheetsAdaptor(ctx, Config{SpreadsheetID: "test-id", SheetName: "audit"},
+				option.WithEndpoint(server.URL), option.WithoutAuthentication())
+			if err != nil {
+				t.Fatalf("NewSheetsAdaptor() error = %v", err)
+			}
+
+			records := []*sheetkv.Record{{Values: map[string]interface{}{"time": "t1", "op": "add", "key": 2}}}
+			if err := adaptor.AppendRecords(ctx, records, []string{"time", "op", "key"}); err != nil {
+				t.Fatalf("AppendRecords() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(gotHeader, tt.wantHeader) {
+				t.Errorf("header = %v, want %v", gotHeader, tt.wantHeader)
+			}
+			if len(gotRows) != 1 || !reflect.DeepEqual(gotRows[0], tt.wantRow) {
+				t.Errorf("rows = %v, want [%v]", gotRows, tt.wantRow)
+			}
+			if insertOption != "INSERT_ROWS" {
+				t.Errorf("insertDataOption = %q, want INSERT_ROWS", insertOption)
+			}
+		})
+	}
+}
@@ -44,7 +44,7 @@ func NewWithJSONKeyFile(ctx context.Context, config Config, jsonPath string) (*S
 	}
 
 	// Parse credentials
-	creds, err := google.CredentialsFromJSON(ctx, jsonData, sheets.SpreadsheetsScope)
+	creds, err := google.CredentialsFromJSON(ctx, jsonData, config.scopes()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse credentials: %w", err)
 	}
@@ -55,7 +55,7 @@ func NewWithJSONKeyFile(ctx context.Context, config Config, jsonPath string) (*S
 // NewWithJSONKeyData creates a new SheetsAdaptor using JSON key data
 func NewWithJSONKeyData(ctx context.Context, config Config, jsonData []byte) (*SheetsAdaptor, error) {
 	// Parse credentials
-	creds, err := google.CredentialsFromJSON(ctx, jsonData, sheets.SpreadsheetsScope)
+	creds, err := google.CredentialsFromJSON(ctx, jsonData, config.scopes()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse credentials: %w", err)
 	}
@@ -69,7 +69,7 @@ func NewWithServiceAccountKey(ctx context.Context, config Config, email string,
 	jwtConfig := &jwt.Config{
 		Email:      email,
 		PrivateKey: []byte(privateKey),
-		Scopes:     []string{sheets.SpreadsheetsScope},
+		Scopes:     config.scopes(),
 		TokenURL:   google.JWTTokenURL,
 	}
 
@@ -86,7 +86,7 @@ func NewWithDefaultCredentials(ctx context.Context, config Config) (*SheetsAdapt
 	// 2. gcloud auth application-default credentials if available
 	// 3. GCE metadata service if running on Google Cloud
 
-	tokenSource, err := google.DefaultTokenSource(ctx, sheets.SpreadsheetsScope)
+	tokenSource, err := google.DefaultTokenSource(ctx, config.scopes()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get default token source: %w", err)
 	}
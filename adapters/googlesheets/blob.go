@@ -0,0 +1,78 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+// DriveBlobStore implements sheetkv.BlobStore with files in a Drive folder.
+// Cells hold the web view link of the files. The credentials need the
+// drive.DriveFileScope scope.
+//
+//	blobs, err := googlesheets.NewDriveBlobStore(ctx, "folder-id",
+//		option.WithCredentialsFile("key.json"), option.WithScopes(drive.DriveFileScope))
+//	config.BlobStore = blobs
+type DriveBlobStore struct {
+	drive    *drive.Service
+	folderID string
+}
+
+// NewDriveBlobStore creates a store uploading to the folder folderID, or to
+// the root of the account's Drive when empty
+func NewDriveBlobStore(ctx context.Context, folderID string, opts ...option.ClientOption) (*DriveBlobStore, error) {
+	driveService, err := drive.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create drive service: %w", err)
+	}
+	return &DriveBlobStore{drive: driveService, folderID: folderID}, nil
+}
+
+// Put uploads content as a new file named name and returns its link
+func (s *DriveBlobStore) Put(ctx context.Context, name string, content io.Reader) (string, error) {
+	file := &drive.File{Name: name}
+	if s.folderID != "" {
+		file.Parents = []string{s.folderID}
+	}
+
+	created, err := s.drive.Files.Create(file).Media(content).Fields("id", "webViewLink").SupportsAllDrives(true).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to upload file: %w", err)
+	}
+	if created.WebViewLink == "" {
+		return "https://drive.google.com/file/d/" + created.Id + "/view", nil
+	}
+	return created.WebViewLink, nil
+}
+
+// Open downloads the file behind link
+func (s *DriveBlobStore) Open(ctx context.Context, link string) (io.ReadCloser, error) {
+	id := fileID(link)
+	if id == "" {
+		return nil, fmt.Errorf("not a Drive file link: %s", link)
+	}
+
+	resp, err := s.drive.Files.Get(id).SupportsAllDrives(true).Context(ctx).Download()
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	return resp.Body, nil
+}
+
+// fileID extracts the file ID from a link like
+// https://drive.google.com/file/d/<id>/view, or returns a bare ID as is
+func fileID(link string) string {
+	if !strings.Contains(link, "/") {
+		return link
+	}
+	_, rest, found := strings.Cut(link, "/d/")
+	if !found {
+		return ""
+	}
+	id, _, _ := strings.Cut(rest, "/")
+	return id
+}
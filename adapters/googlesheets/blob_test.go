@@ -0,0 +1,82 @@
+package googlesheets
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/option"
+)
+
+func TestDriveBlobStore(t *testing.T) {
+	ctx := context.Background()
+
+	var uploaded string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/files"):
+			body, _ := io.ReadAll(r.Body)
+			uploaded = string(body)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id":          "file-1",
+				"webViewLink": "https://drive.google.com/file/d/file-1/view?usp=drivesdk",
+			})
+		case r.URL.Path == "/files/file-1" && r.URL.Query().Get("alt") == "media":
+			w.Write([]byte("report body"))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	store, err := NewDriveBlobStore(ctx, "folder-1", option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewDriveBlobStore() error = %v", err)
+	}
+
+	link, err := store.Put(ctx, "report.txt", strings.NewReader("report body"))
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if link != "https://drive.google.com/file/d/file-1/view?usp=drivesdk" {
+		t.Errorf("Put() = %q, want the web view link", link)
+	}
+	for _, want := range []string{"report body", `"name":"report.txt"`, `"parents":["folder-1"]`} {
+		if !strings.Contains(uploaded, want) {
+			t.Errorf("upload = %q, want it to contain %s", uploaded, want)
+		}
+	}
+
+	content, err := store.Open(ctx, link)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer content.Close()
+	if data, _ := io.ReadAll(content); string(data) != "report body" {
+		t.Errorf("Open() content = %q, want %q", data, "report body")
+	}
+
+	if _, err := store.Open(ctx, "https://example.com/report.txt"); err == nil {
+		t.Error("Open() of a non-Drive link should fail")
+	}
+}
+
+func TestFileID(t *testing.T) {
+	tests := map[string]string{
+		"https://drive.google.com/file/d/abc123/view?usp=drivesdk": "abc123",
+		"https://docs.google.com/document/d/doc-9/edit":            "doc-9",
+		"abc123":                "abc123",
+		"https://example.com/x": "",
+	}
+	for link, want := range tests {
+		if got := fileID(link); got != want {
+			t.Errorf("fileID(%q) = %q, want %q", link, got, want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package googlesheets
+
+import (
+	"context"
+)
+
+// CellLimit is the number of cells a Google spreadsheet can hold across all
+// of its tabs, for use as sheetkv.Config.CellLimit
+const CellLimit = 10000000
+
+// OtherCells returns the cells allocated to the other tabs of the
+// spreadsheet (rows × columns of each grid); it implements sheetkv.CellCounter
+func (a *SheetsAdaptor) OtherCells(ctx context.Context) (int, error) {
+	a.requests.read()
+	ss, err := a.service.Spreadsheets.Get(a.spreadsheetID).
+		Fields("sheets.properties(title,gridProperties(rowCount,columnCount))").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return 0, apiError("get spreadsheet", err)
+	}
+
+	cells := 0
+	for _, sheet := range ss.Sheets {
+		props := sheet.Properties
+		if props == nil || props.Title == a.sheetName || props.GridProperties == nil {
+			continue
+		}
+		cells += int(props.GridProperties.RowCount * props.GridProperties.ColumnCount)
+	}
+	return cells, nil
+}
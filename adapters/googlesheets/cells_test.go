@@ -0,0 +1,47 @@
+package googlesheets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/api/option"
+)
+
+func TestSheetsAdaptor_OtherCells(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/v4/spreadsheets/test-id" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		grid := func(title string, rows, cols int) map[string]interface{} {
+			return map[string]interface{}{"properties": map[string]interface{}{
+				"title":          title,
+				"gridProperties": map[string]interface{}{"rowCount": rows, "columnCount": cols},
+			}}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"sheets": []map[string]interface{}{
+			grid("Users", 1000, 26),
+			grid("Orders", 500, 10),
+			grid("Archive", 100, 4),
+		}})
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{SpreadsheetID: "test-id", SheetName: "Users"},
+		option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+
+	cells, err := adaptor.OtherCells(ctx)
+	if err != nil || cells != 5400 {
+		t.Errorf("OtherCells() = %d, %v, want 5400", cells, err)
+	}
+}
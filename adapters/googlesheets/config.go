@@ -1,15 +1,103 @@
 package googlesheets
 
 import (
+	"fmt"
 	"time"
 
 	sheetkv "github.com/ideamans/go-sheetkv"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/sheets/v4"
 )
 
 // Config represents configuration specific to Google Sheets adapter
 type Config struct {
-	SpreadsheetID string
-	SheetName     string
+	SpreadsheetID   string
+	SheetName       string
+	IndexSheetName  string                // Hidden sheet holding the persisted index (default: _<SheetName>_index)
+	LeaseSheetName  string                // Hidden sheet holding the lease of sheetkv.Config.LeaseDuration (default: _<SheetName>_lease)
+	DriveRevisions  bool                  // Request the Drive metadata scope used by Revision and Watch
+	HeaderRow       int                   // Row holding the column names (default: 1); rows above it are left untouched
+	StartColumn     string                // First managed column, e.g. "C" (default: A); columns before it are left untouched
+	MaxColumns      int                   // Number of managed columns from StartColumn (default: up to ZZ); columns after them are left untouched
+	HeaderRows      int                   // Number of header rows, e.g. 2 for a group row above the field names (default: 1)
+	HeaderSeparator string                // Joins grouped header levels into column names (default: sheetkv.DefaultHeaderSeparator)
+	FormatHeader    bool                  // Freeze and bold the header and auto-size the columns after each save
+	FormulaColumns  []string              // Columns computed by formulas: loaded as computed values, never overwritten on save
+	ColumnAliases   sheetkv.ColumnAliases // Sheet header -> column name used in code
+	QuotaPerMinute  int                   // Read and, separately, write requests allowed per minute, for Limits (default: DefaultQuotaPerMinute)
+	Whitespace      sheetkv.Whitespace    // Cleanup of the whitespace of loaded text, applied before numbers are parsed
+	BoolTokens      sheetkv.BoolTokens    // Additional texts read and written as booleans, e.g. "yes"/"no"
+	TypeInference   sheetkv.TypeInference // Columns loaded as text and conservative number parsing, so "00123" keeps its zeros
+	FloatFormats    sheetkv.FloatFormats  // Decimals and thousands separators of written floats per column (default: written as numbers)
+	ValueRender     ValueRender           // How cells are read (default: FormattedValues)
+	DateTimeRender  DateTimeRender        // How dates are read with UnformattedValues (default: SerialNumberDates)
+	TimeColumns     []string              // Columns whose serial date numbers are loaded as time.Time, and whose times are saved as serial numbers
+	WriteChunkRows  int                   // Rows written per request on save (default: all rows in one request); a failed save resumes after its last written chunk
+}
+
+// ValueRender selects how the Sheets API renders the cells it returns
+type ValueRender string
+
+const (
+	// FormattedValues returns the displayed text, e.g. "¥1,200" or "15%"
+	FormattedValues ValueRender = "FORMATTED_VALUE"
+	// UnformattedValues returns numbers as numbers, whatever their format
+	UnformattedValues ValueRender = "UNFORMATTED_VALUE"
+	// FormulaValues returns the formulas instead of their results
+	FormulaValues ValueRender = "FORMULA"
+)
+
+// DateTimeRender selects how the Sheets API renders dates and times for
+// UnformattedValues and FormulaValues
+type DateTimeRender string
+
+const (
+	// SerialNumberDates returns dates as serial numbers, days since 1899-12-30
+	SerialNumberDates DateTimeRender = "SERIAL_NUMBER"
+	// FormattedDates returns dates as their displayed text
+	FormattedDates DateTimeRender = "FORMATTED_STRING"
+)
+
+// Validate checks if the configuration is valid
+func (c Config) Validate() error {
+	if c.SpreadsheetID == "" {
+		return ErrMissingSpreadsheetID
+	}
+	if c.SheetName == "" {
+		return ErrMissingSheetName
+	}
+	if c.HeaderRow < 0 {
+		return ErrInvalidHeaderRow
+	}
+	if c.HeaderRows < 0 {
+		return ErrInvalidHeaderRows
+	}
+	if _, err := c.startColumn(); err != nil {
+		return err
+	}
+	if c.WriteChunkRows < 0 {
+		return ErrInvalidWriteChunkRows
+	}
+	switch c.ValueRender {
+	case "", FormattedValues, UnformattedValues, FormulaValues:
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidValueRender, c.ValueRender)
+	}
+	switch c.DateTimeRender {
+	case "", SerialNumberDates, FormattedDates:
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidValueRender, c.DateTimeRender)
+	}
+	return nil
+}
+
+// scopes returns the OAuth scopes required by the configuration
+func (c Config) scopes() []string {
+	scopes := []string{sheets.SpreadsheetsScope}
+	if c.DriveRevisions {
+		scopes = append(scopes, drive.DriveMetadataReadonlyScope)
+	}
+	return scopes
 }
 
 // DefaultClientConfig returns the recommended default configuration for Google Sheets
@@ -0,0 +1,142 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+	"google.golang.org/api/drive/v3"
+)
+
+const (
+	// DefaultDriveLockTTL is how long a DriveLock stays valid when its
+	// holder stops without unlocking
+	DefaultDriveLockTTL = time.Minute
+
+	// DefaultDriveLockPoll is how often a DriveLock retries a lock held by
+	// another process
+	DefaultDriveLockPoll = time.Second
+)
+
+// App properties of the spreadsheet file holding the lock
+const (
+	lockOwnerProperty   = "sheetkvLockOwner"
+	lockExpiresProperty = "sheetkvLockExpires"
+)
+
+// DriveLock is a sheetkv.Locker keeping its owner and expiry in the app
+// properties of the spreadsheet's Drive file, so processes writing the
+// spreadsheet take turns saving it. Lock waits while another process holds
+// an unexpired lock. Requires credentials with the drive scope.
+type DriveLock struct {
+	drive  *drive.Service
+	fileID string
+	owner  string
+	ttl    time.Duration
+	mu     sync.Mutex
+}
+
+// DriveLock returns a lock on the spreadsheet file for sheetkv.Config.Locker.
+// owner must identify the process, for example by host name and process ID;
+// ttl bounds how long a crashed holder blocks the others (default:
+// DefaultDriveLockTTL).
+func (a *SheetsAdaptor) DriveLock(owner string, ttl time.Duration) *DriveLock {
+	if ttl <= 0 {
+		ttl = DefaultDriveLockTTL
+	}
+	return &DriveLock{drive: a.drive, fileID: a.spreadsheetID, owner: owner, ttl: ttl}
+}
+
+// Lock takes the lock, waiting while another process holds it until ctx is
+// done
+func (l *DriveLock) Lock(ctx context.Context) error {
+	if l.drive == nil {
+		return fmt.Errorf("drive service is not configured")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for {
+		owner, expires, err := l.read(ctx)
+		if err != nil {
+			return err
+		}
+
+		if owner == "" || owner == l.owner || !expires.After(time.Now()) {
+			if err := l.write(ctx, l.owner, time.Now().Add(l.ttl)); err != nil {
+				return err
+			}
+			// A process taking the lock at the same time may have
+			// overwritten ours, so read it back
+			owner, expires, err = l.read(ctx)
+			if err != nil {
+				return err
+			}
+			if owner == l.owner {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %s until %s: %w", sheetkv.ErrLocked, owner, expires.Format(time.RFC3339), ctx.Err())
+		case <-time.After(DefaultDriveLockPoll):
+		}
+	}
+}
+
+// Unlock releases the lock when this process still holds it
+func (l *DriveLock) Unlock(ctx context.Context) error {
+	if l.drive == nil {
+		return fmt.Errorf("drive service is not configured")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	owner, _, err := l.read(ctx)
+	if err != nil || owner != l.owner {
+		return err
+	}
+	return l.write(ctx, "", time.Time{})
+}
+
+// read returns the owner and expiry of the lock, an empty owner when free
+func (l *DriveLock) read(ctx context.Context) (string, time.Time, error) {
+	file, err := l.drive.Files.Get(l.fileID).Fields("appProperties").SupportsAllDrives(true).Context(ctx).Do()
+	if err != nil {
+		return "", time.Time{}, apiError("get lock", err)
+	}
+
+	owner := file.AppProperties[lockOwnerProperty]
+	if owner == "" {
+		return "", time.Time{}, nil
+	}
+	expires, err := time.Parse(time.RFC3339Nano, file.AppProperties[lockExpiresProperty])
+	if err != nil {
+		// A malformed lock cannot be renewed by its owner, so treat it as expired
+		return owner, time.Time{}, nil
+	}
+	return owner, expires, nil
+}
+
+// write replaces the owner and expiry of the lock; an empty owner frees it
+func (l *DriveLock) write(ctx context.Context, owner string, expires time.Time) error {
+	properties := map[string]string{lockOwnerProperty: owner, lockExpiresProperty: ""}
+	if owner != "" {
+		properties[lockExpiresProperty] = expires.UTC().Format(time.RFC3339Nano)
+	}
+
+	_, err := l.drive.Files.Update(l.fileID, &drive.File{AppProperties: properties}).
+		Fields("id").
+		SupportsAllDrives(true).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return apiError("update lock", err)
+	}
+	return nil
+}
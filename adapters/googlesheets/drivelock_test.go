@@ -0,0 +1,89 @@
+package googlesheets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+func TestDriveLock(t *testing.T) {
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	properties := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/files/test-id" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodPatch {
+			var file drive.File
+			json.NewDecoder(r.Body).Decode(&file)
+			for k, v := range file.AppProperties {
+				properties[k] = v
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": "test-id", "appProperties": properties})
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{SpreadsheetID: "test-id", SheetName: "Users"},
+		option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+	first, second := adaptor.DriveLock("first", time.Minute), adaptor.DriveLock("second", time.Minute)
+
+	if err := first.Lock(ctx); err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	if properties[lockOwnerProperty] != "first" {
+		t.Errorf("app properties = %v, want the lock of first", properties)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := second.Lock(waitCtx); !errors.Is(err, sheetkv.ErrLocked) {
+		t.Fatalf("Lock() error = %v, want ErrLocked", err)
+	}
+
+	// Unlocking by another owner leaves the lock in place
+	if err := second.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock() error = %v", err)
+	}
+	if properties[lockOwnerProperty] != "first" {
+		t.Errorf("app properties = %v, want the lock of first kept", properties)
+	}
+
+	if err := first.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock() error = %v", err)
+	}
+	if err := second.Lock(ctx); err != nil {
+		t.Fatalf("Lock() after Unlock error = %v", err)
+	}
+
+	// An expired lock is taken over
+	mu.Lock()
+	properties[lockExpiresProperty] = time.Now().Add(-time.Second).Format(time.RFC3339Nano)
+	mu.Unlock()
+	if err := first.Lock(ctx); err != nil {
+		t.Fatalf("Lock() of an expired lock error = %v", err)
+	}
+	if properties[lockOwnerProperty] != "first" {
+		t.Errorf("app properties = %v, want the lock taken over", properties)
+	}
+}
@@ -0,0 +1,101 @@
+package googlesheets
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ideamans/go-sheetkv"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/googleapi"
+)
+
+var (
+	// ErrMissingSpreadsheetID is returned when the spreadsheet ID is not specified
+	ErrMissingSpreadsheetID = errors.New("spreadsheet ID is required")
+
+	// ErrMissingSheetName is returned when the sheet name is not specified
+	ErrMissingSheetName = errors.New("sheet name is required")
+
+	// ErrInvalidHeaderRow is returned when the header row is negative
+	ErrInvalidHeaderRow = errors.New("header row must be positive")
+
+	// ErrInvalidHeaderRows is returned when the number of header rows is negative
+	ErrInvalidHeaderRows = errors.New("header rows must not be negative")
+
+	// ErrInvalidStartColumn is returned when the start column is not a column name
+	ErrInvalidStartColumn = errors.New("invalid start column")
+
+	// ErrInvalidMaxColumns is returned when the number of managed columns is negative
+	ErrInvalidMaxColumns = errors.New("max columns must not be negative")
+
+	// ErrInvalidWriteChunkRows is returned when the rows per write request are negative
+	ErrInvalidWriteChunkRows = errors.New("write chunk rows must not be negative")
+
+	// ErrInvalidValueRender is returned for an unknown ValueRender or DateTimeRender
+	ErrInvalidValueRender = errors.New("invalid value render option")
+
+	// ErrUnauthenticated is returned when the credentials are rejected. It
+	// wraps sheetkv.ErrUnauthorized.
+	ErrUnauthenticated = fmt.Errorf("credentials rejected: %w", sheetkv.ErrUnauthorized)
+
+	// ErrPermissionDenied is returned when the credentials may not open the
+	// spreadsheet, typically because it is not shared with them. It wraps
+	// sheetkv.ErrUnauthorized.
+	ErrPermissionDenied = fmt.Errorf("permission denied to the spreadsheet: %w", sheetkv.ErrUnauthorized)
+
+	// ErrSpreadsheetNotFound is returned when no spreadsheet has the ID. It
+	// wraps sheetkv.ErrSheetNotFound.
+	ErrSpreadsheetNotFound = fmt.Errorf("spreadsheet not found: %w", sheetkv.ErrSheetNotFound)
+
+	// ErrSheetNotFound is returned when the spreadsheet has no sheet with the
+	// name. It is sheetkv.ErrSheetNotFound.
+	ErrSheetNotFound = sheetkv.ErrSheetNotFound
+)
+
+// apiError wraps the error of an API call, adding the sentinel of its cause
+// when known: ErrUnauthenticated, ErrPermissionDenied, ErrSpreadsheetNotFound,
+// ErrSheetNotFound or sheetkv.ErrQuotaExceeded
+func apiError(action string, err error) error {
+	if cause := errorCause(err); cause != nil {
+		return fmt.Errorf("failed to %s: %w: %w", action, cause, err)
+	}
+	return fmt.Errorf("failed to %s: %w", action, err)
+}
+
+// errorCause returns the sentinel matching an API error, nil when unknown
+func errorCause(err error) error {
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) {
+		return ErrUnauthenticated
+	}
+
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return nil
+	}
+	switch apiErr.Code {
+	case http.StatusUnauthorized:
+		return ErrUnauthenticated
+	case http.StatusTooManyRequests:
+		return sheetkv.ErrQuotaExceeded
+	case http.StatusForbidden:
+		// Quota errors of older API versions are 403s with a rate reason
+		for _, item := range apiErr.Errors {
+			switch item.Reason {
+			case "rateLimitExceeded", "userRateLimitExceeded", "quotaExceeded":
+				return sheetkv.ErrQuotaExceeded
+			}
+		}
+		return ErrPermissionDenied
+	case http.StatusNotFound:
+		return ErrSpreadsheetNotFound
+	case http.StatusBadRequest:
+		// A range naming a missing sheet
+		if strings.Contains(apiErr.Message, "Unable to parse range") {
+			return ErrSheetNotFound
+		}
+	}
+	return nil
+}
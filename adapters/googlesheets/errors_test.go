@@ -0,0 +1,67 @@
+package googlesheets
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+)
+
+func TestErrorCause(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+		root error
+	}{
+		{"token refresh", &oauth2.RetrieveError{}, ErrUnauthenticated, sheetkv.ErrUnauthorized},
+		{"401", &googleapi.Error{Code: 401}, ErrUnauthenticated, sheetkv.ErrUnauthorized},
+		{"403", &googleapi.Error{Code: 403}, ErrPermissionDenied, sheetkv.ErrUnauthorized},
+		{"403 rate limit", &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}}}, sheetkv.ErrQuotaExceeded, sheetkv.ErrQuotaExceeded},
+		{"429", &googleapi.Error{Code: 429}, sheetkv.ErrQuotaExceeded, sheetkv.ErrQuotaExceeded},
+		{"404", &googleapi.Error{Code: 404}, ErrSpreadsheetNotFound, sheetkv.ErrSheetNotFound},
+		{"missing sheet", &googleapi.Error{Code: 400, Message: "Unable to parse range: Missing!A:ZZ"}, ErrSheetNotFound, sheetkv.ErrSheetNotFound},
+		{"bad request", &googleapi.Error{Code: 400, Message: "Invalid value"}, nil, nil},
+		{"other", errors.New("connection reset"), nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := apiError("get sheet data", tt.err)
+			if tt.want == nil {
+				if errorCause(tt.err) != nil {
+					t.Errorf("errorCause() = %v, want nil", errorCause(tt.err))
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) || !errors.Is(err, tt.root) || !errors.Is(err, tt.err) {
+				t.Errorf("apiError() = %v, want %v wrapping %v and the API error", err, tt.want, tt.root)
+			}
+		})
+	}
+}
+
+func TestSheetsAdaptor_LoadErrors(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"code":429,"message":"Quota exceeded for quota metric 'Read requests'","status":"RESOURCE_EXHAUSTED"}}`))
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{SpreadsheetID: "test-id", SheetName: "TestSheet"},
+		option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+	if _, _, err := adaptor.Load(ctx); !errors.Is(err, sheetkv.ErrQuotaExceeded) {
+		t.Errorf("Load() error = %v, want ErrQuotaExceeded", err)
+	}
+}
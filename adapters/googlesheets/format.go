@@ -0,0 +1,86 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// applyHeaderFormat freezes the header rows, makes them bold and fits the
+// managed columns to their content
+func (a *SheetsAdaptor) applyHeaderFormat(ctx context.Context, columns int) error {
+	if columns == 0 {
+		return nil
+	}
+
+	sheetID, err := a.sheetID(ctx)
+	if err != nil {
+		return err
+	}
+
+	first := int64(a.startColumn - 1)
+	if first < 0 {
+		first = 0
+	}
+
+	req := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			{
+				UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
+					Properties: &sheets.SheetProperties{
+						SheetId:        sheetID,
+						GridProperties: &sheets.GridProperties{FrozenRowCount: int64(a.lastHeader())},
+					},
+					Fields: "gridProperties.frozenRowCount",
+				},
+			},
+			{
+				RepeatCell: &sheets.RepeatCellRequest{
+					Range: &sheets.GridRange{
+						SheetId:          sheetID,
+						StartRowIndex:    int64(a.header() - 1),
+						EndRowIndex:      int64(a.lastHeader()),
+						StartColumnIndex: first,
+						EndColumnIndex:   first + int64(columns),
+					},
+					Cell: &sheets.CellData{
+						UserEnteredFormat: &sheets.CellFormat{TextFormat: &sheets.TextFormat{Bold: true}},
+					},
+					Fields: "userEnteredFormat.textFormat.bold",
+				},
+			},
+			{
+				AutoResizeDimensions: &sheets.AutoResizeDimensionsRequest{
+					Dimensions: &sheets.DimensionRange{
+						SheetId:    sheetID,
+						Dimension:  "COLUMNS",
+						StartIndex: first,
+						EndIndex:   first + int64(columns),
+					},
+				},
+			},
+		},
+	}
+	a.requests.write()
+	if _, err := a.service.Spreadsheets.BatchUpdate(a.spreadsheetID, req).Context(ctx).Do(); err != nil {
+		return apiError("format header", err)
+	}
+	return nil
+}
+
+// sheetID returns the numeric ID of the data sheet
+func (a *SheetsAdaptor) sheetID(ctx context.Context) (int64, error) {
+	a.requests.read()
+	ss, err := a.service.Spreadsheets.Get(a.spreadsheetID).Fields("sheets.properties(sheetId,title)").Context(ctx).Do()
+	if err != nil {
+		return 0, apiError("get spreadsheet", err)
+	}
+
+	for _, sheet := range ss.Sheets {
+		if sheet.Properties != nil && sheet.Properties.Title == a.sheetName {
+			return sheet.Properties.SheetId, nil
+		}
+	}
+	return 0, fmt.Errorf("sheet %s not found", a.sheetName)
+}
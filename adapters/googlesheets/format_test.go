@@ -0,0 +1,74 @@
+package googlesheets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestSheetsAdaptor_FormatHeader(t *testing.T) {
+	ctx := context.Background()
+
+	var batch *sheets.BatchUpdateSpreadsheetRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v4/spreadsheets/test-id/values/TestSheet!C3:ZZ:clear", "/v4/spreadsheets/test-id/values/TestSheet!C3":
+			w.Write([]byte(`{}`))
+		case "/v4/spreadsheets/test-id":
+			w.Write([]byte(`{"sheets": [{"properties": {"sheetId": 0, "title": "Other"}}, {"properties": {"sheetId": 42, "title": "TestSheet"}}]}`))
+		case "/v4/spreadsheets/test-id:batchUpdate":
+			batch = &sheets.BatchUpdateSpreadsheetRequest{}
+			json.NewDecoder(r.Body).Decode(batch)
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{
+		SpreadsheetID: "test-id",
+		SheetName:     "TestSheet",
+		HeaderRow:     3,
+		StartColumn:   "C",
+		FormatHeader:  true,
+	}, option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+
+	records := []*sheetkv.Record{{Key: 2, Values: map[string]interface{}{"name": "John", "age": 30}}}
+	if err := adaptor.Save(ctx, records, []string{"name", "age"}, sheetkv.SyncStrategyCompacting); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if batch == nil || len(batch.Requests) != 3 {
+		t.Fatalf("batchUpdate = %+v, want freeze, bold and resize requests", batch)
+	}
+
+	props := batch.Requests[0].UpdateSheetProperties.Properties
+	if props.SheetId != 42 || props.GridProperties.FrozenRowCount != 3 {
+		t.Errorf("freeze = sheet %d, %d rows, want sheet 42, 3 rows", props.SheetId, props.GridProperties.FrozenRowCount)
+	}
+
+	bold := batch.Requests[1].RepeatCell
+	if r := bold.Range; r.StartRowIndex != 2 || r.EndRowIndex != 3 || r.StartColumnIndex != 2 || r.EndColumnIndex != 4 {
+		t.Errorf("bold range = %+v, want row 3, columns C:D", r)
+	}
+	if !bold.Cell.UserEnteredFormat.TextFormat.Bold {
+		t.Error("header should be bold")
+	}
+
+	resize := batch.Requests[2].AutoResizeDimensions.Dimensions
+	if resize.Dimension != "COLUMNS" || resize.StartIndex != 2 || resize.EndIndex != 4 {
+		t.Errorf("resize = %+v, want columns C:D", resize)
+	}
+}
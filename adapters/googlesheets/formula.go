@@ -0,0 +1,59 @@
+package googlesheets
+
+import "fmt"
+
+// columnSet returns the set of the given columns, nil when empty
+func columnSet(columns []string) map[string]bool {
+	if len(columns) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		set[col] = true
+	}
+	return set
+}
+
+// skipFormulas replaces the cells of formula columns with nil, which the
+// Sheets API leaves untouched on update
+func skipFormulas(rows [][]interface{}, schema []string, formulas map[string]bool) {
+	for _, row := range rows {
+		for i, col := range schema {
+			if i < len(row) && formulas[col] {
+				row[i] = nil
+			}
+		}
+	}
+}
+
+// clearRanges returns the managed ranges to clear before a save: every run
+// of columns between formula columns, from the header row down
+func (a *SheetsAdaptor) clearRanges(schema []string) []string {
+	first := a.startColumn
+	if first <= 0 {
+		first = 1
+	}
+	last := columnNumber("ZZ")
+	if a.maxColumns > 0 {
+		last = first + a.maxColumns - 1
+	}
+
+	var ranges []string
+	start := first
+	for i := 0; i <= len(schema); i++ {
+		col := first + i
+		if i < len(schema) && !a.formulas[schema[i]] {
+			continue
+		}
+		// col is a formula column or the end of the schema
+		end := col - 1
+		if i == len(schema) {
+			end = last
+		}
+		if start <= end {
+			ranges = append(ranges, fmt.Sprintf("%s!%s%d:%s", a.sheetName, columnName(start), a.header(), columnName(end)))
+		}
+		start = col + 1
+	}
+	return ranges
+}
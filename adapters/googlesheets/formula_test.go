@@ -0,0 +1,68 @@
+package googlesheets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestSheetsAdaptor_FormulaColumns(t *testing.T) {
+	ctx := context.Background()
+
+	var cleared []string
+	var written [][]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v4/spreadsheets/test-id/values:batchClear":
+			var req sheets.BatchClearValuesRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			cleared = req.Ranges
+			w.Write([]byte(`{}`))
+		case "/v4/spreadsheets/test-id/values/TestSheet!A1":
+			var req sheets.ValueRange
+			json.NewDecoder(r.Body).Decode(&req)
+			written = req.Values
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{SpreadsheetID: "test-id", SheetName: "TestSheet", FormulaColumns: []string{"total"}},
+		option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+
+	records := []*sheetkv.Record{
+		{Key: 2, Values: map[string]interface{}{"price": 10, "total": 20, "note": "a"}},
+		{Key: 4, Values: map[string]interface{}{"price": 30, "total": 60, "note": "b"}},
+	}
+	if err := adaptor.Save(ctx, records, []string{"price", "total", "note"}, sheetkv.SyncStrategyGapPreserving); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if want := []string{"TestSheet!A1:A", "TestSheet!C1:ZZ"}; !reflect.DeepEqual(cleared, want) {
+		t.Errorf("cleared = %v, want %v", cleared, want)
+	}
+
+	want := [][]interface{}{
+		{"price", "total", "note"},
+		{float64(10), nil, "a"},
+		{"", nil, ""},
+		{float64(30), nil, "b"},
+	}
+	if !reflect.DeepEqual(written, want) {
+		t.Errorf("written = %v, want %v", written, want)
+	}
+}
@@ -0,0 +1,178 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ideamans/go-sheetkv"
+	"google.golang.org/api/sheets/v4"
+)
+
+// indexHeader is the header row of the index sheet
+var indexHeader = []interface{}{"column", "value", "keys"}
+
+// SaveIndex writes the secondary index to the hidden index sheet,
+// creating the sheet on first use
+func (a *SheetsAdaptor) SaveIndex(ctx context.Context, index *sheetkv.Index) error {
+	if err := a.ensureSheet(ctx, a.indexSheet(), true); err != nil {
+		return err
+	}
+
+	values := [][]interface{}{indexHeader}
+	for _, entry := range index.Entries {
+		keys := make([]string, len(entry.Keys))
+		for i, key := range entry.Keys {
+			keys[i] = strconv.Itoa(key)
+		}
+		values = append(values, []interface{}{entry.Column, entry.Value, strings.Join(keys, ",")})
+	}
+
+	clearRange := fmt.Sprintf("%s!A:C", a.indexSheet())
+	a.requests.write()
+	_, err := a.service.Spreadsheets.Values.Clear(a.spreadsheetID, clearRange, &sheets.ClearValuesRequest{}).Context(ctx).Do()
+	if err != nil {
+		return apiError("clear index sheet", err)
+	}
+
+	writeRange := fmt.Sprintf("%s!A1", a.indexSheet())
+	a.requests.write()
+	_, err = a.service.Spreadsheets.Values.Update(a.spreadsheetID, writeRange, &sheets.ValueRange{Values: values}).
+		ValueInputOption("RAW").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return apiError("update index sheet", err)
+	}
+
+	return nil
+}
+
+// LoadIndex reads the secondary index from the hidden index sheet.
+// It returns nil when the index sheet does not exist yet.
+func (a *SheetsAdaptor) LoadIndex(ctx context.Context) (*sheetkv.Index, error) {
+	exists, err := a.sheetExists(ctx, a.indexSheet())
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	readRange := fmt.Sprintf("%s!A:C", a.indexSheet())
+	a.requests.read()
+	resp, err := a.service.Spreadsheets.Values.Get(a.spreadsheetID, readRange).Context(ctx).Do()
+	if err != nil {
+		return nil, apiError("get index data", err)
+	}
+
+	index := &sheetkv.Index{}
+	seen := make(map[string]bool)
+	for i, row := range resp.Values {
+		if i == 0 || len(row) < 3 {
+			continue // Header or malformed row
+		}
+
+		entry := sheetkv.IndexEntry{
+			Column: fmt.Sprintf("%v", row[0]),
+			Value:  fmt.Sprintf("%v", row[1]),
+		}
+		for _, part := range strings.Split(fmt.Sprintf("%v", row[2]), ",") {
+			if key, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+				entry.Keys = append(entry.Keys, key)
+			}
+		}
+
+		if !seen[entry.Column] {
+			seen[entry.Column] = true
+			index.Columns = append(index.Columns, entry.Column)
+		}
+		index.Entries = append(index.Entries, entry)
+	}
+
+	return index, nil
+}
+
+// LoadRows retrieves only the rows stored at the given keys
+func (a *SheetsAdaptor) LoadRows(ctx context.Context, keys []int) ([]*sheetkv.Record, []string, error) {
+	ranges := []string{a.rowsRange(a.header(), a.lastHeader())}
+	for _, key := range keys {
+		ranges = append(ranges, a.rowsRange(a.rowOf(key), a.rowOf(key)))
+	}
+
+	a.requests.read()
+	call := a.service.Spreadsheets.Values.BatchGet(a.spreadsheetID).Ranges(ranges...)
+	if a.valueRender != "" {
+		call = call.ValueRenderOption(string(a.valueRender))
+	}
+	if a.dateTimeRender != "" {
+		call = call.DateTimeRenderOption(string(a.dateTimeRender))
+	}
+	resp, err := call.Context(ctx).Do()
+	if err != nil {
+		return nil, nil, apiError("get rows", err)
+	}
+
+	if len(resp.ValueRanges) == 0 || len(resp.ValueRanges[0].Values) == 0 {
+		return []*sheetkv.Record{}, []string{}, nil
+	}
+	columns, schema := a.parseHeader(resp.ValueRanges[0].Values)
+
+	records := make([]*sheetkv.Record, 0, len(keys))
+	for i, vr := range resp.ValueRanges[1:] {
+		if i >= len(keys) || len(vr.Values) == 0 || len(vr.Values[0]) == 0 {
+			continue
+		}
+		records = append(records, a.parseRecord(keys[i], vr.Values[0], columns))
+	}
+
+	return records, schema, nil
+}
+
+// indexSheet returns the name of the hidden index sheet
+func (a *SheetsAdaptor) indexSheet() string {
+	if a.indexSheetName != "" {
+		return a.indexSheetName
+	}
+	return "_" + a.sheetName + "_index"
+}
+
+// sheetExists reports whether the spreadsheet contains a sheet with the given title
+func (a *SheetsAdaptor) sheetExists(ctx context.Context, title string) (bool, error) {
+	a.requests.read()
+	ss, err := a.service.Spreadsheets.Get(a.spreadsheetID).Fields("sheets.properties.title").Context(ctx).Do()
+	if err != nil {
+		return false, apiError("get spreadsheet", err)
+	}
+
+	for _, sheet := range ss.Sheets {
+		if sheet.Properties != nil && sheet.Properties.Title == title {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ensureSheet creates the sheet with the given title when it does not exist
+func (a *SheetsAdaptor) ensureSheet(ctx context.Context, title string, hidden bool) error {
+	exists, err := a.sheetExists(ctx, title)
+	if err != nil || exists {
+		return err
+	}
+
+	req := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			{
+				AddSheet: &sheets.AddSheetRequest{
+					Properties: &sheets.SheetProperties{Title: title, Hidden: hidden},
+				},
+			},
+		},
+	}
+	a.requests.write()
+	if _, err := a.service.Spreadsheets.BatchUpdate(a.spreadsheetID, req).Context(ctx).Do(); err != nil {
+		return apiError("create sheet "+title, err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package googlesheets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestSheetsAdaptor_Index(t *testing.T) {
+	ctx := context.Background()
+
+	var savedIndex [][]interface{}
+	var addedSheet *sheets.SheetProperties
+	indexExists := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v4/spreadsheets/test-id":
+			titles := []map[string]interface{}{{"properties": map[string]interface{}{"title": "Users"}}}
+			if indexExists {
+				titles = append(titles, map[string]interface{}{"properties": map[string]interface{}{"title": "_Users_index"}})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"sheets": titles})
+		case "/v4/spreadsheets/test-id:batchUpdate":
+			var req sheets.BatchUpdateSpreadsheetRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			addedSheet = req.Requests[0].AddSheet.Properties
+			indexExists = true
+			json.NewEncoder(w).Encode(map[string]interface{}{})
+		case "/v4/spreadsheets/test-id/values/_Users_index!A:C:clear":
+			json.NewEncoder(w).Encode(map[string]interface{}{})
+		case "/v4/spreadsheets/test-id/values/_Users_index!A1":
+			var req sheets.ValueRange
+			json.NewDecoder(r.Body).Decode(&req)
+			savedIndex = req.Values
+			json.NewEncoder(w).Encode(map[string]interface{}{})
+		case "/v4/spreadsheets/test-id/values/_Users_index!A:C":
+			json.NewEncoder(w).Encode(map[string]interface{}{"values": savedIndex})
+		case "/v4/spreadsheets/test-id/values:batchGet":
+			ranges := r.URL.Query()["ranges"]
+			want := []string{"Users!A1:ZZ1", "Users!A3:ZZ3", "Users!A5:ZZ5"}
+			if !reflect.DeepEqual(ranges, want) {
+				t.Errorf("batchGet ranges = %v, want %v", ranges, want)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"valueRanges": []map[string]interface{}{
+					{"values": [][]interface{}{{"email", "dept"}}},
+					{"values": [][]interface{}{{"b@example.com", "Sales"}}},
+					{"values": [][]interface{}{{"d@example.com", "Sales"}}},
+				},
+			})
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{SpreadsheetID: "test-id", SheetName: "Users"},
+		option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("Failed to create adaptor: %v", err)
+	}
+
+	t.Run("LoadIndex before any save", func(t *testing.T) {
+		index, err := adaptor.LoadIndex(ctx)
+		if err != nil || index != nil {
+			t.Errorf("LoadIndex() = %v, %v, want nil, nil", index, err)
+		}
+	})
+
+	index := &sheetkv.Index{
+		Columns: []string{"dept"},
+		Entries: []sheetkv.IndexEntry{
+			{Column: "dept", Value: "Eng", Keys: []int{2}},
+			{Column: "dept", Value: "Sales", Keys: []int{3, 5}},
+		},
+	}
+
+	t.Run("SaveIndex creates a hidden sheet", func(t *testing.T) {
+		if err := adaptor.SaveIndex(ctx, index); err != nil {
+			t.Fatalf("SaveIndex() error = %v", err)
+		}
+		if addedSheet == nil || addedSheet.Title != "_Users_index" || !addedSheet.Hidden {
+			t.Errorf("added sheet = %+v, want hidden _Users_index", addedSheet)
+		}
+		want := [][]interface{}{{"column", "value", "keys"}, {"dept", "Eng", "2"}, {"dept", "Sales", "3,5"}}
+		if !reflect.DeepEqual(savedIndex, want) {
+			t.Errorf("saved index = %v, want %v", savedIndex, want)
+		}
+	})
+
+	t.Run("LoadIndex round trip", func(t *testing.T) {
+		got, err := adaptor.LoadIndex(ctx)
+		if err != nil {
+			t.Fatalf("LoadIndex() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, index) {
+			t.Errorf("LoadIndex() = %+v, want %+v", got, index)
+		}
+	})
+
+	t.Run("LoadRows", func(t *testing.T) {
+		records, schema, err := adaptor.LoadRows(ctx, []int{3, 5})
+		if err != nil {
+			t.Fatalf("LoadRows() error = %v", err)
+		}
+		if !reflect.DeepEqual(schema, []string{"email", "dept"}) {
+			t.Errorf("LoadRows() schema = %v", schema)
+		}
+		if len(records) != 2 || records[0].Key != 3 || records[1].Values["email"] != "d@example.com" {
+			t.Errorf("LoadRows() = %v", records)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+	"google.golang.org/api/sheets/v4"
+)
+
+// leaseHeader is the header row of the lease sheet
+var leaseHeader = []interface{}{"owner", "expires"}
+
+// ReadLease reads the lease from the hidden lease sheet. It returns nil
+// when the sheet does not exist yet or the lease was released.
+func (a *SheetsAdaptor) ReadLease(ctx context.Context) (*sheetkv.Lease, error) {
+	readRange := fmt.Sprintf("%s!A2:B2", a.leaseSheet())
+	a.requests.read()
+	resp, err := a.service.Spreadsheets.Values.Get(a.spreadsheetID, readRange).Context(ctx).Do()
+	if err != nil {
+		if errorCause(err) == ErrSheetNotFound {
+			return nil, nil
+		}
+		return nil, apiError("get lease", err)
+	}
+	if len(resp.Values) == 0 || len(resp.Values[0]) < 2 {
+		return nil, nil
+	}
+
+	row := resp.Values[0]
+	owner := fmt.Sprintf("%v", row[0])
+	if owner == "" {
+		return nil, nil
+	}
+	expires, err := time.Parse(time.RFC3339Nano, fmt.Sprintf("%v", row[1]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid lease expiry %q: %w", row[1], err)
+	}
+	return &sheetkv.Lease{Owner: owner, Expires: expires}, nil
+}
+
+// WriteLease writes the lease to the hidden lease sheet, creating the sheet
+// on first use. A nil lease clears it.
+func (a *SheetsAdaptor) WriteLease(ctx context.Context, lease *sheetkv.Lease) error {
+	if err := a.ensureSheet(ctx, a.leaseSheet(), true); err != nil {
+		return err
+	}
+
+	row := []interface{}{"", ""}
+	if lease != nil {
+		row = []interface{}{lease.Owner, lease.Expires.UTC().Format(time.RFC3339Nano)}
+	}
+
+	writeRange := fmt.Sprintf("%s!A1:B2", a.leaseSheet())
+	a.requests.write()
+	_, err := a.service.Spreadsheets.Values.Update(a.spreadsheetID, writeRange, &sheets.ValueRange{Values: [][]interface{}{leaseHeader, row}}).
+		ValueInputOption("RAW").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return apiError("update lease sheet", err)
+	}
+	return nil
+}
+
+// leaseSheet returns the name of the hidden lease sheet
+func (a *SheetsAdaptor) leaseSheet() string {
+	if a.leaseSheetName != "" {
+		return a.leaseSheetName
+	}
+	return "_" + a.sheetName + "_lease"
+}
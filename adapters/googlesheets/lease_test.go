@@ -0,0 +1,88 @@
+package googlesheets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestSheetsAdaptor_Lease(t *testing.T) {
+	ctx := context.Background()
+
+	var stored [][]interface{}
+	leaseExists := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v4/spreadsheets/test-id":
+			titles := []map[string]interface{}{{"properties": map[string]interface{}{"title": "Users"}}}
+			if leaseExists {
+				titles = append(titles, map[string]interface{}{"properties": map[string]interface{}{"title": "_Users_lease"}})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"sheets": titles})
+		case "/v4/spreadsheets/test-id:batchUpdate":
+			var req sheets.BatchUpdateSpreadsheetRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if props := req.Requests[0].AddSheet.Properties; props.Title != "_Users_lease" || !props.Hidden {
+				t.Errorf("added sheet = %+v, want the hidden lease sheet", props)
+			}
+			leaseExists = true
+			json.NewEncoder(w).Encode(map[string]interface{}{})
+		case "/v4/spreadsheets/test-id/values/_Users_lease!A1:B2":
+			var req sheets.ValueRange
+			json.NewDecoder(r.Body).Decode(&req)
+			stored = req.Values
+			json.NewEncoder(w).Encode(map[string]interface{}{})
+		case "/v4/spreadsheets/test-id/values/_Users_lease!A2:B2":
+			if !leaseExists {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]interface{}{
+					"code": 400, "message": "Unable to parse range: _Users_lease!A2:B2",
+				}})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"values": stored[1:]})
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{SpreadsheetID: "test-id", SheetName: "Users"},
+		option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("Failed to create adaptor: %v", err)
+	}
+
+	if lease, err := adaptor.ReadLease(ctx); err != nil || lease != nil {
+		t.Fatalf("ReadLease() before any write = %v, %v, want nil, nil", lease, err)
+	}
+
+	expires := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := adaptor.WriteLease(ctx, &sheetkv.Lease{Owner: "host:42", Expires: expires}); err != nil {
+		t.Fatalf("WriteLease() error = %v", err)
+	}
+	lease, err := adaptor.ReadLease(ctx)
+	if err != nil {
+		t.Fatalf("ReadLease() error = %v", err)
+	}
+	if lease == nil || lease.Owner != "host:42" || !lease.Expires.Equal(expires) {
+		t.Errorf("ReadLease() = %+v, want the written lease", lease)
+	}
+
+	if err := adaptor.WriteLease(ctx, nil); err != nil {
+		t.Fatalf("WriteLease(nil) error = %v", err)
+	}
+	if lease, err := adaptor.ReadLease(ctx); err != nil || lease != nil {
+		t.Errorf("ReadLease() after release = %v, %v, want nil, nil", lease, err)
+	}
+}
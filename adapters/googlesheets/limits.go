@@ -0,0 +1,111 @@
+package googlesheets
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+const (
+	// DefaultQuotaPerMinute is the Sheets API quota of read requests, and
+	// separately of write requests, per minute and user
+	DefaultQuotaPerMinute = 60
+
+	// RowLimit is the number of rows a sheet can hold (bounded by CellLimit)
+	RowLimit = CellLimit
+	// ColumnLimit is the number of columns a sheet can hold
+	ColumnLimit = 18278
+)
+
+// requestLog remembers the Sheets API requests of the last minute, shared by
+// the adaptors using the same credentials. A nil log records nothing.
+type requestLog struct {
+	mu     sync.Mutex
+	reads  []time.Time
+	writes []time.Time
+}
+
+// read records a read request
+func (l *requestLog) read() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.reads = append(recent(l.reads), time.Now())
+}
+
+// write records a write request
+func (l *requestLog) write() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.writes = append(recent(l.writes), time.Now())
+}
+
+// counts returns the read and write requests of the last minute
+func (l *requestLog) counts() (int, int) {
+	if l == nil {
+		return 0, 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.reads, l.writes = recent(l.reads), recent(l.writes)
+	return len(l.reads), len(l.writes)
+}
+
+// recent drops the times older than a minute
+func recent(times []time.Time) []time.Time {
+	cutoff := time.Now().Add(-time.Minute)
+	i := 0
+	for i < len(times) && times[i].Before(cutoff) {
+		i++
+	}
+	return times[i:]
+}
+
+// Limits returns the grid of the sheet, the cells of the spreadsheet and an
+// estimate of the quota left this minute, based on the requests made by the
+// adaptors sharing this adaptor's credentials; it implements
+// sheetkv.LimitsReporter
+func (a *SheetsAdaptor) Limits(ctx context.Context) (*sheetkv.Limits, error) {
+	a.requests.read()
+	ss, err := a.service.Spreadsheets.Get(a.spreadsheetID).
+		Fields("sheets.properties(title,gridProperties(rowCount,columnCount))").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, apiError("get spreadsheet", err)
+	}
+
+	quota := a.quota
+	if quota <= 0 {
+		quota = DefaultQuotaPerMinute
+	}
+	reads, writes := a.requests.counts()
+	limits := &sheetkv.Limits{
+		RowLimit:       RowLimit,
+		ColumnLimit:    ColumnLimit,
+		CellLimit:      CellLimit,
+		ReadQuota:      quota,
+		ReadRemaining:  max(quota-reads, 0),
+		WriteQuota:     quota,
+		WriteRemaining: max(quota-writes, 0),
+	}
+	for _, sheet := range ss.Sheets {
+		props := sheet.Properties
+		if props == nil || props.GridProperties == nil {
+			continue
+		}
+		grid := props.GridProperties
+		limits.Cells += int(grid.RowCount * grid.ColumnCount)
+		if props.Title == a.sheetName {
+			limits.Rows, limits.Columns = int(grid.RowCount), int(grid.ColumnCount)
+		}
+	}
+	return limits, nil
+}
@@ -0,0 +1,64 @@
+package googlesheets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/api/option"
+)
+
+func TestSheetsAdaptor_Limits(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v4/spreadsheets/test-id":
+			grid := func(title string, rows, cols int) map[string]interface{} {
+				return map[string]interface{}{"properties": map[string]interface{}{
+					"title":          title,
+					"gridProperties": map[string]interface{}{"rowCount": rows, "columnCount": cols},
+				}}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"sheets": []map[string]interface{}{
+				grid("Users", 1000, 26),
+				grid("Orders", 500, 10),
+			}})
+		case "/v4/spreadsheets/test-id/values/Users!A:ZZ":
+			json.NewEncoder(w).Encode(map[string]interface{}{"values": [][]interface{}{{"name"}, {"John"}}})
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{SpreadsheetID: "test-id", SheetName: "Users", QuotaPerMinute: 100},
+		option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+
+	if _, _, err := adaptor.Load(ctx); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	limits, err := adaptor.Limits(ctx)
+	if err != nil {
+		t.Fatalf("Limits() error = %v", err)
+	}
+
+	if limits.Rows != 1000 || limits.Columns != 26 || limits.Cells != 31000 || limits.CellLimit != CellLimit {
+		t.Errorf("Limits() = %d x %d, %d of %d cells, want 1000 x 26, 31000 of %d",
+			limits.Rows, limits.Columns, limits.Cells, limits.CellLimit, CellLimit)
+	}
+	// The load and the spreadsheet read of Limits itself
+	if limits.ReadQuota != 100 || limits.ReadRemaining != 98 {
+		t.Errorf("reads = %d of %d left, want 98 of 100", limits.ReadRemaining, limits.ReadQuota)
+	}
+	if limits.WriteRemaining != 100 {
+		t.Errorf("WriteRemaining = %d, want 100", limits.WriteRemaining)
+	}
+}
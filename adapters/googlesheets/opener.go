@@ -0,0 +1,34 @@
+package googlesheets
+
+import (
+	"context"
+
+	"github.com/ideamans/go-sheetkv"
+	"google.golang.org/api/option"
+)
+
+// NewOpener returns a sheetkv.Opener creating adaptors that share one set of
+// credentials and API services, for use with sheetkv.NewManager. Every
+// adaptor uses config with the requested spreadsheet and sheet; leave
+// IndexSheetName empty so each sheet gets its own index sheet.
+//
+//	opener, err := googlesheets.NewOpener(ctx, googlesheets.Config{}, option.WithCredentialsFile("key.json"))
+//	manager := sheetkv.NewManager(opener, &sheetkv.ManagerConfig{IdleTimeout: 10 * time.Minute})
+func NewOpener(ctx context.Context, config Config, opts ...option.ClientOption) (sheetkv.Opener, error) {
+	if _, err := config.startColumn(); err != nil {
+		return nil, err
+	}
+
+	service, driveService, err := newServices(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	requests := &requestLog{}
+	return func(ctx context.Context, spreadsheetID, sheet string) (sheetkv.Adapter, error) {
+		sheetConfig := config
+		sheetConfig.SpreadsheetID = spreadsheetID
+		sheetConfig.SheetName = sheet
+		return newSheetsAdaptor(sheetConfig, service, driveService, requests)
+	}, nil
+}
@@ -0,0 +1,46 @@
+package googlesheets
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/api/option"
+)
+
+func TestNewOpener(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Shares services", func(t *testing.T) {
+		opener, err := NewOpener(ctx, Config{HeaderRow: 2}, option.WithEndpoint("http://localhost"), option.WithoutAuthentication())
+		if err != nil {
+			t.Fatalf("NewOpener() error = %v", err)
+		}
+
+		first, err := opener(ctx, "customer-a", "orders")
+		if err != nil {
+			t.Fatalf("opener() error = %v", err)
+		}
+		second, err := opener(ctx, "customer-b", "orders")
+		if err != nil {
+			t.Fatalf("opener() error = %v", err)
+		}
+
+		a, b := first.(*SheetsAdaptor), second.(*SheetsAdaptor)
+		if a.spreadsheetID != "customer-a" || b.spreadsheetID != "customer-b" || a.sheetName != "orders" {
+			t.Errorf("adaptors = %q/%q, %q/%q, want customer-a/orders, customer-b/orders",
+				a.spreadsheetID, a.sheetName, b.spreadsheetID, b.sheetName)
+		}
+		if a.service != b.service {
+			t.Error("adaptors do not share the sheets service")
+		}
+		if a.headerRow != 2 {
+			t.Errorf("headerRow = %d, want 2", a.headerRow)
+		}
+	})
+
+	t.Run("Invalid config", func(t *testing.T) {
+		if _, err := NewOpener(ctx, Config{StartColumn: "1"}, option.WithoutAuthentication()); err == nil {
+			t.Error("NewOpener() error = nil, want invalid start column")
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ideamans/go-sheetkv"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+// Partitions implements sheetkv.PartitionSource over the tabs
+// <SheetName>_1, <SheetName>_2, … of one spreadsheet. Every tab is read and
+// written by an adaptor configured like the others.
+//
+//	partitions, err := googlesheets.NewPartitions(ctx, googlesheets.Config{
+//		SpreadsheetID: "your-spreadsheet-id",
+//		SheetName:     "users",
+//	}, option.WithCredentialsFile("key.json"))
+//	client := sheetkv.New(sheetkv.NewPartitionedAdapter(partitions, 10000), config)
+type Partitions struct {
+	config   Config
+	service  *sheets.Service
+	drive    *drive.Service
+	requests *requestLog
+	mu       sync.Mutex
+	adaptors map[int]*SheetsAdaptor
+}
+
+// NewPartitions creates the partition source of config.SheetName
+func NewPartitions(ctx context.Context, config Config, opts ...option.ClientOption) (*Partitions, error) {
+	if _, err := config.startColumn(); err != nil {
+		return nil, err
+	}
+
+	service, driveService, err := newServices(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Partitions{
+		config:   config,
+		service:  service,
+		drive:    driveService,
+		requests: &requestLog{},
+		adaptors: make(map[int]*SheetsAdaptor),
+	}, nil
+}
+
+// sheetName returns the tab of partition i (0-based)
+func (p *Partitions) sheetName(i int) string {
+	return fmt.Sprintf("%s_%d", p.config.SheetName, i+1)
+}
+
+// Partitions returns the number of consecutive partition tabs from the first
+func (p *Partitions) Partitions(ctx context.Context) (int, error) {
+	p.requests.read()
+	ss, err := p.service.Spreadsheets.Get(p.config.SpreadsheetID).Fields("sheets.properties.title").Context(ctx).Do()
+	if err != nil {
+		return 0, apiError("get spreadsheet", err)
+	}
+
+	titles := make(map[string]bool, len(ss.Sheets))
+	for _, sheet := range ss.Sheets {
+		if sheet.Properties != nil {
+			titles[sheet.Properties.Title] = true
+		}
+	}
+
+	count := 0
+	for titles[p.sheetName(count)] {
+		count++
+	}
+	return count, nil
+}
+
+// CreatePartition adds the tab of partition i when it does not exist
+func (p *Partitions) CreatePartition(ctx context.Context, i int) error {
+	return p.adaptor(i).ensureSheet(ctx, p.sheetName(i), false)
+}
+
+// Partition returns the adaptor of partition i
+func (p *Partitions) Partition(i int) sheetkv.Adapter {
+	return p.adaptor(i)
+}
+
+// adaptor returns the adaptor of partition i, creating it on first use
+func (p *Partitions) adaptor(i int) *SheetsAdaptor {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if adaptor, ok := p.adaptors[i]; ok {
+		return adaptor
+	}
+
+	config := p.config
+	config.SheetName = p.sheetName(i)
+	config.IndexSheetName = ""
+	// The configuration was validated by NewPartitions
+	adaptor, _ := newSheetsAdaptor(config, p.service, p.drive, p.requests)
+	p.adaptors[i] = adaptor
+	return adaptor
+}
@@ -0,0 +1,80 @@
+package googlesheets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestPartitions(t *testing.T) {
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	titles := []string{"users_1", "users_2", "users_4", "orders_1"}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v4/spreadsheets/test-id":
+			sheetList := make([]map[string]interface{}, 0, len(titles))
+			for _, title := range titles {
+				sheetList = append(sheetList, map[string]interface{}{"properties": map[string]interface{}{"title": title}})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"sheets": sheetList})
+		case "/v4/spreadsheets/test-id:batchUpdate":
+			var req sheets.BatchUpdateSpreadsheetRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			titles = append(titles, req.Requests[0].AddSheet.Properties.Title)
+			json.NewEncoder(w).Encode(map[string]interface{}{})
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	partitions, err := NewPartitions(ctx, Config{SpreadsheetID: "test-id", SheetName: "users"},
+		option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewPartitions() error = %v", err)
+	}
+
+	t.Run("Counts consecutive tabs", func(t *testing.T) {
+		count, err := partitions.Partitions(ctx)
+		if err != nil || count != 2 {
+			t.Errorf("Partitions() = %d, %v, want 2", count, err)
+		}
+	})
+
+	t.Run("Creates tabs", func(t *testing.T) {
+		if err := partitions.CreatePartition(ctx, 1); err != nil {
+			t.Fatalf("CreatePartition(1) error = %v", err)
+		}
+		if err := partitions.CreatePartition(ctx, 2); err != nil {
+			t.Fatalf("CreatePartition(2) error = %v", err)
+		}
+		count, err := partitions.Partitions(ctx)
+		if err != nil || count != 4 {
+			t.Errorf("Partitions() = %d, %v, want 4", count, err)
+		}
+	})
+
+	t.Run("Adaptors per tab", func(t *testing.T) {
+		adaptor := partitions.Partition(2).(*SheetsAdaptor)
+		if adaptor.sheetName != "users_3" || adaptor.indexSheet() != "_users_3_index" {
+			t.Errorf("sheet = %q, index = %q, want users_3, _users_3_index", adaptor.sheetName, adaptor.indexSheet())
+		}
+		if partitions.Partition(2) != partitions.Partition(2) {
+			t.Error("Partition() created a new adaptor for the same tab")
+		}
+	})
+}
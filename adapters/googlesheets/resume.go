@@ -0,0 +1,85 @@
+package googlesheets
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// PartialSaveError is returned by Save when a chunk fails to be written
+// after others were. The next Save of the same records resumes after the
+// written rows instead of clearing the sheet and starting over.
+type PartialSaveError struct {
+	Written int // Rows written, header rows included
+	Total   int // Rows of the save, header rows included
+	Err     error
+}
+
+func (e *PartialSaveError) Error() string {
+	return fmt.Sprintf("saved %d of %d rows: %v", e.Written, e.Total, e.Err)
+}
+
+func (e *PartialSaveError) Unwrap() error {
+	return e.Err
+}
+
+// partialSave is the progress of a failed chunked save
+type partialSave struct {
+	sum     [sha256.Size]byte // Checksum of the saved values
+	written int               // Rows written from the header row
+}
+
+// valuesSum returns the checksum identifying the values of a save
+func valuesSum(values [][]interface{}) [sha256.Size]byte {
+	data, err := json.Marshal(values)
+	if err != nil {
+		// Values are built from sheet values, which always marshal; an
+		// empty sum only means the save cannot resume
+		return [sha256.Size]byte{}
+	}
+	return sha256.Sum256(data)
+}
+
+// resumeRow returns the row of values to resume writing at, 0 when the save
+// of values starts over
+func (a *SheetsAdaptor) resumeRow(sum [sha256.Size]byte) int {
+	partial := a.partial
+	a.partial = nil
+	if partial == nil || partial.sum != sum {
+		return 0
+	}
+	return partial.written
+}
+
+// writeChunks writes values from row start, in chunks of writeChunkRows
+// rows, recording the rows written when a chunk fails
+func (a *SheetsAdaptor) writeChunks(ctx context.Context, values [][]interface{}, start int, sum [sha256.Size]byte) error {
+	size := a.writeChunkRows
+	if size <= 0 {
+		size = len(values)
+	}
+
+	for from := start; from < len(values); from += size {
+		to := min(from+size, len(values))
+		vr := &sheets.ValueRange{Values: values[from:to]}
+		a.requests.write()
+		_, err := a.service.Spreadsheets.Values.Update(a.spreadsheetID, a.cell(a.header()+from), vr).
+			ValueInputOption("RAW").
+			Context(ctx).
+			Do()
+		if err == nil {
+			continue
+		}
+
+		err = apiError("update sheet", err)
+		if a.writeChunkRows <= 0 {
+			return err
+		}
+		a.partial = &partialSave{sum: sum, written: from}
+		return &PartialSaveError{Written: from, Total: len(values), Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package googlesheets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+	"google.golang.org/api/option"
+)
+
+func TestSheetsAdaptor_WriteChunkRows(t *testing.T) {
+	ctx := context.Background()
+
+	var requests []string
+	failAt := "TestSheet!A5"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/v4/spreadsheets/test-id/values/")
+		requests = append(requests, path)
+		if path == failAt {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":{"code":503,"message":"backend error"}}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{SpreadsheetID: "test-id", SheetName: "TestSheet", WriteChunkRows: 2},
+		option.WithEndpoint(server.URL), option.WithoutAuthentication(), option.WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+
+	var records []*sheetkv.Record
+	for key := 2; key <= 6; key++ {
+		records = append(records, &sheetkv.Record{Key: key, Values: map[string]interface{}{"name": fmt.Sprintf("user%d", key)}})
+	}
+	schema := []string{"name"}
+
+	// The third chunk fails after the header and three records are written
+	err = adaptor.Save(ctx, records, schema, sheetkv.SyncStrategyGapPreserving)
+	var partial *PartialSaveError
+	if !errors.As(err, &partial) || partial.Written != 4 || partial.Total != 6 {
+		t.Fatalf("Save() error = %v, want a PartialSaveError after 4 of 6 rows", err)
+	}
+	want := []string{"TestSheet!A:ZZ:clear", "TestSheet!A1", "TestSheet!A3", "TestSheet!A5"}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+
+	// Saving the same records resumes at the failed chunk
+	requests, failAt = nil, ""
+	if err := adaptor.Save(ctx, records, schema, sheetkv.SyncStrategyGapPreserving); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if want := []string{"TestSheet!A5"}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("resumed requests = %v, want %v", requests, want)
+	}
+
+	// Other records start over
+	requests, failAt = nil, "TestSheet!A3"
+	if err := adaptor.Save(ctx, records, schema, sheetkv.SyncStrategyGapPreserving); err == nil {
+		t.Fatal("Save() should fail")
+	}
+	requests, failAt = nil, ""
+	records[0].Values["name"] = "changed"
+	if err := adaptor.Save(ctx, records, schema, sheetkv.SyncStrategyGapPreserving); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if len(requests) != 4 || requests[0] != "TestSheet!A:ZZ:clear" {
+		t.Errorf("requests after a change = %v, want a full save", requests)
+	}
+}
+
+func TestConfig_ValidateWriteChunkRows(t *testing.T) {
+	config := Config{SpreadsheetID: "test-id", SheetName: "TestSheet", WriteChunkRows: -1}
+	if err := config.Validate(); !errors.Is(err, ErrInvalidWriteChunkRows) {
+		t.Errorf("Validate() error = %v, want ErrInvalidWriteChunkRows", err)
+	}
+}
@@ -0,0 +1,22 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// Revision returns the Drive version of the spreadsheet file. The version
+// increases on every change, including edits made in the browser.
+// Requires Config.DriveRevisions (or credentials with a Drive scope).
+func (a *SheetsAdaptor) Revision(ctx context.Context) (string, error) {
+	if a.drive == nil {
+		return "", fmt.Errorf("drive service is not configured")
+	}
+
+	file, err := a.drive.Files.Get(a.spreadsheetID).Fields("version").SupportsAllDrives(true).Context(ctx).Do()
+	if err != nil {
+		return "", apiError("get file version", err)
+	}
+	return strconv.FormatInt(file.Version, 10), nil
+}
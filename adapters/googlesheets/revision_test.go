@@ -0,0 +1,61 @@
+package googlesheets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+func TestSheetsAdaptor_Revision(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/files/test-id" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.URL.Query().Get("fields"); got != "version" {
+			t.Errorf("fields = %q, want version", got)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"version": "42"})
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{SpreadsheetID: "test-id", SheetName: "Users"},
+		option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+
+	revision, err := adaptor.Revision(ctx)
+	if err != nil {
+		t.Fatalf("Revision() error = %v", err)
+	}
+	if revision != "42" {
+		t.Errorf("Revision() = %q, want %q", revision, "42")
+	}
+
+	// Adaptors built without a drive service report an error
+	if _, err := (&SheetsAdaptor{spreadsheetID: "test-id"}).Revision(ctx); err == nil {
+		t.Error("Revision() without drive service should fail")
+	}
+}
+
+func TestConfig_Scopes(t *testing.T) {
+	if got := (Config{}).scopes(); len(got) != 1 {
+		t.Errorf("scopes() = %v, want only the spreadsheets scope", got)
+	}
+	got := Config{DriveRevisions: true}.scopes()
+	want := []string{"https://www.googleapis.com/auth/spreadsheets", drive.DriveMetadataReadonlyScope}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("scopes() = %v, want %v", got, want)
+	}
+}
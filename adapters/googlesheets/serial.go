@@ -0,0 +1,44 @@
+package googlesheets
+
+import "time"
+
+// serialEpoch is day zero of the serial date numbers of Google Sheets
+var serialEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// SerialToTime converts a Sheets serial date number, the days since
+// 1899-12-30 with the time of day as fraction, to a UTC time
+func SerialToTime(serial float64) time.Time {
+	d := time.Duration(serial * float64(24*time.Hour))
+	return serialEpoch.Add(d.Round(time.Millisecond))
+}
+
+// TimeToSerial converts the wall clock of t to a Sheets serial date number
+func TimeToSerial(t time.Time) float64 {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return float64(wall.Sub(serialEpoch)) / float64(24*time.Hour)
+}
+
+// loadTime converts a loaded number of a time column to time.Time
+func loadTime(v interface{}) (time.Time, bool) {
+	switch val := v.(type) {
+	case int64:
+		return SerialToTime(float64(val)), true
+	case float64:
+		return SerialToTime(val), true
+	}
+	return time.Time{}, false
+}
+
+// saveTime returns the time of a time column value, either a time.Time or
+// an RFC 3339 string as stored by Record.SetTime
+func saveTime(v interface{}) (time.Time, bool) {
+	switch val := v.(type) {
+	case time.Time:
+		return val, true
+	case string:
+		if t, err := time.Parse(time.RFC3339, val); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
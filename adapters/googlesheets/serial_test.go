@@ -0,0 +1,41 @@
+package googlesheets
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSerialToTime(t *testing.T) {
+	tests := []struct {
+		serial float64
+		want   time.Time
+	}{
+		{0, time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)},
+		{45658, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{45658.75, time.Date(2025, 1, 1, 18, 0, 0, 0, time.UTC)},
+		{45658 + 1.0/86400, time.Date(2025, 1, 1, 0, 0, 1, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := SerialToTime(tt.serial); !got.Equal(tt.want) {
+			t.Errorf("SerialToTime(%v) = %v, want %v", tt.serial, got, tt.want)
+		}
+	}
+}
+
+func TestTimeToSerial(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	tests := []struct {
+		t    time.Time
+		want float64
+	}{
+		{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), 45658},
+		{time.Date(2025, 1, 1, 18, 0, 0, 0, time.UTC), 45658.75},
+		// The wall clock is kept, whatever the location
+		{time.Date(2025, 1, 1, 18, 0, 0, 0, jst), 45658.75},
+	}
+	for _, tt := range tests {
+		if got := TimeToSerial(tt.t); got != tt.want {
+			t.Errorf("TimeToSerial(%v) = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+}
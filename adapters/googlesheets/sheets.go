@@ -3,32 +3,121 @@ package googlesheets
 import (
 	"context"
 	"fmt"
+	"math"
+	"reflect"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/ideamans/go-sheetkv"
+	"google.golang.org/api/drive/v3"
 	"google.golang.org/api/option"
 	"google.golang.org/api/sheets/v4"
 )
 
 // SheetsAdaptor implements the Adapter interface for Google Sheets
 type SheetsAdaptor struct {
-	service       *sheets.Service
-	spreadsheetID string
-	sheetName     string
+	service        *sheets.Service
+	drive          *drive.Service // Used for revisions, nil when not configured
+	spreadsheetID  string
+	sheetName      string
+	indexSheetName string
+	leaseSheetName string
+	headerRow      int // Row holding the column names, 0 means 1
+	startColumn    int // First managed column (1-based), 0 means 1
+	maxColumns     int // Number of managed columns, 0 means up to ZZ
+	headerRows     int // Number of header rows, 0 means 1
+	separator      string
+	formatHeader   bool
+	formulas       map[string]bool // Columns owned by formulas
+	aliases        sheetkv.ColumnAliases
+	whitespace     sheetkv.Whitespace
+	boolTokens     sheetkv.BoolTokens
+	inference      sheetkv.TypeInference
+	floatFormats   sheetkv.FloatFormats
+	valueRender    ValueRender
+	dateTimeRender DateTimeRender
+	timeColumns    map[string]bool
+	writeChunkRows int          // Rows per write request, 0 means all rows in one request
+	partial        *partialSave // Progress of the last failed chunked save
+	quota          int          // Requests per minute, 0 means DefaultQuotaPerMinute
+	requests       *requestLog  // Requests of the last minute, shared with adaptors on the same credentials
 }
 
 // NewSheetsAdaptor creates a new Google Sheets adaptor with provided options
 func NewSheetsAdaptor(ctx context.Context, config Config, opts ...option.ClientOption) (*SheetsAdaptor, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	service, driveService, err := newServices(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return newSheetsAdaptor(config, service, driveService, &requestLog{})
+}
+
+// newServices creates the API services used by adaptors
+func newServices(ctx context.Context, opts ...option.ClientOption) (*sheets.Service, *drive.Service, error) {
 	service, err := sheets.NewService(ctx, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create sheets service: %w", err)
+		return nil, nil, fmt.Errorf("failed to create sheets service: %w", err)
+	}
+
+	driveService, err := drive.NewService(ctx, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create drive service: %w", err)
 	}
+	return service, driveService, nil
+}
+
+// startColumn returns the validated first managed column (1-based)
+func (c Config) startColumn() (int, error) {
+	startColumn := 1
+	if c.StartColumn != "" {
+		startColumn = columnNumber(c.StartColumn)
+		if startColumn == 0 {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidStartColumn, c.StartColumn)
+		}
+	}
+	if c.MaxColumns < 0 {
+		return 0, fmt.Errorf("%w: %d", ErrInvalidMaxColumns, c.MaxColumns)
+	}
+	return startColumn, nil
+}
+
+// newSheetsAdaptor creates an adaptor using existing services
+func newSheetsAdaptor(config Config, service *sheets.Service, driveService *drive.Service, requests *requestLog) (*SheetsAdaptor, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	startColumn, _ := config.startColumn() // Checked by Validate
 
 	return &SheetsAdaptor{
-		service:       service,
-		spreadsheetID: config.SpreadsheetID,
-		sheetName:     config.SheetName,
+		service:        service,
+		drive:          driveService,
+		spreadsheetID:  config.SpreadsheetID,
+		sheetName:      config.SheetName,
+		indexSheetName: config.IndexSheetName,
+		leaseSheetName: config.LeaseSheetName,
+		headerRow:      config.HeaderRow,
+		startColumn:    startColumn,
+		maxColumns:     config.MaxColumns,
+		headerRows:     config.HeaderRows,
+		separator:      config.HeaderSeparator,
+		formatHeader:   config.FormatHeader,
+		formulas:       columnSet(config.FormulaColumns),
+		aliases:        config.ColumnAliases,
+		whitespace:     config.Whitespace,
+		boolTokens:     config.BoolTokens,
+		inference:      config.TypeInference,
+		floatFormats:   config.FloatFormats,
+		valueRender:    config.ValueRender,
+		dateTimeRender: config.DateTimeRender,
+		timeColumns:    columnSet(config.TimeColumns),
+		writeChunkRows: config.WriteChunkRows,
+		quota:          config.QuotaPerMinute,
+		requests:       requests,
 	}, nil
 }
 
@@ -36,48 +125,38 @@ func NewSheetsAdaptor(ctx context.Context, config Config, opts ...option.ClientO
 func (a *SheetsAdaptor) Load(ctx context.Context) ([]*sheetkv.Record, []string, error) {
 
 	// Get all data from the sheet
-	readRange := fmt.Sprintf("%s!A:ZZ", a.sheetName)
-	resp, err := a.service.Spreadsheets.Values.Get(a.spreadsheetID, readRange).Context(ctx).Do()
+	readRange := a.dataRange()
+	a.requests.read()
+	call := a.service.Spreadsheets.Values.Get(a.spreadsheetID, readRange)
+	if a.valueRender != "" {
+		call = call.ValueRenderOption(string(a.valueRender))
+	}
+	if a.dateTimeRender != "" {
+		call = call.DateTimeRenderOption(string(a.dateTimeRender))
+	}
+	resp, err := call.Context(ctx).Do()
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get sheet data: %w", err)
+		return nil, nil, apiError("get sheet data", err)
 	}
 
 	if len(resp.Values) == 0 {
 		return []*sheetkv.Record{}, []string{}, nil
 	}
 
-	// First row is schema
-	schema := make([]string, 0)
-	if len(resp.Values) > 0 && len(resp.Values[0]) > 0 {
-		for i := 0; i < len(resp.Values[0]); i++ {
-			if col, ok := resp.Values[0][i].(string); ok && col != "" {
-				schema = append(schema, col)
-			}
-		}
-	}
+	// First rows of the range are the header rows
+	height := a.headerHeight()
+	columns, schema := a.parseHeader(resp.Values)
 
 	// Parse records from remaining rows
 	records := make([]*sheetkv.Record, 0)
-	for i := 1; i < len(resp.Values); i++ {
+	for i := height; i < len(resp.Values); i++ {
 		row := resp.Values[i]
 		if len(row) == 0 {
 			continue
 		}
 
-		// Build record with row number as key (row 1 is header, so data starts at row 2)
-		record := &sheetkv.Record{
-			Key:    i + 1, // Row number (1-based, but data starts at row 2)
-			Values: make(map[string]interface{}),
-		}
-
-		for j := 0; j < len(row) && j < len(schema); j++ {
-			colName := schema[j]
-			if colName != "" && row[j] != nil {
-				record.Values[colName] = convertCellValue(row[j])
-			}
-		}
-
-		records = append(records, record)
+		// Data starts at key 2 on the row after the header
+		records = append(records, a.parseRecord(i-height+2, row, columns))
 	}
 
 	return records, schema, nil
@@ -86,6 +165,10 @@ func (a *SheetsAdaptor) Load(ctx context.Context) ([]*sheetkv.Record, []string,
 // Save replaces all data in the spreadsheet with the provided records
 func (a *SheetsAdaptor) Save(ctx context.Context, records []*sheetkv.Record, schema []string, strategy sheetkv.SyncStrategy) error {
 
+	if a.maxColumns > 0 && len(schema) > a.maxColumns {
+		return fmt.Errorf("too many columns: %d, at most %d", len(schema), a.maxColumns)
+	}
+
 	// Sort records by key (row number)
 	sortedRecords := make([]*sheetkv.Record, len(records))
 	copy(sortedRecords, records)
@@ -96,12 +179,8 @@ func (a *SheetsAdaptor) Save(ctx context.Context, records []*sheetkv.Record, sch
 	// Build values array
 	values := make([][]interface{}, 0)
 
-	// Header row (schema columns only)
-	header := make([]interface{}, len(schema))
-	for i, col := range schema {
-		header[i] = col
-	}
-	values = append(values, header)
+	// Header rows (schema columns only)
+	values = append(values, a.headerValues(schema)...)
 
 	// Data rows based on sync strategy
 	if strategy == sheetkv.SyncStrategyGapPreserving {
@@ -123,7 +202,7 @@ func (a *SheetsAdaptor) Save(ctx context.Context, records []*sheetkv.Record, sch
 			row := make([]interface{}, len(schema))
 			for i, col := range schema {
 				if val, ok := record.Values[col]; ok {
-					row[i] = convertToSheetValue(val)
+					row[i] = a.sheetValue(col, val)
 				} else {
 					row[i] = ""
 				}
@@ -137,7 +216,7 @@ func (a *SheetsAdaptor) Save(ctx context.Context, records []*sheetkv.Record, sch
 			row := make([]interface{}, len(schema))
 			for i, col := range schema {
 				if val, ok := record.Values[col]; ok {
-					row[i] = convertToSheetValue(val)
+					row[i] = a.sheetValue(col, val)
 				} else {
 					row[i] = ""
 				}
@@ -146,103 +225,329 @@ func (a *SheetsAdaptor) Save(ctx context.Context, records []*sheetkv.Record, sch
 		}
 	}
 
-	// Clear the entire sheet first
-	clearRange := fmt.Sprintf("%s!A:ZZ", a.sheetName)
-	_, err := a.service.Spreadsheets.Values.Clear(a.spreadsheetID, clearRange, &sheets.ClearValuesRequest{}).Context(ctx).Do()
-	if err != nil {
-		return fmt.Errorf("failed to clear sheet: %w", err)
+	if len(a.formulas) > 0 {
+		skipFormulas(values[a.headerHeight():], schema, a.formulas)
 	}
 
-	// Write all data
-	writeRange := fmt.Sprintf("%s!A1", a.sheetName)
-	vr := &sheets.ValueRange{
-		Values: values,
+	// A save of the same values as a failed one resumes after its last
+	// written chunk, the sheet being already cleared
+	sum := valuesSum(values)
+	start := a.resumeRow(sum)
+	if start == 0 {
+		// Clear the entire sheet first, except formula columns
+		var err error
+		if len(a.formulas) == 0 {
+			clearRange := a.dataRange()
+			a.requests.write()
+			_, err = a.service.Spreadsheets.Values.Clear(a.spreadsheetID, clearRange, &sheets.ClearValuesRequest{}).Context(ctx).Do()
+		} else {
+			req := &sheets.BatchClearValuesRequest{Ranges: a.clearRanges(schema)}
+			a.requests.write()
+			_, err = a.service.Spreadsheets.Values.BatchClear(a.spreadsheetID, req).Context(ctx).Do()
+		}
+		if err != nil {
+			return apiError("clear sheet", err)
+		}
 	}
-	_, err = a.service.Spreadsheets.Values.Update(a.spreadsheetID, writeRange, vr).
-		ValueInputOption("RAW").
-		Context(ctx).
-		Do()
-	if err != nil {
-		return fmt.Errorf("failed to update sheet: %w", err)
+
+	// Write the data, in chunks of writeChunkRows rows when set
+	if err := a.writeChunks(ctx, values, start, sum); err != nil {
+		return err
 	}
 
+	if a.formatHeader {
+		return a.applyHeaderFormat(ctx, len(schema))
+	}
 	return nil
 }
 
-// BatchUpdate performs multiple operations in a single request
+// BatchUpdate writes the rows of the operations alone, without rewriting
+// the sheet: the header and the rows of the keys are read in one request,
+// and the changed rows written in another. Added records fill the row of
+// their key, which must be empty; updated records must exist and get their
+// columns written, clearing those set to nil; deleted rows are cleared.
+// Columns missing from the header are added to its end.
 func (a *SheetsAdaptor) BatchUpdate(ctx context.Context, operations []sheetkv.Operation) error {
-	// For simplicity, we'll load all data, apply operations, and save
-	// In a production implementation, this could be optimized with batch API calls
+	if len(operations) == 0 {
+		return nil
+	}
 
-	records, schema, err := a.Load(ctx)
+	// The header, and the rows that must be empty or not
+	ranges := []string{a.rowsRange(a.header(), a.lastHeader())}
+	var checked []sheetkv.Operation
+	for _, op := range operations {
+		if op.Type == sheetkv.OpAdd || op.Type == sheetkv.OpUpdate {
+			ranges = append(ranges, a.rowsRange(a.rowOf(op.Record.Key), a.rowOf(op.Record.Key)))
+			checked = append(checked, op)
+		}
+	}
+	a.requests.read()
+	resp, err := a.service.Spreadsheets.Values.BatchGet(a.spreadsheetID).Ranges(ranges...).Context(ctx).Do()
 	if err != nil {
-		return fmt.Errorf("failed to load data for batch update: %w", err)
+		return apiError("get rows for batch update", err)
 	}
 
-	// Convert to map for easier manipulation
-	recordMap := make(map[int]*sheetkv.Record)
-	for _, r := range records {
-		recordMap[r.Key] = r
+	var columns, schema []string
+	if len(resp.ValueRanges) > 0 {
+		columns, schema = a.parseHeader(resp.ValueRanges[0].Values)
+	}
+	for i, op := range checked {
+		exists := false
+		if i+1 < len(resp.ValueRanges) && len(resp.ValueRanges[i+1].Values) > 0 {
+			exists = a.rowExists(resp.ValueRanges[i+1].Values[0], columns)
+		}
+		switch {
+		case op.Type == sheetkv.OpAdd && exists:
+			return fmt.Errorf("cannot add record with duplicate key: %d", op.Record.Key)
+		case op.Type == sheetkv.OpUpdate && !exists:
+			return fmt.Errorf("cannot update non-existent record: %d", op.Record.Key)
+		}
 	}
 
-	// Apply operations
+	// Extend the header with new columns
+	extended := columns
 	for _, op := range operations {
-		switch op.Type {
-		case sheetkv.OpAdd:
-			if _, exists := recordMap[op.Record.Key]; exists {
-				return fmt.Errorf("cannot add record with duplicate key: %d", op.Record.Key)
-			}
-			recordMap[op.Record.Key] = op.Record
-			// Update schema if needed
-			for col := range op.Record.Values {
-				found := false
-				for _, s := range schema {
-					if s == col {
-						found = true
-						break
-					}
-				}
-				if !found {
-					schema = append(schema, col)
-				}
+		if op.Type == sheetkv.OpDelete {
+			continue
+		}
+		for _, col := range sortedNames(op.Record.Values) {
+			if op.Record.Values[col] != nil && !containsColumn(extended, col) {
+				extended = append(extended, col)
+				schema = append(schema, col)
 			}
+		}
+	}
+	if a.maxColumns > 0 && len(schema) > a.maxColumns {
+		return fmt.Errorf("too many columns: %d, at most %d", len(schema), a.maxColumns)
+	}
 
-		case sheetkv.OpUpdate:
-			if existing, exists := recordMap[op.Record.Key]; exists {
-				// Merge values
-				for k, v := range op.Record.Values {
-					existing.Values[k] = v
-				}
-				// Update schema if needed
-				for col := range op.Record.Values {
-					found := false
-					for _, s := range schema {
-						if s == col {
-							found = true
-							break
-						}
-					}
-					if !found {
-						schema = append(schema, col)
-					}
-				}
-			} else {
-				return fmt.Errorf("cannot update non-existent record: %d", op.Record.Key)
+	var data []*sheets.ValueRange
+	if len(extended) != len(columns) {
+		data = append(data, &sheets.ValueRange{Range: a.cell(a.header()), Values: a.headerValues(extended)})
+	}
+	for _, op := range operations {
+		row := make([]interface{}, len(extended))
+		for i, col := range extended {
+			value, ok := op.Record.Values[col]
+			switch {
+			case col == "" || a.formulas[col]:
+				// Left to the sheet
+			case op.Type == sheetkv.OpDelete:
+				row[i] = ""
+			case ok:
+				row[i] = a.sheetValue(col, value)
+			case op.Type == sheetkv.OpAdd:
+				row[i] = ""
 			}
+		}
+		data = append(data, &sheets.ValueRange{Range: a.cell(a.rowOf(op.Record.Key)), Values: [][]interface{}{row}})
+	}
+
+	a.requests.write()
+	req := &sheets.BatchUpdateValuesRequest{ValueInputOption: "RAW", Data: data}
+	if _, err := a.service.Spreadsheets.Values.BatchUpdate(a.spreadsheetID, req).Context(ctx).Do(); err != nil {
+		return apiError("batch update rows", err)
+	}
+
+	if a.formatHeader && len(extended) != len(columns) {
+		return a.applyHeaderFormat(ctx, len(extended))
+	}
+	return nil
+}
 
-		case sheetkv.OpDelete:
-			delete(recordMap, op.Record.Key)
+// rowExists reports whether row holds a value in a managed column, formula
+// columns aside as they compute values for empty rows too
+func (a *SheetsAdaptor) rowExists(row []interface{}, columns []string) bool {
+	for i, cell := range row {
+		if i >= len(columns) || columns[i] == "" || a.formulas[columns[i]] {
+			continue
+		}
+		if cell != nil && cell != "" {
+			return true
 		}
 	}
+	return false
+}
 
-	// Convert back to slice
-	newRecords := make([]*sheetkv.Record, 0, len(recordMap))
-	for _, r := range recordMap {
-		newRecords = append(newRecords, r)
+// header returns the 1-based row holding the column names
+func (a *SheetsAdaptor) header() int {
+	if a.headerRow > 0 {
+		return a.headerRow
 	}
+	return 1
+}
 
-	// Save all data (use gap-preserving strategy for batch updates)
-	return a.Save(ctx, newRecords, schema, sheetkv.SyncStrategyGapPreserving)
+// rowOf returns the sheet row storing key. Keys start at 2 for the first
+// row below the header, whatever the header row is.
+func (a *SheetsAdaptor) rowOf(key int) int {
+	return key + a.header() + a.headerHeight() - 2
+}
+
+// headerHeight returns the number of header rows
+func (a *SheetsAdaptor) headerHeight() int {
+	if a.headerRows > 1 {
+		return a.headerRows
+	}
+	return 1
+}
+
+// lastHeader returns the last header row
+func (a *SheetsAdaptor) lastHeader() int {
+	return a.header() + a.headerHeight() - 1
+}
+
+// parseHeader returns the column name at each position of the header rows
+// at the top of values, and the schema made of the non-empty names
+func (a *SheetsAdaptor) parseHeader(values [][]interface{}) ([]string, []string) {
+	if len(values) == 0 {
+		return []string{}, []string{}
+	}
+	if a.headerHeight() == 1 {
+		schema := a.aliases.Columns(parseSchema(values[0]))
+		return schema, schema
+	}
+
+	rows := make([][]string, 0, a.headerHeight())
+	for _, row := range values[:min(a.headerHeight(), len(values))] {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = fmt.Sprintf("%v", cell)
+		}
+		rows = append(rows, cells)
+	}
+
+	columns := a.aliases.Columns(sheetkv.FlattenHeader(rows, a.separator))
+	schema := make([]string, 0, len(columns))
+	for _, col := range columns {
+		if col != "" {
+			schema = append(schema, col)
+		}
+	}
+	return columns, schema
+}
+
+// headerValues returns the header rows written for schema
+func (a *SheetsAdaptor) headerValues(schema []string) [][]interface{} {
+	headers := a.aliases.Headers(schema)
+	rows := [][]string{headers}
+	if a.headerHeight() > 1 {
+		rows = sheetkv.SplitHeader(headers, a.headerHeight(), a.separator)
+	}
+
+	values := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		values[i] = make([]interface{}, len(row))
+		for j, cell := range row {
+			values[i][j] = cell
+		}
+	}
+	return values
+}
+
+// columns returns the first and last managed column names
+func (a *SheetsAdaptor) columns() (string, string) {
+	first := a.startColumn
+	if first <= 0 {
+		first = 1
+	}
+	if a.maxColumns > 0 {
+		return columnName(first), columnName(first + a.maxColumns - 1)
+	}
+	return columnName(first), "ZZ"
+}
+
+// dataRange returns the managed columns from the header row to the end of
+// the sheet
+func (a *SheetsAdaptor) dataRange() string {
+	first, last := a.columns()
+	if a.header() == 1 {
+		return fmt.Sprintf("%s!%s:%s", a.sheetName, first, last)
+	}
+	return fmt.Sprintf("%s!%s%d:%s", a.sheetName, first, a.header(), last)
+}
+
+// rowsRange returns the managed columns of the rows from..to
+func (a *SheetsAdaptor) rowsRange(from, to int) string {
+	first, last := a.columns()
+	return fmt.Sprintf("%s!%s%d:%s%d", a.sheetName, first, from, last, to)
+}
+
+// cell returns the first managed cell of row
+func (a *SheetsAdaptor) cell(row int) string {
+	first, _ := a.columns()
+	return fmt.Sprintf("%s!%s%d", a.sheetName, first, row)
+}
+
+// columnName converts a column number to its name (1 -> A, 27 -> AA)
+func columnName(col int) string {
+	name := ""
+	for col > 0 {
+		col--
+		name = string(rune('A'+col%26)) + name
+		col /= 26
+	}
+	return name
+}
+
+// columnNumber converts a column name to its number (A -> 1), returning 0
+// for invalid names
+func columnNumber(name string) int {
+	n := 0
+	for _, r := range strings.ToUpper(name) {
+		if r < 'A' || r > 'Z' {
+			return 0
+		}
+		n = n*26 + int(r-'A'+1)
+	}
+	return n
+}
+
+// parseSchema extracts the column names from the header row
+func parseSchema(header []interface{}) []string {
+	schema := make([]string, 0)
+	for i := 0; i < len(header); i++ {
+		if col, ok := header[i].(string); ok && col != "" {
+			schema = append(schema, col)
+		}
+	}
+	return schema
+}
+
+// parseRecord builds a record from a sheet row, cleaning the whitespace of
+// text cells and inferring their type as configured
+func (a *SheetsAdaptor) parseRecord(key int, row []interface{}, schema []string) *sheetkv.Record {
+	record := &sheetkv.Record{
+		Key:    key,
+		Values: make(map[string]interface{}),
+	}
+
+	for j := 0; j < len(row) && j < len(schema); j++ {
+		colName := schema[j]
+		if colName == "" || row[j] == nil {
+			continue
+		}
+		value := row[j]
+		if s, ok := value.(string); ok {
+			s = a.floatFormats.Unformat(colName, a.whitespace.Clean(s))
+			if !a.inference.Infers(colName, s) {
+				record.Values[colName] = s
+				continue
+			}
+			if b, ok := a.boolTokens.Parse(colName, s); ok {
+				record.Values[colName] = b
+				continue
+			}
+			value = s
+		}
+		value = convertCellValue(value)
+		if a.timeColumns[colName] {
+			if t, ok := loadTime(value); ok {
+				value = t
+			}
+		}
+		record.Values[colName] = value
+	}
+	return record
 }
 
 // convertCellValue converts a Google Sheets cell value to Go type
@@ -277,25 +582,63 @@ func convertCellValue(v interface{}) interface{} {
 	}
 }
 
-// convertToSheetValue converts a Go value to Google Sheets cell value
+// sheetValue converts the value of column col to a cell value, writing
+// times, booleans and floats as configured
+func (a *SheetsAdaptor) sheetValue(col string, v interface{}) interface{} {
+	if a.timeColumns[col] {
+		if t, ok := saveTime(v); ok {
+			return TimeToSerial(t)
+		}
+	}
+	switch val := v.(type) {
+	case bool:
+		if token, ok := a.boolTokens.Format(col, val); ok {
+			return token
+		}
+	case float32:
+		if format, ok := a.floatFormats.For(col); ok {
+			return format.Format(float64(val))
+		}
+	case float64:
+		if format, ok := a.floatFormats.For(col); ok {
+			return format.Format(val)
+		}
+	}
+	return convertToSheetValue(v)
+}
+
+// convertToSheetValue converts a Go value to Google Sheets cell value.
+// Numbers and booleans are sent as JSON numbers and booleans, so formulas
+// and charts in the sheet compute with them.
 func convertToSheetValue(v interface{}) interface{} {
 	switch val := v.(type) {
 	case nil:
 		return ""
 	case string:
 		return val
-	case int, int8, int16, int32, int64:
-		return fmt.Sprintf("%d", val)
-	case uint, uint8, uint16, uint32, uint64:
-		return fmt.Sprintf("%d", val)
-	case float32, float64:
-		return fmt.Sprintf("%g", val)
-	case bool:
-		if val {
-			return "TRUE"
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint()
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Sprintf("%g", f) // Not representable in JSON
 		}
-		return "FALSE"
+		if rv.Kind() == reflect.Float32 {
+			// Through the shortest text, so float32(0.1) is written as 0.1
+			f, _ = strconv.ParseFloat(strconv.FormatFloat(f, 'g', -1, 32), 64)
+		}
+		return f
+	case reflect.Bool:
+		return rv.Bool()
 	default:
-		return fmt.Sprintf("%v", val)
+		return fmt.Sprintf("%v", v)
 	}
 }
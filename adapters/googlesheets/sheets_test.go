@@ -2,13 +2,19 @@ package googlesheets
 
 import (
 	"context"
+	"encoding/json"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ideamans/go-sheetkv"
 	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
 )
 
 func TestSheetsAdaptor_Load(t *testing.T) {
@@ -324,14 +330,12 @@ func TestSheetsAdaptor_Save(t *testing.T) {
 }
 
 func TestSheetsAdaptor_BatchUpdate(t *testing.T) {
-	// Initial data for mock
-	initialData := `{
-		"values": [
-			["name", "age"],
-			["John", "30"],
-			["Jane", "25"]
-		]
-	}`
+	// Initial data for mock, by range
+	initialData := map[string][][]interface{}{
+		"TestSheet!A1:ZZ1": {{"name", "age"}},
+		"TestSheet!A2:ZZ2": {{"John", "30"}},
+		"TestSheet!A3:ZZ3": {{"Jane", "25"}},
+	}
 
 	tests := []struct {
 		name       string
@@ -437,15 +441,18 @@ func TestSheetsAdaptor_BatchUpdate(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create mock server
 			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
 				switch r.URL.Path {
-				case "/v4/spreadsheets/test-id/values/TestSheet!A:ZZ":
-					w.Header().Set("Content-Type", "application/json")
-					w.Write([]byte(initialData))
-				case "/v4/spreadsheets/test-id/values/TestSheet!A:ZZ:clear":
-					w.Write([]byte(`{}`))
-				case "/v4/spreadsheets/test-id/values/TestSheet!A1":
-					w.Write([]byte(`{"updatedCells": 10}`))
+				case "/v4/spreadsheets/test-id/values:batchGet":
+					var ranges []map[string]interface{}
+					for _, rng := range r.URL.Query()["ranges"] {
+						ranges = append(ranges, map[string]interface{}{"range": rng, "values": initialData[rng]})
+					}
+					json.NewEncoder(w).Encode(map[string]interface{}{"valueRanges": ranges})
+				case "/v4/spreadsheets/test-id/values:batchUpdate":
+					w.Write([]byte(`{"totalUpdatedCells": 10}`))
 				default:
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
 					w.WriteHeader(404)
 				}
 			}))
@@ -477,6 +484,64 @@ func TestSheetsAdaptor_BatchUpdate(t *testing.T) {
 	}
 }
 
+func TestSheetsAdaptor_BatchUpdateWritesRowsOnly(t *testing.T) {
+	var got sheets.BatchUpdateValuesRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v4/spreadsheets/test-id/values:batchGet":
+			want := []string{"TestSheet!A1:ZZ1", "TestSheet!A2:ZZ2", "TestSheet!A4:ZZ4"}
+			if ranges := r.URL.Query()["ranges"]; !reflect.DeepEqual(ranges, want) {
+				t.Errorf("batchGet ranges = %v, want %v", ranges, want)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"valueRanges": []map[string]interface{}{
+				{"values": [][]interface{}{{"name", "age"}}},
+				{"values": [][]interface{}{{"John", "30"}}},
+				{},
+			}})
+		case "/v4/spreadsheets/test-id/values:batchUpdate":
+			json.NewDecoder(r.Body).Decode(&got)
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	adaptor, err := NewSheetsAdaptor(ctx, Config{SpreadsheetID: "test-id", SheetName: "TestSheet"},
+		option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+
+	err = adaptor.BatchUpdate(ctx, []sheetkv.Operation{
+		{Type: sheetkv.OpUpdate, Record: &sheetkv.Record{Key: 2, Values: map[string]interface{}{"age": 31, "email": "john@example.com"}}},
+		{Type: sheetkv.OpDelete, Record: &sheetkv.Record{Key: 3}},
+		{Type: sheetkv.OpAdd, Record: &sheetkv.Record{Key: 4, Values: map[string]interface{}{"name": "Bob"}}},
+	})
+	if err != nil {
+		t.Fatalf("BatchUpdate() error = %v", err)
+	}
+
+	// The header gains the new column; untouched cells of updates are skipped
+	want := []*sheets.ValueRange{
+		{Range: "TestSheet!A1", Values: [][]interface{}{{"name", "age", "email"}}},
+		{Range: "TestSheet!A2", Values: [][]interface{}{{nil, float64(31), "john@example.com"}}},
+		{Range: "TestSheet!A3", Values: [][]interface{}{{"", "", ""}}},
+		{Range: "TestSheet!A4", Values: [][]interface{}{{"Bob", "", ""}}},
+	}
+	if got.ValueInputOption != "RAW" || len(got.Data) != len(want) {
+		t.Fatalf("batchUpdate = %+v, want %d RAW ranges", got, len(want))
+	}
+	for i, vr := range want {
+		if got.Data[i].Range != vr.Range || !reflect.DeepEqual(got.Data[i].Values, vr.Values) {
+			t.Errorf("range %d = %s %v, want %s %v", i, got.Data[i].Range, got.Data[i].Values, vr.Range, vr.Values)
+		}
+	}
+}
+
 func TestConvertCellValue(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -509,6 +574,7 @@ func TestConvertCellValue(t *testing.T) {
 }
 
 func TestConvertToSheetValue(t *testing.T) {
+	type celsius float64
 	tests := []struct {
 		name  string
 		input interface{}
@@ -516,11 +582,15 @@ func TestConvertToSheetValue(t *testing.T) {
 	}{
 		{"nil to empty string", nil, ""},
 		{"string", "hello", "hello"},
-		{"int", 123, "123"},
-		{"int64", int64(456), "456"},
-		{"float64", 123.45, "123.45"},
-		{"bool true", true, "TRUE"},
-		{"bool false", false, "FALSE"},
+		{"int", 123, int64(123)},
+		{"int64", int64(456), int64(456)},
+		{"uint", uint(7), uint64(7)},
+		{"float64", 123.45, 123.45},
+		{"float32", float32(0.1), 0.1},
+		{"NaN", math.NaN(), "NaN"},
+		{"named float", celsius(21.5), 21.5},
+		{"bool true", true, true},
+		{"bool false", false, false},
 		{"other type", []int{1, 2}, "[1 2]"},
 	}
 
@@ -538,3 +608,462 @@ func TestConvertToSheetValue(t *testing.T) {
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s[:len(substr)] == substr || (len(s) > len(substr) && contains(s[1:], substr)))
 }
+
+func TestSheetsAdaptor_HeaderRow(t *testing.T) {
+	ctx := context.Background()
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v4/spreadsheets/test-id/values/TestSheet!A3:ZZ":
+			w.Write([]byte(`{"values": [["name", "age"], ["John", "30"], [], ["Jane", "25"]]}`))
+		case "/v4/spreadsheets/test-id/values/TestSheet!A3:ZZ:clear", "/v4/spreadsheets/test-id/values/TestSheet!A3":
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{SpreadsheetID: "test-id", SheetName: "TestSheet", HeaderRow: 3},
+		option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+
+	records, schema, err := adaptor.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(schema) != 2 {
+		t.Errorf("schema = %v, want [name age]", schema)
+	}
+	if len(records) != 2 || records[0].Key != 2 || records[1].Key != 4 {
+		t.Fatalf("records = %+v, want keys 2 and 4", records)
+	}
+
+	if err := adaptor.Save(ctx, records, schema, sheetkv.SyncStrategyGapPreserving); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if got := adaptor.rowOf(2); got != 4 {
+		t.Errorf("rowOf(2) = %d, want 4", got)
+	}
+	if len(requests) != 3 {
+		t.Errorf("requests = %v, want load, clear and update from row 3", requests)
+	}
+}
+
+func TestSheetsAdaptor_ColumnRange(t *testing.T) {
+	ctx := context.Background()
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v4/spreadsheets/test-id/values/TestSheet!C:D":
+			w.Write([]byte(`{"values": [["name", "age"], ["John", "30"]]}`))
+		case "/v4/spreadsheets/test-id/values/TestSheet!C:D:clear", "/v4/spreadsheets/test-id/values/TestSheet!C1":
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{SpreadsheetID: "test-id", SheetName: "TestSheet", StartColumn: "C", MaxColumns: 2},
+		option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+
+	records, schema, err := adaptor.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(records) != 1 || records[0].Values["name"] != "John" {
+		t.Fatalf("records = %+v, want John", records)
+	}
+
+	if err := adaptor.Save(ctx, records, schema, sheetkv.SyncStrategyCompacting); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if len(requests) != 3 {
+		t.Errorf("requests = %v, want load, clear and update of C:D", requests)
+	}
+
+	if err := adaptor.Save(ctx, records, []string{"name", "age", "email"}, sheetkv.SyncStrategyCompacting); err == nil {
+		t.Error("Save() should fail when the schema does not fit in MaxColumns")
+	}
+
+	if _, err := NewSheetsAdaptor(ctx, Config{SpreadsheetID: "test-id", SheetName: "TestSheet", StartColumn: "C3"},
+		option.WithEndpoint(server.URL), option.WithoutAuthentication()); err == nil {
+		t.Error("NewSheetsAdaptor() should reject an invalid start column")
+	}
+}
+
+func TestColumnNames(t *testing.T) {
+	for _, tt := range []struct {
+		number int
+		name   string
+	}{{1, "A"}, {3, "C"}, {26, "Z"}, {27, "AA"}, {702, "ZZ"}} {
+		if got := columnName(tt.number); got != tt.name {
+			t.Errorf("columnName(%d) = %q, want %q", tt.number, got, tt.name)
+		}
+		if got := columnNumber(tt.name); got != tt.number {
+			t.Errorf("columnNumber(%q) = %d, want %d", tt.name, got, tt.number)
+		}
+	}
+}
+
+func TestSheetsAdaptor_GroupedHeader(t *testing.T) {
+	ctx := context.Background()
+
+	var written [][]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v4/spreadsheets/test-id/values/TestSheet!A:ZZ":
+			if r.Method == http.MethodGet {
+				w.Write([]byte(`{"values": [["name", "address"], ["", "city", "zip"], ["John", "Tokyo", "100-0001"]]}`))
+				return
+			}
+		case "/v4/spreadsheets/test-id/values/TestSheet!A:ZZ:clear":
+			w.Write([]byte(`{}`))
+			return
+		case "/v4/spreadsheets/test-id/values/TestSheet!A1":
+			var req sheets.ValueRange
+			json.NewDecoder(r.Body).Decode(&req)
+			written = req.Values
+			w.Write([]byte(`{}`))
+			return
+		}
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(404)
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{SpreadsheetID: "test-id", SheetName: "TestSheet", HeaderRows: 2},
+		option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+
+	records, schema, err := adaptor.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if want := []string{"name", "address/city", "address/zip"}; !reflect.DeepEqual(schema, want) {
+		t.Errorf("schema = %v, want %v", schema, want)
+	}
+	if len(records) != 1 || records[0].Key != 2 || records[0].Values["address/zip"] != "100-0001" {
+		t.Fatalf("records = %+v, want one record at key 2", records)
+	}
+
+	if err := adaptor.Save(ctx, records, schema, sheetkv.SyncStrategyCompacting); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	want := [][]interface{}{
+		{"name", "address", ""},
+		{"", "city", "zip"},
+		{"John", "Tokyo", "100-0001"},
+	}
+	if !reflect.DeepEqual(written, want) {
+		t.Errorf("written = %v, want %v", written, want)
+	}
+}
+
+func TestSheetsAdaptor_ColumnAliases(t *testing.T) {
+	ctx := context.Background()
+
+	var written [][]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v4/spreadsheets/test-id/values/TestSheet!A:ZZ" && r.Method == http.MethodGet:
+			w.Write([]byte(`{"values": [["氏名", "Join Date"], ["山田太郎", "2024-04-01"]]}`))
+		case r.URL.Path == "/v4/spreadsheets/test-id/values/TestSheet!A:ZZ:clear":
+			w.Write([]byte(`{}`))
+		case r.URL.Path == "/v4/spreadsheets/test-id/values/TestSheet!A1":
+			var req sheets.ValueRange
+			json.NewDecoder(r.Body).Decode(&req)
+			written = req.Values
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{
+		SpreadsheetID: "test-id",
+		SheetName:     "TestSheet",
+		ColumnAliases: sheetkv.ColumnAliases{"氏名": "name", "Join Date": "joined_at"},
+	}, option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+
+	records, schema, err := adaptor.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if want := []string{"name", "joined_at"}; !reflect.DeepEqual(schema, want) {
+		t.Errorf("schema = %v, want %v", schema, want)
+	}
+	if records[0].Values["name"] != "山田太郎" {
+		t.Errorf("name = %v, want 山田太郎", records[0].Values["name"])
+	}
+
+	if err := adaptor.Save(ctx, records, schema, sheetkv.SyncStrategyCompacting); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if len(written) == 0 || !reflect.DeepEqual(written[0], []interface{}{"氏名", "Join Date"}) {
+		t.Errorf("header = %v, want the sheet headers", written)
+	}
+}
+
+func TestSheetsAdaptor_Whitespace(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"values": [["name", "age"], ["　山田　　太郎 ", " 42 "]]}`))
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{
+		SpreadsheetID: "test-id",
+		SheetName:     "TestSheet",
+		Whitespace:    sheetkv.Whitespace{Trim: true, Collapse: true, FullWidth: true},
+	}, option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+
+	records, _, err := adaptor.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := records[0].Values["name"]; got != "山田 太郎" {
+		t.Errorf("name = %q, want %q", got, "山田 太郎")
+	}
+	if got := records[0].Values["age"]; got != int64(42) {
+		t.Errorf("age = %#v, want int64(42) once trimmed", got)
+	}
+}
+
+func TestSheetsAdaptor_BoolTokens(t *testing.T) {
+	ctx := context.Background()
+
+	var written [][]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`{"values": [["task", "done"], ["write", "yes"], ["review", "No"]]}`))
+		case strings.HasSuffix(r.URL.Path, ":clear"):
+			w.Write([]byte(`{}`))
+		default:
+			var req sheets.ValueRange
+			json.NewDecoder(r.Body).Decode(&req)
+			written = req.Values
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{
+		SpreadsheetID: "test-id",
+		SheetName:     "TestSheet",
+		BoolTokens:    sheetkv.BoolTokens{True: []string{"yes"}, False: []string{"no"}},
+	}, option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+
+	records, schema, err := adaptor.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if records[0].Values["done"] != true || records[1].Values["done"] != false {
+		t.Errorf("done = %v, %v, want true, false", records[0].Values["done"], records[1].Values["done"])
+	}
+
+	if err := adaptor.Save(ctx, records, schema, sheetkv.SyncStrategyCompacting); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if len(written) != 3 || written[1][1] != "yes" || written[2][1] != "no" {
+		t.Errorf("written = %v, want the tokens written back", written)
+	}
+}
+
+func TestSheetsAdaptor_TypeInference(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"values": [["zip", "phone", "qty", "flag"], ["123", "09012345678", "42", "TRUE"]]}`))
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{
+		SpreadsheetID: "test-id",
+		SheetName:     "TestSheet",
+		TypeInference: sheetkv.TypeInference{TextColumns: []string{"zip"}, Conservative: true},
+	}, option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+
+	records, _, err := adaptor.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := map[string]interface{}{"zip": "123", "phone": "09012345678", "qty": int64(42), "flag": true}
+	if !reflect.DeepEqual(records[0].Values, want) {
+		t.Errorf("Values = %#v, want %#v", records[0].Values, want)
+	}
+}
+
+func TestSheetsAdaptor_FloatFormats(t *testing.T) {
+	ctx := context.Background()
+
+	var written [][]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`{"values": [["price", "rate"], ["1,234.57", "1e-7"]]}`))
+		case strings.HasSuffix(r.URL.Path, ":clear"):
+			w.Write([]byte(`{}`))
+		default:
+			var req sheets.ValueRange
+			json.NewDecoder(r.Body).Decode(&req)
+			written = req.Values
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{
+		SpreadsheetID: "test-id",
+		SheetName:     "TestSheet",
+		FloatFormats: sheetkv.FloatFormats{
+			"price": {Fixed: true, Decimals: 2, Thousands: true},
+			"":      {},
+		},
+	}, option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+
+	records, schema, err := adaptor.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := records[0].Values["price"]; got != 1234.57 {
+		t.Errorf("price = %#v, want 1234.57", got)
+	}
+
+	records[0].Values["price"] = 9876543.219
+	if err := adaptor.Save(ctx, records, schema, sheetkv.SyncStrategyCompacting); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if want := []interface{}{"9,876,543.22", "0.0000001"}; len(written) != 2 || !reflect.DeepEqual(written[1], want) {
+		t.Errorf("written = %v, want row %v", written, want)
+	}
+}
+
+func TestSheetsAdaptor_ValueRender(t *testing.T) {
+	ctx := context.Background()
+
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"values": [["price", "rate", "due"], [1200, 0.15, 45658]]}`))
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{
+		SpreadsheetID:  "test-id",
+		SheetName:      "TestSheet",
+		ValueRender:    UnformattedValues,
+		DateTimeRender: SerialNumberDates,
+	}, option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+
+	records, _, err := adaptor.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := query.Get("valueRenderOption"); got != "UNFORMATTED_VALUE" {
+		t.Errorf("valueRenderOption = %q, want UNFORMATTED_VALUE", got)
+	}
+	if got := query.Get("dateTimeRenderOption"); got != "SERIAL_NUMBER" {
+		t.Errorf("dateTimeRenderOption = %q, want SERIAL_NUMBER", got)
+	}
+	want := map[string]interface{}{"price": int64(1200), "rate": 0.15, "due": int64(45658)}
+	if !reflect.DeepEqual(records[0].Values, want) {
+		t.Errorf("Values = %#v, want %#v", records[0].Values, want)
+	}
+}
+
+func TestSheetsAdaptor_TimeColumns(t *testing.T) {
+	ctx := context.Background()
+
+	var written [][]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`{"values": [["due", "count"], [45658.75, 45658]]}`))
+		case strings.HasSuffix(r.URL.Path, ":clear"):
+			w.Write([]byte(`{}`))
+		default:
+			var req sheets.ValueRange
+			json.NewDecoder(r.Body).Decode(&req)
+			written = req.Values
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{
+		SpreadsheetID: "test-id",
+		SheetName:     "TestSheet",
+		ValueRender:   UnformattedValues,
+		TimeColumns:   []string{"due"},
+	}, option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+
+	records, schema, err := adaptor.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got, want := records[0].Values["due"], time.Date(2025, 1, 1, 18, 0, 0, 0, time.UTC); got != want {
+		t.Errorf("due = %#v, want %v", got, want)
+	}
+	if got := records[0].Values["count"]; got != int64(45658) {
+		t.Errorf("count = %#v, want 45658", got)
+	}
+
+	records = append(records, &sheetkv.Record{Key: 3})
+	records[1].SetTime("due", time.Date(2025, 1, 2, 6, 0, 0, 0, time.UTC))
+	if err := adaptor.Save(ctx, records, schema, sheetkv.SyncStrategyCompacting); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if len(written) != 3 || written[1][0] != 45658.75 || written[2][0] != 45659.25 {
+		t.Errorf("written = %v, want due serials 45658.75 and 45659.25", written)
+	}
+}
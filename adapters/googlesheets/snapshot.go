@@ -0,0 +1,128 @@
+package googlesheets
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/ideamans/go-sheetkv"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+// Snapshots implements sheetkv.SnapshotStore over the tabs
+// <SheetName>_<label> of one spreadsheet, such as users_2024-06-01, giving
+// point-in-time backups inside the workbook of the data.
+//
+//	snapshots, err := googlesheets.NewSnapshots(ctx, googlesheets.Config{
+//		SpreadsheetID: "your-spreadsheet-id",
+//		SheetName:     "users",
+//	}, option.WithCredentialsFile("key.json"))
+//	config.Jobs = []sheetkv.Job{sheetkv.SnapshotJob(snapshots, 7, 24*time.Hour)}
+type Snapshots struct {
+	config   Config
+	service  *sheets.Service
+	drive    *drive.Service
+	requests *requestLog
+	mu       sync.Mutex
+	adaptors map[string]*SheetsAdaptor
+}
+
+// NewSnapshots creates the snapshot store of config.SheetName
+func NewSnapshots(ctx context.Context, config Config, opts ...option.ClientOption) (*Snapshots, error) {
+	if _, err := config.startColumn(); err != nil {
+		return nil, err
+	}
+
+	service, driveService, err := newServices(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Snapshots{
+		config:   config,
+		service:  service,
+		drive:    driveService,
+		requests: &requestLog{},
+		adaptors: make(map[string]*SheetsAdaptor),
+	}, nil
+}
+
+// sheetName returns the tab of the snapshot labeled label
+func (s *Snapshots) sheetName(label string) string {
+	return s.config.SheetName + "_" + label
+}
+
+// Snapshots returns the labels of the tabs named <SheetName>_<label>
+func (s *Snapshots) Snapshots(ctx context.Context) ([]string, error) {
+	s.requests.read()
+	ss, err := s.service.Spreadsheets.Get(s.config.SpreadsheetID).Fields("sheets.properties.title").Context(ctx).Do()
+	if err != nil {
+		return nil, apiError("get spreadsheet", err)
+	}
+
+	prefix := s.sheetName("")
+	var labels []string
+	for _, sheet := range ss.Sheets {
+		if sheet.Properties != nil && strings.HasPrefix(sheet.Properties.Title, prefix) {
+			if label := strings.TrimPrefix(sheet.Properties.Title, prefix); label != "" {
+				labels = append(labels, label)
+			}
+		}
+	}
+	return labels, nil
+}
+
+// CreateSnapshot adds the tab of the snapshot labeled label when it does not
+// exist and returns its adaptor
+func (s *Snapshots) CreateSnapshot(ctx context.Context, label string) (sheetkv.Adapter, error) {
+	adaptor := s.adaptor(label)
+	if err := adaptor.ensureSheet(ctx, adaptor.sheetName, false); err != nil {
+		return nil, err
+	}
+	return adaptor, nil
+}
+
+// DeleteSnapshot removes the tab of the snapshot labeled label
+func (s *Snapshots) DeleteSnapshot(ctx context.Context, label string) error {
+	adaptor := s.adaptor(label)
+	id, err := adaptor.sheetID(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			{DeleteSheet: &sheets.DeleteSheetRequest{SheetId: id}},
+		},
+	}
+	s.requests.write()
+	if _, err := s.service.Spreadsheets.BatchUpdate(s.config.SpreadsheetID, req).Context(ctx).Do(); err != nil {
+		return apiError("delete sheet "+adaptor.sheetName, err)
+	}
+
+	s.mu.Lock()
+	delete(s.adaptors, label)
+	s.mu.Unlock()
+	return nil
+}
+
+// adaptor returns the adaptor of the snapshot labeled label, creating it on
+// first use
+func (s *Snapshots) adaptor(label string) *SheetsAdaptor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if adaptor, ok := s.adaptors[label]; ok {
+		return adaptor
+	}
+
+	config := s.config
+	config.SheetName = s.sheetName(label)
+	config.IndexSheetName = ""
+	// The configuration was validated by NewSnapshots
+	adaptor, _ := newSheetsAdaptor(config, s.service, s.drive, s.requests)
+	s.adaptors[label] = adaptor
+	return adaptor
+}
@@ -0,0 +1,103 @@
+package googlesheets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestSnapshots(t *testing.T) {
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	ids := map[string]int64{"users": 1, "users_2024-06-01": 2, "users_2024-06-02": 3, "orders_2024-06-01": 4}
+	nextID := int64(5)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v4/spreadsheets/test-id":
+			sheetList := make([]map[string]interface{}, 0, len(ids))
+			for title, id := range ids {
+				sheetList = append(sheetList, map[string]interface{}{"properties": map[string]interface{}{"title": title, "sheetId": id}})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"sheets": sheetList})
+		case "/v4/spreadsheets/test-id:batchUpdate":
+			var req sheets.BatchUpdateSpreadsheetRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			switch {
+			case req.Requests[0].AddSheet != nil:
+				ids[req.Requests[0].AddSheet.Properties.Title] = nextID
+				nextID++
+			case req.Requests[0].DeleteSheet != nil:
+				for title, id := range ids {
+					if id == req.Requests[0].DeleteSheet.SheetId {
+						delete(ids, title)
+					}
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{})
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	snapshots, err := NewSnapshots(ctx, Config{SpreadsheetID: "test-id", SheetName: "users"},
+		option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSnapshots() error = %v", err)
+	}
+
+	labels := func() []string {
+		t.Helper()
+		labels, err := snapshots.Snapshots(ctx)
+		if err != nil {
+			t.Fatalf("Snapshots() error = %v", err)
+		}
+		sort.Strings(labels)
+		return labels
+	}
+
+	t.Run("Lists the tabs of the sheet", func(t *testing.T) {
+		if got := labels(); len(got) != 2 || got[0] != "2024-06-01" || got[1] != "2024-06-02" {
+			t.Errorf("Snapshots() = %v, want [2024-06-01 2024-06-02]", got)
+		}
+	})
+
+	t.Run("Creates tabs", func(t *testing.T) {
+		adapter, err := snapshots.CreateSnapshot(ctx, "2024-06-03")
+		if err != nil {
+			t.Fatalf("CreateSnapshot() error = %v", err)
+		}
+		if name := adapter.(*SheetsAdaptor).sheetName; name != "users_2024-06-03" {
+			t.Errorf("sheet = %q, want users_2024-06-03", name)
+		}
+		if got := labels(); len(got) != 3 {
+			t.Errorf("Snapshots() = %v, want 3 labels", got)
+		}
+	})
+
+	t.Run("Deletes tabs", func(t *testing.T) {
+		if err := snapshots.DeleteSnapshot(ctx, "2024-06-01"); err != nil {
+			t.Fatalf("DeleteSnapshot() error = %v", err)
+		}
+		if got := labels(); len(got) != 2 || got[0] != "2024-06-02" {
+			t.Errorf("Snapshots() = %v, want [2024-06-02 2024-06-03]", got)
+		}
+		if _, ok := ids["orders_2024-06-01"]; !ok {
+			t.Error("DeleteSnapshot() removed the tab of another sheet")
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package googlesheets
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/ideamans/go-sheetkv"
+	"google.golang.org/api/sheets/v4"
+)
+
+// SheetStyle describes the presentation of the data sheet, applied by
+// ApplyStyle. Zero fields leave the sheet as it is.
+type SheetStyle struct {
+	FrozenRows    int                            // Rows frozen at the top, usually the header rows
+	FrozenColumns int                            // Columns frozen at the left, such as a name column
+	BoldHeader    bool                           // Make the header rows bold
+	Filter        bool                           // Set a basic filter over the header and data of the managed columns
+	AutoResize    bool                           // Fit the managed columns to their content
+	ColumnWidths  map[string]int                 // Width in pixels per column, applied after AutoResize
+	NumberFormats map[string]sheets.NumberFormat // Number format of the data cells per column, such as {Type: "DATE", Pattern: "yyyy-mm-dd"}
+}
+
+// StyleAfterSave returns a hook for sheetkv.Config.AfterSave applying style
+// to the sheet after each sync. Adapters other than *SheetsAdaptor are left
+// alone.
+func StyleAfterSave(style SheetStyle) sheetkv.AfterSaveHook {
+	return func(ctx context.Context, adapter sheetkv.Adapter) error {
+		if a, ok := adapter.(*SheetsAdaptor); ok {
+			return a.ApplyStyle(ctx, style)
+		}
+		return nil
+	}
+}
+
+// ApplyStyle applies style to the sheet in a single batch update. Columns
+// are found by their name in the header; those missing from it are skipped.
+func (a *SheetsAdaptor) ApplyStyle(ctx context.Context, style SheetStyle) error {
+	a.requests.read()
+	resp, err := a.service.Spreadsheets.Values.Get(a.spreadsheetID, a.rowsRange(a.header(), a.lastHeader())).Context(ctx).Do()
+	if err != nil {
+		return apiError("get header", err)
+	}
+	positions, _ := a.parseHeader(resp.Values)
+
+	sheetID, err := a.sheetID(ctx)
+	if err != nil {
+		return err
+	}
+	requests := a.styleRequests(sheetID, positions, style)
+	if len(requests) == 0 {
+		return nil
+	}
+
+	a.requests.write()
+	req := &sheets.BatchUpdateSpreadsheetRequest{Requests: requests}
+	if _, err := a.service.Spreadsheets.BatchUpdate(a.spreadsheetID, req).Context(ctx).Do(); err != nil {
+		return apiError("apply style", err)
+	}
+	return nil
+}
+
+// styleRequests returns the requests applying style to the sheet whose
+// header has a column name at each position
+func (a *SheetsAdaptor) styleRequests(sheetID int64, positions []string, style SheetStyle) []*sheets.Request {
+	first := int64(a.startColumn - 1)
+	if first < 0 {
+		first = 0
+	}
+	end := first + int64(len(positions))
+	column := func(name string) (int64, bool) {
+		for i, position := range positions {
+			if position == name {
+				return first + int64(i), true
+			}
+		}
+		return 0, false
+	}
+
+	var requests []*sheets.Request
+	if style.FrozenRows > 0 || style.FrozenColumns > 0 {
+		grid := &sheets.GridProperties{}
+		var fields []string
+		if style.FrozenRows > 0 {
+			grid.FrozenRowCount = int64(style.FrozenRows)
+			fields = append(fields, "gridProperties.frozenRowCount")
+		}
+		if style.FrozenColumns > 0 {
+			grid.FrozenColumnCount = int64(style.FrozenColumns)
+			fields = append(fields, "gridProperties.frozenColumnCount")
+		}
+		requests = append(requests, &sheets.Request{
+			UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
+				Properties: &sheets.SheetProperties{SheetId: sheetID, GridProperties: grid},
+				Fields:     strings.Join(fields, ","),
+			},
+		})
+	}
+	if len(positions) == 0 {
+		return requests
+	}
+
+	if style.BoldHeader {
+		requests = append(requests, &sheets.Request{
+			RepeatCell: &sheets.RepeatCellRequest{
+				Range: &sheets.GridRange{
+					SheetId:          sheetID,
+					StartRowIndex:    int64(a.header() - 1),
+					EndRowIndex:      int64(a.lastHeader()),
+					StartColumnIndex: first,
+					EndColumnIndex:   end,
+				},
+				Cell:   &sheets.CellData{UserEnteredFormat: &sheets.CellFormat{TextFormat: &sheets.TextFormat{Bold: true}}},
+				Fields: "userEnteredFormat.textFormat.bold",
+			},
+		})
+	}
+	if style.Filter {
+		requests = append(requests, &sheets.Request{
+			SetBasicFilter: &sheets.SetBasicFilterRequest{
+				Filter: &sheets.BasicFilter{Range: &sheets.GridRange{
+					SheetId:          sheetID,
+					StartRowIndex:    int64(a.lastHeader() - 1),
+					StartColumnIndex: first,
+					EndColumnIndex:   end,
+				}},
+			},
+		})
+	}
+	if style.AutoResize {
+		requests = append(requests, &sheets.Request{
+			AutoResizeDimensions: &sheets.AutoResizeDimensionsRequest{
+				Dimensions: &sheets.DimensionRange{SheetId: sheetID, Dimension: "COLUMNS", StartIndex: first, EndIndex: end},
+			},
+		})
+	}
+	for _, name := range sortedNames(style.ColumnWidths) {
+		index, ok := column(name)
+		if !ok {
+			continue
+		}
+		requests = append(requests, &sheets.Request{
+			UpdateDimensionProperties: &sheets.UpdateDimensionPropertiesRequest{
+				Range:      &sheets.DimensionRange{SheetId: sheetID, Dimension: "COLUMNS", StartIndex: index, EndIndex: index + 1},
+				Properties: &sheets.DimensionProperties{PixelSize: int64(style.ColumnWidths[name])},
+				Fields:     "pixelSize",
+			},
+		})
+	}
+	for _, name := range sortedNames(style.NumberFormats) {
+		index, ok := column(name)
+		if !ok {
+			continue
+		}
+		format := style.NumberFormats[name]
+		requests = append(requests, &sheets.Request{
+			RepeatCell: &sheets.RepeatCellRequest{
+				Range: &sheets.GridRange{
+					SheetId:          sheetID,
+					StartRowIndex:    int64(a.lastHeader()),
+					StartColumnIndex: index,
+					EndColumnIndex:   index + 1,
+				},
+				Cell:   &sheets.CellData{UserEnteredFormat: &sheets.CellFormat{NumberFormat: &format}},
+				Fields: "userEnteredFormat.numberFormat",
+			},
+		})
+	}
+	return requests
+}
+
+// sortedNames returns the keys of m in order, so requests are stable
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
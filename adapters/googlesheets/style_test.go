@@ -0,0 +1,81 @@
+package googlesheets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestSheetsAdaptor_ApplyStyle(t *testing.T) {
+	ctx := context.Background()
+
+	var batch *sheets.BatchUpdateSpreadsheetRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v4/spreadsheets/test-id/values/TestSheet!B1:ZZ1":
+			w.Write([]byte(`{"values": [["name", "price", "added"]]}`))
+		case "/v4/spreadsheets/test-id":
+			w.Write([]byte(`{"sheets": [{"properties": {"sheetId": 42, "title": "TestSheet"}}]}`))
+		case "/v4/spreadsheets/test-id:batchUpdate":
+			batch = &sheets.BatchUpdateSpreadsheetRequest{}
+			json.NewDecoder(r.Body).Decode(batch)
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{
+		SpreadsheetID: "test-id",
+		SheetName:     "TestSheet",
+		StartColumn:   "B",
+	}, option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+
+	hook := StyleAfterSave(SheetStyle{
+		FrozenRows:    1,
+		Filter:        true,
+		ColumnWidths:  map[string]int{"name": 200, "missing": 50},
+		NumberFormats: map[string]sheets.NumberFormat{"price": {Type: "NUMBER", Pattern: "#,##0.00"}},
+	})
+	if err := hook(ctx, adaptor); err != nil {
+		t.Fatalf("hook error = %v", err)
+	}
+
+	if batch == nil || len(batch.Requests) != 4 {
+		t.Fatalf("batchUpdate = %+v, want freeze, filter, width and number format requests", batch)
+	}
+	if freeze := batch.Requests[0].UpdateSheetProperties; freeze.Properties.GridProperties.FrozenRowCount != 1 || freeze.Fields != "gridProperties.frozenRowCount" {
+		t.Errorf("freeze = %+v, want 1 row", freeze)
+	}
+	if r := batch.Requests[1].SetBasicFilter.Filter.Range; r.SheetId != 42 || r.StartRowIndex != 0 || r.StartColumnIndex != 1 || r.EndColumnIndex != 4 {
+		t.Errorf("filter range = %+v, want columns B:D from the header", r)
+	}
+	if width := batch.Requests[2].UpdateDimensionProperties; width.Range.StartIndex != 1 || width.Properties.PixelSize != 200 {
+		t.Errorf("width = %+v, want 200 pixels for column B", width)
+	}
+	format := batch.Requests[3].RepeatCell
+	if r := format.Range; r.StartRowIndex != 1 || r.StartColumnIndex != 2 || r.EndColumnIndex != 3 {
+		t.Errorf("number format range = %+v, want the data of column C", r)
+	}
+	if format.Cell.UserEnteredFormat.NumberFormat.Pattern != "#,##0.00" {
+		t.Errorf("number format = %+v", format.Cell.UserEnteredFormat.NumberFormat)
+	}
+
+	t.Run("Other adapters", func(t *testing.T) {
+		if err := hook(ctx, sheetkv.NewRangeAdapter(nil, sheetkv.TypeInference{})); err != nil {
+			t.Errorf("hook error = %v, want nil", err)
+		}
+	})
+}
@@ -111,7 +111,7 @@ func TestSheetsAdaptor_SyncStrategies(t *testing.T) {
 		// Check data rows with gaps
 		if len(savedValues) > 1 {
 			// Row 2 (index 1) should have data
-			if !reflect.DeepEqual(savedValues[1], []interface{}{"1", "First"}) {
+			if !reflect.DeepEqual(savedValues[1], []interface{}{float64(1), "First"}) {
 				t.Errorf("Row 2 = %v, want [1 First]", savedValues[1])
 			}
 		}
@@ -123,7 +123,7 @@ func TestSheetsAdaptor_SyncStrategies(t *testing.T) {
 		}
 		if len(savedValues) > 3 {
 			// Row 4 (index 3) should have data
-			if !reflect.DeepEqual(savedValues[3], []interface{}{"3", "Third"}) {
+			if !reflect.DeepEqual(savedValues[3], []interface{}{float64(3), "Third"}) {
 				t.Errorf("Row 4 = %v, want [3 Third]", savedValues[3])
 			}
 		}
@@ -135,7 +135,7 @@ func TestSheetsAdaptor_SyncStrategies(t *testing.T) {
 		}
 		if len(savedValues) > 5 {
 			// Row 6 (index 5) should have data
-			if !reflect.DeepEqual(savedValues[5], []interface{}{"5", "Fifth"}) {
+			if !reflect.DeepEqual(savedValues[5], []interface{}{float64(5), "Fifth"}) {
 				t.Errorf("Row 6 = %v, want [5 Fifth]", savedValues[5])
 			}
 		}
@@ -236,19 +236,19 @@ func TestSheetsAdaptor_SyncStrategies(t *testing.T) {
 		// Check data rows are compacted (no gaps)
 		if len(savedValues) > 1 {
 			// Row 2 (index 1) should have first record
-			if !reflect.DeepEqual(savedValues[1], []interface{}{"1", "First"}) {
+			if !reflect.DeepEqual(savedValues[1], []interface{}{float64(1), "First"}) {
 				t.Errorf("Row 2 = %v, want [1 First]", savedValues[1])
 			}
 		}
 		if len(savedValues) > 2 {
 			// Row 3 (index 2) should have second record (no gap)
-			if !reflect.DeepEqual(savedValues[2], []interface{}{"3", "Third"}) {
+			if !reflect.DeepEqual(savedValues[2], []interface{}{float64(3), "Third"}) {
 				t.Errorf("Row 3 = %v, want [3 Third]", savedValues[2])
 			}
 		}
 		if len(savedValues) > 3 {
 			// Row 4 (index 3) should have third record (no gap)
-			if !reflect.DeepEqual(savedValues[3], []interface{}{"5", "Fifth"}) {
+			if !reflect.DeepEqual(savedValues[3], []interface{}{float64(5), "Fifth"}) {
 				t.Errorf("Row 4 = %v, want [5 Fifth]", savedValues[3])
 			}
 		}
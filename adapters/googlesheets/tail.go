@@ -0,0 +1,49 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// LoadAfter implements sheetkv.RowTailer, reading the header and the rows
+// below key in one request, so tailing a form-response sheet does not read
+// the responses already seen
+func (a *SheetsAdaptor) LoadAfter(ctx context.Context, key int) ([]*sheetkv.Record, []string, error) {
+	first, last := a.columns()
+	from := a.rowOf(key + 1)
+	ranges := []string{
+		a.rowsRange(a.header(), a.lastHeader()),
+		fmt.Sprintf("%s!%s%d:%s", a.sheetName, first, from, last),
+	}
+
+	a.requests.read()
+	call := a.service.Spreadsheets.Values.BatchGet(a.spreadsheetID).Ranges(ranges...)
+	if a.valueRender != "" {
+		call = call.ValueRenderOption(string(a.valueRender))
+	}
+	if a.dateTimeRender != "" {
+		call = call.DateTimeRenderOption(string(a.dateTimeRender))
+	}
+	resp, err := call.Context(ctx).Do()
+	if err != nil {
+		return nil, nil, apiError("get rows", err)
+	}
+
+	if len(resp.ValueRanges) == 0 || len(resp.ValueRanges[0].Values) == 0 {
+		return []*sheetkv.Record{}, []string{}, nil
+	}
+	columns, schema := a.parseHeader(resp.ValueRanges[0].Values)
+
+	records := make([]*sheetkv.Record, 0)
+	if len(resp.ValueRanges) > 1 {
+		for i, row := range resp.ValueRanges[1].Values {
+			if len(row) == 0 {
+				continue
+			}
+			records = append(records, a.parseRecord(key+1+i, row, columns))
+		}
+	}
+	return records, schema, nil
+}
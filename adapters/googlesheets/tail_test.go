@@ -0,0 +1,58 @@
+package googlesheets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+	"google.golang.org/api/option"
+)
+
+func TestSheetsAdaptor_LoadAfter(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/v4/spreadsheets/test-id/values:batchGet" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ranges := r.URL.Query()["ranges"]
+		want := []string{"Responses!A1:ZZ1", "Responses!A4:ZZ"}
+		if !reflect.DeepEqual(ranges, want) {
+			t.Errorf("batchGet ranges = %v, want %v", ranges, want)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"valueRanges": []map[string]interface{}{
+				{"values": [][]interface{}{{"タイムスタンプ", "メールアドレス"}}},
+				{"values": [][]interface{}{{"2024/06/01 9:00:00", "a@example.com"}, {}, {"2024/06/01 9:05:00", "b@example.com"}}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{
+		SpreadsheetID: "test-id",
+		SheetName:     "Responses",
+		ColumnAliases: sheetkv.ColumnAliases{"タイムスタンプ": "timestamp", "メールアドレス": "email"},
+	}, option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("Failed to create adaptor: %v", err)
+	}
+
+	records, schema, err := adaptor.LoadAfter(ctx, 3)
+	if err != nil {
+		t.Fatalf("LoadAfter() error = %v", err)
+	}
+	if !reflect.DeepEqual(schema, []string{"timestamp", "email"}) {
+		t.Errorf("LoadAfter() schema = %v, want the aliased columns", schema)
+	}
+	if len(records) != 2 || records[0].Key != 4 || records[1].Key != 6 || records[1].Values["email"] != "b@example.com" {
+		t.Errorf("LoadAfter() = %v, want the keys 4 and 6", records)
+	}
+}
@@ -0,0 +1,108 @@
+package googlesheets
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/ideamans/go-sheetkv"
+	"google.golang.org/api/sheets/v4"
+)
+
+// ValidationRules reads the strict data-validation rules set on the first
+// data row of the sheet, keyed by the header of their column. Only dropdown
+// lists, number comparisons and checkboxes are converted; other conditions
+// are left to the sheet.
+func (a *SheetsAdaptor) ValidationRules(ctx context.Context) ([]sheetkv.ColumnRule, error) {
+	readRange := a.rowsRange(a.header(), a.rowOf(2))
+	a.requests.read()
+	ss, err := a.service.Spreadsheets.Get(a.spreadsheetID).
+		Ranges(readRange).
+		IncludeGridData(true).
+		Fields("sheets.data.rowData.values(formattedValue,dataValidation)").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, apiError("get validation rules", err)
+	}
+
+	if len(ss.Sheets) == 0 || len(ss.Sheets[0].Data) == 0 {
+		return nil, nil
+	}
+	rows := ss.Sheets[0].Data[0].RowData
+	height := a.headerHeight()
+	if len(rows) <= height {
+		return nil, nil
+	}
+
+	header := make([][]string, height)
+	for l, row := range rows[:height] {
+		for _, cell := range row.Values {
+			header[l] = append(header[l], cell.FormattedValue)
+		}
+	}
+	columns := a.aliases.Columns(sheetkv.FlattenHeader(header, a.separator))
+
+	var rules []sheetkv.ColumnRule
+	for i, cell := range rows[height].Values {
+		if i >= len(columns) || cell.DataValidation == nil || !cell.DataValidation.Strict {
+			continue
+		}
+		column := columns[i]
+		if column == "" {
+			continue
+		}
+		if rule, ok := convertRule(column, cell.DataValidation.Condition); ok {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules, nil
+}
+
+// convertRule converts a Sheets validation condition to a column rule
+func convertRule(column string, cond *sheets.BooleanCondition) (sheetkv.ColumnRule, bool) {
+	rule := sheetkv.ColumnRule{Column: column}
+	if cond == nil {
+		return rule, false
+	}
+
+	values := make([]string, len(cond.Values))
+	for i, v := range cond.Values {
+		values[i] = v.UserEnteredValue
+	}
+	numbers := make([]*float64, len(values))
+	for i, v := range values {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			numbers[i] = &f
+		}
+	}
+
+	switch cond.Type {
+	case "ONE_OF_LIST":
+		rule.OneOf = values
+		return rule, len(values) > 0
+	case "BOOLEAN":
+		rule.Boolean = true
+		return rule, true
+	case "NUMBER_BETWEEN":
+		if len(numbers) < 2 || numbers[0] == nil || numbers[1] == nil {
+			return rule, false
+		}
+		rule.Min, rule.Max = numbers[0], numbers[1]
+	case "NUMBER_GREATER", "NUMBER_GREATER_THAN_EQ":
+		if len(numbers) < 1 || numbers[0] == nil {
+			return rule, false
+		}
+		rule.Min = numbers[0]
+		rule.MinExclusive = cond.Type == "NUMBER_GREATER"
+	case "NUMBER_LESS", "NUMBER_LESS_THAN_EQ":
+		if len(numbers) < 1 || numbers[0] == nil {
+			return rule, false
+		}
+		rule.Max = numbers[0]
+		rule.MaxExclusive = cond.Type == "NUMBER_LESS"
+	default:
+		return rule, false
+	}
+	return rule, true
+}
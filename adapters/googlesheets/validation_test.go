@@ -0,0 +1,92 @@
+package googlesheets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/api/option"
+)
+
+func TestSheetsAdaptor_ValidationRules(t *testing.T) {
+	ctx := context.Background()
+
+	cell := func(value string, validation map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{"formattedValue": value}
+		if validation != nil {
+			c["dataValidation"] = validation
+		}
+		return c
+	}
+	condition := func(typ string, strict bool, values ...string) map[string]interface{} {
+		vals := make([]map[string]interface{}, len(values))
+		for i, v := range values {
+			vals[i] = map[string]interface{}{"userEnteredValue": v}
+		}
+		return map[string]interface{}{
+			"condition": map[string]interface{}{"type": typ, "values": vals},
+			"strict":    strict,
+		}
+	}
+
+	var gotRanges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v4/spreadsheets/test-id" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		gotRanges = r.URL.Query()["ranges"]
+
+		header := []interface{}{cell("status", nil), cell("score", nil), cell("active", nil), cell("name", nil), cell("note", nil)}
+		row := []interface{}{
+			cell("", condition("ONE_OF_LIST", true, "open", "closed")),
+			cell("", condition("NUMBER_BETWEEN", true, "0", "100")),
+			cell("", condition("BOOLEAN", true)),
+			cell("", condition("ONE_OF_LIST", false, "a", "b")), // Warning only
+			cell("", condition("TEXT_CONTAINS", true, "@")),     // Not supported
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sheets": []interface{}{map[string]interface{}{
+				"data": []interface{}{map[string]interface{}{
+					"rowData": []interface{}{
+						map[string]interface{}{"values": header},
+						map[string]interface{}{"values": row},
+					},
+				}},
+			}},
+		})
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{SpreadsheetID: "test-id", SheetName: "Sheet1"},
+		option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+
+	rules, err := adaptor.ValidationRules(ctx)
+	if err != nil {
+		t.Fatalf("ValidationRules() error = %v", err)
+	}
+
+	if len(gotRanges) != 1 || gotRanges[0] != "Sheet1!A1:ZZ2" {
+		t.Errorf("ranges = %v, want [Sheet1!A1:ZZ2]", gotRanges)
+	}
+	if len(rules) != 3 {
+		t.Fatalf("ValidationRules() returned %d rules, want 3: %+v", len(rules), rules)
+	}
+
+	if rules[0].Column != "status" || len(rules[0].OneOf) != 2 || rules[0].OneOf[1] != "closed" {
+		t.Errorf("rules[0] = %+v, want status one of [open closed]", rules[0])
+	}
+	if rules[1].Column != "score" || rules[1].Min == nil || *rules[1].Min != 0 || rules[1].Max == nil || *rules[1].Max != 100 {
+		t.Errorf("rules[1] = %+v, want score between 0 and 100", rules[1])
+	}
+	if rules[2].Column != "active" || !rules[2].Boolean {
+		t.Errorf("rules[2] = %+v, want active boolean", rules[2])
+	}
+}
@@ -0,0 +1,44 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+)
+
+// Verify checks that the credentials are accepted, the spreadsheet can be
+// opened and it has the data sheet, so a misconfiguration shows up when the
+// adaptor is created rather than at the first Load. The error wraps
+// ErrUnauthenticated, ErrPermissionDenied, ErrSpreadsheetNotFound or
+// ErrSheetNotFound when the cause is known.
+func (a *SheetsAdaptor) Verify(ctx context.Context) error {
+	a.requests.read()
+	ss, err := a.service.Spreadsheets.Get(a.spreadsheetID).Fields("sheets.properties.title").Context(ctx).Do()
+	if err != nil {
+		return verifyError(a.spreadsheetID, err)
+	}
+
+	for _, sheet := range ss.Sheets {
+		if sheet.Properties != nil && sheet.Properties.Title == a.sheetName {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q in spreadsheet %s", ErrSheetNotFound, a.sheetName, a.spreadsheetID)
+}
+
+// Ping implements sheetkv.Pinger with Verify, so a sheet deleted or
+// unshared while the client runs shows as unreachable too
+func (a *SheetsAdaptor) Ping(ctx context.Context) error {
+	return a.Verify(ctx)
+}
+
+// verifyError classifies the error of opening the spreadsheet
+func verifyError(spreadsheetID string, err error) error {
+	switch cause := errorCause(err); cause {
+	case nil:
+		return fmt.Errorf("failed to get spreadsheet: %w", err)
+	case ErrUnauthenticated:
+		return fmt.Errorf("%w: %w", cause, err)
+	default:
+		return fmt.Errorf("%w: %s: %w", cause, spreadsheetID, err)
+	}
+}
@@ -0,0 +1,81 @@
+package googlesheets
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/api/option"
+)
+
+func TestSheetsAdaptor_Verify(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr error
+	}{
+		{"reachable", http.StatusOK, `{"sheets": [{"properties": {"title": "Other"}}, {"properties": {"title": "Data"}}]}`, nil},
+		{"missing sheet", http.StatusOK, `{"sheets": [{"properties": {"title": "Other"}}]}`, ErrSheetNotFound},
+		{"rejected credentials", http.StatusUnauthorized, `{"error": {"code": 401, "message": "invalid"}}`, ErrUnauthenticated},
+		{"not shared", http.StatusForbidden, `{"error": {"code": 403, "message": "denied"}}`, ErrPermissionDenied},
+		{"unknown spreadsheet", http.StatusNotFound, `{"error": {"code": 404, "message": "not found"}}`, ErrSpreadsheetNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			adaptor, err := NewSheetsAdaptor(ctx, Config{SpreadsheetID: "test-id", SheetName: "Data"},
+				option.WithEndpoint(server.URL), option.WithoutAuthentication())
+			if err != nil {
+				t.Fatalf("NewSheetsAdaptor() error = %v", err)
+			}
+
+			err = adaptor.Verify(ctx)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Verify() error = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := Config{SpreadsheetID: "test-id", SheetName: "Data"}
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr error
+	}{
+		{"valid", func(c *Config) {}, nil},
+		{"missing spreadsheet ID", func(c *Config) { c.SpreadsheetID = "" }, ErrMissingSpreadsheetID},
+		{"missing sheet name", func(c *Config) { c.SheetName = "" }, ErrMissingSheetName},
+		{"negative header row", func(c *Config) { c.HeaderRow = -1 }, ErrInvalidHeaderRow},
+		{"negative header rows", func(c *Config) { c.HeaderRows = -1 }, ErrInvalidHeaderRows},
+		{"invalid start column", func(c *Config) { c.StartColumn = "1A" }, ErrInvalidStartColumn},
+		{"negative max columns", func(c *Config) { c.MaxColumns = -1 }, ErrInvalidMaxColumns},
+		{"unknown value render", func(c *Config) { c.ValueRender = "RAW" }, ErrInvalidValueRender},
+		{"unknown date render", func(c *Config) { c.DateTimeRender = "ISO" }, ErrInvalidValueRender},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid
+			tt.modify(&config)
+			if err := config.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
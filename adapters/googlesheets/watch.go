@@ -0,0 +1,170 @@
+package googlesheets
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+)
+
+// WatchOptions configures a Drive push-notification channel
+type WatchOptions struct {
+	Address    string        // HTTPS URL receiving the notifications
+	ChannelID  string        // Channel ID (default: random)
+	Token      string        // Token echoed back in every notification, verified by the handler
+	Expiration time.Duration // Requested channel lifetime (default: decided by Drive, at most one day)
+}
+
+// WatchChannel identifies a registered Drive push-notification channel
+type WatchChannel struct {
+	ID         string
+	ResourceID string
+	Token      string
+	Expiration time.Time // Zero when Drive did not report one
+}
+
+// Watch registers a Drive watch channel for the spreadsheet, so Google calls
+// opts.Address whenever the file changes. Channels expire and must be renewed
+// by calling Watch again before Expiration.
+// Requires Config.DriveRevisions (or credentials with a Drive scope).
+func (a *SheetsAdaptor) Watch(ctx context.Context, opts WatchOptions) (*WatchChannel, error) {
+	if a.drive == nil {
+		return nil, fmt.Errorf("drive service is not configured")
+	}
+	if opts.Address == "" {
+		return nil, fmt.Errorf("watch address is required")
+	}
+
+	id := opts.ChannelID
+	if id == "" {
+		buf := make([]byte, 16)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("failed to generate channel ID: %w", err)
+		}
+		id = hex.EncodeToString(buf)
+	}
+
+	channel := &drive.Channel{
+		Id:      id,
+		Type:    "web_hook",
+		Address: opts.Address,
+		Token:   opts.Token,
+	}
+	if opts.Expiration > 0 {
+		channel.Expiration = time.Now().Add(opts.Expiration).UnixMilli()
+	}
+
+	resp, err := a.drive.Files.Watch(a.spreadsheetID, channel).SupportsAllDrives(true).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch spreadsheet: %w", err)
+	}
+
+	watch := &WatchChannel{ID: resp.Id, ResourceID: resp.ResourceId, Token: opts.Token}
+	if resp.Expiration > 0 {
+		watch.Expiration = time.UnixMilli(resp.Expiration)
+	}
+	return watch, nil
+}
+
+// StopWatch stops a channel registered by Watch
+func (a *SheetsAdaptor) StopWatch(ctx context.Context, channel *WatchChannel) error {
+	if a.drive == nil {
+		return fmt.Errorf("drive service is not configured")
+	}
+
+	err := a.drive.Channels.Stop(&drive.Channel{Id: channel.ID, ResourceId: channel.ResourceID}).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to stop watch channel: %w", err)
+	}
+	return nil
+}
+
+// Reloader is implemented by *sheetkv.Client
+type Reloader interface {
+	Reload(ctx context.Context) error
+}
+
+// NotificationHandler receives Drive push notifications and reloads a client
+type NotificationHandler struct {
+	Reloader Reloader
+	Channel  *WatchChannel   // Notifications for other channels are rejected
+	Delay    time.Duration   // Quiet period before reloading, coalescing bursts (default: 2s)
+	OnError  func(err error) // Called when a reload fails
+	Timeout  time.Duration   // Timeout of each reload (default: 30s)
+	mu       sync.Mutex
+	timer    *time.Timer
+}
+
+// NewNotificationHandler creates a handler reloading client on changes of channel
+func NewNotificationHandler(client Reloader, channel *WatchChannel) *NotificationHandler {
+	return &NotificationHandler{Reloader: client, Channel: channel}
+}
+
+// ServeHTTP implements http.Handler
+func (h *NotificationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.Channel != nil {
+		if r.Header.Get("X-Goog-Channel-ID") != h.Channel.ID || r.Header.Get("X-Goog-Channel-Token") != h.Channel.Token {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+	}
+
+	// "sync" is sent once when the channel is created
+	if state := r.Header.Get("X-Goog-Resource-State"); state != "" && state != "sync" {
+		h.schedule()
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// schedule (re)starts the reload timer so a burst of notifications causes a
+// single reload
+func (h *NotificationHandler) schedule() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delay := h.Delay
+	if delay <= 0 {
+		delay = 2 * time.Second
+	}
+
+	if h.timer != nil {
+		h.timer.Stop()
+	}
+	h.timer = time.AfterFunc(delay, h.reload)
+}
+
+// reload reloads the client, reporting failures to OnError
+func (h *NotificationHandler) reload() {
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := h.Reloader.Reload(ctx); err != nil && h.OnError != nil {
+		h.OnError(err)
+	}
+}
+
+// Stop cancels a pending reload
+func (h *NotificationHandler) Stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.timer != nil {
+		h.timer.Stop()
+		h.timer = nil
+	}
+}
@@ -0,0 +1,114 @@
+package googlesheets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+func TestSheetsAdaptor_Watch(t *testing.T) {
+	ctx := context.Background()
+
+	var watched, stopped drive.Channel
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/files/test-id/watch":
+			json.NewDecoder(r.Body).Decode(&watched)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id":         watched.Id,
+				"resourceId": "resource-1",
+				"expiration": "1700000000000",
+			})
+		case "/channels/stop":
+			json.NewDecoder(r.Body).Decode(&stopped)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	adaptor, err := NewSheetsAdaptor(ctx, Config{SpreadsheetID: "test-id", SheetName: "Users"},
+		option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSheetsAdaptor() error = %v", err)
+	}
+
+	if _, err := adaptor.Watch(ctx, WatchOptions{}); err == nil {
+		t.Error("Watch() without address should fail")
+	}
+
+	channel, err := adaptor.Watch(ctx, WatchOptions{Address: "https://example.com/hook", Token: "secret"})
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	if watched.Type != "web_hook" || watched.Address != "https://example.com/hook" || watched.Token != "secret" {
+		t.Errorf("watch request = %+v", watched)
+	}
+	if channel.ID == "" || channel.ID != watched.Id {
+		t.Errorf("channel ID = %q, want generated ID %q", channel.ID, watched.Id)
+	}
+	if channel.ResourceID != "resource-1" {
+		t.Errorf("ResourceID = %q, want resource-1", channel.ResourceID)
+	}
+	if !channel.Expiration.Equal(time.UnixMilli(1700000000000)) {
+		t.Errorf("Expiration = %v", channel.Expiration)
+	}
+
+	if err := adaptor.StopWatch(ctx, channel); err != nil {
+		t.Fatalf("StopWatch() error = %v", err)
+	}
+	if stopped.Id != channel.ID || stopped.ResourceId != "resource-1" {
+		t.Errorf("stop request = %+v", stopped)
+	}
+}
+
+type reloaderFunc func(ctx context.Context) error
+
+func (f reloaderFunc) Reload(ctx context.Context) error { return f(ctx) }
+
+func TestNotificationHandler(t *testing.T) {
+	var reloads int32
+	handler := NewNotificationHandler(reloaderFunc(func(ctx context.Context) error {
+		atomic.AddInt32(&reloads, 1)
+		return nil
+	}), &WatchChannel{ID: "ch-1", Token: "secret"})
+	handler.Delay = 20 * time.Millisecond
+	defer handler.Stop()
+
+	notify := func(id, token, state string) int {
+		req := httptest.NewRequest(http.MethodPost, "/hook", nil)
+		req.Header.Set("X-Goog-Channel-ID", id)
+		req.Header.Set("X-Goog-Channel-Token", token)
+		req.Header.Set("X-Goog-Resource-State", state)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := notify("ch-1", "wrong", "update"); code != http.StatusForbidden {
+		t.Errorf("wrong token status = %d, want %d", code, http.StatusForbidden)
+	}
+	if code := notify("ch-1", "secret", "sync"); code != http.StatusOK {
+		t.Errorf("sync status = %d, want %d", code, http.StatusOK)
+	}
+
+	// A burst of notifications triggers a single reload
+	for i := 0; i < 5; i++ {
+		notify("ch-1", "secret", "update")
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	if got := atomic.LoadInt32(&reloads); got != 1 {
+		t.Errorf("reloads = %d, want 1", got)
+	}
+}
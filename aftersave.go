@@ -0,0 +1,24 @@
+package sheetkv
+
+import (
+	"context"
+	"fmt"
+)
+
+// AfterSaveHook is called with the client's adapter after each sync that
+// wrote the sheet, see Config.AfterSave. Sheets are rewritten by syncs, so
+// the hook is the place to reapply what a sync may lose or what the rows it
+// added lack: formatting, filters, frozen panes.
+type AfterSaveHook func(ctx context.Context, adapter Adapter) error
+
+// afterSave runs Config.AfterSave. The records are saved by then, so a
+// failing hook fails the sync without the records being saved again.
+func (c *Client) afterSave(ctx context.Context) error {
+	if c.config.AfterSave == nil {
+		return nil
+	}
+	if err := c.config.AfterSave(ctx, c.adaptor); err != nil {
+		return fmt.Errorf("after save hook: %w", err)
+	}
+	return nil
+}
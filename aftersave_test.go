@@ -0,0 +1,57 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_AfterSave(t *testing.T) {
+	ctx := context.Background()
+	adapter := newMemoryAdapter([]string{"name"})
+	var calls int
+	var hookErr error
+	client := sheetkv.New(adapter, &sheetkv.Config{
+		DisableAutoSync: true,
+		AfterSave: func(ctx context.Context, got sheetkv.Adapter) error {
+			if got != adapter {
+				t.Errorf("AfterSave adapter = %v, want the client's", got)
+			}
+			if adapter.saveCount() == 0 {
+				t.Error("AfterSave called before the save")
+			}
+			calls++
+			return hookErr
+		},
+	})
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("AfterSave calls = %d after a sync without changes, want 0", calls)
+	}
+
+	client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "a"}})
+	if err := client.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("AfterSave calls = %d, want 1", calls)
+	}
+
+	t.Run("Error fails the sync", func(t *testing.T) {
+		hookErr = errors.New("format failed")
+		client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "b"}})
+		if err := client.Sync(); !errors.Is(err, hookErr) {
+			t.Errorf("Sync() error = %v, want the hook's", err)
+		}
+		hookErr = nil
+	})
+}
@@ -0,0 +1,85 @@
+package sheetkv_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestAnonymize(t *testing.T) {
+	records := []*sheetkv.Record{
+		{Key: 2, Values: map[string]interface{}{"name": "John Doe", "email": "john@corp.test", "phone": "03-1234-5678", "salary": int64(500), "dept": "Sales"}},
+		{Key: 3, Values: map[string]interface{}{"name": "Jane Roe", "email": "jane@corp.test", "phone": "", "salary": int64(600), "dept": "Dev"}},
+		{Key: 4, Values: map[string]interface{}{"name": "John Doe", "email": "john@corp.test", "salary": int64(700), "dept": "Sales"}},
+	}
+	rules := []sheetkv.AnonymizeRule{
+		{Column: "name", Faker: sheetkv.FakeName},
+		{Column: "email", Faker: sheetkv.FakeEmail, Deterministic: true, Secret: "s3cret"},
+		{Column: "phone", Faker: sheetkv.FakePhone},
+		{Column: "dept", Faker: sheetkv.FakeToken, Deterministic: true, Secret: "s3cret"},
+		{Column: "salary"},
+	}
+
+	anonymized := sheetkv.Anonymize(records, rules)
+	if len(anonymized) != 3 {
+		t.Fatalf("Anonymize() returned %d records, want 3", len(anonymized))
+	}
+
+	t.Run("Originals are untouched", func(t *testing.T) {
+		if records[0].Values["name"] != "John Doe" || records[0].Values["salary"] != int64(500) {
+			t.Errorf("Anonymize() modified the records: %v", records[0].Values)
+		}
+	})
+
+	t.Run("Values are replaced", func(t *testing.T) {
+		email := regexp.MustCompile(`^[a-z]+\.[a-z]+\d+@example\.com$`)
+		phone := regexp.MustCompile(`^\d{3}-555-01\d{2}$`)
+		for _, r := range anonymized {
+			if r.Values["name"] == "John Doe" || r.Values["name"] == "Jane Roe" {
+				t.Errorf("record %d: name was not replaced", r.Key)
+			}
+			if s, _ := r.Values["email"].(string); !email.MatchString(s) {
+				t.Errorf("record %d: email = %v", r.Key, r.Values["email"])
+			}
+			if _, ok := r.Values["salary"]; ok {
+				t.Errorf("record %d: salary should be removed", r.Key)
+			}
+		}
+		if s, _ := anonymized[0].Values["phone"].(string); !phone.MatchString(s) {
+			t.Errorf("phone = %v", anonymized[0].Values["phone"])
+		}
+		if anonymized[1].Values["phone"] != "" {
+			t.Errorf("empty values should stay empty, got %v", anonymized[1].Values["phone"])
+		}
+		if _, ok := anonymized[2].Values["phone"]; ok {
+			t.Error("missing values should stay missing")
+		}
+	})
+
+	t.Run("Deterministic rules", func(t *testing.T) {
+		if anonymized[0].Values["email"] != anonymized[2].Values["email"] {
+			t.Errorf("equal emails got %v and %v", anonymized[0].Values["email"], anonymized[2].Values["email"])
+		}
+		if anonymized[0].Values["email"] == anonymized[1].Values["email"] {
+			t.Error("different emails should get different fakes")
+		}
+
+		again := sheetkv.Anonymize(records, rules)
+		if again[0].Values["dept"] != anonymized[0].Values["dept"] {
+			t.Error("deterministic fakes should survive runs")
+		}
+
+		rules := []sheetkv.AnonymizeRule{{Column: "dept", Faker: sheetkv.FakeToken, Deterministic: true, Secret: "other"}}
+		if sheetkv.Anonymize(records, rules)[0].Values["dept"] == anonymized[0].Values["dept"] {
+			t.Error("another secret should give other fakes")
+		}
+	})
+
+	t.Run("Redact", func(t *testing.T) {
+		redacted := sheetkv.Anonymize(records, []sheetkv.AnonymizeRule{{Column: "name", Faker: sheetkv.Redact("***")}})
+		if redacted[1].Values["name"] != "***" {
+			t.Errorf("name = %v, want ***", redacted[1].Values["name"])
+		}
+	})
+}
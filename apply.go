@@ -0,0 +1,162 @@
+package sheetkv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Apply applies operations as one unit: OpAdd appends Record like Append,
+// setting its key, OpUpdate merges Record.Values into the record at
+// Record.Key like Update (nil values remove columns), and OpDelete removes
+// the record at Record.Key. Every operation is checked before the first is
+// applied, so on error the cache is unchanged, and no other call of the
+// client sees part of the batch. The next sync saves the changes together.
+//
+// Appends in Apply do not roll over on Config.CellLimit; they fail with
+// ErrCellLimit like other writes.
+func (c *Client) Apply(ops []Operation) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return fmt.Errorf("client is closed")
+	}
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
+
+	for _, op := range ops {
+		if op.Type == OpAdd {
+			// Appended keys follow the last row of the sheet
+			if err := c.readThroughTable(context.Background()); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	if err := c.checkOperations(ops); err != nil {
+		return err
+	}
+
+	for i, op := range ops {
+		var err error
+		switch op.Type {
+		case OpAdd:
+			err = c.appendRecord(op.Record)
+		case OpUpdate:
+			err = c.updateRecord(op.Record.Key, op.Record.Values)
+		case OpDelete:
+			err = c.deleteRecord(op.Record.Key)
+		}
+		if err != nil {
+			return errors.Join(fmt.Errorf("operation %d: %w", i, err), c.writeThrough(context.Background()))
+		}
+	}
+	return c.writeThrough(context.Background())
+}
+
+// checkOperations checks ops as applied in order, each against the state
+// the earlier ones leave: an update of a record deleted earlier in the
+// batch fails, records are validated with the changes of earlier updates,
+// and the cell limit counts the columns added by every operation. Callers
+// must hold c.mu.
+func (c *Client) checkOperations(ops []Operation) error {
+	written := make(map[int]map[string]interface{}) // Values left by earlier operations, nil once deleted
+	current := func(key int) (map[string]interface{}, bool) {
+		if values, ok := written[key]; ok {
+			return values, values != nil
+		}
+		record, err := c.cache.Get(key)
+		if err != nil {
+			return nil, false
+		}
+		return record.Values, true
+	}
+	nextKey := c.cache.maxKey() + 1
+	rows, schema := c.cache.maxKey(), c.cache.GetSchema()
+	checkCells := func(key int, values map[string]interface{}) error {
+		rows = max(rows, key)
+		schema = append(schema, c.addedColumns(schema, values)...)
+		return c.checkCellCount(rows*len(schema) + int(c.otherCells.Load()))
+	}
+
+	for i, op := range ops {
+		if op.Record == nil {
+			return fmt.Errorf("operation %d: record is required", i)
+		}
+
+		switch op.Type {
+		case OpAdd:
+			if err := c.checkFields(nextKey, op.Record.Values); err != nil {
+				return fmt.Errorf("operation %d: %w", i, err)
+			}
+			if err := checkCells(nextKey, op.Record.Values); err != nil {
+				return fmt.Errorf("operation %d: %w", i, err)
+			}
+			values := copyValues(op.Record.Values)
+			if err := c.validateRecord(&Record{Key: nextKey, Values: values}); err != nil {
+				return fmt.Errorf("operation %d: %w", i, err)
+			}
+			written[nextKey] = values
+			nextKey++
+		case OpUpdate:
+			existing, ok := current(op.Record.Key)
+			if !ok {
+				return fmt.Errorf("operation %d: %w", i, ErrKeyNotFound)
+			}
+			if err := c.checkFields(op.Record.Key, op.Record.Values); err != nil {
+				return fmt.Errorf("operation %d: %w", i, err)
+			}
+			if err := checkCells(op.Record.Key, op.Record.Values); err != nil {
+				return fmt.Errorf("operation %d: %w", i, err)
+			}
+			values := copyValues(existing)
+			for col, value := range op.Record.Values {
+				if value == nil {
+					delete(values, col)
+				} else {
+					values[col] = value
+				}
+			}
+			if err := c.validateRecord(&Record{Key: op.Record.Key, Values: values}); err != nil {
+				return fmt.Errorf("operation %d: %w", i, err)
+			}
+			if err := c.checkRowLock(op.Record.Key); err != nil {
+				return fmt.Errorf("operation %d: %w", i, err)
+			}
+			written[op.Record.Key] = values
+		case OpDelete:
+			if _, ok := current(op.Record.Key); !ok {
+				return fmt.Errorf("operation %d: %w", i, ErrKeyNotFound)
+			}
+			if err := c.checkRowLock(op.Record.Key); err != nil {
+				return fmt.Errorf("operation %d: %w", i, err)
+			}
+			written[op.Record.Key] = nil
+		default:
+			return fmt.Errorf("operation %d: unknown operation type %v", i, op.Type)
+		}
+	}
+	return nil
+}
+
+// checkWrite runs the checks of a write of values at key
+func (c *Client) checkWrite(key int, values map[string]interface{}) error {
+	if err := c.checkFields(key, values); err != nil {
+		return err
+	}
+	return c.checkCells(key, values)
+}
+
+// checkFields runs the checks of the columns and values of a write of
+// values at key
+func (c *Client) checkFields(key int, values map[string]interface{}) error {
+	if err := c.checkColumns(values); err != nil {
+		return err
+	}
+	if err := c.checkValues(values); err != nil {
+		return err
+	}
+	return c.checkID(key, values)
+}
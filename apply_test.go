@@ -0,0 +1,140 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_Apply(t *testing.T) {
+	newClient := func(t *testing.T) *sheetkv.Client {
+		adapter := newMemoryAdapter([]string{"name", "age"},
+			&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30)}},
+			&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane", "age": int64(25)}},
+		)
+		client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true, Columns: []string{"name", "age"}, StrictSchema: true})
+		if err := client.Initialize(context.Background()); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		t.Cleanup(func() { client.Close() })
+		return client
+	}
+
+	t.Run("Mixed operations", func(t *testing.T) {
+		client := newClient(t)
+		added := &sheetkv.Record{Values: map[string]interface{}{"name": "Bob"}}
+		err := client.Apply([]sheetkv.Operation{
+			{Type: sheetkv.OpAdd, Record: added},
+			{Type: sheetkv.OpAdd, Record: &sheetkv.Record{Values: map[string]interface{}{"name": "Ann"}}},
+			{Type: sheetkv.OpUpdate, Record: &sheetkv.Record{Key: 2, Values: map[string]interface{}{"age": int64(31)}}},
+			{Type: sheetkv.OpDelete, Record: &sheetkv.Record{Key: 3}},
+		})
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+
+		if added.Key != 4 {
+			t.Errorf("added key = %d, want 4", added.Key)
+		}
+		if record, _ := client.Get(5); record == nil || record.Values["name"] != "Ann" {
+			t.Errorf("Get(5) = %v, want Ann", record)
+		}
+		if record, _ := client.Get(2); record.Values["age"] != int64(31) || record.Values["name"] != "John" {
+			t.Errorf("Get(2) = %v, want John aged 31", record.Values)
+		}
+		if _, err := client.Get(3); err != sheetkv.ErrKeyNotFound {
+			t.Errorf("Get(3) error = %v, want ErrKeyNotFound", err)
+		}
+	})
+
+	t.Run("Failed operation leaves the cache unchanged", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			ops     []sheetkv.Operation
+			wantErr error
+		}{
+			{"unknown key", []sheetkv.Operation{
+				{Type: sheetkv.OpAdd, Record: &sheetkv.Record{Values: map[string]interface{}{"name": "Bob"}}},
+				{Type: sheetkv.OpUpdate, Record: &sheetkv.Record{Key: 9, Values: map[string]interface{}{"age": int64(1)}}},
+			}, sheetkv.ErrKeyNotFound},
+			{"deleted earlier in the batch", []sheetkv.Operation{
+				{Type: sheetkv.OpDelete, Record: &sheetkv.Record{Key: 2}},
+				{Type: sheetkv.OpUpdate, Record: &sheetkv.Record{Key: 2, Values: map[string]interface{}{"age": int64(1)}}},
+			}, sheetkv.ErrKeyNotFound},
+			{"unknown column", []sheetkv.Operation{
+				{Type: sheetkv.OpDelete, Record: &sheetkv.Record{Key: 3}},
+				{Type: sheetkv.OpAdd, Record: &sheetkv.Record{Values: map[string]interface{}{"nickname": "B"}}},
+			}, sheetkv.ErrUnknownColumn},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				client := newClient(t)
+				if err := client.Apply(tt.ops); !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Apply() error = %v, want %v", err, tt.wantErr)
+				}
+				if stats := client.Stats(); stats.Records != 2 || stats.Dirty != 0 {
+					t.Errorf("Stats() = %+v, want the 2 loaded records and nothing dirty", stats)
+				}
+			})
+		}
+	})
+
+	t.Run("Missing record", func(t *testing.T) {
+		client := newClient(t)
+		if err := client.Apply([]sheetkv.Operation{{Type: sheetkv.OpDelete}}); err == nil {
+			t.Error("Apply() should fail without a record")
+		}
+	})
+}
+
+func TestClient_ApplyChecksEarlierOperations(t *testing.T) {
+	newClient := func(t *testing.T, config *sheetkv.Config) *sheetkv.Client {
+		adapter := newMemoryAdapter([]string{"name", "age"},
+			&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30)}},
+			&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane", "age": int64(25)}},
+		)
+		client := sheetkv.New(adapter, config)
+		if err := client.Initialize(context.Background()); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		t.Cleanup(func() { client.Close() })
+		return client
+	}
+
+	t.Run("Columns added by each operation count", func(t *testing.T) {
+		// 3 rows x 2 columns; each new column adds 3 cells
+		client := newClient(t, &sheetkv.Config{DisableAutoSync: true, CellLimit: 10})
+		err := client.Apply([]sheetkv.Operation{
+			{Type: sheetkv.OpUpdate, Record: &sheetkv.Record{Key: 2, Values: map[string]interface{}{"email": "john@example.com"}}},
+			{Type: sheetkv.OpUpdate, Record: &sheetkv.Record{Key: 3, Values: map[string]interface{}{"phone": "555-0100"}}},
+		})
+		if !errors.Is(err, sheetkv.ErrCellLimit) {
+			t.Fatalf("Apply() error = %v, want ErrCellLimit", err)
+		}
+		if stats := client.Stats(); stats.Dirty != 0 {
+			t.Errorf("Stats() = %+v, want nothing dirty", stats)
+		}
+	})
+
+	t.Run("Updates are validated with earlier ones", func(t *testing.T) {
+		withinLimit := sheetkv.ValidatorFunc(func(r *sheetkv.Record) error {
+			if limit, ok := r.Values["limit"]; ok && r.GetAsInt64("age", 0) > limit.(int64) {
+				return errors.New("age over limit")
+			}
+			return nil
+		})
+		client := newClient(t, &sheetkv.Config{DisableAutoSync: true, Validators: []sheetkv.Validator{withinLimit}})
+		err := client.Apply([]sheetkv.Operation{
+			{Type: sheetkv.OpUpdate, Record: &sheetkv.Record{Key: 2, Values: map[string]interface{}{"limit": int64(35)}}},
+			{Type: sheetkv.OpUpdate, Record: &sheetkv.Record{Key: 2, Values: map[string]interface{}{"age": int64(40)}}},
+		})
+		if !errors.Is(err, sheetkv.ErrInvalidValue) {
+			t.Fatalf("Apply() error = %v, want ErrInvalidValue", err)
+		}
+		if record, _ := client.Get(2); record.Values["limit"] != nil {
+			t.Errorf("Get(2) = %v, want unchanged", record.Values)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package sheetkv
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// BlobStore keeps the content of attachments outside the sheet, which only
+// holds the link returned by Put (see Config.BlobStore)
+type BlobStore interface {
+	// Put uploads content under name and returns the link to store in the cell
+	Put(ctx context.Context, name string, content io.Reader) (string, error)
+
+	// Open returns the content behind a link returned by Put
+	Open(ctx context.Context, link string) (io.ReadCloser, error)
+}
+
+// SetAttachment uploads content to Config.BlobStore and stores its link in
+// column col of the record at key. The previous content, if any, is left in
+// the store.
+func (c *Client) SetAttachment(ctx context.Context, key int, col, name string, content io.Reader) error {
+	if c.config.BlobStore == nil {
+		return fmt.Errorf("no blob store configured")
+	}
+
+	// Fail before uploading content no record would reference
+	if _, err := c.Get(key); err != nil {
+		return err
+	}
+
+	// Not retried: content cannot be read twice
+	link, err := c.config.BlobStore.Put(ctx, name, content)
+	if err != nil {
+		return fmt.Errorf("failed to upload attachment: %w", err)
+	}
+
+	return c.Update(key, map[string]interface{}{col: link})
+}
+
+// GetAttachment opens the content linked in column col of the record at key.
+// The caller must close it.
+func (c *Client) GetAttachment(ctx context.Context, key int, col string) (io.ReadCloser, error) {
+	if c.config.BlobStore == nil {
+		return nil, fmt.Errorf("no blob store configured")
+	}
+
+	record, err := c.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	link := record.GetAsString(col, "")
+	if link == "" {
+		return nil, fmt.Errorf("no attachment in column %s of record %d", col, key)
+	}
+
+	var content io.ReadCloser
+	err = c.withRetry(ctx, func() error {
+		var err error
+		content, err = c.config.BlobStore.Open(ctx, link)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open attachment: %w", err)
+	}
+	return content, nil
+}
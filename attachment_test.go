@@ -0,0 +1,102 @@
+package sheetkv_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// memoryBlobStore keeps attachments in memory
+type memoryBlobStore struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+}
+
+func (s *memoryBlobStore) Put(ctx context.Context, name string, content io.Reader) (string, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.blobs == nil {
+		s.blobs = make(map[string][]byte)
+	}
+	link := fmt.Sprintf("mem://%d/%s", len(s.blobs)+1, name)
+	s.blobs[link] = data
+	return link, nil
+}
+
+func (s *memoryBlobStore) Open(ctx context.Context, link string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.blobs[link]
+	if !ok {
+		return nil, fmt.Errorf("blob not found: %s", link)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func TestClient_Attachment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Without blob store", func(t *testing.T) {
+		client := sheetkv.New(newMemoryAdapter([]string{"name"}), &sheetkv.Config{DisableAutoSync: true})
+		client.Initialize(ctx)
+		defer client.Close()
+
+		if err := client.SetAttachment(ctx, 2, "file", "a.txt", strings.NewReader("x")); err == nil {
+			t.Error("SetAttachment() without blob store should fail")
+		}
+	})
+
+	store := &memoryBlobStore{}
+	client := sheetkv.New(newMemoryAdapter([]string{"name"}), &sheetkv.Config{DisableAutoSync: true, BlobStore: store})
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	defer client.Close()
+	client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "Alice"}})
+
+	t.Run("Round trip", func(t *testing.T) {
+		if err := client.SetAttachment(ctx, 2, "report", "report.pdf", strings.NewReader("pdf data")); err != nil {
+			t.Fatalf("SetAttachment() error = %v", err)
+		}
+
+		record, _ := client.Get(2)
+		if link := record.GetAsString("report", ""); link != "mem://1/report.pdf" {
+			t.Errorf("report cell = %q, want the link", link)
+		}
+
+		content, err := client.GetAttachment(ctx, 2, "report")
+		if err != nil {
+			t.Fatalf("GetAttachment() error = %v", err)
+		}
+		defer content.Close()
+		if data, _ := io.ReadAll(content); string(data) != "pdf data" {
+			t.Errorf("GetAttachment() content = %q, want %q", data, "pdf data")
+		}
+	})
+
+	t.Run("Missing record", func(t *testing.T) {
+		err := client.SetAttachment(ctx, 99, "report", "x.pdf", strings.NewReader("x"))
+		if err != sheetkv.ErrKeyNotFound {
+			t.Errorf("SetAttachment() error = %v, want ErrKeyNotFound", err)
+		}
+		if len(store.blobs) != 1 {
+			t.Errorf("store holds %d blobs, want nothing uploaded", len(store.blobs))
+		}
+	})
+
+	t.Run("Empty column", func(t *testing.T) {
+		if _, err := client.GetAttachment(ctx, 2, "photo"); err == nil {
+			t.Error("GetAttachment() of an empty column should fail")
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package sheetkv
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AuditEntry is one row of the audit log
+type AuditEntry struct {
+	Time    time.Time
+	Actor   string
+	Op      OperationType
+	Key     int
+	Columns []string // Changed columns
+}
+
+// auditSchema lists the columns of the audit log sheet
+var auditSchema = []string{"time", "actor", "op", "key", "columns"}
+
+// queueAudit adds a mutation to the entries written after the next sync
+func (c *Client) queueAudit(m mutation) {
+	c.auditMu.Lock()
+	defer c.auditMu.Unlock()
+
+	c.auditQueue = append(c.auditQueue, AuditEntry{
+		Time:    m.time,
+		Actor:   c.config.AuditActor,
+		Op:      m.op,
+		Key:     m.key,
+		Columns: m.columns,
+	})
+}
+
+// pendingAudit returns the number of audit entries queued so far
+func (c *Client) pendingAudit() int {
+	c.auditMu.Lock()
+	defer c.auditMu.Unlock()
+	return len(c.auditQueue)
+}
+
+// flushAudit writes the first n queued entries to the audit adapter. Entries
+// that fail to be written stay queued for the next sync.
+func (c *Client) flushAudit(ctx context.Context, n int) error {
+	if c.config.AuditAdapter == nil || n == 0 {
+		return nil
+	}
+
+	c.auditMu.Lock()
+	entries := make([]AuditEntry, n)
+	copy(entries, c.auditQueue[:n])
+	c.auditMu.Unlock()
+
+	records := make([]*Record, len(entries))
+	for i, entry := range entries {
+		records[i] = &Record{Values: map[string]interface{}{
+			"time":    entry.Time.Format(c.timeFormat()),
+			"actor":   entry.Actor,
+			"op":      entry.Op.String(),
+			"key":     entry.Key,
+			"columns": strings.Join(entry.Columns, ","),
+		}}
+	}
+
+	err := c.withRetry(ctx, func() error {
+		return appendRecords(ctx, c.config.AuditAdapter, records, auditSchema)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+
+	c.auditMu.Lock()
+	c.auditQueue = c.auditQueue[n:]
+	c.auditMu.Unlock()
+	return nil
+}
+
+// appendRecords adds records after the last row of the adapter's sheet,
+// assigning their keys. Adapters implementing RecordAppender append in
+// place; others are loaded and saved back.
+func appendRecords(ctx context.Context, adapter Adapter, records []*Record, schema []string) error {
+	if appender, ok := adapter.(RecordAppender); ok {
+		return appender.AppendRecords(ctx, records, schema)
+	}
+
+	existing, current, err := adapter.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	maxKey := 1
+	for _, r := range existing {
+		if r.Key > maxKey {
+			maxKey = r.Key
+		}
+	}
+	for i, r := range records {
+		r.Key = maxKey + 1 + i
+	}
+
+	merged := current
+	for _, col := range schema {
+		if !containsString(merged, col) {
+			merged = append(merged, col)
+		}
+	}
+
+	return adapter.Save(ctx, append(existing, records...), merged, SyncStrategyGapPreserving)
+}
@@ -0,0 +1,118 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_AuditLog(t *testing.T) {
+	setup := func(t *testing.T) (*sheetkv.Client, *memoryAdapter, *memoryAdapter) {
+		data := newMemoryAdapter([]string{"name", "age"},
+			&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30)}},
+		)
+		audit := newMemoryAdapter(nil)
+		client := sheetkv.New(data, &sheetkv.Config{
+			DisableAutoSync: true,
+			MaxRetries:      1,
+			AuditAdapter:    audit,
+			AuditActor:      "batch-job",
+		})
+		if err := client.Initialize(context.Background()); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		return client, data, audit
+	}
+
+	t.Run("One row per synced mutation", func(t *testing.T) {
+		client, _, audit := setup(t)
+
+		client.Update(2, map[string]interface{}{"age": int64(31)})
+		client.Update(2, map[string]interface{}{"age": int64(31)}) // No change, not audited
+		record := &sheetkv.Record{Values: map[string]interface{}{"name": "Jane"}}
+		client.Append(record)
+		client.Delete(2)
+
+		if len(audit.records) != 0 {
+			t.Fatalf("audit rows before sync = %d, want 0", len(audit.records))
+		}
+		if err := client.Sync(); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+
+		if want := []string{"time", "actor", "op", "key", "columns"}; len(audit.schema) != len(want) {
+			t.Errorf("audit schema = %v, want %v", audit.schema, want)
+		}
+		want := []struct {
+			op      string
+			key     int
+			columns string
+		}{
+			{"update", 2, "age"},
+			{"add", record.Key, "name"},
+			{"delete", 2, "age,name"},
+		}
+		if len(audit.records) != len(want) {
+			t.Fatalf("audit rows = %d, want %d", len(audit.records), len(want))
+		}
+		for i, w := range want {
+			row := audit.records[i]
+			if row.Key != i+2 {
+				t.Errorf("row %d key = %d, want %d", i, row.Key, i+2)
+			}
+			if row.Values["op"] != w.op || row.Values["key"] != w.key || row.Values["columns"] != w.columns {
+				t.Errorf("row %d = %v, want %+v", i, row.Values, w)
+			}
+			if row.Values["actor"] != "batch-job" || row.Values["time"] == "" {
+				t.Errorf("row %d actor/time = %v/%v", i, row.Values["actor"], row.Values["time"])
+			}
+		}
+
+		// Entries are written once
+		client.Sync()
+		if len(audit.records) != len(want) {
+			t.Errorf("audit rows after second sync = %d, want %d", len(audit.records), len(want))
+		}
+	})
+
+	t.Run("Failed data save does not audit", func(t *testing.T) {
+		client, data, audit := setup(t)
+		data.saveErr = errors.New("boom")
+
+		client.Update(2, map[string]interface{}{"age": int64(31)})
+		if err := client.Sync(); err == nil {
+			t.Fatal("Sync() should fail")
+		}
+		if len(audit.records) != 0 {
+			t.Errorf("audit rows = %d, want 0", len(audit.records))
+		}
+
+		data.saveErr = nil
+		if err := client.Sync(); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+		if len(audit.records) != 1 {
+			t.Errorf("audit rows = %d, want 1", len(audit.records))
+		}
+	})
+
+	t.Run("Failed audit write is retried on the next sync", func(t *testing.T) {
+		client, _, audit := setup(t)
+		audit.saveErr = errors.New("boom")
+
+		client.Update(2, map[string]interface{}{"age": int64(31)})
+		if err := client.Sync(); err == nil {
+			t.Fatal("Sync() should report the audit failure")
+		}
+
+		audit.saveErr = nil
+		if err := client.Sync(); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+		if len(audit.records) != 1 {
+			t.Errorf("audit rows = %d, want 1", len(audit.records))
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package sheetkv
+
+import "strings"
+
+// BoolTokens lists texts that adapters read as booleans in addition to
+// true/false/TRUE/FALSE, such as "yes"/"no", "1"/"0" or "○"/"×". Tokens are
+// matched case-insensitively before numbers are parsed, and booleans are
+// written back as the first token so that columns round-trip.
+type BoolTokens struct {
+	True    []string // Texts read as true; the first one is written for true
+	False   []string // Texts read as false; the first one is written for false
+	Columns []string // Columns the tokens apply to (default: every column)
+}
+
+// applies reports whether the tokens are used for column
+func (t BoolTokens) applies(column string) bool {
+	return len(t.Columns) == 0 || containsString(t.Columns, column)
+}
+
+// Parse returns the boolean s stands for in column. ok is false when s is
+// not a token of the column.
+func (t BoolTokens) Parse(column, s string) (value bool, ok bool) {
+	if !t.applies(column) {
+		return false, false
+	}
+	for _, token := range t.True {
+		if strings.EqualFold(s, token) {
+			return true, true
+		}
+	}
+	for _, token := range t.False {
+		if strings.EqualFold(s, token) {
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// Format returns the token written for v in column. ok is false when no
+// token is configured for it.
+func (t BoolTokens) Format(column string, v bool) (token string, ok bool) {
+	if !t.applies(column) {
+		return "", false
+	}
+	tokens := t.False
+	if v {
+		tokens = t.True
+	}
+	if len(tokens) == 0 {
+		return "", false
+	}
+	return tokens[0], true
+}
@@ -0,0 +1,43 @@
+package sheetkv_test
+
+import (
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestBoolTokens(t *testing.T) {
+	tokens := sheetkv.BoolTokens{True: []string{"○", "yes"}, False: []string{"×", "no"}}
+
+	for _, tt := range []struct {
+		in       string
+		want, ok bool
+	}{
+		{"○", true, true},
+		{"YES", true, true},
+		{"no", false, true},
+		{"maybe", false, false},
+	} {
+		if got, ok := tokens.Parse("done", tt.in); got != tt.want || ok != tt.ok {
+			t.Errorf("Parse(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if got, ok := tokens.Format("done", true); got != "○" || !ok {
+		t.Errorf("Format(true) = %q, %v, want the first true token", got, ok)
+	}
+	if got, ok := tokens.Format("done", false); got != "×" || !ok {
+		t.Errorf("Format(false) = %q, %v, want the first false token", got, ok)
+	}
+
+	scoped := sheetkv.BoolTokens{True: []string{"1"}, False: []string{"0"}, Columns: []string{"active"}}
+	if _, ok := scoped.Parse("count", "1"); ok {
+		t.Error("Parse() outside Columns should not match")
+	}
+	if got, ok := scoped.Parse("active", "1"); !got || !ok {
+		t.Errorf("Parse(active, 1) = %v, %v, want true", got, ok)
+	}
+	if _, ok := (sheetkv.BoolTokens{}).Format("active", true); ok {
+		t.Error("Format() without tokens should not match")
+	}
+}
@@ -1,9 +1,11 @@
 package sheetkv
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"sync"
+	"sync/atomic"
 )
 
 // Cache manages in-memory storage of records
@@ -12,6 +14,21 @@ type Cache struct {
 	data   map[int]*Record // Key -> Record (row number)
 	dirty  map[int]bool    // 変更追跡
 	schema []string        // カラム名のリスト
+	index  *valueIndex     // Secondary index (nil when no columns are indexed)
+
+	saved       map[int]uint64 // Content hashes as last loaded or saved
+	savedSchema []string       // Schema as last loaded or saved
+	revisions   map[int]uint64 // Latest revision per key, kept after deletion
+	changeSeq   uint64         // Position of the last change in the change feed
+	changedAt   map[int]uint64 // Feed position of the last change per key, kept after deletion
+	queries     *queryCache    // Memoized query results (nil when disabled)
+	computed    []computedColumn
+	order       ColumnOrder // How new columns are placed in the schema
+	declared    []string    // Columns placed first by ColumnOrderDeclared
+	comparison  *comparison // String comparison of queries (nil: byte comparison)
+
+	shared    atomic.Bool  // data is read by a snapshot and is cloned before the next write
+	snapshots atomic.Int64 // Live snapshots; replaced records are not recycled while any exist
 }
 
 // NewCache creates a new Cache instance
@@ -20,9 +37,25 @@ func NewCache() *Cache {
 		data:   make(map[int]*Record),
 		dirty:  make(map[int]bool),
 		schema: []string{},
+		saved:  make(map[int]uint64),
 	}
 }
 
+// nextRevision increments and returns the revision of a key.
+// Callers must hold the write lock.
+func (c *Cache) nextRevision(key int) uint64 {
+	if c.revisions == nil {
+		c.revisions = make(map[int]uint64)
+	}
+	c.revisions[key]++
+	if c.changedAt == nil {
+		c.changedAt = make(map[int]uint64)
+	}
+	c.changeSeq++
+	c.changedAt[key] = c.changeSeq
+	return c.revisions[key]
+}
+
 // Get retrieves a record by key (row number)
 func (c *Cache) Get(key int) (*Record, error) {
 	c.mu.RLock()
@@ -45,9 +78,16 @@ func (c *Cache) Set(key int, record *Record) error {
 	// Ensure the record has the correct key
 	record.Key = key
 
-	// Store a copy
+	// Store a copy, recycling the version it replaces
+	c.own()
+	old := c.data[key]
 	c.data[key] = c.copyRecord(record)
+	c.compute(c.data[key])
+	c.data[key].Revision = c.nextRevision(key)
 	c.dirty[key] = true
+	c.reindex(old, c.data[key])
+	c.invalidateQueries(key, old, c.data[key])
+	c.release(old)
 
 	// Update schema
 	c.updateSchema(record)
@@ -65,8 +105,13 @@ func (c *Cache) Append(record *Record) error {
 	}
 
 	// Store a copy
+	c.own()
 	c.data[record.Key] = c.copyRecord(record)
+	c.compute(c.data[record.Key])
+	c.data[record.Key].Revision = c.nextRevision(record.Key)
 	c.dirty[record.Key] = true
+	c.reindex(nil, c.data[record.Key])
+	c.invalidateQueries(record.Key, nil, c.data[record.Key])
 
 	// Update schema
 	c.updateSchema(record)
@@ -94,8 +139,14 @@ func (c *Cache) Update(key int, updates map[string]interface{}) error {
 		}
 	}
 
+	c.compute(updatedRecord)
+	updatedRecord.Revision = c.nextRevision(key)
+	c.own()
 	c.data[key] = updatedRecord
 	c.dirty[key] = true
+	c.reindex(record, updatedRecord)
+	c.invalidateQueries(key, record, updatedRecord)
+	c.release(record)
 
 	// Update schema
 	c.updateSchema(updatedRecord)
@@ -108,12 +159,18 @@ func (c *Cache) Delete(key int) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if _, exists := c.data[key]; !exists {
+	record, exists := c.data[key]
+	if !exists {
 		return ErrKeyNotFound
 	}
 
+	c.own()
 	delete(c.data, key)
 	delete(c.dirty, key)
+	c.nextRevision(key)
+	c.reindex(record, nil)
+	c.invalidateQueries(key, record, nil)
+	c.release(record)
 
 	return nil
 }
@@ -128,14 +185,81 @@ func (c *Cache) Query(query Query) ([]*Record, error) {
 		return nil, fmt.Errorf("invalid query: %w", err)
 	}
 
-	// Collect all records
-	records := make([]*Record, 0, len(c.data))
-	for _, record := range c.data {
-		records = append(records, c.copyRecord(record))
+	return c.query(query), nil
+}
+
+// QueryCtx is like Query but stops with ctx.Err() when ctx is done before
+// the records are filtered
+func (c *Cache) QueryCtx(ctx context.Context, query Query) ([]*Record, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if err := ValidateQuery(query); err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+
+	return c.queryCtx(ctx, query)
+}
+
+// query runs a validated query. Callers must hold the read lock.
+func (c *Cache) query(query Query) []*Record {
+	results, _ := c.queryCtx(context.Background(), query)
+	return results
+}
+
+// queryCtx runs a validated query, checking ctx between batches of records.
+// Callers must hold the read lock.
+func (c *Cache) queryCtx(ctx context.Context, query Query) ([]*Record, error) {
+	return c.queryPlanned(ctx, query, nil)
+}
+
+// queryPlanned runs a validated query like queryCtx. With plan, the
+// conditions answered by the index are those resolved when the query was
+// prepared. Callers must hold the read lock.
+func (c *Cache) queryPlanned(ctx context.Context, query Query, plan *indexPlan) ([]*Record, error) {
+	// Serve memoized results; custom orderings cannot be keyed
+	cacheable := c.queries != nil && query.SortFunc == nil
+	if cacheable {
+		if keys, ok := c.queries.get(query); ok {
+			results := make([]*Record, 0, len(keys))
+			for _, key := range keys {
+				results = append(results, c.copyRecord(c.data[key]))
+			}
+			return results, nil
+		}
+	}
+
+	// Collect candidate records, narrowed down by the index when possible
+	var records []*Record
+	candidates := c.indexCandidates
+	if plan != nil {
+		candidates = plan.candidates(c)
+	}
+	if keys, _, ok := candidates(query); ok {
+		records = make([]*Record, 0, len(keys))
+		for key := range keys {
+			if record, exists := c.data[key]; exists {
+				records = append(records, record)
+			}
+		}
+	} else {
+		records = make([]*Record, 0, len(c.data))
+		for _, record := range c.data {
+			records = append(records, record)
+		}
 	}
 
-	// Apply query
-	results := ApplyQuery(records, query)
+	// Apply query to the stored records and copy only the matches
+	results, err := applyQuery(ctx, records, query, c.comparison)
+	if err != nil {
+		return nil, err
+	}
+	if cacheable {
+		c.queries.put(query, results)
+	}
+	for i, record := range results {
+		results[i] = c.copyRecord(record)
+	}
 
 	return results, nil
 }
@@ -174,6 +298,23 @@ func (c *Cache) GetDirtyKeys() []int {
 	return keys
 }
 
+// deletedKeys returns the keys of records deleted since they were last
+// loaded or saved
+func (c *Cache) deletedKeys() []int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var keys []int
+	for key := range c.saved {
+		if _, exists := c.data[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Ints(keys)
+	return keys
+}
+
 // ClearDirty marks all records as clean
 func (c *Cache) ClearDirty() {
 	c.mu.Lock()
@@ -207,18 +348,189 @@ func (c *Cache) Load(records []*Record, schema []string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	// Clear existing data
-	c.data = make(map[int]*Record)
-	c.dirty = make(map[int]bool)
+	// Records whose content changed get a new revision, others keep theirs
+	previous := c.data
 
 	// Load new data
+	c.data = make(map[int]*Record, len(records))
+	c.shared.Store(false)
+	c.dirty = make(map[int]bool)
+	c.saved = make(map[int]uint64, len(records))
 	for _, record := range records {
-		c.data[record.Key] = c.copyRecord(record)
+		stored := c.copyRecord(record)
+		c.compute(stored)
+		hash := c.hash(stored)
+		if prev, ok := previous[record.Key]; ok && c.hash(prev) == hash {
+			stored.Revision = prev.Revision
+		} else {
+			stored.Revision = c.nextRevision(record.Key)
+		}
+		c.data[record.Key] = stored
+		c.saved[record.Key] = c.hash(record)
+		if c.saved[record.Key] != hash {
+			c.dirty[record.Key] = true // A stored computed column is out of date
+		}
+	}
+
+	// Recycle the replaced records, counting the removed ones as changes
+	for key, record := range previous {
+		if _, ok := c.data[key]; !ok {
+			c.nextRevision(key)
+		}
+		c.release(record)
 	}
+	c.rebuildIndex()
+	c.resetQueries()
 
 	// Set schema
 	c.schema = make([]string, len(schema))
 	copy(c.schema, schema)
+	c.savedSchema = make([]string, len(schema))
+	copy(c.savedSchema, schema)
+	c.addStoredColumns()
+	c.arrangeSchema()
+}
+
+// HasChanges reports whether the data differs from what was last loaded or
+// saved. Records that were marked dirty but hold identical values (for
+// example rows "touched" idempotently) do not count as changes, while
+// deleted records do.
+func (c *Cache) HasChanges() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.data) != len(c.saved) || !equalSchemas(c.schema, c.savedSchema) {
+		return true
+	}
+
+	// With equal counts, any added or replaced record is dirty
+	for key := range c.dirty {
+		hash, ok := c.saved[key]
+		if !ok || hash != c.hash(c.data[key]) {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkSaved records the given records and schema as the persisted state and
+// clears the dirty flag of every record that still matches it. Records
+// modified while the save was in flight stay dirty.
+func (c *Cache) MarkSaved(records []*Record, schema []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.saved = make(map[int]uint64, len(records))
+	for _, record := range records {
+		c.saved[record.Key] = c.hash(record)
+	}
+	c.savedSchema = make([]string, len(schema))
+	copy(c.savedSchema, schema)
+
+	for key := range c.dirty {
+		record, exists := c.data[key]
+		if !exists {
+			delete(c.dirty, key)
+			continue
+		}
+		if hash, ok := c.saved[key]; ok && hash == c.hash(record) {
+			delete(c.dirty, key)
+		}
+	}
+}
+
+// markRecordSaved records the current version of key, or its absence, as
+// persisted
+func (c *Cache) markRecordSaved(key int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if record, exists := c.data[key]; exists {
+		c.saved[key] = c.hash(record)
+	} else {
+		delete(c.saved, key)
+	}
+	delete(c.dirty, key)
+}
+
+// isDirty reports whether the record at key was modified or deleted since
+// it was last loaded or saved
+func (c *Cache) isDirty(key int) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.dirty[key] || c.deleted(key)
+}
+
+// deleted reports whether the record at key was deleted since it was last
+// loaded or saved. Callers must hold the lock.
+func (c *Cache) deleted(key int) bool {
+	if _, saved := c.saved[key]; !saved {
+		return false
+	}
+	_, exists := c.data[key]
+	return !exists
+}
+
+// refreshRecord replaces the record at key with its version read from the
+// backend, nil when the row no longer exists, and records it as persisted.
+// Columns of schema missing from the cache are added. A dirty or deleted
+// record is kept that way, so local changes win until they are saved.
+func (c *Cache) refreshRecord(key int, record *Record, schema []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.dirty[key] || c.deleted(key) {
+		return
+	}
+
+	c.own()
+	old := c.data[key]
+	if record == nil {
+		if old == nil {
+			return
+		}
+		delete(c.data, key)
+		delete(c.saved, key)
+		c.nextRevision(key)
+		c.reindex(old, nil)
+		c.invalidateQueries(key, old, nil)
+		c.release(old)
+		return
+	}
+
+	stored := c.copyRecord(record)
+	stored.Key = key
+	c.compute(stored)
+	if old != nil && c.hash(old) == c.hash(stored) {
+		stored.Revision = old.Revision
+	} else {
+		stored.Revision = c.nextRevision(key)
+	}
+	c.data[key] = stored
+	c.saved[key] = c.hash(record)
+	if c.saved[key] != c.hash(stored) {
+		c.dirty[key] = true // A stored computed column is out of date
+	}
+	c.reindex(old, stored)
+	c.invalidateQueries(key, old, stored)
+	c.release(old)
+
+	// New columns of the sheet are persisted already
+	unchanged := equalSchemas(c.schema, c.savedSchema)
+	for _, col := range schema {
+		if !containsString(c.schema, col) {
+			c.schema = append(c.schema, col)
+		}
+		if !containsString(c.savedSchema, col) {
+			c.savedSchema = append(c.savedSchema, col)
+		}
+	}
+	c.updateSchema(stored)
+	c.arrangeSchema()
+	if unchanged {
+		c.savedSchema = append(c.savedSchema[:0:0], c.schema...)
+	}
 }
 
 // Size returns the number of records
@@ -234,17 +546,182 @@ func (c *Cache) Clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	for key := range c.data {
+		c.nextRevision(key) // The removal is a change of the feed
+	}
+	c.releaseAll()
 	c.data = make(map[int]*Record)
+	c.shared.Store(false)
 	c.dirty = make(map[int]bool)
 	c.schema = []string{}
+	c.saved = make(map[int]uint64)
+	c.savedSchema = nil
+	c.revisions = nil
+	c.rebuildIndex()
+	c.resetQueries()
+}
+
+// SetQueryCacheSize enables memoization of up to size query results (0
+// disables it). Cached results are dropped as soon as a write could change
+// them, so callers always see current data.
+func (c *Cache) SetQueryCacheSize(size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if size <= 0 {
+		c.queries = nil
+		return
+	}
+	c.queries = newQueryCache(size)
+}
+
+// invalidateQueries drops the memoized results affected by a write
+func (c *Cache) invalidateQueries(key int, old, updated *Record) {
+	if c.queries != nil {
+		c.queries.invalidate(key, old, updated)
+	}
+}
+
+// resetQueries drops every memoized result
+func (c *Cache) resetQueries() {
+	if c.queries != nil {
+		c.queries.reset()
+	}
+}
+
+// SetIndexColumns sets the columns maintained in the secondary index.
+// Equality and "in" conditions on indexed columns are answered without
+// scanning every record.
+func (c *Cache) SetIndexColumns(columns []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(columns) == 0 {
+		c.index = nil
+		return
+	}
+	c.index = newValueIndex(columns)
+	c.rebuildIndex()
+}
+
+// Index returns a serializable snapshot of the secondary index, or nil when
+// no columns are indexed
+func (c *Cache) Index() *Index {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.index == nil {
+		return nil
+	}
+	return c.index.snapshot()
+}
+
+// indexCandidates returns the candidate keys for a query from the index
+func (c *Cache) indexCandidates(query Query) (map[int]struct{}, string, bool) {
+	if c.index == nil {
+		return nil, "", false
+	}
+	if c.comparison == nil {
+		return c.index.candidates(query.Conditions)
+	}
+
+	// The index holds exact values, which collated conditions may not match
+	conditions := make([]Condition, 0, len(query.Conditions))
+	for _, cond := range query.Conditions {
+		if c.comparison.collation(cond.Column) == nil {
+			conditions = append(conditions, cond)
+		}
+	}
+	return c.index.candidates(conditions)
+}
+
+// indexPlan holds the positions of the conditions of a prepared query the
+// index can answer, so runs skip looking for them
+type indexPlan struct {
+	conditions []int
+}
+
+// planIndex returns the plan of the conditions of query the index answers:
+// equality and "in" conditions on indexed columns without a collation
+func (c *Cache) planIndex(query Query) *indexPlan {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	plan := &indexPlan{}
+	if c.index == nil {
+		return plan
+	}
+	for i, cond := range query.Conditions {
+		if _, indexed := c.index.columns[cond.Column]; !indexed {
+			continue
+		}
+		if cond.Operator != "==" && cond.Operator != "in" {
+			continue
+		}
+		if c.comparison != nil && c.comparison.collation(cond.Column) != nil {
+			continue
+		}
+		plan.conditions = append(plan.conditions, i)
+	}
+	return plan
+}
+
+// candidates returns the lookup of the candidate keys of the planned
+// conditions in the index of cache
+func (p *indexPlan) candidates(cache *Cache) func(Query) (map[int]struct{}, string, bool) {
+	return func(query Query) (map[int]struct{}, string, bool) {
+		if cache.index == nil || len(p.conditions) == 0 {
+			return nil, "", false
+		}
+		conditions := make([]Condition, len(p.conditions))
+		for i, position := range p.conditions {
+			conditions[i] = query.Conditions[position]
+		}
+		return cache.index.candidates(conditions)
+	}
+}
+
+// reindex replaces the index entries of old with those of updated; either may be nil
+func (c *Cache) reindex(old, updated *Record) {
+	if c.index == nil {
+		return
+	}
+	if old != nil {
+		c.index.remove(old)
+	}
+	if updated != nil {
+		c.index.add(updated)
+	}
+}
+
+// rebuildIndex recreates the index from the stored records
+func (c *Cache) rebuildIndex() {
+	if c.index == nil {
+		return
+	}
+	columns := make([]string, 0, len(c.index.columns))
+	for col := range c.index.columns {
+		columns = append(columns, col)
+	}
+	c.index = newValueIndex(columns)
+	c.index.normalize = c.comparison.normalizes()
+	for _, record := range c.data {
+		c.index.add(record)
+	}
+}
+
+// releaseAll returns every stored record to the pool
+func (c *Cache) releaseAll() {
+	for _, record := range c.data {
+		c.release(record)
+	}
 }
 
 // copyRecord creates a deep copy of a record
 func (c *Cache) copyRecord(record *Record) *Record {
-	copy := &Record{
-		Key:    record.Key,
-		Values: make(map[string]interface{}),
-	}
+	copy := acquireRecord()
+	copy.Key = record.Key
+	copy.Revision = record.Revision
 
 	for k, v := range record.Values {
 		copy.Values[k] = v
@@ -261,12 +738,19 @@ func (c *Cache) updateSchema(record *Record) {
 		existing[col] = true
 	}
 
-	// Add new columns from the record
+	// Add new columns from the record, in a stable order
+	var added []string
 	for col := range record.Values {
-		if !existing[col] {
-			c.schema = append(c.schema, col)
+		if !existing[col] && !c.isVirtual(col) {
+			added = append(added, col)
 		}
 	}
+	if len(added) == 0 {
+		return
+	}
+	sort.Strings(added)
+	c.schema = append(c.schema, added...)
+	c.arrangeSchema()
 }
 
 // MergeSchemas merges current schema with sheet schema preserving order
@@ -1,6 +1,8 @@
 package sheetkv_test
 
 import (
+	"context"
+	"errors"
 	"reflect"
 	"sync"
 	"testing"
@@ -235,6 +237,74 @@ func TestCache_Query(t *testing.T) {
 	})
 }
 
+// cancelAfter is a context reporting itself canceled after n checks
+type cancelAfter struct {
+	context.Context
+	n      int
+	checks int
+}
+
+func (c *cancelAfter) Err() error {
+	c.checks++
+	if c.checks > c.n {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestCache_QueryCtx(t *testing.T) {
+	cache := sheetkv.NewCache()
+	for key := 2; key < 5002; key++ {
+		cache.Set(key, &sheetkv.Record{Values: map[string]interface{}{"n": key}})
+	}
+	query := sheetkv.Query{Conditions: []sheetkv.Condition{{Column: "n", Operator: ">", Value: 100}}}
+
+	t.Run("Completes", func(t *testing.T) {
+		results, err := cache.QueryCtx(context.Background(), query)
+		if err != nil {
+			t.Fatalf("QueryCtx() error = %v", err)
+		}
+		if len(results) != 4901 {
+			t.Errorf("QueryCtx() returned %d records, want 4901", len(results))
+		}
+	})
+
+	t.Run("Canceled between batches", func(t *testing.T) {
+		ctx := &cancelAfter{Context: context.Background(), n: 2}
+		results, err := cache.QueryCtx(ctx, query)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("QueryCtx() error = %v, want context.Canceled", err)
+		}
+		if results != nil {
+			t.Errorf("QueryCtx() returned %d records, want none", len(results))
+		}
+		if ctx.checks != 3 {
+			t.Errorf("context checked %d times, want 3 (stopped at the third batch)", ctx.checks)
+		}
+	})
+}
+
+func TestClient_QueryCtx(t *testing.T) {
+	adapter := newMemoryAdapter([]string{"name"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John"}},
+	)
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results, err := client.QueryCtx(ctx, sheetkv.Query{})
+	if err != nil || len(results) != 1 {
+		t.Errorf("QueryCtx() = %d records, %v, want 1 record", len(results), err)
+	}
+
+	cancel()
+	if _, err := client.QueryCtx(ctx, sheetkv.Query{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("QueryCtx() error = %v, want context.Canceled", err)
+	}
+}
+
 func TestCache_DirtyTracking(t *testing.T) {
 	cache := sheetkv.NewCache()
 
@@ -570,3 +640,56 @@ func containsAll(slice []string, elements []string) bool {
 	}
 	return true
 }
+
+func TestCache_Revisions(t *testing.T) {
+	cache := sheetkv.NewCache()
+	cache.Load([]*sheetkv.Record{
+		{Key: 2, Values: map[string]interface{}{"name": "John"}},
+		{Key: 3, Values: map[string]interface{}{"name": "Jane"}},
+	}, []string{"name"})
+
+	revision := func(key int) uint64 {
+		t.Helper()
+		record, err := cache.Get(key)
+		if err != nil {
+			t.Fatalf("Get(%d) error = %v", key, err)
+		}
+		return record.Revision
+	}
+
+	if got := revision(2); got != 1 {
+		t.Errorf("revision after load = %d, want 1", got)
+	}
+
+	t.Run("Every mutation increments the revision", func(t *testing.T) {
+		cache.Update(2, map[string]interface{}{"name": "Johnny"})
+		cache.Set(2, &sheetkv.Record{Values: map[string]interface{}{"name": "John"}})
+		if got := revision(2); got != 3 {
+			t.Errorf("revision = %d, want 3", got)
+		}
+		if got := revision(3); got != 1 {
+			t.Errorf("untouched revision = %d, want 1", got)
+		}
+	})
+
+	t.Run("Recreated keys continue after deletion", func(t *testing.T) {
+		cache.Delete(3)
+		cache.Append(&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Bob"}})
+		if got := revision(3); got != 3 {
+			t.Errorf("revision = %d, want 3", got)
+		}
+	})
+
+	t.Run("Reload keeps revisions of unchanged records", func(t *testing.T) {
+		cache.Load([]*sheetkv.Record{
+			{Key: 2, Values: map[string]interface{}{"name": "John"}},
+			{Key: 3, Values: map[string]interface{}{"name": "Robert"}},
+		}, []string{"name"})
+		if got := revision(2); got != 3 {
+			t.Errorf("unchanged revision = %d, want 3", got)
+		}
+		if got := revision(3); got != 4 {
+			t.Errorf("changed revision = %d, want 4", got)
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package sheetkv
+
+import "context"
+
+// GetCell returns a single value of a record without copying the record.
+// ok is false when the record or the column does not exist.
+func (c *Cache) GetCell(key int, col string) (value interface{}, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	record, exists := c.data[key]
+	if !exists {
+		return nil, false
+	}
+	value, ok = record.Values[col]
+	return value, ok
+}
+
+// GetCell returns a single value of a record without copying the record.
+// ok is false when the client is closed, the record or column does not
+// exist, or reading through Config.ReadThroughTTL fails.
+func (c *Client) GetCell(key int, col string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, false
+	}
+	if err := c.readThroughKey(context.Background(), key); err != nil {
+		return nil, false
+	}
+
+	return c.cache.GetCell(key, col)
+}
+
+// SetCell updates a single value of an existing record. The whole record is
+// marked dirty, as with Update; a nil value removes the column.
+func (c *Client) SetCell(key int, col string, value interface{}) error {
+	return c.Update(key, map[string]interface{}{col: value})
+}
@@ -0,0 +1,54 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_Cells(t *testing.T) {
+	adapter := newMemoryAdapter([]string{"name", "age"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30)}},
+	)
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	t.Run("GetCell", func(t *testing.T) {
+		if v, ok := client.GetCell(2, "name"); !ok || v != "John" {
+			t.Errorf("GetCell(2, name) = %v, %v, want John, true", v, ok)
+		}
+		if _, ok := client.GetCell(2, "email"); ok {
+			t.Error("GetCell() of a missing column should report false")
+		}
+		if _, ok := client.GetCell(99, "name"); ok {
+			t.Error("GetCell() of a missing record should report false")
+		}
+	})
+
+	t.Run("SetCell", func(t *testing.T) {
+		if err := client.SetCell(2, "age", int64(31)); err != nil {
+			t.Fatalf("SetCell() error = %v", err)
+		}
+		if v, _ := client.GetCell(2, "age"); v != int64(31) {
+			t.Errorf("age = %v, want 31", v)
+		}
+		if v, _ := client.GetCell(2, "name"); v != "John" {
+			t.Errorf("name = %v, want John (other cells untouched)", v)
+		}
+
+		if err := client.SetCell(99, "age", 1); !errors.Is(err, sheetkv.ErrKeyNotFound) {
+			t.Errorf("SetCell() error = %v, want %v", err, sheetkv.ErrKeyNotFound)
+		}
+
+		if err := client.Sync(); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+		if got := adapter.saveCount(); got != 1 {
+			t.Errorf("saves = %d, want 1", got)
+		}
+	})
+}
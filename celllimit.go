@@ -0,0 +1,196 @@
+package sheetkv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// CellLimitPolicy decides what happens to writes that would take the
+// spreadsheet past Config.CellLimit
+type CellLimitPolicy int
+
+const (
+	// CellLimitError rejects the write with ErrCellLimit
+	CellLimitError CellLimitPolicy = iota
+	// CellLimitNewTab rolls appends over to a new tab returned by Config.OnCellLimit.
+	// The full tab still counts against the limit unless OnCellLimit moves it away.
+	CellLimitNewTab
+	// CellLimitNewSpreadsheet rolls appends over to a new spreadsheet returned by Config.OnCellLimit
+	CellLimitNewSpreadsheet
+)
+
+// String returns the name of the policy
+func (p CellLimitPolicy) String() string {
+	switch p {
+	case CellLimitError:
+		return "error"
+	case CellLimitNewTab:
+		return "new tab"
+	case CellLimitNewSpreadsheet:
+		return "new spreadsheet"
+	default:
+		return fmt.Sprintf("CellLimitPolicy(%d)", int(p))
+	}
+}
+
+// OverflowFunc returns the adapter a client continues in once its sheet
+// reaches the cell limit: a new tab or a new spreadsheet, depending on policy
+type OverflowFunc func(ctx context.Context, policy CellLimitPolicy) (Adapter, error)
+
+// defaultCellLimitWarning is the fraction of the limit reported as near it
+const defaultCellLimitWarning = 0.8
+
+// maxKey returns the highest key, or 1 (the header row) when empty
+func (c *Cache) maxKey() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	maxKey := 1
+	for key := range c.data {
+		if key > maxKey {
+			maxKey = key
+		}
+	}
+	return maxKey
+}
+
+// cells returns the cells of the spreadsheet, counting the data of the
+// client's sheet as its rows (keys are row numbers) times its columns
+func (c *Client) cells() int {
+	return c.cache.maxKey()*len(c.cache.GetSchema()) + int(c.otherCells.Load())
+}
+
+// projectedCells returns the cells of the spreadsheet once values are
+// written at key
+func (c *Client) projectedCells(key int, values map[string]interface{}) int {
+	rows := max(c.cache.maxKey(), key)
+	schema := c.cache.GetSchema()
+	columns := len(schema) + len(c.addedColumns(schema, values))
+	return rows*columns + int(c.otherCells.Load())
+}
+
+// addedColumns returns the columns a write of values adds to schema
+func (c *Client) addedColumns(schema []string, values map[string]interface{}) []string {
+	var added []string
+	for col, value := range values {
+		if value != nil && !containsString(schema, col) && !c.cache.isComputed(col) {
+			added = append(added, col)
+		}
+	}
+	return added
+}
+
+// checkCells enforces Config.CellLimit on a write of values at key.
+// Callers must hold c.mu.
+func (c *Client) checkCells(key int, values map[string]interface{}) error {
+	if c.config.CellLimit <= 0 {
+		return nil
+	}
+	return c.checkCellCount(c.projectedCells(key, values))
+}
+
+// checkCellCount returns ErrCellLimit when cells exceed Config.CellLimit
+func (c *Client) checkCellCount(cells int) error {
+	if limit := c.config.CellLimit; limit > 0 && cells > limit {
+		return fmt.Errorf("%w: %d cells, at most %d", ErrCellLimit, cells, limit)
+	}
+	return nil
+}
+
+// checkAppendCells enforces Config.CellLimit on an append, rolling over to
+// the adapter returned by Config.OnCellLimit when the policy asks for it.
+// Callers must hold c.mu.
+func (c *Client) checkAppendCells(values map[string]interface{}) error {
+	err := c.checkCells(c.cache.maxKey()+1, values)
+	if err == nil || c.config.CellLimitPolicy == CellLimitError || c.config.OnCellLimit == nil {
+		return err
+	}
+
+	if err := c.rollover(context.Background()); err != nil {
+		return fmt.Errorf("failed to roll over to a %s: %w", c.config.CellLimitPolicy, err)
+	}
+	return c.checkCells(c.cache.maxKey()+1, values)
+}
+
+// rollover saves the data of the full sheet, then switches to the adapter
+// returned by Config.OnCellLimit and loads it. Callers must hold c.mu.
+func (c *Client) rollover(ctx context.Context) error {
+	// Keep the periodic sync from saving while the adapter changes
+	if c.syncManager != nil {
+		c.syncManager.syncMutex.Lock()
+		defer c.syncManager.syncMutex.Unlock()
+	}
+
+	if err := c.saveToAdapter(ctx, SyncStrategyGapPreserving); err != nil {
+		return err
+	}
+
+	adapter, err := c.config.OnCellLimit(ctx, c.config.CellLimitPolicy)
+	if err != nil {
+		return err
+	}
+
+	c.adaptor = adapter
+	c.index = nil
+	c.cache.Clear()
+	c.loaded.Store(false)
+	return c.loadFromAdapter(ctx)
+}
+
+// countOtherCells refreshes the cells of the other tabs when the adapter can
+// count them
+func (c *Client) countOtherCells(ctx context.Context) error {
+	counter, ok := c.adaptor.(CellCounter)
+	if !ok || c.config.CellLimit <= 0 {
+		return nil
+	}
+
+	var cells int
+	err := c.withRetry(ctx, func() error {
+		var err error
+		cells, err = counter.OtherCells(ctx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to count cells: %w", err)
+	}
+	c.otherCells.Store(int64(cells))
+	return nil
+}
+
+// nearCellLimit reports whether cells reached the warning fraction of the limit
+func (c *Client) nearCellLimit(cells int) bool {
+	if c.config.CellLimit <= 0 {
+		return false
+	}
+	warning := c.config.CellLimitWarning
+	if warning <= 0 {
+		warning = defaultCellLimitWarning
+	}
+	return float64(cells) >= warning*float64(c.config.CellLimit)
+}
+
+// Limits returns the limits and usage reported by the adapter. It fails with
+// errors.ErrUnsupported when the adapter does not implement LimitsReporter.
+func (c *Client) Limits(ctx context.Context) (*Limits, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, fmt.Errorf("client is closed")
+	}
+
+	reporter, ok := c.adaptor.(LimitsReporter)
+	if !ok {
+		return nil, fmt.Errorf("adapter does not report limits: %w", errors.ErrUnsupported)
+	}
+
+	var limits *Limits
+	err := c.withRetry(ctx, func() error {
+		var err error
+		limits, err = reporter.Limits(ctx)
+		return err
+	})
+	return limits, err
+}
@@ -0,0 +1,131 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// countingAdapter is a memory adapter reporting the cells of other tabs
+type countingAdapter struct {
+	*memoryAdapter
+	other int
+}
+
+func (a *countingAdapter) OtherCells(ctx context.Context) (int, error) {
+	return a.other, nil
+}
+
+func TestClient_CellLimit(t *testing.T) {
+	ctx := context.Background()
+	records := func() []*sheetkv.Record {
+		return []*sheetkv.Record{
+			{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30)}},
+			{Key: 3, Values: map[string]interface{}{"name": "Jane", "age": int64(25)}},
+		}
+	}
+
+	t.Run("Stats", func(t *testing.T) {
+		adapter := &countingAdapter{memoryAdapter: newMemoryAdapter([]string{"name", "age"}, records()...), other: 10}
+		client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true, CellLimit: 20})
+		if err := client.Initialize(ctx); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+
+		// 3 rows (header included) x 2 columns + 10 cells elsewhere
+		stats := client.Stats()
+		if stats.Cells != 16 || stats.CellLimit != 20 || !stats.NearCellLimit {
+			t.Errorf("Stats() = %d cells of %d, near %v, want 16 of 20, near", stats.Cells, stats.CellLimit, stats.NearCellLimit)
+		}
+	})
+
+	t.Run("Error policy", func(t *testing.T) {
+		adapter := &countingAdapter{memoryAdapter: newMemoryAdapter([]string{"name", "age"}, records()...), other: 10}
+		client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true, CellLimit: 18})
+		if err := client.Initialize(ctx); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+
+		if err := client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "Bob"}}); err != nil {
+			t.Errorf("Append() within the limit error = %v", err)
+		}
+		err := client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "Alice"}})
+		if !errors.Is(err, sheetkv.ErrCellLimit) {
+			t.Errorf("Append() error = %v, want ErrCellLimit", err)
+		}
+		err = client.Update(2, map[string]interface{}{"email": "john@example.com"})
+		if !errors.Is(err, sheetkv.ErrCellLimit) {
+			t.Errorf("Update() with a new column error = %v, want ErrCellLimit", err)
+		}
+		if err := client.Update(2, map[string]interface{}{"age": int64(31)}); err != nil {
+			t.Errorf("Update() of an existing cell error = %v", err)
+		}
+	})
+
+	t.Run("Roll over", func(t *testing.T) {
+		full := &countingAdapter{memoryAdapter: newMemoryAdapter([]string{"name", "age"}, records()...)}
+		next := &countingAdapter{memoryAdapter: newMemoryAdapter(nil)}
+		var policies []sheetkv.CellLimitPolicy
+
+		client := sheetkv.New(full, &sheetkv.Config{
+			DisableAutoSync: true,
+			CellLimit:       6,
+			CellLimitPolicy: sheetkv.CellLimitNewSpreadsheet,
+			OnCellLimit: func(ctx context.Context, policy sheetkv.CellLimitPolicy) (sheetkv.Adapter, error) {
+				policies = append(policies, policy)
+				return next, nil
+			},
+		})
+		if err := client.Initialize(ctx); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+
+		client.Update(3, map[string]interface{}{"age": int64(26)})
+		if err := client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "Bob"}}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+		if len(policies) != 1 || policies[0] != sheetkv.CellLimitNewSpreadsheet {
+			t.Errorf("OnCellLimit() calls = %v, want [new spreadsheet]", policies)
+		}
+		if got := full.saveCount(); got != 1 {
+			t.Errorf("saves of the full sheet = %d, want 1", got)
+		}
+
+		record, err := client.Get(2)
+		if err != nil || record.GetAsString("name", "") != "Bob" {
+			t.Errorf("Get(2) = %v, %v, want Bob in the new sheet", record, err)
+		}
+		if err := client.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		if got := next.saveCount(); got != 1 {
+			t.Errorf("saves of the new sheet = %d, want 1", got)
+		}
+	})
+}
+
+// limitsAdapter is a memory adapter reporting fixed limits
+type limitsAdapter struct {
+	*memoryAdapter
+}
+
+func (a *limitsAdapter) Limits(ctx context.Context) (*sheetkv.Limits, error) {
+	return &sheetkv.Limits{Cells: 42, CellLimit: 100}, nil
+}
+
+func TestClient_Limits(t *testing.T) {
+	ctx := context.Background()
+
+	client := sheetkv.New(&limitsAdapter{memoryAdapter: newMemoryAdapter(nil)}, &sheetkv.Config{DisableAutoSync: true})
+	limits, err := client.Limits(ctx)
+	if err != nil || limits.Cells != 42 || limits.CellLimit != 100 {
+		t.Errorf("Limits() = %+v, %v, want 42 of 100 cells", limits, err)
+	}
+
+	client = sheetkv.New(newMemoryAdapter(nil), &sheetkv.Config{DisableAutoSync: true})
+	if _, err := client.Limits(ctx); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("Limits() error = %v, want errors.ErrUnsupported", err)
+	}
+}
@@ -0,0 +1,54 @@
+package sheetkv
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RecordChange is the latest state of a record changed after a position of
+// the change feed
+type RecordChange struct {
+	Seq      uint64  // Position of the change in the feed
+	Key      int     // Key of the record
+	Revision uint64  // Revision of the record, as in Record.Revision
+	Record   *Record // Current record, nil when it was deleted
+}
+
+// Changes returns the records changed after position since of the change
+// feed, ordered by position, and the position to pass next time. Start with
+// 0. Each record appears once with its current state, however often it
+// changed, so a consumer indexing the data only has to apply the result.
+func (c *Cache) Changes(since uint64) ([]RecordChange, uint64) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var changes []RecordChange
+	for key, seq := range c.changedAt {
+		if seq <= since {
+			continue
+		}
+		change := RecordChange{Seq: seq, Key: key, Revision: c.revisions[key]}
+		if record, ok := c.data[key]; ok {
+			change.Record = c.copyRecord(record)
+		}
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Seq < changes[j].Seq })
+	return changes, c.changeSeq
+}
+
+// Changes returns the records changed after position since of the change
+// feed, and the position to pass next time; see Cache.Changes. The feed
+// covers changes made through the client and those found by loading the
+// sheet, such as edits made by others and picked up by Reload or Tail.
+func (c *Client) Changes(since uint64) ([]RecordChange, uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, 0, fmt.Errorf("client is closed")
+	}
+
+	changes, next := c.cache.Changes(since)
+	return changes, next, nil
+}
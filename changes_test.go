@@ -0,0 +1,81 @@
+package sheetkv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_Changes(t *testing.T) {
+	ctx := context.Background()
+	adapter := newMemoryAdapter([]string{"name", "age"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30)}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane", "age": int64(25)}},
+	)
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+	defer client.Close()
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	// keys returns the keys of changes, with -key for deletions
+	keys := func(changes []sheetkv.RecordChange) []int {
+		var keys []int
+		for _, change := range changes {
+			if change.Record == nil {
+				keys = append(keys, -change.Key)
+			} else {
+				keys = append(keys, change.Key)
+			}
+		}
+		return keys
+	}
+
+	changes, checkpoint, err := client.Changes(0)
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("Changes(0) = %v, want the 2 loaded records", keys(changes))
+	}
+
+	client.Update(2, map[string]interface{}{"age": int64(31)})
+	client.Update(2, map[string]interface{}{"age": int64(32)})
+	client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "Bob"}})
+	client.Delete(3)
+
+	changes, next, _ := client.Changes(checkpoint)
+	if got := keys(changes); len(got) != 3 || got[0] != 2 || got[1] != 4 || got[2] != -3 {
+		t.Errorf("Changes() = %v, want [2 4 -3] in order", got)
+	}
+	if changes[0].Revision != 3 || changes[0].Record.Values["age"] != int64(32) {
+		t.Errorf("change of 2 = %+v, want revision 3 aged 32", changes[0])
+	}
+	if changes, _, _ := client.Changes(next); len(changes) != 0 {
+		t.Errorf("Changes(next) = %v, want none", keys(changes))
+	}
+
+	t.Run("Changes found by a reload", func(t *testing.T) {
+		if err := client.Sync(); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+		if err := client.Reload(ctx); err != nil {
+			t.Fatalf("Reload() error = %v", err)
+		}
+		_, checkpoint, _ := client.Changes(next)
+
+		adapter.mu.Lock()
+		adapter.records = adapter.records[:1]
+		adapter.records[0] = &sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(40)}}
+		adapter.mu.Unlock()
+		if err := client.Reload(ctx); err != nil {
+			t.Fatalf("Reload() error = %v", err)
+		}
+
+		changes, _, _ := client.Changes(checkpoint)
+		if got := keys(changes); len(got) != 2 {
+			t.Errorf("Changes() after Reload = %v, want the edit of 2 and the removal of 4", got)
+		}
+	})
+}
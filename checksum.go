@@ -0,0 +1,38 @@
+package sheetkv
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+)
+
+// hashRecord returns a content hash of a record's values. Column order does
+// not matter; the dynamic type of each value does, so int64(30) and "30"
+// hash differently.
+func hashRecord(record *Record) uint64 {
+	cols := make([]string, 0, len(record.Values))
+	for col := range record.Values {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+
+	h := fnv.New64a()
+	for _, col := range cols {
+		v := record.Values[col]
+		fmt.Fprintf(h, "%s\x00%T\x00%v\x01", col, v, v)
+	}
+	return h.Sum64()
+}
+
+// equalSchemas reports whether two schemas hold the same columns in the same order
+func equalSchemas(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
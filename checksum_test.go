@@ -0,0 +1,153 @@
+package sheetkv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestCache_HasChanges(t *testing.T) {
+	load := func() *sheetkv.Cache {
+		cache := sheetkv.NewCache()
+		cache.Load([]*sheetkv.Record{
+			{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30)}},
+			{Key: 3, Values: map[string]interface{}{"name": "Jane", "age": int64(25)}},
+		}, []string{"name", "age"})
+		return cache
+	}
+
+	t.Run("Freshly loaded cache has no changes", func(t *testing.T) {
+		if load().HasChanges() {
+			t.Error("HasChanges() = true, want false")
+		}
+	})
+
+	t.Run("Touching a record with identical values is not a change", func(t *testing.T) {
+		cache := load()
+		cache.Update(2, map[string]interface{}{"age": int64(30)})
+		cache.Set(3, &sheetkv.Record{Values: map[string]interface{}{"age": int64(25), "name": "Jane"}})
+
+		if len(cache.GetDirtyKeys()) != 2 {
+			t.Fatalf("GetDirtyKeys() = %v, want 2 keys", cache.GetDirtyKeys())
+		}
+		if cache.HasChanges() {
+			t.Error("HasChanges() = true, want false")
+		}
+	})
+
+	t.Run("Value and type changes are detected", func(t *testing.T) {
+		cache := load()
+		cache.Update(2, map[string]interface{}{"age": int64(31)})
+		if !cache.HasChanges() {
+			t.Error("HasChanges() after value change = false, want true")
+		}
+
+		cache = load()
+		cache.Update(2, map[string]interface{}{"age": "30"})
+		if !cache.HasChanges() {
+			t.Error("HasChanges() after type change = false, want true")
+		}
+	})
+
+	t.Run("Appends, deletes and schema changes are detected", func(t *testing.T) {
+		cache := load()
+		cache.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "Bob"}})
+		if !cache.HasChanges() {
+			t.Error("HasChanges() after append = false, want true")
+		}
+
+		cache = load()
+		cache.Delete(3)
+		if !cache.HasChanges() {
+			t.Error("HasChanges() after delete = false, want true")
+		}
+
+		cache = load()
+		cache.SetSchema([]string{"name", "age", "email"})
+		if !cache.HasChanges() {
+			t.Error("HasChanges() after schema change = false, want true")
+		}
+	})
+
+	t.Run("MarkSaved resets the baseline", func(t *testing.T) {
+		cache := load()
+		cache.Update(2, map[string]interface{}{"age": int64(31)})
+
+		cache.MarkSaved(cache.GetAllRecords(), cache.GetSchema())
+		if cache.HasChanges() {
+			t.Error("HasChanges() after MarkSaved = true, want false")
+		}
+		if len(cache.GetDirtyKeys()) != 0 {
+			t.Errorf("GetDirtyKeys() after MarkSaved = %v, want none", cache.GetDirtyKeys())
+		}
+	})
+
+	t.Run("MarkSaved keeps records modified after the snapshot dirty", func(t *testing.T) {
+		cache := load()
+		cache.Update(2, map[string]interface{}{"age": int64(31)})
+		records, schema := cache.GetAllRecords(), cache.GetSchema()
+
+		cache.Update(2, map[string]interface{}{"age": int64(32)})
+		cache.MarkSaved(records, schema)
+
+		if dirty := cache.GetDirtyKeys(); len(dirty) != 1 || dirty[0] != 2 {
+			t.Errorf("GetDirtyKeys() = %v, want [2]", dirty)
+		}
+		if !cache.HasChanges() {
+			t.Error("HasChanges() = false, want true")
+		}
+	})
+}
+
+func TestClient_SyncSkipsUnchangedContent(t *testing.T) {
+	adapter := newMemoryAdapter([]string{"name", "status"}, &sheetkv.Record{
+		Key:    2,
+		Values: map[string]interface{}{"name": "John", "status": "active"},
+	})
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	// Idempotent touch
+	if err := client.Update(2, map[string]interface{}{"status": "active"}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := client.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if got := adapter.saveCount(); got != 0 {
+		t.Errorf("saves after idempotent update = %d, want 0", got)
+	}
+
+	if err := client.Update(2, map[string]interface{}{"status": "inactive"}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := client.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if got := adapter.saveCount(); got != 1 {
+		t.Errorf("saves after real update = %d, want 1", got)
+	}
+
+	// Writing the saved value again is a no-op
+	client.Update(2, map[string]interface{}{"status": "inactive"})
+	if err := client.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if got := adapter.saveCount(); got != 1 {
+		t.Errorf("saves after repeated update = %d, want 1", got)
+	}
+
+	// Deletes alone are saved
+	if err := client.Delete(2); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := client.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if got := adapter.saveCount(); got != 2 {
+		t.Errorf("saves after delete = %d, want 2", got)
+	}
+}
@@ -2,56 +2,108 @@ package sheetkv
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // Client is the main KVS client
 type Client struct {
-	config      Config
-	cache       *Cache
-	adaptor     Adapter
-	syncManager *SyncManager
-	mu          sync.Mutex
-	closed      bool
+	config        Config
+	cache         *Cache
+	adaptor       Adapter
+	syncManager   *SyncManager
+	mu            sync.Mutex
+	closed        bool
+	loaded        atomic.Bool  // Whether the cache holds the adapter's data
+	index         *Index       // Persisted index used before the cache is loaded
+	loadedAt      atomic.Int64 // Unix nanoseconds of the last full load, see Config.ReadThroughTTL
+	readMu        sync.Mutex
+	fetchedAt     map[int]time.Time // Rows read through individually since the last full load
+	leaseMu       sync.Mutex
+	leaseExpires  time.Time   // Expiry of the lease held, see Config.LeaseDuration
+	leader        atomic.Bool // Whether this client leads, see Config.ElectLeader
+	revisionMu    sync.Mutex
+	revision      string // Spreadsheet revision as of the last load or save
+	stats         clientStats
+	auditMu       sync.Mutex
+	auditQueue    []AuditEntry // Mutations waiting for the next sync
+	historyMu     sync.Mutex
+	history       map[int][]*RecordVersion // Prior versions per key, oldest first
+	historyQueue  []*RecordVersion         // Versions waiting for the next sync
+	snapshotMu    sync.Mutex
+	snapshot      *syncSnapshot // Last synced data, see Config.KeepSyncSnapshot
+	rulesMu       sync.RWMutex
+	rules         map[string][]ColumnRule // Validation rules per column
+	watchMu       sync.Mutex
+	watchers      map[chan Change]struct{} // Channels returned by Watch
+	watchClosed   bool
+	otherCells    atomic.Int64 // Cells of the other tabs, see Config.CellLimit
+	jobs          *jobScheduler
+	oplogMu       sync.Mutex
+	oplog         []LoggedOperation // Last operations, see Config.OperationLogSize
+	oplogSeq      uint64            // Sequence number of the last operation
+	publishMu     sync.Mutex
+	publishQueue  []ChangeEvent  // Events waiting for the next sync
+	pendingWrites []pendingWrite // Mutations waiting for their write-through, see Config.WriteThrough
+	viewMu        sync.Mutex
+	viewSums      []uint64 // Content hashes of the views last saved, see Config.Views
+	viewSaved     []bool
+	remapped      map[int]int // Keys moved by the last compacting sync, see Compact
 }
 
-// New creates a new KVS client with the given adapter and configuration
+// New creates a new KVS client with the given adapter and configuration,
+// normalized with Config.Normalize. Call Config.Validate first to reject
+// configurations without a defined meaning.
 func New(adapter Adapter, config *Config) *Client {
 	// Use default config if not provided
 	if config == nil {
-		config = &Config{
-			SyncInterval:  30 * time.Second,
-			MaxRetries:    3,
-			RetryInterval: 1 * time.Second,
-		}
+		config = &Config{}
 	}
+	normalized := config.Normalize()
+	config = &normalized
 
-	// Set defaults for zero values
-	if config.MaxRetries <= 0 {
-		config.MaxRetries = 3
+	cache := NewCache()
+	if len(config.IndexColumns) > 0 {
+		cache.SetIndexColumns(config.IndexColumns)
 	}
-	if config.RetryInterval <= 0 {
-		config.RetryInterval = 1 * time.Second
+	if config.QueryCacheSize > 0 {
+		cache.SetQueryCacheSize(config.QueryCacheSize)
+	}
+	if config.ColumnOrder != ColumnOrderAppend || len(config.Columns) > 0 {
+		cache.SetColumnOrder(config.ColumnOrder, config.Columns)
+	}
+	if config.Collation != nil || len(config.ColumnCollations) > 0 {
+		cache.SetCollation(config.Collation, config.ColumnCollations)
+	}
+	if config.NormalizeUnicode {
+		cache.SetUnicodeNormalization(true)
 	}
-
-	cache := NewCache()
 
 	client := &Client{
 		config:  *config,
 		cache:   cache,
 		adaptor: adapter,
+		rules:   groupRules(nil, config.ValidationRules),
+	}
+	if len(config.Views) > 0 {
+		client.viewSums = make([]uint64, len(config.Views))
+		client.viewSaved = make([]bool, len(config.Views))
 	}
 
 	// Note: Initial data loading is done lazily or can be done explicitly
 	// to avoid error in constructor. This matches the new API design.
 
-	// Start sync manager if interval is specified
+	// Start sync manager unless disabled
 	if config.SyncInterval > 0 {
 		client.syncManager = NewSyncManager(client, config.SyncInterval)
 		client.syncManager.Start()
 	}
+	if len(config.Jobs) > 0 {
+		client.jobs = startJobs(client, config.Jobs)
+	}
 
 	return client
 }
@@ -65,49 +117,260 @@ func (c *Client) Initialize(ctx context.Context) error {
 func (c *Client) loadFromAdapter(ctx context.Context) error {
 	var records []*Record
 	var schema []string
-	var err error
 
-	for i := 0; i <= c.config.MaxRetries; i++ {
+	// Read the revision before the data so that edits made while loading
+	// are detected on the next save rather than missed
+	revision, err := c.fetchRevision(ctx)
+	if err != nil {
+		return err
+	}
+
+	progress := c.trackProgress(PhaseLoad, 0)
+	defer progress.finish()
+
+	err = c.withRetry(ctx, func() error {
+		var err error
 		records, schema, err = c.adaptor.Load(ctx)
-		if err == nil {
-			break
-		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.decodeRecords(ctx, records); err != nil {
+		return err
+	}
+	progress.add(records)
 
-		if i < c.config.MaxRetries {
-			// Exponential backoff with reasonable limits
-			// #nosec G115 - i is bounded by MaxRetries which is typically small
-			backoff := time.Duration(1<<uint(i)) * 100 * time.Millisecond
-			if backoff > 2*time.Second {
-				backoff = 2 * time.Second
-			}
-			time.Sleep(backoff)
+	if c.config.EnforceSheetValidation {
+		if err := c.loadValidationRules(ctx); err != nil {
+			return err
 		}
 	}
-
-	if err != nil {
-		return fmt.Errorf("failed after %d retries: %w", c.config.MaxRetries, err)
+	if err := c.countOtherCells(ctx); err != nil {
+		return err
 	}
 
 	c.cache.Load(records, schema)
+	if err := c.assignMissingIDs(); err != nil {
+		return err
+	}
+	c.loaded.Store(true)
+	c.loadedNow()
+	c.setRevision(revision)
+	c.keepSnapshot(records, schema)
 	return nil
 }
 
 // saveToAdapter saves data to the adaptor with retry logic
 func (c *Client) saveToAdapter(ctx context.Context, strategy SyncStrategy) error {
-	// Check if there's any dirty data to save
-	dirtyKeys := c.cache.GetDirtyKeys()
-	if len(dirtyKeys) == 0 {
-		return nil // Nothing to save
+	return c.save(ctx, strategy, false)
+}
+
+// save saves data to the adaptor with retry logic. With gaps, a compacting
+// save is written even when the content matches what was last saved.
+func (c *Client) save(ctx context.Context, strategy SyncStrategy, gaps bool) (err error) {
+	start := time.Now()
+	defer func() { c.stats.recordSync(start, err) }()
+
+	// A tailing client never writes its sheet
+	if c.config.TailAppends {
+		return nil
+	}
+
+	// Only mutations made before the snapshot below belong to this save
+	audited, versioned, published := c.pendingAudit(), c.pendingHistory(), c.pendingPublish()
+
+	if strategy == SyncStrategyCompacting && c.config.PruneOnCompact {
+		c.cache.PruneSchema()
+	}
+
+	// Skip the write when the content matches what was last saved,
+	// even if records were marked dirty
+	gaps = gaps && strategy == SyncStrategyCompacting && c.cache.maxKey() != c.cache.Size()+1
+	if !c.cache.HasChanges() && !gaps {
+		c.cache.ClearDirty()
+		return c.flushLogs(ctx, audited, versioned, published)
+	}
+
+	if c.config.ReadThroughTTL > 0 && !c.loaded.Load() {
+		return fmt.Errorf("%w: %w", ErrSyncFailed, errPartialCache)
+	}
+	unlock, err := c.lock(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSyncFailed, err)
+	}
+	defer func() { err = errors.Join(err, unlock()) }()
+
+	if err := c.checkRevision(ctx); err != nil {
+		return err
 	}
 
 	records := c.cache.GetAllRecords()
 	schema := c.cache.GetSchema()
 
+	progress := c.trackProgress(PhaseSave, len(records))
+	defer progress.finish()
+
+	stored, err := c.encodeRecords(ctx, records)
+	if err != nil {
+		return err
+	}
+	err = c.withRetry(ctx, func() error {
+		return c.adaptor.Save(ctx, stored, schema, strategy)
+	})
+	if err != nil {
+		return err
+	}
+	progress.add(records)
+
+	if strategy == SyncStrategyCompacting {
+		records = c.renumber(records)
+	}
+	c.cache.MarkSaved(records, schema)
+	c.keepSnapshot(records, schema)
+
+	if c.config.PersistIndex {
+		if err := c.saveIndex(ctx); err != nil {
+			return fmt.Errorf("failed to save index: %w", err)
+		}
+	}
+
+	// Formatting bumps the revision too, so it comes first
+	if err := c.afterSave(ctx); err != nil {
+		return err
+	}
+
+	// Our own writes bump the revision, so record the new one
+	revision, err := c.fetchRevision(ctx)
+	if err != nil {
+		return err
+	}
+	c.setRevision(revision)
+
+	return c.flushLogs(ctx, audited, versioned, published)
+}
+
+// flushLogs writes the audit entries and history versions, and publishes
+// the change events, queued before a save, then materializes the views
+func (c *Client) flushLogs(ctx context.Context, audited, versioned, published int) error {
+	if err := c.flushAudit(ctx, audited); err != nil {
+		return err
+	}
+	if err := c.flushHistory(ctx, versioned); err != nil {
+		return err
+	}
+	if err := c.flushPublish(ctx, published); err != nil {
+		return err
+	}
+	return c.materializeViews(ctx)
+}
+
+// checkRevision refuses the save when the spreadsheet was modified since the
+// last load or save. The local changes stay dirty so the application can
+// reload and reapply them.
+func (c *Client) checkRevision(ctx context.Context) error {
+	c.revisionMu.Lock()
+	expected := c.revision
+	c.revisionMu.Unlock()
+	if expected == "" {
+		return nil // Nothing recorded yet
+	}
+
+	current, err := c.fetchRevision(ctx)
+	if err != nil {
+		return err
+	}
+	if current == expected {
+		return nil
+	}
+
+	conflictErr := fmt.Errorf("%w: %w (revision %s, last synced %s)", ErrSyncFailed, ErrRemoteConflict, current, expected)
+	if c.config.OnConflict != nil {
+		c.config.OnConflict(conflictErr)
+	}
+	return conflictErr
+}
+
+// fetchRevision returns the current spreadsheet revision, or an empty string
+// when remote change detection is disabled or unsupported by the adapter
+func (c *Client) fetchRevision(ctx context.Context) (string, error) {
+	source, ok := c.adaptor.(RevisionSource)
+	if !c.config.DetectRemoteChanges || !ok {
+		return "", nil
+	}
+
+	var revision string
+	err := c.withRetry(ctx, func() error {
+		var err error
+		revision, err = source.Revision(ctx)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get revision: %w", err)
+	}
+	return revision, nil
+}
+
+// setRevision records the revision of the last load or save
+func (c *Client) setRevision(revision string) {
+	c.revisionMu.Lock()
+	defer c.revisionMu.Unlock()
+	c.revision = revision
+}
+
+// saveIndex persists the secondary index through the adapter
+func (c *Client) saveIndex(ctx context.Context) error {
+	store, ok := c.adaptor.(IndexStore)
+	if !ok {
+		return nil
+	}
+
+	index := c.cache.Index()
+	if index == nil {
+		return nil
+	}
+
+	return c.withRetry(ctx, func() error {
+		return store.SaveIndex(ctx, index)
+	})
+}
+
+// withRetry calls fn until it succeeds or the retries are exhausted,
+// waiting for the rate limiter before every attempt. With
+// Config.MaxElapsedRetryTime it gives up with ErrRetryTime once the next
+// attempt would start after the budget.
+func (c *Client) withRetry(ctx context.Context, fn func() error) error {
+	start := time.Now()
+	budget := c.config.MaxElapsedRetryTime
+	exceeded := func(attempts int, err error) error {
+		return fmt.Errorf("%w: %d attempts in %s, over MaxElapsedRetryTime %s: %w",
+			ErrRetryTime, attempts, time.Since(start).Round(time.Millisecond), budget, err)
+	}
+
 	var err error
 	for i := 0; i <= c.config.MaxRetries; i++ {
-		err = c.adaptor.Save(ctx, records, schema, strategy)
+		if c.config.RateLimiter != nil {
+			waitCtx, cancel := ctx, context.CancelFunc(func() {})
+			if budget > 0 && i > 0 {
+				waitCtx, cancel = context.WithDeadline(ctx, start.Add(budget))
+			}
+			waitErr := c.config.RateLimiter.Wait(waitCtx)
+			cancel()
+			if waitErr != nil {
+				if ctx.Err() == nil && err != nil {
+					return exceeded(i, err)
+				}
+				return waitErr
+			}
+		}
+
+		c.stats.apiCalls.Add(1)
+		if i > 0 {
+			c.stats.retries.Add(1)
+		}
+
+		err = fn()
 		if err == nil {
-			c.cache.ClearDirty()
 			return nil
 		}
 
@@ -118,6 +381,9 @@ func (c *Client) saveToAdapter(ctx context.Context, strategy SyncStrategy) error
 			if backoff > 2*time.Second {
 				backoff = 2 * time.Second
 			}
+			if budget > 0 && time.Since(start)+backoff > budget {
+				return exceeded(i+1, err)
+			}
 			time.Sleep(backoff)
 		}
 	}
@@ -133,20 +399,88 @@ func (c *Client) Get(key int) (*Record, error) {
 	if c.closed {
 		return nil, fmt.Errorf("client is closed")
 	}
+	if err := c.readThroughKey(context.Background(), key); err != nil {
+		return nil, err
+	}
 
 	return c.cache.Get(key)
 }
 
 // Set stores or updates a record
 func (c *Client) Set(key int, record *Record) error {
+	return c.setGuarded(key, record, nil)
+}
+
+// writeGuard refuses a write from the record it replaces, nil when the key
+// is empty. It is called under c.mu, so the record checked is the one
+// written.
+type writeGuard func(existing *Record) error
+
+// guard calls guard, when not nil, with the record at key, read through
+// like Get does. Callers must hold c.mu.
+func (c *Client) guard(key int, guard writeGuard) error {
+	if guard == nil {
+		return nil
+	}
+	if err := c.readThroughKey(context.Background(), key); err != nil {
+		return err
+	}
+	existing, err := c.cache.Get(key)
+	if err != nil && err != ErrKeyNotFound {
+		return err
+	}
+	return guard(existing)
+}
+
+// setGuarded is Set refused by guard
+func (c *Client) setGuarded(key int, record *Record, guard writeGuard) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	if c.closed {
 		return fmt.Errorf("client is closed")
 	}
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
+	if err := c.guard(key, guard); err != nil {
+		return err
+	}
+
+	if err := c.checkColumns(record.Values); err != nil {
+		return err
+	}
+	if err := c.checkValues(record.Values); err != nil {
+		return err
+	}
+	if err := c.checkCells(key, record.Values); err != nil {
+		return err
+	}
+	if err := c.checkRecord(key, record.Values, false); err != nil {
+		return err
+	}
+	if err := c.checkRowLock(key); err != nil {
+		return err
+	}
+	if err := c.checkID(key, record.Values); err != nil {
+		return err
+	}
+
+	record, err := c.assignID(key, record)
+	if err != nil {
+		return err
+	}
+	if c.config.CreatedAtColumn != "" || c.config.UpdatedAtColumn != "" {
+		record = c.stampSet(key, record)
+	}
+	record = c.keepRowLock(key, record)
 
-	return c.cache.Set(key, record)
+	old := c.beforeMutation(key)
+	if err := c.cache.Set(key, record); err != nil {
+		return err
+	}
+	c.mutated(key, old)
+	return c.writeThrough(context.Background())
 }
 
 // Append adds a new record
@@ -157,41 +491,202 @@ func (c *Client) Append(record *Record) error {
 	if c.closed {
 		return fmt.Errorf("client is closed")
 	}
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
 
-	// Find the next available key (row number)
-	maxKey := 1 // Start from row 2 (row 1 is header)
-	for _, r := range c.cache.GetAllRecords() {
-		if r.Key > maxKey {
-			maxKey = r.Key
-		}
+	if err := c.checkColumns(record.Values); err != nil {
+		return err
+	}
+	if err := c.checkValues(record.Values); err != nil {
+		return err
+	}
+	// The next key follows the last row of the sheet, not of the rows read through
+	if err := c.readThroughTable(context.Background()); err != nil {
+		return err
+	}
+	if err := c.checkAppendCells(record.Values); err != nil {
+		return err
+	}
+	if err := c.checkRecord(c.cache.maxKey()+1, record.Values, false); err != nil {
+		return err
 	}
 
-	record.Key = maxKey + 1
-	return c.cache.Append(record)
+	if err := c.appendRecord(record); err != nil {
+		return err
+	}
+	return c.writeThrough(context.Background())
+}
+
+// appendRecord stores record under the next available key, which is set on
+// record. Callers must hold c.mu and have checked the record.
+func (c *Client) appendRecord(record *Record) error {
+	// The next available key (row number), from row 2 as row 1 is the header
+	record.Key = c.cache.maxKey() + 1
+	record, err := c.assignID(record.Key, record)
+	if err != nil {
+		return err
+	}
+	if c.config.CreatedAtColumn != "" || c.config.UpdatedAtColumn != "" {
+		now := c.timestamp()
+		stamped := &Record{Key: record.Key, Values: copyValues(record.Values)}
+		if col := c.config.CreatedAtColumn; col != "" && stamped.Values[col] == nil {
+			stamped.Values[col] = now
+		}
+		if c.config.UpdatedAtColumn != "" {
+			stamped.Values[c.config.UpdatedAtColumn] = now
+		}
+		record = stamped
+	}
+	if err := c.cache.Append(record); err != nil {
+		return err
+	}
+	c.mutated(record.Key, nil)
+	return nil
 }
 
 // Update partially updates a record
 func (c *Client) Update(key int, updates map[string]interface{}) error {
+	return c.updateGuarded(key, updates, nil)
+}
+
+// updateGuarded is Update refused by guard
+func (c *Client) updateGuarded(key int, updates map[string]interface{}, guard writeGuard) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	if c.closed {
 		return fmt.Errorf("client is closed")
 	}
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
+	if err := c.guard(key, guard); err != nil {
+		return err
+	}
+
+	if err := c.checkColumns(updates); err != nil {
+		return err
+	}
+	if err := c.checkValues(updates); err != nil {
+		return err
+	}
+	if err := c.checkCells(key, updates); err != nil {
+		return err
+	}
+	if err := c.checkRecord(key, updates, true); err != nil {
+		return err
+	}
+	if err := c.checkRowLock(key); err != nil {
+		return err
+	}
+	if err := c.checkID(key, updates); err != nil {
+		return err
+	}
+	if err := c.updateRecord(key, updates); err != nil {
+		return err
+	}
+	return c.writeThrough(context.Background())
+}
+
+// updateRecord merges updates into the record at key. Callers must hold
+// c.mu and have checked the updates.
+func (c *Client) updateRecord(key int, updates map[string]interface{}) error {
+	if c.config.UpdatedAtColumn != "" {
+		updates = copyValues(updates)
+		updates[c.config.UpdatedAtColumn] = c.timestamp()
+	}
 
-	return c.cache.Update(key, updates)
+	old := c.beforeMutation(key)
+	if err := c.cache.Update(key, updates); err != nil {
+		return err
+	}
+	c.mutated(key, old)
+	return nil
+}
+
+// stampSet returns a copy of record with the timestamp columns filled in.
+// The creation time of an existing record is preserved.
+func (c *Client) stampSet(key int, record *Record) *Record {
+	now := c.timestamp()
+	stamped := &Record{Key: record.Key, Values: copyValues(record.Values)}
+
+	if col := c.config.CreatedAtColumn; col != "" {
+		if existing, err := c.cache.Get(key); err == nil && existing.Values[col] != nil {
+			stamped.Values[col] = existing.Values[col]
+		} else {
+			stamped.Values[col] = now
+		}
+	}
+	if col := c.config.UpdatedAtColumn; col != "" {
+		stamped.Values[col] = now
+	}
+	return stamped
+}
+
+// now returns the current time of Config.Clock
+func (c *Client) now() time.Time {
+	return c.config.Clock.Now()
+}
+
+// timestamp returns the current time formatted with Config.TimeFormat
+func (c *Client) timestamp() string {
+	return c.now().Format(c.timeFormat())
+}
+
+// timeFormat returns Config.TimeFormat or its default
+func (c *Client) timeFormat() string {
+	if c.config.TimeFormat == "" {
+		return time.RFC3339
+	}
+	return c.config.TimeFormat
+}
+
+// copyValues returns a shallow copy of a values map
+func copyValues(values map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(values)+2)
+	for k, v := range values {
+		copied[k] = v
+	}
+	return copied
 }
 
 // Delete removes a record
 func (c *Client) Delete(key int) error {
+	return c.deleteGuarded(key, nil)
+}
+
+// deleteGuarded is Delete refused by guard
+func (c *Client) deleteGuarded(key int, guard writeGuard) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	if c.closed {
 		return fmt.Errorf("client is closed")
 	}
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
+	if err := c.guard(key, guard); err != nil {
+		return err
+	}
+	if err := c.checkRowLock(key); err != nil {
+		return err
+	}
+	if err := c.deleteRecord(key); err != nil {
+		return err
+	}
+	return c.writeThrough(context.Background())
+}
 
-	return c.cache.Delete(key)
+// deleteRecord removes the record at key. Callers must hold c.mu.
+func (c *Client) deleteRecord(key int) error {
+	old := c.beforeMutation(key)
+	if err := c.cache.Delete(key); err != nil {
+		return err
+	}
+	c.mutated(key, old)
+	return nil
 }
 
 // Query searches for records matching the given conditions
@@ -202,10 +697,154 @@ func (c *Client) Query(query Query) ([]*Record, error) {
 	if c.closed {
 		return nil, fmt.Errorf("client is closed")
 	}
+	if err := c.readThroughTable(context.Background()); err != nil {
+		return nil, err
+	}
 
 	return c.cache.Query(query)
 }
 
+// QueryCtx is like Query but checks ctx while filtering, so a long query
+// over many rows can be abandoned. It returns ctx.Err() once ctx is done.
+func (c *Client) QueryCtx(ctx context.Context, query Query) ([]*Record, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, fmt.Errorf("client is closed")
+	}
+	if err := c.readThroughTable(ctx); err != nil {
+		return nil, err
+	}
+
+	return c.cache.QueryCtx(ctx, query)
+}
+
+// Lookup returns the records whose indexed column equals value.
+// Once the client is initialized the in-memory index answers the lookup.
+// Before that, the index persisted by the adapter (see Config.PersistIndex)
+// is used to fetch only the matching rows instead of reading the whole sheet;
+// without a persisted index the client is initialized first.
+func (c *Client) Lookup(ctx context.Context, column string, value interface{}) ([]*Record, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, fmt.Errorf("client is closed")
+	}
+
+	if c.config.NormalizeUnicode {
+		value = normalizeValue(value)
+	}
+	query := Query{Conditions: []Condition{{Column: column, Operator: "==", Value: value}}}
+	if c.loaded.Load() {
+		if err := c.readThroughTable(ctx); err != nil {
+			return nil, err
+		}
+		return c.cache.Query(query)
+	}
+
+	// The persisted index holds exact values, which a collated lookup may not match
+	store, isStore := c.adaptor.(IndexStore)
+	loader, isLoader := c.adaptor.(RowLoader)
+	if isStore && isLoader && c.cache.comparison.collation(column) == nil {
+		if c.index == nil {
+			err := c.withRetry(ctx, func() error {
+				var err error
+				c.index, err = store.LoadIndex(ctx)
+				return err
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to load index: %w", err)
+			}
+		}
+
+		if c.index != nil && containsString(c.index.Columns, column) {
+			keys := c.index.Keys(column, value)
+			if len(keys) == 0 {
+				return []*Record{}, nil
+			}
+
+			var records []*Record
+			err := c.withRetry(ctx, func() error {
+				var err error
+				records, _, err = loader.LoadRows(ctx, keys)
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+			if err := c.decodeRecords(ctx, records); err != nil {
+				return nil, err
+			}
+			// The persisted index may be stale, so verify every row
+			return applyQuery(ctx, records, query, c.cache.comparison)
+		}
+	}
+
+	if err := c.loadFromAdapter(ctx); err != nil {
+		return nil, err
+	}
+	return c.cache.Query(query)
+}
+
+// Reload replaces the cached data with the current contents of the
+// spreadsheet, picking up edits made outside the client. Records modified
+// or deleted locally since the last sync are kept that way, so they are
+// written by the next sync; with Config.MergePolicy they are merged into their
+// remote versions instead of replacing them.
+func (c *Client) Reload(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return fmt.Errorf("client is closed")
+	}
+
+	return c.reload(ctx)
+}
+
+// reload loads the spreadsheet, keeping the records modified or deleted
+// since the last sync. Callers must hold c.mu.
+func (c *Client) reload(ctx context.Context) error {
+	var pending []*Record
+	for _, key := range c.cache.GetDirtyKeys() {
+		if record, err := c.cache.Get(key); err == nil {
+			pending = append(pending, record)
+		}
+	}
+	deleted := c.cache.deletedKeys()
+
+	if err := c.loadFromAdapter(ctx); err != nil {
+		return err
+	}
+
+	// Deletions are not dirty records, so they are replayed on their own
+	for _, key := range deleted {
+		if err := c.cache.Delete(key); err != nil && err != ErrKeyNotFound {
+			return err
+		}
+	}
+
+	for _, record := range pending {
+		if c.config.MergePolicy != nil {
+			if remote, err := c.cache.Get(record.Key); err == nil {
+				merged := copyValues(remote.Values)
+				MergeValues(merged, record.Values, *c.config.MergePolicy)
+				record = &Record{Key: record.Key, Values: merged}
+			}
+		}
+		if err := c.cache.Set(record.Key, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Sync forces synchronization with the backend
 func (c *Client) Sync() error {
 	c.mu.Lock()
@@ -230,7 +869,15 @@ func (c *Client) Close() error {
 	c.closed = true
 	syncManager := c.syncManager
 	c.syncManager = nil
+	jobs := c.jobs
+	c.jobs = nil
 	c.mu.Unlock()
+	c.closeWatchers()
+
+	// Running jobs fail once the client is closed, so this returns quickly
+	if jobs != nil {
+		jobs.stop()
+	}
 
 	// Stop the sync manager if running (without holding the mutex)
 	if syncManager != nil {
@@ -241,15 +888,16 @@ func (c *Client) Close() error {
 	if err := c.saveToAdapter(context.Background(), SyncStrategyCompacting); err != nil {
 		return fmt.Errorf("failed to sync on close: %w", err)
 	}
-
-	return nil
+	err := c.releaseLease(context.Background())
+	c.setLeader(false)
+	return err
 }
 
 // SyncManager manages periodic synchronization
 type SyncManager struct {
 	client    *Client
 	interval  time.Duration
-	ticker    *time.Ticker
+	ticker    Ticker
 	done      chan bool
 	syncMutex sync.Mutex
 	syncing   bool
@@ -267,7 +915,7 @@ func NewSyncManager(client *Client, interval time.Duration) *SyncManager {
 
 // Start begins the periodic sync process
 func (sm *SyncManager) Start() {
-	sm.ticker = time.NewTicker(sm.interval)
+	sm.ticker = sm.client.config.Clock.NewTicker(sm.interval)
 	sm.wg.Add(1)
 
 	go func() {
@@ -275,7 +923,7 @@ func (sm *SyncManager) Start() {
 
 		for {
 			select {
-			case <-sm.ticker.C:
+			case <-sm.ticker.C():
 				sm.performSync()
 			case <-sm.done:
 				return
@@ -296,14 +944,28 @@ func (sm *SyncManager) performSync() {
 	sm.syncing = true
 	defer func() { sm.syncing = false }()
 
-	// Check if there are dirty records
-	dirtyKeys := sm.client.cache.GetDirtyKeys()
-	if len(dirtyKeys) == 0 {
+	sm.client.backgroundSync(context.Background())
+}
+
+// backgroundSync performs one periodic sync: saving the changes, reloading
+// when another client leads, or reading the new rows of a tailed sheet
+func (c *Client) backgroundSync(ctx context.Context) {
+	if c.config.ElectLeader {
+		c.electedSync(ctx)
+		return
+	}
+	if c.config.TailAppends {
+		_, _ = c.Tail(ctx)
+		return
+	}
+
+	// Check if there is anything to save
+	if !c.cache.HasChanges() {
 		return
 	}
 
 	// Perform sync
-	_ = sm.client.saveToAdapter(context.Background(), SyncStrategyGapPreserving)
+	_ = c.saveToAdapter(ctx, SyncStrategyGapPreserving)
 }
 
 // Stop stops the sync manager and waits for ongoing sync
@@ -0,0 +1,253 @@
+package sheetkv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ClientPoolConfig represents configuration for a ClientPool
+type ClientPoolConfig struct {
+	Client          *Config       // Configuration of every client (default: New's defaults)
+	RateLimiter     *RateLimiter  // Shared by every client, overriding Client.RateLimiter (default: none)
+	SyncInterval    time.Duration // Interval of the shared sync loop (default: Client.SyncInterval, then DefaultSyncInterval)
+	DisableAutoSync bool          // Do not sync periodically; changes are saved by Sync and Close only
+}
+
+// ClientPool runs the clients of several tabs of the same spreadsheets in one
+// process. Instead of one sync loop per client, a single loop syncs every
+// client on each tick, one spreadsheet at a time and skipping ticks while
+// the previous round is running. Saves and write-throughs of the clients of
+// one spreadsheet are serialized, whoever starts them, and the clients share
+// one rate limiter.
+type ClientPool struct {
+	config  ClientPoolConfig
+	mu      sync.Mutex
+	sheets  map[string]*pooledSpreadsheet
+	order   []string // Spreadsheets in the order of their first client
+	closed  bool
+	syncing sync.Mutex // Held by a sync round, so overlapping ticks are skipped
+	ticker  Ticker
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// pooledSpreadsheet holds the clients of one spreadsheet
+type pooledSpreadsheet struct {
+	lock    *poolLock
+	clients []*Client
+}
+
+// NewClientPool creates an empty ClientPool and starts its sync loop
+func NewClientPool(config *ClientPoolConfig) *ClientPool {
+	if config == nil {
+		config = &ClientPoolConfig{}
+	}
+
+	p := &ClientPool{
+		config: *config,
+		sheets: make(map[string]*pooledSpreadsheet),
+		done:   make(chan struct{}),
+	}
+	if p.config.SyncInterval <= 0 {
+		p.config.SyncInterval = DefaultSyncInterval
+		if p.config.Client != nil && p.config.Client.SyncInterval > 0 {
+			p.config.SyncInterval = p.config.Client.SyncInterval
+		}
+	}
+	if !p.config.DisableAutoSync {
+		clock := SystemClock
+		if p.config.Client != nil && p.config.Client.Clock != nil {
+			clock = p.config.Client.Clock
+		}
+		p.ticker = clock.NewTicker(p.config.SyncInterval)
+		p.wg.Add(1)
+		go p.loop()
+	}
+	return p
+}
+
+// Add creates and initializes a client of adapter, a tab of spreadsheetID.
+// The client runs no sync loop of its own; the pool syncs it, so the
+// SyncInterval of a Consistency preset gives way to the pool's. Configurations
+// with LeaseDuration are refused: the pool serializes saves with a Locker,
+// which replaces the lease.
+func (p *ClientPool) Add(ctx context.Context, spreadsheetID string, adapter Adapter) (*Client, error) {
+	config := &Config{}
+	if p.config.Client != nil {
+		copied := *p.config.Client
+		config = &copied
+	}
+	if config.LeaseDuration > 0 {
+		return nil, fmt.Errorf("%w: a ClientPool serializes saves with a Locker, which replaces the lease of LeaseDuration", ErrInvalidConfig)
+	}
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, fmt.Errorf("pool is closed")
+	}
+	sheet, exists := p.sheets[spreadsheetID]
+	if !exists {
+		sheet = &pooledSpreadsheet{lock: newPoolLock()}
+		p.sheets[spreadsheetID] = sheet
+		p.order = append(p.order, spreadsheetID)
+	}
+	p.mu.Unlock()
+
+	// The preset is applied first, as it would turn the sync loop back on
+	config.Consistency.apply(config)
+	config.Consistency = Consistency{}
+	config.DisableAutoSync = true
+	config.SyncInterval = 0
+	config.Locker = &pooledLocker{lock: sheet.lock, next: config.Locker}
+	if p.config.RateLimiter != nil {
+		config.RateLimiter = p.config.RateLimiter
+	}
+
+	client := New(adapter, config)
+	if err := client.Initialize(ctx); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("spreadsheet %q: %w", spreadsheetID, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		client.Close()
+		return nil, fmt.Errorf("pool is closed")
+	}
+	sheet.clients = append(sheet.clients, client)
+	return client, nil
+}
+
+// Sync saves the changes of every client now, one spreadsheet at a time.
+// Errors from every client are joined together.
+func (p *ClientPool) Sync() error {
+	p.syncing.Lock()
+	defer p.syncing.Unlock()
+
+	var errs []error
+	for _, client := range p.clients() {
+		if err := client.Sync(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Len returns the number of clients of the pool
+func (p *ClientPool) Len() int {
+	return len(p.clients())
+}
+
+// Close stops the sync loop and closes every client, performing their final
+// syncs. Errors from every client are joined together.
+func (p *ClientPool) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	if p.ticker != nil {
+		p.ticker.Stop()
+	}
+	close(p.done)
+	p.wg.Wait()
+
+	var errs []error
+	for _, client := range p.clients() {
+		if err := client.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// clients returns the clients grouped by spreadsheet
+func (p *ClientPool) clients() []*Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var clients []*Client
+	for _, id := range p.order {
+		clients = append(clients, p.sheets[id].clients...)
+	}
+	return clients
+}
+
+// loop runs a sync round on each tick
+func (p *ClientPool) loop() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case <-p.ticker.C():
+			p.tick()
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// tick runs a background sync of every open client, unless the previous
+// round or a Sync is still running
+func (p *ClientPool) tick() {
+	if !p.syncing.TryLock() {
+		return
+	}
+	defer p.syncing.Unlock()
+
+	for _, client := range p.clients() {
+		client.mu.Lock()
+		closed := client.closed
+		client.mu.Unlock()
+		if !closed {
+			client.backgroundSync(context.Background())
+		}
+	}
+}
+
+// poolLock is the in-process lock of one spreadsheet
+type poolLock struct {
+	ch chan struct{}
+}
+
+func newPoolLock() *poolLock {
+	return &poolLock{ch: make(chan struct{}, 1)}
+}
+
+// pooledLocker takes the lock of the spreadsheet, then the client's own
+// Locker if any
+type pooledLocker struct {
+	lock *poolLock
+	next Locker
+}
+
+func (l *pooledLocker) Lock(ctx context.Context) error {
+	select {
+	case l.lock.ch <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if l.next != nil {
+		if err := l.next.Lock(ctx); err != nil {
+			<-l.lock.ch
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *pooledLocker) Unlock(ctx context.Context) error {
+	defer func() { <-l.lock.ch }()
+	if l.next != nil {
+		return l.next.Unlock(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// overlapAdapter records how many saves of a spreadsheet overlap
+type overlapAdapter struct {
+	*memoryAdapter
+	running, overlaps *atomic.Int32
+}
+
+func (a *overlapAdapter) Save(ctx context.Context, records []*sheetkv.Record, schema []string, strategy sheetkv.SyncStrategy) error {
+	if a.running.Add(1) > 1 {
+		a.overlaps.Add(1)
+	}
+	defer a.running.Add(-1)
+	time.Sleep(10 * time.Millisecond)
+	return a.memoryAdapter.Save(ctx, records, schema, strategy)
+}
+
+func TestClientPool(t *testing.T) {
+	ctx := context.Background()
+	clock := sheetkv.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	pool := sheetkv.NewClientPool(&sheetkv.ClientPoolConfig{
+		Client:       &sheetkv.Config{Clock: clock},
+		SyncInterval: time.Minute,
+	})
+	defer pool.Close()
+
+	var running, overlaps atomic.Int32
+	var adapters []*overlapAdapter
+	var clients []*sheetkv.Client
+	for i := 0; i < 2; i++ {
+		adapter := &overlapAdapter{memoryAdapter: newMemoryAdapter([]string{"name"}), running: &running, overlaps: &overlaps}
+		client, err := pool.Add(ctx, "book", adapter)
+		if err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		adapters = append(adapters, adapter)
+		clients = append(clients, client)
+	}
+	if pool.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", pool.Len())
+	}
+
+	// One tick of the pool syncs every client
+	for _, client := range clients {
+		client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "a"}})
+	}
+	clock.Advance(time.Minute)
+	waitFor(t, "the pooled sync", func() bool {
+		return adapters[0].saveCount() == 1 && adapters[1].saveCount() == 1
+	})
+
+	t.Run("Saves of a spreadsheet are serialized", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for _, client := range clients {
+			client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "b"}})
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				client.Sync()
+			}()
+		}
+		wg.Wait()
+		if overlaps.Load() != 0 {
+			t.Errorf("%d saves overlapped", overlaps.Load())
+		}
+	})
+
+	t.Run("Leases are refused", func(t *testing.T) {
+		pool := sheetkv.NewClientPool(&sheetkv.ClientPoolConfig{
+			Client:          &sheetkv.Config{LeaseDuration: time.Minute},
+			DisableAutoSync: true,
+		})
+		defer pool.Close()
+		if _, err := pool.Add(ctx, "book", newMemoryAdapter([]string{"name"})); !errors.Is(err, sheetkv.ErrInvalidConfig) {
+			t.Errorf("Add() error = %v, want ErrInvalidConfig", err)
+		}
+	})
+
+	t.Run("Presets keep the shared loop", func(t *testing.T) {
+		clock := sheetkv.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+		pool := sheetkv.NewClientPool(&sheetkv.ClientPoolConfig{
+			Client:          &sheetkv.Config{Clock: clock, Consistency: sheetkv.BoundedStaleness(time.Second)},
+			DisableAutoSync: true,
+		})
+		defer pool.Close()
+		adapter := newMemoryAdapter([]string{"name"})
+		client, err := pool.Add(ctx, "book", adapter)
+		if err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+
+		// A sync loop of the client would save on these ticks
+		client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "a"}})
+		for i := 0; i < 3; i++ {
+			clock.Advance(time.Second)
+			time.Sleep(10 * time.Millisecond)
+		}
+		if got := adapter.saveCount(); got != 0 {
+			t.Errorf("saves = %d, want none without the pool's loop", got)
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package sheetkv
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the source of time of a client: its periodic syncs and jobs,
+// read-through TTLs, leases, row locks and timestamps. See Config.Clock and
+// FakeClock.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+
+	// NewTicker returns a ticker sending the time every d
+	NewTicker(d time.Duration) Ticker
+
+	// After returns a channel receiving the time once d has elapsed
+	After(d time.Duration) <-chan time.Time
+}
+
+// Ticker delivers the ticks of a Clock
+type Ticker interface {
+	// C returns the channel receiving the ticks
+	C() <-chan time.Time
+
+	// Stop turns the ticker off
+	Stop()
+}
+
+// SystemClock is the Clock of the time package, used by default
+var SystemClock Clock = systemClock{}
+
+// systemClock implements Clock with the time package
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+// systemTicker adapts a time.Ticker to Ticker
+type systemTicker struct {
+	ticker *time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time { return t.ticker.C }
+func (t systemTicker) Stop()               { t.ticker.Stop() }
+
+// FakeClock is a Clock for tests whose time only moves with Advance, so
+// intervals and TTLs can be exercised without sleeping. Like time.Ticker,
+// its tickers drop ticks their receiver is not ready for.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+	waiters []fakeWaiter
+}
+
+// fakeWaiter is a pending After of a FakeClock
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// NewFakeClock returns a fake clock set to now
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the time of the clock
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// NewTicker returns a ticker firing every d of advanced time
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("sheetkv: non-positive interval for FakeClock.NewTicker")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTicker{clock: c, interval: d, next: c.now.Add(d), ch: make(chan time.Time, 1)}
+	c.tickers = append(c.tickers, t)
+	return t
+}
+
+// After returns a channel receiving the time once the clock advanced by d
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward by d, firing the tickers and After
+// channels that fall due in order
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	end := c.now.Add(d)
+	for {
+		next, fire := c.nextEvent(end)
+		if fire == nil {
+			break
+		}
+		c.now = next
+		fire()
+	}
+	c.now = end
+}
+
+// nextEvent returns the earliest tick or After due by end and the function
+// firing it, nil when none is. Callers must hold c.mu.
+func (c *FakeClock) nextEvent(end time.Time) (time.Time, func()) {
+	sort.SliceStable(c.waiters, func(i, j int) bool { return c.waiters[i].at.Before(c.waiters[j].at) })
+
+	var at time.Time
+	var fire func()
+	if len(c.waiters) > 0 && !c.waiters[0].at.After(end) {
+		w := c.waiters[0]
+		at = w.at
+		fire = func() {
+			c.waiters = c.waiters[1:]
+			w.ch <- w.at
+		}
+	}
+	for _, t := range c.tickers {
+		if t.next.After(end) || (fire != nil && !t.next.Before(at)) {
+			continue
+		}
+		t := t
+		at = t.next
+		fire = func() {
+			select {
+			case t.ch <- t.next:
+			default:
+			}
+			t.next = t.next.Add(t.interval)
+		}
+	}
+	return at, fire
+}
+
+// fakeTicker is a Ticker of a FakeClock
+type fakeTicker struct {
+	clock    *FakeClock
+	interval time.Duration
+	next     time.Time // Time of the next tick
+	ch       chan time.Time
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.ch }
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	for i, other := range t.clock.tickers {
+		if other == t {
+			t.clock.tickers = append(t.clock.tickers[:i], t.clock.tickers[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package sheetkv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := sheetkv.NewFakeClock(start)
+
+	ticker := clock.NewTicker(time.Minute)
+	after := clock.After(90 * time.Second)
+
+	clock.Advance(59 * time.Second)
+	select {
+	case <-ticker.C():
+		t.Fatal("ticker fired early")
+	default:
+	}
+
+	clock.Advance(time.Second)
+	if tick := <-ticker.C(); !tick.Equal(start.Add(time.Minute)) {
+		t.Errorf("tick = %s, want %s", tick, start.Add(time.Minute))
+	}
+
+	// Ticks the receiver is not ready for are dropped
+	clock.Advance(3 * time.Minute)
+	if at := <-after; !at.Equal(start.Add(90 * time.Second)) {
+		t.Errorf("After() = %s, want %s", at, start.Add(90*time.Second))
+	}
+	<-ticker.C()
+	select {
+	case <-ticker.C():
+		t.Error("ticker kept more than one pending tick")
+	default:
+	}
+	if now := clock.Now(); !now.Equal(start.Add(4 * time.Minute)) {
+		t.Errorf("Now() = %s, want %s", now, start.Add(4*time.Minute))
+	}
+
+	ticker.Stop()
+	clock.Advance(time.Hour)
+	select {
+	case <-ticker.C():
+		t.Error("stopped ticker fired")
+	default:
+	}
+}
+
+func TestClient_FakeClock(t *testing.T) {
+	clock := sheetkv.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	data := &indexedAdapter{memoryAdapter: newMemoryAdapter([]string{"name"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John"}},
+	)}
+	client := sheetkv.New(data, &sheetkv.Config{
+		SyncInterval:    time.Minute,
+		ReadThroughTTL:  10 * time.Minute,
+		UpdatedAtColumn: "updated_at",
+		Clock:           clock,
+	})
+	defer client.Close()
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	// Timestamps come from the clock
+	if err := client.Update(2, map[string]interface{}{"name": "Johnny"}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if record, _ := client.Get(2); record.Values["updated_at"] != "2025-01-01T00:00:00Z" {
+		t.Errorf("updated_at = %v, want the clock's time", record.Values["updated_at"])
+	}
+
+	// The periodic sync runs when the clock reaches the interval
+	if data.saveCount() != 0 {
+		t.Fatalf("saves = %d before the interval", data.saveCount())
+	}
+	clock.Advance(time.Minute)
+	waitFor(t, "the periodic sync", func() bool { return data.saveCount() == 1 })
+
+	// Read-through TTLs follow the clock
+	data.mu.Lock()
+	data.records[0].Values["name"] = "Jonathan"
+	data.mu.Unlock()
+	if record, _ := client.Get(2); record.Values["name"] != "Johnny" {
+		t.Errorf("fresh Get(2) = %v, want the cached value", record.Values)
+	}
+	clock.Advance(10 * time.Minute)
+	if record, _ := client.Get(2); record.Values["name"] != "Jonathan" {
+		t.Errorf("stale Get(2) = %v, want the sheet's value", record.Values)
+	}
+}
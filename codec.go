@@ -0,0 +1,105 @@
+package sheetkv
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"io"
+	"strings"
+)
+
+// compressedPrefix marks cell values stored gzip-compressed and
+// base64-encoded (see Config.CompressColumns). Values without it are loaded
+// as is, so columns can opt in after data was written uncompressed.
+const compressedPrefix = "gz:"
+
+// compressValue returns s compressed and encoded with the marker prefix
+func compressValue(s string) string {
+	var buf bytes.Buffer
+	w, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	w.Write([]byte(s))
+	w.Close()
+	return compressedPrefix + base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+// decompressValue decodes a value written by compressValue. ok is false
+// when s is not a compressed value, including legacy text that merely starts
+// with the prefix.
+func decompressValue(s string) (string, bool) {
+	encoded, found := strings.CutPrefix(s, compressedPrefix)
+	if !found {
+		return "", false
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", false
+	}
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return "", false
+	}
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		return "", false
+	}
+	return string(decoded), true
+}
+
+// encodeRecords returns the records as stored in the sheet: the text of
+// compressed columns is compressed, then values still too long are moved to
+// the overflow store (see Config.OverflowStore). Records without such values
+// are returned as is, the others are copied.
+func (c *Client) encodeRecords(ctx context.Context, records []*Record) ([]*Record, error) {
+	if len(c.config.CompressColumns) == 0 && c.config.OverflowStore == nil {
+		return records, nil
+	}
+
+	encoded := make([]*Record, len(records))
+	for i, record := range records {
+		encoded[i] = record
+		for _, col := range c.config.CompressColumns {
+			s, ok := record.Values[col].(string)
+			if !ok || s == "" {
+				continue
+			}
+			if encoded[i] == record {
+				encoded[i] = &Record{Key: record.Key, Revision: record.Revision, Values: copyValues(record.Values)}
+			}
+			encoded[i].Values[col] = compressValue(s)
+		}
+	}
+
+	if c.config.OverflowStore != nil {
+		if err := c.overflowRecords(ctx, encoded, records); err != nil {
+			return nil, err
+		}
+	}
+	return encoded, nil
+}
+
+// decodeRecords restores the values of records just loaded from the
+// adapter: references are resolved from the overflow store, then compressed
+// columns are decompressed and, with Config.NormalizeUnicode, strings are
+// NFC-normalized
+func (c *Client) decodeRecords(ctx context.Context, records []*Record) error {
+	if c.config.OverflowStore != nil {
+		if err := c.resolveRecords(ctx, records); err != nil {
+			return err
+		}
+	}
+
+	for _, record := range records {
+		for _, col := range c.config.CompressColumns {
+			if s, ok := record.Values[col].(string); ok {
+				if decoded, ok := decompressValue(s); ok {
+					record.Values[col] = decoded
+				}
+			}
+		}
+	}
+	if c.config.NormalizeUnicode {
+		normalizeRecords(records)
+	}
+	return nil
+}
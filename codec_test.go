@@ -0,0 +1,76 @@
+package sheetkv_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_CompressColumns(t *testing.T) {
+	ctx := context.Background()
+	body := strings.Repeat("Lorem ipsum dolor sit amet. ", 5000)
+
+	adapter := newMemoryAdapter([]string{"title", "body"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"title": "legacy", "body": "plain text"}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"title": "marker", "body": "gz:not compressed"}},
+	)
+	config := &sheetkv.Config{DisableAutoSync: true, CompressColumns: []string{"body"}}
+	client := sheetkv.New(adapter, config)
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	t.Run("Legacy values", func(t *testing.T) {
+		for key, want := range map[int]string{2: "plain text", 3: "gz:not compressed"} {
+			record, err := client.Get(key)
+			if err != nil || record.GetAsString("body", "") != want {
+				t.Errorf("Get(%d) = %v, %v, want body %q", key, record, err, want)
+			}
+		}
+	})
+
+	t.Run("Compressed on save", func(t *testing.T) {
+		client.Append(&sheetkv.Record{Values: map[string]interface{}{"title": "long", "body": body}})
+		if err := client.Sync(); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+
+		records, _, _ := adapter.Load(ctx)
+		for _, record := range records {
+			stored := record.GetAsString("body", "")
+			switch record.Key {
+			case 4:
+				if !strings.HasPrefix(stored, "gz:") || len(stored) >= len(body)/10 {
+					t.Errorf("stored body = %d chars, want a compressed value", len(stored))
+				}
+			default:
+				if !strings.HasPrefix(stored, "gz:") {
+					t.Errorf("stored body of %d = %q, want compressed on save", record.Key, stored)
+				}
+			}
+			if got := record.GetAsString("title", ""); strings.HasPrefix(got, "gz:") {
+				t.Errorf("title = %q, want uncompressed", got)
+			}
+		}
+
+		record, _ := client.Get(4)
+		if got := record.GetAsString("body", ""); got != body {
+			t.Errorf("Get(4) body = %d chars, want the original %d", len(got), len(body))
+		}
+	})
+
+	t.Run("Decompressed on load", func(t *testing.T) {
+		reloaded := sheetkv.New(adapter, config)
+		if err := reloaded.Initialize(ctx); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		for key, want := range map[int]string{2: "plain text", 3: "gz:not compressed", 4: body} {
+			record, err := reloaded.Get(key)
+			if err != nil || record.GetAsString("body", "") != want {
+				t.Errorf("Get(%d) body = %.20q, %v, want %.20q", key, record.GetAsString("body", ""), err, want)
+			}
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package sheetkv
+
+import (
+	"fmt"
+	"sync"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// Collation compares strings by the rules of a language instead of byte by
+// byte, so that accented and Japanese text matches and sorts the way
+// spreadsheet users expect. It is safe for concurrent use.
+type Collation struct {
+	tag      language.Tag
+	options  []collate.Option
+	collator sync.Pool // *collate.Collator, which is not safe for concurrent use
+}
+
+// NewCollation creates a collation for tag. Options such as
+// collate.IgnoreCase, collate.IgnoreDiacritics or collate.IgnoreWidth
+// (half- and full-width kana and ASCII) make more strings equal.
+func NewCollation(tag language.Tag, options ...collate.Option) *Collation {
+	c := &Collation{tag: tag, options: options}
+	c.collator.New = func() interface{} {
+		return collate.New(c.tag, c.options...)
+	}
+	return c
+}
+
+// Compare returns -1, 0 or 1 depending on whether a sorts before, with or
+// after b
+func (c *Collation) Compare(a, b string) int {
+	collator := c.collator.Get().(*collate.Collator)
+	defer c.collator.Put(collator)
+	return collator.CompareString(a, b)
+}
+
+// SortFunc returns a Query.SortFunc ordering records by the text of column,
+// records without the column first
+func (c *Collation) SortFunc(column string, descending bool) func(a, b *Record) bool {
+	return func(a, b *Record) bool {
+		av, aok := a.Values[column]
+		bv, bok := b.Values[column]
+		switch {
+		case !aok || av == nil:
+			return !descending && bok && bv != nil
+		case !bok || bv == nil:
+			return descending
+		}
+		cmp := c.Compare(fmt.Sprintf("%v", av), fmt.Sprintf("%v", bv))
+		if descending {
+			return cmp > 0
+		}
+		return cmp < 0
+	}
+}
+
+// comparison describes how the strings of queries are compared
+type comparison struct {
+	fallback  *Collation            // Collation of the other columns, nil for byte comparison
+	columns   map[string]*Collation // Per-column collations
+	normalize bool                  // NFC-normalize strings before comparing them
+}
+
+// collation returns the collation of column, or nil for byte comparison
+func (cmp *comparison) collation(column string) *Collation {
+	if cmp == nil {
+		return nil
+	}
+	if c, ok := cmp.columns[column]; ok {
+		return c
+	}
+	return cmp.fallback
+}
+
+// normalizes reports whether strings are NFC-normalized before comparison
+func (cmp *comparison) normalizes() bool {
+	return cmp != nil && cmp.normalize
+}
+
+// collatedStrings returns a and b as strings when they are compared by c:
+// both non-numeric and non-nil, and c set
+func collatedStrings(c *Collation, a, b interface{}) (string, string, bool) {
+	if c == nil || a == nil || b == nil || isNumeric(a) || isNumeric(b) {
+		return "", "", false
+	}
+	return fmt.Sprintf("%v", a), fmt.Sprintf("%v", b), true
+}
+
+// SetCollation compares the strings of queries with fallback, or with the
+// collation of columns for the columns it lists. Nil collations compare
+// bytes. Conditions on collated columns are not answered from the index.
+func (c *Cache) SetCollation(fallback *Collation, columns map[string]*Collation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.setComparison(comparison{fallback: fallback, columns: columns, normalize: c.comparison.normalizes()})
+}
+
+// setComparison replaces how strings are compared. Callers must hold the
+// write lock.
+func (c *Cache) setComparison(cmp comparison) {
+	if cmp.fallback == nil && len(cmp.columns) == 0 && !cmp.normalize {
+		c.comparison = nil
+	} else {
+		c.comparison = &cmp
+	}
+	if c.queries != nil {
+		c.queries.reset()
+	}
+}
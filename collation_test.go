@@ -0,0 +1,107 @@
+package sheetkv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+func TestCollation_Compare(t *testing.T) {
+	tests := []struct {
+		name string
+		c    *sheetkv.Collation
+		a, b string
+		want int
+	}{
+		{"accent sorts after its base letter", sheetkv.NewCollation(language.French), "é", "f", -1},
+		{"case ignored", sheetkv.NewCollation(language.English, collate.IgnoreCase), "Resume", "resume", 0},
+		{"case and diacritics ignored", sheetkv.NewCollation(language.English, collate.IgnoreCase, collate.IgnoreDiacritics), "Résumé", "resume", 0},
+		{"width ignored", sheetkv.NewCollation(language.Japanese, collate.IgnoreWidth), "ｶﾀｶﾅ", "カタカナ", 0},
+		{"hiragana before kanji", sheetkv.NewCollation(language.Japanese), "あ", "亜", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.Compare(tt.a, tt.b); got != tt.want {
+				t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Collation(t *testing.T) {
+	ctx := context.Background()
+	adapter := newMemoryAdapter([]string{"name", "code"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "Émile", "code": "A"}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "emile", "code": "a"}},
+		&sheetkv.Record{Key: 4, Values: map[string]interface{}{"name": "Zoé", "code": "B"}},
+		&sheetkv.Record{Key: 5, Values: map[string]interface{}{"name": "Fabien", "code": "b"}},
+	)
+	loose := sheetkv.NewCollation(language.French, collate.IgnoreCase, collate.IgnoreDiacritics)
+	client := sheetkv.New(adapter, &sheetkv.Config{
+		DisableAutoSync:  true,
+		IndexColumns:     []string{"name"},
+		Collation:        loose,
+		ColumnCollations: map[string]*sheetkv.Collation{"code": nil},
+	})
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	defer client.Close()
+
+	keys := func(records []*sheetkv.Record) []int {
+		result := make([]int, len(records))
+		for i, r := range records {
+			result[i] = r.Key
+		}
+		return result
+	}
+	sameKeys := func(got []int, want ...int) bool {
+		if len(got) != len(want) {
+			return false
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	t.Run("Collated equality", func(t *testing.T) {
+		records, err := client.Query(sheetkv.Query{
+			Conditions: []sheetkv.Condition{{Column: "name", Operator: "==", Value: "EMILE"}},
+			SortFunc:   func(a, b *sheetkv.Record) bool { return a.Key < b.Key },
+		})
+		if err != nil || !sameKeys(keys(records), 2, 3) {
+			t.Errorf("Query() = %v, %v, want keys 2 and 3 despite the index", keys(records), err)
+		}
+	})
+
+	t.Run("Byte comparison override", func(t *testing.T) {
+		records, _ := client.Query(sheetkv.Query{Conditions: []sheetkv.Condition{{Column: "code", Operator: "==", Value: "a"}}})
+		if !sameKeys(keys(records), 3) {
+			t.Errorf("Query() = %v, want only key 3", keys(records))
+		}
+	})
+
+	t.Run("Collated range", func(t *testing.T) {
+		records, _ := client.Query(sheetkv.Query{
+			Conditions: []sheetkv.Condition{{Column: "name", Operator: "<", Value: "f"}},
+			SortFunc:   func(a, b *sheetkv.Record) bool { return a.Key < b.Key },
+		})
+		if !sameKeys(keys(records), 2, 3) {
+			t.Errorf("Query(< f) = %v, want keys 2 and 3", keys(records))
+		}
+	})
+
+	t.Run("Collated sort", func(t *testing.T) {
+		strict := sheetkv.NewCollation(language.French)
+		records, _ := client.Query(sheetkv.Query{SortFunc: strict.SortFunc("name", false)})
+		if !sameKeys(keys(records), 3, 2, 5, 4) {
+			t.Errorf("sorted keys = %v, want emile, Émile, Fabien, Zoé", keys(records))
+		}
+	})
+}
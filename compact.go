@@ -0,0 +1,122 @@
+package sheetkv
+
+import (
+	"context"
+	"fmt"
+)
+
+// Compact runs a compacting sync, which removes the gaps left by deleted
+// rows, and returns the old key → new key of every record it moved; keys
+// the application handed out earlier can be fixed up with it. The cache
+// uses the new keys once Compact returns, and Config.OnCompact receives
+// the same map. It is empty when nothing moved.
+func (c *Client) Compact(ctx context.Context) (map[int]int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, fmt.Errorf("client is closed")
+	}
+
+	c.remapped = nil
+	if err := c.save(ctx, SyncStrategyCompacting, true); err != nil {
+		return nil, err
+	}
+	remapped := c.remapped
+	if remapped == nil {
+		remapped = map[int]int{}
+	}
+	return remapped, nil
+}
+
+// compactedKeys returns the keys of records sorted by key after a
+// compacting save, which writes them from row 2 on, for those that move
+func compactedKeys(records []*Record) map[int]int {
+	remap := make(map[int]int)
+	for i, record := range records {
+		if record.Key != i+2 {
+			remap[record.Key] = i + 2
+		}
+	}
+	return remap
+}
+
+// renumber moves the records of the cache and the state kept per key to the
+// keys of a compacting save, returning the saved records with their new
+// keys, and reports the moves to Config.OnCompact
+func (c *Client) renumber(records []*Record) []*Record {
+	remap := compactedKeys(records)
+	c.remapped = remap
+	if len(remap) == 0 {
+		return records
+	}
+
+	renumbered := make([]*Record, len(records))
+	for i, record := range records {
+		renumbered[i] = &Record{Key: i + 2, Values: record.Values, Revision: record.Revision}
+	}
+	c.cache.renumber(remap)
+
+	c.historyMu.Lock()
+	if c.history != nil {
+		history := make(map[int][]*RecordVersion, len(c.history))
+		for key, versions := range c.history {
+			if moved, ok := remap[key]; ok {
+				key = moved
+			}
+			history[key] = versions
+		}
+		c.history = history
+	}
+	c.historyMu.Unlock()
+
+	c.readMu.Lock()
+	c.fetchedAt = nil
+	c.readMu.Unlock()
+
+	if c.config.OnCompact != nil {
+		c.config.OnCompact(remap)
+	}
+	return renumbered
+}
+
+// renumber moves the records to the keys of remap, old key → new key. The
+// old and new keys of the moved records enter the change feed.
+func (c *Cache) renumber(remap map[int]int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.own()
+	data := make(map[int]*Record, len(c.data))
+	dirty := make(map[int]bool, len(c.dirty))
+	saved := make(map[int]uint64, len(c.saved))
+	for key, record := range c.data {
+		if moved, ok := remap[key]; ok {
+			copied := c.copyRecord(record)
+			copied.Key = moved
+			record = copied
+			key = moved
+		}
+		data[key] = record
+	}
+	for key := range c.dirty {
+		if moved, ok := remap[key]; ok {
+			key = moved
+		}
+		dirty[key] = true
+	}
+	for key, hash := range c.saved {
+		if moved, ok := remap[key]; ok {
+			key = moved
+		}
+		saved[key] = hash
+	}
+	c.data, c.dirty, c.saved = data, dirty, saved
+
+	for old, moved := range remap {
+		c.nextRevision(old)
+		data[moved].Revision = c.nextRevision(moved)
+	}
+	c.rebuildIndex()
+	c.resetQueries()
+}
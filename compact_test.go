@@ -0,0 +1,93 @@
+package sheetkv_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_Compact(t *testing.T) {
+	ctx := context.Background()
+	adapter := newMemoryAdapter([]string{"name", "dept"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "a", "dept": "Eng"}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "b", "dept": "Sales"}},
+		&sheetkv.Record{Key: 5, Values: map[string]interface{}{"name": "c", "dept": "Eng"}},
+		&sheetkv.Record{Key: 6, Values: map[string]interface{}{"name": "d", "dept": "Sales"}},
+	)
+	var reported map[int]int
+	client := sheetkv.New(adapter, &sheetkv.Config{
+		DisableAutoSync: true,
+		IndexColumns:    []string{"dept"},
+		OnCompact:       func(remap map[int]int) { reported = remap },
+	})
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	defer client.Close()
+	_, since, _ := client.Changes(0)
+
+	if err := client.Delete(3); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	remap, err := client.Compact(ctx)
+	if err != nil {
+		t.Fatalf("Compact() error = %v", err)
+	}
+	want := map[int]int{5: 3, 6: 4}
+	if !reflect.DeepEqual(remap, want) || !reflect.DeepEqual(reported, want) {
+		t.Errorf("Compact() = %v, OnCompact = %v, want %v", remap, reported, want)
+	}
+
+	for key, name := range map[int]string{2: "a", 3: "c", 4: "d"} {
+		record, err := client.Get(key)
+		if err != nil || record.Values["name"] != name || record.Key != key {
+			t.Errorf("Get(%d) = %v, %v, want %s", key, record, err, name)
+		}
+	}
+	if _, err := client.Get(5); err != sheetkv.ErrKeyNotFound {
+		t.Errorf("Get(5) error = %v, want ErrKeyNotFound", err)
+	}
+	sales, err := client.Lookup(ctx, "dept", "Sales")
+	if err != nil || len(sales) != 1 || sales[0].Key != 4 {
+		t.Errorf("Lookup(Sales) = %v, %v, want the record at 4", sales, err)
+	}
+	feed, _, _ := client.Changes(since)
+	present := make(map[int]bool)
+	for _, change := range feed {
+		present[change.Key] = change.Record != nil
+	}
+	if !reflect.DeepEqual(present, map[int]bool{3: true, 4: true, 5: false, 6: false}) {
+		t.Errorf("Changes() = %v, want 3 and 4 present, 5 and 6 removed", feed)
+	}
+
+	t.Run("Nothing to move", func(t *testing.T) {
+		saves := adapter.saveCount()
+		remap, err := client.Compact(ctx)
+		if err != nil || len(remap) != 0 {
+			t.Errorf("Compact() = %v, %v, want an empty map", remap, err)
+		}
+		if adapter.saveCount() != saves {
+			t.Error("Compact() saved a compacted sheet without changes")
+		}
+	})
+
+	t.Run("Closes gaps without changes", func(t *testing.T) {
+		adapter := newMemoryAdapter([]string{"name"},
+			&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "a"}},
+			&sheetkv.Record{Key: 4, Values: map[string]interface{}{"name": "b"}},
+		)
+		client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+		client.Initialize(ctx)
+		defer client.Close()
+
+		remap, err := client.Compact(ctx)
+		if err != nil || !reflect.DeepEqual(remap, map[int]int{4: 3}) {
+			t.Errorf("Compact() = %v, %v, want 4 moved to 3", remap, err)
+		}
+		if adapter.saveCount() != 1 {
+			t.Errorf("saves = %d, want 1", adapter.saveCount())
+		}
+	})
+}
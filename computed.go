@@ -0,0 +1,141 @@
+package sheetkv
+
+// ComputeFunc derives the value of a computed column from a record. It must
+// not modify the record.
+type ComputeFunc func(r *Record) interface{}
+
+// computedColumn is a column whose value is derived by the client
+type computedColumn struct {
+	name    string
+	fn      ComputeFunc
+	persist bool // Written to the sheet on sync
+}
+
+// AddComputedColumn defines a column derived from the other values of each
+// record. Its values are available to Get and Query (and can be indexed),
+// but are never written to the sheet.
+func (c *Client) AddComputedColumn(name string, fn ComputeFunc) {
+	c.cache.AddComputedColumn(name, fn, false)
+}
+
+// AddStoredColumn defines a computed column like AddComputedColumn whose
+// values are also written to the sheet on sync, for readers of the sheet
+// itself
+func (c *Client) AddStoredColumn(name string, fn ComputeFunc) {
+	c.cache.AddComputedColumn(name, fn, true)
+}
+
+// AddComputedColumn defines a computed column and computes it for the
+// records already loaded. Columns are computed in the order they are added,
+// so a column can use the ones defined before it.
+func (c *Cache) AddComputedColumn(name string, fn ComputeFunc, persist bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, col := range c.computed {
+		if col.name == name {
+			c.computed = append(c.computed[:i], c.computed[i+1:]...)
+			break
+		}
+	}
+	c.computed = append(c.computed, computedColumn{name: name, fn: fn, persist: persist})
+
+	if persist {
+		c.addStoredColumns()
+	} else {
+		for i, col := range c.schema {
+			if col == name {
+				c.schema = append(c.schema[:i:i], c.schema[i+1:]...)
+				break
+			}
+		}
+	}
+
+	// Compute on copies so snapshots keep the previous versions
+	c.own()
+	for key, record := range c.data {
+		before := c.hash(record)
+		updated := c.copyRecord(record)
+		c.compute(updated)
+		if hash := c.hash(updated); hash != before {
+			updated.Revision = c.nextRevision(key)
+			if hash != c.saved[key] {
+				c.dirty[key] = true
+			}
+		}
+		c.data[key] = updated
+		c.release(record)
+	}
+	c.rebuildIndex()
+	c.resetQueries()
+}
+
+// compute sets the computed columns of a record owned by the cache.
+// Callers must hold the write lock.
+func (c *Cache) compute(record *Record) {
+	for _, col := range c.computed {
+		if value := col.fn(record); value != nil {
+			record.Values[col.name] = value
+		} else {
+			delete(record.Values, col.name)
+		}
+	}
+}
+
+// isVirtual reports whether col is a computed column kept out of the sheet
+func (c *Cache) isVirtual(col string) bool {
+	for _, computed := range c.computed {
+		if computed.name == col {
+			return !computed.persist
+		}
+	}
+	return false
+}
+
+// addStoredColumns appends the stored computed columns missing from the
+// schema. Callers must hold the write lock.
+func (c *Cache) addStoredColumns() {
+	for _, col := range c.computed {
+		if col.persist && !containsString(c.schema, col.name) {
+			c.schema = append(c.schema, col.name)
+		}
+	}
+}
+
+// hash returns the content hash of a record, ignoring virtual columns so
+// they never count as unsaved changes
+func (c *Cache) hash(record *Record) uint64 {
+	virtual := false
+	for _, col := range c.computed {
+		if !col.persist {
+			if _, ok := record.Values[col.name]; ok {
+				virtual = true
+				break
+			}
+		}
+	}
+	if !virtual {
+		return hashRecord(record)
+	}
+
+	values := make(map[string]interface{}, len(record.Values))
+	for col, value := range record.Values {
+		if !c.isVirtual(col) {
+			values[col] = value
+		}
+	}
+	return hashRecord(&Record{Key: record.Key, Values: values})
+}
+
+// isComputed reports whether col is a computed column
+func (c *Cache) isComputed(col string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, computed := range c.computed {
+		if computed.name == col {
+			return true
+		}
+	}
+	return false
+}
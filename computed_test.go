@@ -0,0 +1,105 @@
+package sheetkv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func total(r *sheetkv.Record) interface{} {
+	price, _ := r.Values["price"].(int64)
+	quantity, _ := r.Values["quantity"].(int64)
+	return price * quantity
+}
+
+func TestClient_ComputedColumn(t *testing.T) {
+	adapter := newMemoryAdapter([]string{"price", "quantity"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"price": int64(100), "quantity": int64(2)}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"price": int64(50), "quantity": int64(1)}},
+	)
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	client.AddComputedColumn("total", total)
+
+	t.Run("Available to Get and Query", func(t *testing.T) {
+		record, _ := client.Get(2)
+		if record.Values["total"] != int64(200) {
+			t.Errorf("total = %v, want 200", record.Values["total"])
+		}
+
+		results, err := client.Query(sheetkv.Query{
+			Conditions: []sheetkv.Condition{{Column: "total", Operator: ">", Value: 100}},
+		})
+		if err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+		if len(results) != 1 || results[0].Key != 2 {
+			t.Errorf("Query() = %v, want row 2", results)
+		}
+	})
+
+	t.Run("Recomputed on writes", func(t *testing.T) {
+		if err := client.Update(3, map[string]interface{}{"quantity": int64(4)}); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if record, _ := client.Get(3); record.Values["total"] != int64(200) {
+			t.Errorf("total = %v, want 200", record.Values["total"])
+		}
+
+		if err := client.Append(&sheetkv.Record{Values: map[string]interface{}{"price": int64(10), "quantity": int64(3)}}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+		if record, _ := client.Get(4); record.Values["total"] != int64(30) {
+			t.Errorf("total = %v, want 30", record.Values["total"])
+		}
+	})
+
+	t.Run("Not written to the sheet", func(t *testing.T) {
+		if err := client.Sync(); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+		adapter.mu.Lock()
+		schema := adapter.schema
+		adapter.mu.Unlock()
+		if len(schema) != 2 {
+			t.Errorf("saved schema = %v, want the computed column left out", schema)
+		}
+		saves := adapter.saveCount()
+		if err := client.Sync(); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+		if got := adapter.saveCount(); got != saves {
+			t.Errorf("saves = %d, want %d (computed values are not unsaved changes)", got, saves)
+		}
+	})
+}
+
+func TestClient_StoredColumn(t *testing.T) {
+	adapter := newMemoryAdapter([]string{"price", "quantity"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"price": int64(100), "quantity": int64(2)}},
+	)
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+	client.AddStoredColumn("total", total)
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	if err := client.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if adapter.saveCount() != 1 {
+		t.Fatalf("saves = %d, want 1 to write the stored column", adapter.saveCount())
+	}
+
+	adapter.mu.Lock()
+	defer adapter.mu.Unlock()
+	if len(adapter.schema) != 3 || adapter.schema[2] != "total" {
+		t.Errorf("saved schema = %v, want total appended", adapter.schema)
+	}
+	if adapter.records[0].Values["total"] != int64(200) {
+		t.Errorf("saved total = %v, want 200", adapter.records[0].Values["total"])
+	}
+}
@@ -1,10 +1,195 @@
 package sheetkv
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+// Defaults of the zero values of Config, filled in by Normalize
+const (
+	DefaultSyncInterval  = 30 * time.Second
+	DefaultMaxRetries    = 3
+	DefaultRetryInterval = time.Second
+)
 
 // Config represents configuration for the KVS client
 type Config struct {
-	SyncInterval  time.Duration // Interval for periodic sync (default: 30s)
-	MaxRetries    int           // Maximum number of retries for API calls (default: 3)
-	RetryInterval time.Duration // Base interval between retries for exponential backoff (default: 1s)
+	SyncInterval           time.Duration             // Interval for periodic sync (default: DefaultSyncInterval)
+	DisableAutoSync        bool                      // Do not sync periodically; changes are saved by Sync and Close only
+	Consistency            Consistency               // Preset of WriteThrough, ReadThroughTTL and SyncInterval, see Strong, BoundedStaleness and Eventual
+	WriteThrough           bool                      // Persist every write through the adapter's BatchUpdate before it returns
+	ReadThroughTTL         time.Duration             // Refresh rows and queries older than this from the adapter on reads (0: disabled)
+	MaxRetries             int                       // Maximum number of retries for API calls (default: DefaultMaxRetries)
+	RetryInterval          time.Duration             // Base interval between retries for exponential backoff (default: DefaultRetryInterval)
+	MaxElapsedRetryTime    time.Duration             // Total time allowed for the attempts of one API call, including waits (0: unbounded)
+	RateLimiter            *RateLimiter              // Optional limiter applied to every adapter call, may be shared between clients
+	IndexColumns           []string                  // Columns kept in the secondary index for fast equality lookups
+	PersistIndex           bool                      // Persist the index through the adapter (requires IndexStore) after each sync
+	DetectRemoteChanges    bool                      // Refuse to save when the spreadsheet revision (requires RevisionSource) changed since the last sync
+	OnConflict             func(err error)           // Called when a save is refused because of a remote change
+	OnCompact              func(remap map[int]int)   // Called after a compacting sync with the old key -> new key of the records it moved
+	AfterSave              AfterSaveHook             // Called after each sync that wrote the sheet, to format it (see googlesheets.StyleAfterSave); its error fails the sync
+	MergePolicy            *MergePolicy              // How Reload combines records modified locally with their remote versions (default: the local records win)
+	TailAppends            bool                      // Read-only mode for append-only sheets, such as Google Forms responses: syncs read the new rows (see Client.Tail) and writes fail with ErrReadOnly
+	LeaseDuration          time.Duration             // Hold the sheet's lease (requires LeaseStore) from each save for this long, refusing saves while another process holds it (0: disabled)
+	LeaseOwner             string                    // Identifies this process in the lease and row locks (default: host name and process ID)
+	ElectLeader            bool                      // Run background syncs only while holding the lease (requires LeaseDuration); other clients reload the sheet instead
+	OnLeaderChange         func(leader bool)         // Called when this client gains or loses the leadership of ElectLeader
+	Locker                 Locker                    // Held around each save and write-through, instead of the lease
+	LockColumn             string                    // Column holding the row locks of Client.Lock (default: DefaultLockColumn)
+	RecordIDs              bool                      // Give every record a UUID in IDColumn, its durable identity for GetByID
+	IDColumn               string                    // Column holding the IDs of RecordIDs (default: DefaultIDColumn)
+	CreatedAtColumn        string                    // Column stamped with the current time on Append and Set of a new key, unless already set
+	UpdatedAtColumn        string                    // Column stamped with the current time on Append, Set and Update
+	TimeFormat             string                    // Layout of the stamped times (default: time.RFC3339)
+	AuditAdapter           Adapter                   // Adapter of the audit tab receiving one row per synced mutation
+	AuditActor             string                    // Value of the "actor" column of audit rows
+	HistoryLimit           int                       // Prior versions kept in memory per record (0: disabled)
+	HistoryAdapter         Adapter                   // Adapter of the history tab receiving replaced versions after each sync
+	Views                  []View                    // Derived tables saved to their own tabs after each sync when their content changed
+	KeepSyncSnapshot       bool                      // Keep a copy of the last synced data for RollbackToLastSync
+	QueryCacheSize         int                       // Number of query results memoized until a write affects them (0: disabled)
+	ValidationRules        []ColumnRule              // Rules enforced on every write
+	EnforceSheetValidation bool                      // Also enforce the sheet's strict data-validation rules (requires ValidationRuleSource)
+	Validators             []Validator               // Checks of whole records run on every write before the cache accepts it
+	Templates              map[string]RecordTemplate // Record templates of NewRecordFromTemplate, by name
+	ColumnOrder            ColumnOrder               // Order of the sheet columns (default: ColumnOrderAppend)
+	Columns                []string                  // Columns in their declared order, for ColumnOrderDeclared
+	PruneOnCompact         bool                      // Remove columns without values on compacting syncs (see PruneSchema)
+	StrictSchema           bool                      // Reject writes to columns outside Columns (or the loaded schema) with ErrUnknownColumn
+	CellLimit              int                       // Cells allowed in the spreadsheet across tabs, counted with CellCounter (0: unchecked)
+	CellLimitWarning       float64                   // Fraction of CellLimit from which Stats.NearCellLimit is set (default: 0.8)
+	CellLimitPolicy        CellLimitPolicy           // What happens to writes past CellLimit (default: CellLimitError)
+	OnCellLimit            OverflowFunc              // Returns the adapter appends roll over to, for CellLimitNewTab and CellLimitNewSpreadsheet
+	CompressColumns        []string                  // Columns whose text is stored gzip-compressed and base64-encoded behind a "gz:" marker
+	OverflowStore          OverflowStore             // Store of values still too long for a cell, which keeps a "ref:" token instead
+	OverflowThreshold      int                       // Length from which values go to OverflowStore (default: DefaultOverflowThreshold)
+	BlobStore              BlobStore                 // Store of attachment content for SetAttachment and GetAttachment
+	Jobs                   []Job                     // Maintenance tasks run periodically, see ExpireJob, CompactJob, RefreshIndexJob and SnapshotJob
+	OnJob                  func(JobResult)           // Called after each run of a job
+	Collation              *Collation                // Comparison of strings in queries (default: byte comparison)
+	ColumnCollations       map[string]*Collation     // Per-column comparison of strings, overriding Collation
+	NormalizeUnicode       bool                      // NFC-normalize strings on load and when evaluating conditions, so NFD text from macOS matches
+	OperationLogSize       int                       // Operations kept in memory for OperationsSince (0: disabled)
+	Publisher              Publisher                 // Message bus receiving a ChangeEvent per mutation after each successful sync
+	PublishTopic           string                    // Topic of the published events (default: DefaultPublishTopic)
+	OnProgress             func(Progress)            // Called as loads, saves and imports progress, see Progress
+	Clock                  Clock                     // Source of time of syncs, jobs, TTLs, leases, locks and timestamps (default: SystemClock)
+}
+
+// Validate reports the settings without a defined meaning: negative
+// durations, sizes and limits, SyncInterval set with DisableAutoSync,
+// Locker set with LeaseDuration, ElectLeader without a lease renewed by the
+// periodic sync, TailAppends with WriteThrough or ElectLeader, views without
+// an adapter, and settings overridden by Consistency.
+// Zero values are valid and mean the default documented on each field.
+func (c *Config) Validate() error {
+	durations := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"SyncInterval", c.SyncInterval},
+		{"RetryInterval", c.RetryInterval},
+		{"MaxElapsedRetryTime", c.MaxElapsedRetryTime},
+		{"ReadThroughTTL", c.ReadThroughTTL},
+		{"LeaseDuration", c.LeaseDuration},
+	}
+	for _, d := range durations {
+		if d.value < 0 {
+			return fmt.Errorf("%w: %s must not be negative, got %s", ErrInvalidConfig, d.name, d.value)
+		}
+	}
+
+	counts := []struct {
+		name  string
+		value int
+	}{
+		{"MaxRetries", c.MaxRetries},
+		{"HistoryLimit", c.HistoryLimit},
+		{"QueryCacheSize", c.QueryCacheSize},
+		{"CellLimit", c.CellLimit},
+		{"OverflowThreshold", c.OverflowThreshold},
+		{"OperationLogSize", c.OperationLogSize},
+	}
+	for _, n := range counts {
+		if n.value < 0 {
+			return fmt.Errorf("%w: %s must not be negative, got %d", ErrInvalidConfig, n.name, n.value)
+		}
+	}
+
+	if c.DisableAutoSync && c.SyncInterval > 0 {
+		return fmt.Errorf("%w: SyncInterval is set but DisableAutoSync turns the periodic sync off", ErrInvalidConfig)
+	}
+	if c.ElectLeader {
+		interval := c.SyncInterval
+		if interval == 0 {
+			interval = DefaultSyncInterval
+		}
+		switch {
+		case c.LeaseDuration <= 0:
+			return fmt.Errorf("%w: ElectLeader requires LeaseDuration", ErrInvalidConfig)
+		case c.DisableAutoSync:
+			return fmt.Errorf("%w: ElectLeader requires the periodic sync", ErrInvalidConfig)
+		case interval >= c.LeaseDuration/2:
+			return fmt.Errorf("%w: SyncInterval %s must be under half of LeaseDuration %s to renew the lease", ErrInvalidConfig, interval, c.LeaseDuration)
+		}
+	}
+	if c.TailAppends && (c.WriteThrough || c.ElectLeader) {
+		return fmt.Errorf("%w: TailAppends refuses the writes of WriteThrough and ElectLeader", ErrInvalidConfig)
+	}
+	if c.Locker != nil && c.LeaseDuration > 0 {
+		return fmt.Errorf("%w: Locker replaces the lease of LeaseDuration", ErrInvalidConfig)
+	}
+	for i, view := range c.Views {
+		if view.Adapter == nil {
+			return fmt.Errorf("%w: view %d (%q) has no Adapter", ErrInvalidConfig, i, view.Name)
+		}
+	}
+	if c.CellLimitWarning < 0 || c.CellLimitWarning > 1 {
+		return fmt.Errorf("%w: CellLimitWarning must be between 0 and 1, got %v", ErrInvalidConfig, c.CellLimitWarning)
+	}
+	return c.Consistency.validate(c)
+}
+
+// Normalize returns a copy of the config with the defaults of its zero
+// values filled in and Consistency applied, as used by the client.
+// SyncInterval stays zero only with DisableAutoSync.
+func (c Config) Normalize() Config {
+	c.Consistency.apply(&c)
+	if c.DisableAutoSync {
+		c.SyncInterval = 0
+	} else if c.SyncInterval <= 0 {
+		c.SyncInterval = DefaultSyncInterval
+	}
+	if c.MaxRetries <= 0 {
+		c.MaxRetries = DefaultMaxRetries
+	}
+	if c.RetryInterval <= 0 {
+		c.RetryInterval = DefaultRetryInterval
+	}
+	if c.TimeFormat == "" {
+		c.TimeFormat = time.RFC3339
+	}
+	if c.CellLimitWarning <= 0 {
+		c.CellLimitWarning = defaultCellLimitWarning
+	}
+	if c.OverflowThreshold <= 0 {
+		c.OverflowThreshold = DefaultOverflowThreshold
+	}
+	if c.PublishTopic == "" {
+		c.PublishTopic = DefaultPublishTopic
+	}
+	if c.LeaseOwner == "" {
+		c.LeaseOwner = defaultLeaseOwner()
+	}
+	if c.LockColumn == "" {
+		c.LockColumn = DefaultLockColumn
+	}
+	if c.IDColumn == "" {
+		c.IDColumn = DefaultIDColumn
+	}
+	if c.Clock == nil {
+		c.Clock = SystemClock
+	}
+	return c
 }
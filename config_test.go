@@ -0,0 +1,110 @@
+package sheetkv_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  sheetkv.Config
+		wantErr bool
+	}{
+		{"zero values", sheetkv.Config{}, false},
+		{"disabled auto-sync", sheetkv.Config{DisableAutoSync: true}, false},
+		{"negative sync interval", sheetkv.Config{SyncInterval: -time.Second}, true},
+		{"sync interval with auto-sync disabled", sheetkv.Config{SyncInterval: time.Second, DisableAutoSync: true}, true},
+		{"negative retries", sheetkv.Config{MaxRetries: -1}, true},
+		{"negative retry time", sheetkv.Config{MaxElapsedRetryTime: -time.Second}, true},
+		{"negative history", sheetkv.Config{HistoryLimit: -1}, true},
+		{"cell limit warning above 1", sheetkv.Config{CellLimitWarning: 1.5}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, sheetkv.ErrInvalidConfig) {
+				t.Errorf("Validate() error = %v, want ErrInvalidConfig", err)
+			}
+		})
+	}
+}
+
+func TestConfig_Normalize(t *testing.T) {
+	config := sheetkv.Config{}.Normalize()
+	if config.SyncInterval != sheetkv.DefaultSyncInterval || config.MaxRetries != sheetkv.DefaultMaxRetries || config.RetryInterval != sheetkv.DefaultRetryInterval {
+		t.Errorf("Normalize() = %+v, want the defaults", config)
+	}
+	if config.TimeFormat != time.RFC3339 || config.PublishTopic != sheetkv.DefaultPublishTopic || config.OverflowThreshold != sheetkv.DefaultOverflowThreshold {
+		t.Errorf("Normalize() = %+v, want the defaults", config)
+	}
+
+	if disabled := (sheetkv.Config{DisableAutoSync: true}).Normalize(); disabled.SyncInterval != 0 {
+		t.Errorf("SyncInterval = %s, want 0 with DisableAutoSync", disabled.SyncInterval)
+	}
+	if set := (sheetkv.Config{SyncInterval: time.Minute}).Normalize(); set.SyncInterval != time.Minute {
+		t.Errorf("SyncInterval = %s, want 1m", set.SyncInterval)
+	}
+}
+
+func TestConfig_Consistency(t *testing.T) {
+	strong := sheetkv.Config{Consistency: sheetkv.Strong()}.Normalize()
+	if !strong.WriteThrough || strong.ReadThroughTTL <= 0 {
+		t.Errorf("Strong() = %+v, want write-through and read-through", strong)
+	}
+
+	bounded := sheetkv.Config{Consistency: sheetkv.BoundedStaleness(5 * time.Second)}.Normalize()
+	if bounded.WriteThrough || bounded.ReadThroughTTL != 5*time.Second || bounded.SyncInterval != 5*time.Second {
+		t.Errorf("BoundedStaleness(5s) = %+v, want a 5s sync interval and read TTL", bounded)
+	}
+
+	eventual := sheetkv.Config{Consistency: sheetkv.Eventual()}.Normalize()
+	if eventual.WriteThrough || eventual.ReadThroughTTL != 0 || eventual.SyncInterval != sheetkv.DefaultSyncInterval {
+		t.Errorf("Eventual() = %+v, want the cached defaults", eventual)
+	}
+
+	if got := sheetkv.BoundedStaleness(time.Minute).String(); got != "bounded staleness (1m0s)" {
+		t.Errorf("String() = %q", got)
+	}
+
+	invalid := []sheetkv.Config{
+		{Consistency: sheetkv.BoundedStaleness(0)},
+		{Consistency: sheetkv.BoundedStaleness(time.Second), SyncInterval: time.Minute},
+		{Consistency: sheetkv.Strong(), ReadThroughTTL: time.Minute},
+		{Consistency: sheetkv.Eventual(), WriteThrough: true},
+	}
+	for _, config := range invalid {
+		if err := config.Validate(); !errors.Is(err, sheetkv.ErrInvalidConfig) {
+			t.Errorf("Validate(%s) error = %v, want ErrInvalidConfig", config.Consistency, err)
+		}
+	}
+	if err := (&sheetkv.Config{Consistency: sheetkv.Strong(), DisableAutoSync: true}).Validate(); err != nil {
+		t.Errorf("Validate(strong) error = %v", err)
+	}
+}
+
+func TestConfig_ValidateElectLeader(t *testing.T) {
+	invalid := []sheetkv.Config{
+		{ElectLeader: true},
+		{ElectLeader: true, LeaseDuration: time.Minute, DisableAutoSync: true},
+		{ElectLeader: true, LeaseDuration: time.Minute, SyncInterval: 30 * time.Second},
+		{ElectLeader: true, LeaseDuration: time.Minute},
+	}
+	for _, config := range invalid {
+		if err := config.Validate(); !errors.Is(err, sheetkv.ErrInvalidConfig) {
+			t.Errorf("Validate(%+v) error = %v, want ErrInvalidConfig", config, err)
+		}
+	}
+
+	valid := sheetkv.Config{ElectLeader: true, LeaseDuration: time.Minute, SyncInterval: 10 * time.Second}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
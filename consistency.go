@@ -0,0 +1,115 @@
+package sheetkv
+
+import (
+	"fmt"
+	"time"
+)
+
+// ConsistencyLevel names the guarantees of a Consistency
+type ConsistencyLevel int
+
+const (
+	// ConsistencyCustom leaves WriteThrough, ReadThroughTTL and SyncInterval as set
+	ConsistencyCustom ConsistencyLevel = iota
+	// ConsistencyEventual keeps reads and writes in the cache: writes reach
+	// the sheet on the periodic sync and edits made outside the client are
+	// seen after Reload
+	ConsistencyEventual
+	// ConsistencyBoundedStaleness keeps reads and writes at most Staleness
+	// behind the sheet
+	ConsistencyBoundedStaleness
+	// ConsistencyStrong writes through before returning, and Get, GetCell,
+	// Query, Lookup, Snapshot and ReadTx read from the adapter every time
+	ConsistencyStrong
+)
+
+// String returns the name of the level
+func (l ConsistencyLevel) String() string {
+	switch l {
+	case ConsistencyCustom:
+		return "custom"
+	case ConsistencyEventual:
+		return "eventual"
+	case ConsistencyBoundedStaleness:
+		return "bounded staleness"
+	case ConsistencyStrong:
+		return "strong"
+	default:
+		return fmt.Sprintf("ConsistencyLevel(%d)", int(l))
+	}
+}
+
+// Consistency is a preset of the settings deciding how fresh reads are and
+// when writes reach the sheet, see Config.Consistency
+type Consistency struct {
+	Level     ConsistencyLevel
+	Staleness time.Duration // Bound of ConsistencyBoundedStaleness
+}
+
+// Strong returns the preset persisting every write before it returns and
+// reading every row and query from the adapter. It costs API calls on each
+// operation.
+func Strong() Consistency {
+	return Consistency{Level: ConsistencyStrong}
+}
+
+// BoundedStaleness returns the preset syncing writes every d and refreshing
+// rows and queries older than d on reads
+func BoundedStaleness(d time.Duration) Consistency {
+	return Consistency{Level: ConsistencyBoundedStaleness, Staleness: d}
+}
+
+// Eventual returns the preset serving reads and writes from the cache,
+// syncing writes periodically. It is the default behavior.
+func Eventual() Consistency {
+	return Consistency{Level: ConsistencyEventual}
+}
+
+// String returns the name of the preset
+func (c Consistency) String() string {
+	if c.Level == ConsistencyBoundedStaleness {
+		return fmt.Sprintf("%s (%s)", c.Level, c.Staleness)
+	}
+	return c.Level.String()
+}
+
+// validate reports the settings of config contradicting the preset
+func (c Consistency) validate(config *Config) error {
+	switch c.Level {
+	case ConsistencyCustom:
+		return nil
+	case ConsistencyEventual, ConsistencyStrong:
+	case ConsistencyBoundedStaleness:
+		if c.Staleness <= 0 {
+			return fmt.Errorf("%w: BoundedStaleness must be positive, got %s", ErrInvalidConfig, c.Staleness)
+		}
+		if config.SyncInterval > 0 || config.DisableAutoSync {
+			return fmt.Errorf("%w: Consistency %s sets SyncInterval", ErrInvalidConfig, c)
+		}
+	default:
+		return fmt.Errorf("%w: unknown %s", ErrInvalidConfig, c.Level)
+	}
+
+	if config.WriteThrough || config.ReadThroughTTL > 0 {
+		return fmt.Errorf("%w: Consistency %s sets WriteThrough and ReadThroughTTL", ErrInvalidConfig, c)
+	}
+	return nil
+}
+
+// apply sets the settings of the preset on config
+func (c Consistency) apply(config *Config) {
+	switch c.Level {
+	case ConsistencyEventual:
+		config.WriteThrough = false
+		config.ReadThroughTTL = 0
+	case ConsistencyBoundedStaleness:
+		config.WriteThrough = false
+		config.ReadThroughTTL = c.Staleness
+		config.SyncInterval = c.Staleness
+		config.DisableAutoSync = false
+	case ConsistencyStrong:
+		// The shortest TTL makes every read stale
+		config.WriteThrough = true
+		config.ReadThroughTTL = time.Nanosecond
+	}
+}
@@ -1,10 +1,51 @@
 package sheetkv
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrKeyNotFound   = errors.New("key not found")
 	ErrDuplicateKey  = errors.New("duplicate key")
 	ErrSyncFailed    = errors.New("sync failed")
 	ErrQuotaExceeded = errors.New("quota exceeded")
+	ErrTableNotFound = errors.New("table not found")
+	ErrInvalidValue  = errors.New("invalid value")
+	ErrUnknownColumn = errors.New("unknown column")
+	ErrCellLimit     = errors.New("cell limit exceeded")
+	ErrCheckpoint    = errors.New("checkpoint no longer in the operation log")
+	ErrRetryTime     = errors.New("retry time exceeded")
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrSheetNotFound = errors.New("sheet not found")
+	ErrInvalidConfig = errors.New("invalid config")
+
+	// ErrReadOnly is returned by writes to a client tailing its sheet, see
+	// Config.TailAppends
+	ErrReadOnly = errors.New("client is read-only")
+
+	// ErrOutOfScope is returned by writes of a ScopedClient to records
+	// outside its scope
+	ErrOutOfScope = errors.New("record outside the scope")
+
+	// ErrTemplateNotFound is returned by NewRecordFromTemplate for names
+	// missing from Config.Templates
+	ErrTemplateNotFound = errors.New("template not found")
+
+	// ErrRemoteConflict is returned by saves refused because the sheet was
+	// changed since the last load or save (Config.DetectRemoteChanges). It
+	// comes wrapped with ErrSyncFailed.
+	ErrRemoteConflict = errors.New("spreadsheet was modified remotely")
+
+	// ErrRecordLocked is matched by the *RecordLockedError of writes to a
+	// record locked by another owner, see Client.Lock
+	ErrRecordLocked = errors.New("record locked")
+
+	// ErrLocked is returned by Lockers failing to take a lock held by
+	// another process. Saves refused for it come wrapped with ErrSyncFailed.
+	ErrLocked = errors.New("locked by another process")
+
+	// ErrLeaseHeld is returned by saves refused because another process
+	// holds the lease of the sheet (Config.LeaseDuration). It wraps ErrLocked.
+	ErrLeaseHeld = fmt.Errorf("%w: lease held", ErrLocked)
 )
@@ -0,0 +1,106 @@
+package sheetkv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// QueryPlan describes how a query is executed
+type QueryPlan struct {
+	IndexColumn string           // Indexed column narrowing the candidates, empty for a full scan
+	TotalRows   int              // Records in the cache
+	RowsScanned int              // Records the conditions are evaluated against
+	Conditions  []Condition      // Evaluation order; evaluation of a record stops at the first false condition
+	Groups      []ConditionGroup // Condition groups, evaluated after Conditions
+	OrderBy     []SortKey        // Sort keys of the matches, before Offset and Limit
+	Limit       int
+	Offset      int
+}
+
+// String formats the plan for logs
+func (p *QueryPlan) String() string {
+	var b strings.Builder
+	if p.IndexColumn != "" {
+		fmt.Fprintf(&b, "index lookup on %q: scan %d of %d rows", p.IndexColumn, p.RowsScanned, p.TotalRows)
+	} else {
+		fmt.Fprintf(&b, "full scan: %d rows", p.TotalRows)
+	}
+	for i, cond := range p.Conditions {
+		fmt.Fprintf(&b, "\n  %d. %s %s %v", i+1, cond.Column, cond.Operator, cond.Value)
+	}
+	for i, group := range p.Groups {
+		fmt.Fprintf(&b, "\n  %d. %s", len(p.Conditions)+i+1, formatGroup(group))
+	}
+	if len(p.OrderBy) > 0 {
+		keys := make([]string, len(p.OrderBy))
+		for i, key := range p.OrderBy {
+			keys[i] = key.Column
+			if key.Descending {
+				keys[i] += " desc"
+			}
+		}
+		fmt.Fprintf(&b, "\n  order by %s", strings.Join(keys, ", "))
+	}
+	if p.Offset > 0 {
+		fmt.Fprintf(&b, "\n  offset %d", p.Offset)
+	}
+	if p.Limit > 0 {
+		fmt.Fprintf(&b, "\n  limit %d", p.Limit)
+	}
+	return b.String()
+}
+
+// formatGroup formats group as its conditions joined by its logic, nested
+// groups between parentheses
+func formatGroup(group ConditionGroup) string {
+	logic := group.Logic
+	if logic == "" {
+		logic = LogicAnd
+	}
+	parts := make([]string, 0, len(group.Conditions)+len(group.Groups))
+	for _, cond := range group.Conditions {
+		parts = append(parts, fmt.Sprintf("%s %s %v", cond.Column, cond.Operator, cond.Value))
+	}
+	for _, nested := range group.Groups {
+		parts = append(parts, "("+formatGroup(nested)+")")
+	}
+	return strings.Join(parts, " "+logic+" ")
+}
+
+// Explain returns the plan the cache would use for query without running it
+func (c *Cache) Explain(query Query) (*QueryPlan, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if err := ValidateQuery(query); err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+
+	plan := &QueryPlan{
+		TotalRows:   len(c.data),
+		RowsScanned: len(c.data),
+		Conditions:  append([]Condition(nil), query.Conditions...),
+		Groups:      append([]ConditionGroup(nil), query.Groups...),
+		OrderBy:     append([]SortKey(nil), query.OrderBy...),
+		Limit:       query.Limit,
+		Offset:      query.Offset,
+	}
+	if keys, column, ok := c.indexCandidates(query); ok {
+		plan.IndexColumn = column
+		plan.RowsScanned = len(keys)
+	}
+	return plan, nil
+}
+
+// Explain returns the plan Query would use, such as whether an index
+// narrows down the scanned rows
+func (c *Client) Explain(query Query) (*QueryPlan, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, fmt.Errorf("client is closed")
+	}
+
+	return c.cache.Explain(query)
+}
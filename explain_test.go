@@ -0,0 +1,58 @@
+package sheetkv_test
+
+import (
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestCache_Explain(t *testing.T) {
+	cache := sheetkv.NewCache()
+	cache.SetIndexColumns([]string{"dept"})
+	cache.Load([]*sheetkv.Record{
+		{Key: 2, Values: map[string]interface{}{"dept": "Sales", "age": int64(30)}},
+		{Key: 3, Values: map[string]interface{}{"dept": "Sales", "age": int64(40)}},
+		{Key: 4, Values: map[string]interface{}{"dept": "Dev", "age": int64(25)}},
+		{Key: 5, Values: map[string]interface{}{"dept": "Ops", "age": int64(35)}},
+	}, []string{"dept", "age"})
+
+	t.Run("Index lookup", func(t *testing.T) {
+		plan, err := cache.Explain(sheetkv.Query{Conditions: []sheetkv.Condition{
+			{Column: "age", Operator: ">", Value: 20},
+			{Column: "dept", Operator: "==", Value: "Sales"},
+		}, Limit: 1})
+		if err != nil {
+			t.Fatalf("Explain() error = %v", err)
+		}
+		if plan.IndexColumn != "dept" || plan.RowsScanned != 2 || plan.TotalRows != 4 {
+			t.Errorf("Explain() = %+v, want index on dept scanning 2 of 4", plan)
+		}
+		if len(plan.Conditions) != 2 || plan.Conditions[0].Column != "age" {
+			t.Errorf("Conditions = %v, want the given order", plan.Conditions)
+		}
+		if !contains(plan.String(), `index lookup on "dept"`) || !contains(plan.String(), "limit 1") {
+			t.Errorf("String() = %s", plan.String())
+		}
+	})
+
+	t.Run("Full scan", func(t *testing.T) {
+		plan, err := cache.Explain(sheetkv.Query{Conditions: []sheetkv.Condition{
+			{Column: "age", Operator: ">", Value: 20},
+		}})
+		if err != nil {
+			t.Fatalf("Explain() error = %v", err)
+		}
+		if plan.IndexColumn != "" || plan.RowsScanned != 4 {
+			t.Errorf("Explain() = %+v, want a full scan of 4 rows", plan)
+		}
+		if !contains(plan.String(), "full scan: 4 rows") {
+			t.Errorf("String() = %s", plan.String())
+		}
+	})
+
+	t.Run("Invalid query", func(t *testing.T) {
+		if _, err := cache.Explain(sheetkv.Query{Conditions: []sheetkv.Condition{{Column: "age", Operator: "~"}}}); err == nil {
+			t.Error("Explain() should reject invalid queries")
+		}
+	})
+}
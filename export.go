@@ -0,0 +1,125 @@
+package sheetkv
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// ToMarkdown renders records as a GitHub-flavored Markdown table with one
+// column per schema entry. Missing values are left empty.
+func ToMarkdown(records []*Record, schema []string) string {
+	var b strings.Builder
+
+	b.WriteString("|")
+	for _, col := range schema {
+		b.WriteString(" " + escapeMarkdown(col) + " |")
+	}
+	b.WriteString("\n|")
+	for range schema {
+		b.WriteString(" --- |")
+	}
+	b.WriteString("\n")
+
+	for _, record := range records {
+		b.WriteString("|")
+		for _, col := range schema {
+			b.WriteString(" " + escapeMarkdown(cellText(record, col)) + " |")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// ExportMarkdown writes the records matching query as a Markdown table with
+// the client's schema
+func (c *Client) ExportMarkdown(w io.Writer, query Query) error {
+	records, err := c.Query(query)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, ToMarkdown(records, c.cache.GetSchema())); err != nil {
+		return fmt.Errorf("failed to write markdown: %w", err)
+	}
+	return nil
+}
+
+// cellText returns the text form of a record value, empty when missing
+func cellText(record *Record, col string) string {
+	if v, ok := record.Values[col]; !ok || v == nil {
+		return ""
+	}
+	return record.GetAsString(col, "")
+}
+
+// escapeMarkdown keeps a value inside its table cell
+func escapeMarkdown(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r\n", "<br>")
+	s = strings.ReplaceAll(s, "\n", "<br>")
+	return s
+}
+
+// HTMLOptions controls the HTML export
+type HTMLOptions struct {
+	Columns []string // Columns to include, in order (default: the schema)
+	Class   string   // Class attribute of the table (default: sheetkv)
+	Styled  bool     // Prepend a small stylesheet with borders and a shaded header
+}
+
+// htmlStyle is the stylesheet emitted for HTMLOptions.Styled
+const htmlStyle = `<style>
+table.%[1]s { border-collapse: collapse; }
+table.%[1]s th, table.%[1]s td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+table.%[1]s th { background: #f3f3f3; }
+</style>
+`
+
+// ToHTML renders records as an HTML table. Values are HTML-escaped.
+func ToHTML(records []*Record, schema []string, opts HTMLOptions) string {
+	columns := schema
+	if len(opts.Columns) > 0 {
+		columns = opts.Columns
+	}
+	class := opts.Class
+	if class == "" {
+		class = "sheetkv"
+	}
+
+	var b strings.Builder
+	if opts.Styled {
+		fmt.Fprintf(&b, htmlStyle, html.EscapeString(class))
+	}
+
+	fmt.Fprintf(&b, "<table class=\"%s\">\n<thead>\n<tr>", html.EscapeString(class))
+	for _, col := range columns {
+		b.WriteString("<th>" + html.EscapeString(col) + "</th>")
+	}
+	b.WriteString("</tr>\n</thead>\n<tbody>\n")
+
+	for _, record := range records {
+		b.WriteString("<tr>")
+		for _, col := range columns {
+			b.WriteString("<td>" + html.EscapeString(cellText(record, col)) + "</td>")
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>\n")
+	return b.String()
+}
+
+// ExportHTML writes the records matching query as an HTML table
+func (c *Client) ExportHTML(w io.Writer, query Query, opts HTMLOptions) error {
+	records, err := c.Query(query)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, ToHTML(records, c.cache.GetSchema(), opts)); err != nil {
+		return fmt.Errorf("failed to write html: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package sheetkv_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestToMarkdown(t *testing.T) {
+	records := []*sheetkv.Record{
+		{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30), "active": true}},
+		{Key: 3, Values: map[string]interface{}{"name": "A|B\nC"}},
+	}
+
+	got := sheetkv.ToMarkdown(records, []string{"name", "age", "active"})
+	want := "| name | age | active |\n" +
+		"| --- | --- | --- |\n" +
+		"| John | 30 | true |\n" +
+		"| A\\|B<br>C |  |  |\n"
+	if got != want {
+		t.Errorf("ToMarkdown() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestClient_ExportMarkdown(t *testing.T) {
+	adapter := newMemoryAdapter([]string{"name", "status"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "status": "active"}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane", "status": "inactive"}},
+	)
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	query := sheetkv.Query{Conditions: []sheetkv.Condition{{Column: "status", Operator: "==", Value: "active"}}}
+	if err := client.ExportMarkdown(&buf, query); err != nil {
+		t.Fatalf("ExportMarkdown() error = %v", err)
+	}
+
+	want := "| name | status |\n| --- | --- |\n| John | active |\n"
+	if buf.String() != want {
+		t.Errorf("ExportMarkdown() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestToHTML(t *testing.T) {
+	records := []*sheetkv.Record{
+		{Key: 2, Values: map[string]interface{}{"name": "<John>", "age": int64(30), "email": "john@example.com"}},
+	}
+
+	t.Run("Schema columns", func(t *testing.T) {
+		got := sheetkv.ToHTML(records, []string{"name", "age"}, sheetkv.HTMLOptions{})
+		want := "<table class=\"sheetkv\">\n<thead>\n<tr><th>name</th><th>age</th></tr>\n</thead>\n<tbody>\n" +
+			"<tr><td>&lt;John&gt;</td><td>30</td></tr>\n</tbody>\n</table>\n"
+		if got != want {
+			t.Errorf("ToHTML() =\n%s\nwant\n%s", got, want)
+		}
+	})
+
+	t.Run("Selected columns and styling", func(t *testing.T) {
+		got := sheetkv.ToHTML(records, []string{"name", "age", "email"}, sheetkv.HTMLOptions{
+			Columns: []string{"email"},
+			Class:   "report",
+			Styled:  true,
+		})
+		if !contains(got, "<style>") || !contains(got, "table.report th") {
+			t.Errorf("ToHTML() = %s, want a stylesheet for class report", got)
+		}
+		if !contains(got, "<th>email</th></tr>") || contains(got, "<th>name</th>") {
+			t.Errorf("ToHTML() = %s, want only the email column", got)
+		}
+	})
+}
+
+func TestClient_ExportHTML(t *testing.T) {
+	adapter := newMemoryAdapter([]string{"name"}, &sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John"}})
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := client.ExportHTML(&buf, sheetkv.Query{}, sheetkv.HTMLOptions{}); err != nil {
+		t.Fatalf("ExportHTML() error = %v", err)
+	}
+	if !contains(buf.String(), "<td>John</td>") {
+		t.Errorf("ExportHTML() = %s, want John's row", buf.String())
+	}
+}
@@ -0,0 +1,97 @@
+package sheetkv
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FloatFormat describes how adapters write floating-point numbers. Without
+// Fixed, numbers are written in their shortest exact form, never in
+// scientific notation.
+type FloatFormat struct {
+	Decimals  int  // Digits after the decimal point, with Fixed
+	Fixed     bool // Write exactly Decimals digits, rounding the number
+	Thousands bool // Separate thousands with commas, e.g. 1,234,567.5
+}
+
+// Format returns v written as configured
+func (f FloatFormat) Format(v float64) string {
+	precision := -1
+	if f.Fixed {
+		precision = max(f.Decimals, 0)
+	}
+	s := strconv.FormatFloat(v, 'f', precision, 64)
+	if f.Thousands {
+		s = groupThousands(s)
+	}
+	return s
+}
+
+// NumberFormat returns the equivalent spreadsheet number format, such as
+// "#,##0.00", or "" when the number is shown as is
+func (f FloatFormat) NumberFormat() string {
+	if !f.Fixed && !f.Thousands {
+		return ""
+	}
+	integer := "0"
+	if f.Thousands {
+		integer = "#,##0"
+	}
+	if !f.Fixed {
+		return integer + ".##########"
+	}
+	if f.Decimals <= 0 {
+		return integer
+	}
+	return integer + "." + strings.Repeat("0", f.Decimals)
+}
+
+// groupThousands inserts commas between the thousands of a formatted number
+func groupThousands(s string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	integer, fraction, hasFraction := strings.Cut(s, ".")
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	if hasFraction {
+		b.WriteByte('.')
+		b.WriteString(fraction)
+	}
+	return b.String()
+}
+
+// FloatFormats maps columns to the format of their floats. The format of
+// the "" column applies to the other columns.
+type FloatFormats map[string]FloatFormat
+
+// For returns the format of column
+func (f FloatFormats) For(column string) (FloatFormat, bool) {
+	if format, ok := f[column]; ok {
+		return format, true
+	}
+	format, ok := f[""]
+	return format, ok
+}
+
+// Unformat removes the thousands separators written for column from s, so
+// that the number is parsed on load. Other text is returned as is.
+func (f FloatFormats) Unformat(column, s string) string {
+	format, ok := f.For(column)
+	if !ok || !format.Thousands || !strings.Contains(s, ",") {
+		return s
+	}
+	plain := strings.ReplaceAll(s, ",", "")
+	if _, err := strconv.ParseFloat(plain, 64); err != nil || groupThousands(plain) != s {
+		return s
+	}
+	return plain
+}
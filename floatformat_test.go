@@ -0,0 +1,62 @@
+package sheetkv_test
+
+import (
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestFloatFormat(t *testing.T) {
+	tests := []struct {
+		name         string
+		format       sheetkv.FloatFormat
+		in           float64
+		want, number string
+	}{
+		{"shortest", sheetkv.FloatFormat{}, 1e21, "1000000000000000000000", ""},
+		{"small", sheetkv.FloatFormat{}, 0.000001, "0.000001", ""},
+		{"fixed", sheetkv.FloatFormat{Fixed: true, Decimals: 2}, 3.14159, "3.14", "0.00"},
+		{"fixed integer", sheetkv.FloatFormat{Fixed: true}, 2.5, "2", "0"},
+		{"thousands", sheetkv.FloatFormat{Thousands: true}, -1234567.5, "-1,234,567.5", "#,##0.##########"},
+		{"fixed thousands", sheetkv.FloatFormat{Fixed: true, Decimals: 1, Thousands: true}, 999.96, "1,000.0", "#,##0.0"},
+		{"below a thousand", sheetkv.FloatFormat{Thousands: true}, 999, "999", "#,##0.##########"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.format.Format(tt.in); got != tt.want {
+				t.Errorf("Format(%v) = %q, want %q", tt.in, got, tt.want)
+			}
+			if got := tt.format.NumberFormat(); got != tt.number {
+				t.Errorf("NumberFormat() = %q, want %q", got, tt.number)
+			}
+		})
+	}
+}
+
+func TestFloatFormats(t *testing.T) {
+	formats := sheetkv.FloatFormats{
+		"":      {Fixed: true, Decimals: 2},
+		"price": {Thousands: true},
+	}
+
+	if format, ok := formats.For("rate"); !ok || format.Decimals != 2 {
+		t.Errorf("For(rate) = %+v, %v, want the default format", format, ok)
+	}
+	if _, ok := (sheetkv.FloatFormats{"price": {}}).For("rate"); ok {
+		t.Error("For() without a default should not match other columns")
+	}
+
+	for in, want := range map[string]string{
+		"1,234.5": "1234.5",
+		"-12,345": "-12345",
+		"1,2,3":   "1,2,3",
+		"a,b":     "a,b",
+	} {
+		if got := formats.Unformat("price", in); got != want {
+			t.Errorf("Unformat(price, %q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := formats.Unformat("rate", "1,234"); got != "1,234" {
+		t.Errorf("Unformat(rate) = %q, want separators kept without Thousands", got)
+	}
+}
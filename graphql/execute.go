@@ -0,0 +1,834 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// Request is a GraphQL request
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response is a GraphQL response. Data is absent when the request could
+// not be executed at all.
+type Response struct {
+	Data   json.RawMessage `json:"data,omitempty"`
+	Errors []*Error        `json:"errors,omitempty"`
+}
+
+// Error is an error of a GraphQL response
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// object is a response object keeping the order of its fields
+type object struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newObject() *object {
+	return &object{values: make(map[string]interface{})}
+}
+
+func (o *object) set(key string, v interface{}) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = v
+}
+
+// MarshalJSON writes the fields in selection order
+func (o *object) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// execution holds the state of one request
+type execution struct {
+	h         *Handler
+	doc       *document
+	op        *operation
+	variables map[string]value
+	errors    []*Error
+}
+
+// Execute runs a request. Errors that prevent execution, such as syntax
+// errors or unknown fields, are returned in a response without data; errors
+// of individual fields leave the field null and are listed next to the data.
+func (h *Handler) Execute(ctx context.Context, req Request) *Response {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return failed(err)
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return failed(err)
+	}
+
+	e := &execution{h: h, doc: doc, op: op}
+	if err := e.validate(); err != nil {
+		return failed(err)
+	}
+	if e.variables, err = e.coerceVariables(req.Variables); err != nil {
+		return failed(err)
+	}
+
+	data := newObject()
+	fields, err := e.collectFields(op.selections, rootTypeName(op.kind), nil)
+	if err != nil {
+		return failed(err)
+	}
+	for _, field := range fields {
+		if err := ctx.Err(); err != nil {
+			e.fieldError([]interface{}{field.responseKey()}, err)
+			data.set(field.responseKey(), nil)
+			continue
+		}
+		data.set(field.responseKey(), e.rootField(field))
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return failed(err)
+	}
+	return &Response{Data: raw, Errors: e.errors}
+}
+
+// failed returns the response of a request that could not be executed
+func failed(err error) *Response {
+	return &Response{Errors: []*Error{{Message: err.Error()}}}
+}
+
+// fieldError records the error of a field
+func (e *execution) fieldError(path []interface{}, err error) {
+	e.errors = append(e.errors, &Error{Message: err.Error(), Path: path})
+}
+
+func rootTypeName(kind string) string {
+	if kind == "mutation" {
+		return "Mutation"
+	}
+	return "Query"
+}
+
+// selectOperation returns the operation to run
+func selectOperation(doc *document, name string) (*operation, error) {
+	if name == "" {
+		if len(doc.operations) > 1 {
+			return nil, fmt.Errorf("operationName is required when the document has several operations")
+		}
+		return doc.operations[0], nil
+	}
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation %q", name)
+}
+
+// validate checks the fields, arguments, fragments and variables of the
+// operation against the schema
+func (e *execution) validate() error {
+	defined := make(map[string]bool, len(e.op.variables))
+	for _, def := range e.op.variables {
+		if defined[def.name] {
+			return fmt.Errorf("variable $%s is defined more than once", def.name)
+		}
+		defined[def.name] = true
+	}
+
+	used := make(map[string]bool)
+	var checkValue func(v value) error
+	checkValue = func(v value) error {
+		switch v := v.(type) {
+		case variableRef:
+			if !defined[string(v)] {
+				return fmt.Errorf("variable $%s is not defined", v)
+			}
+			used[string(v)] = true
+		case []value:
+			for _, item := range v {
+				if err := checkValue(item); err != nil {
+					return err
+				}
+			}
+		case map[string]value:
+			for _, item := range v {
+				if err := checkValue(item); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	checkDirectives := func(directives []*directive) error {
+		for _, d := range directives {
+			if d.name != "skip" && d.name != "include" {
+				return fmt.Errorf("unknown directive @%s", d.name)
+			}
+			if _, ok := d.arguments["if"]; !ok || len(d.arguments) != 1 {
+				return fmt.Errorf("directive @%s takes exactly the argument \"if\"", d.name)
+			}
+			if err := checkValue(d.arguments["if"]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var walk func(selections []selection, typeName string, visiting map[string]bool) error
+	walk = func(selections []selection, typeName string, visiting map[string]bool) error {
+		for _, sel := range selections {
+			switch sel := sel.(type) {
+			case *fieldSelection:
+				if err := checkDirectives(sel.directives); err != nil {
+					return err
+				}
+				if err := e.validateField(sel, typeName); err != nil {
+					return err
+				}
+				for _, v := range sel.arguments {
+					if err := checkValue(v); err != nil {
+						return err
+					}
+				}
+				if typeName != "Record" && sel.name != "__typename" {
+					if f, _ := findRootField(e.op.kind, sel.name); f.record {
+						if err := walk(sel.selections, "Record", visiting); err != nil {
+							return err
+						}
+					}
+				}
+			case *fragmentSpread:
+				if err := checkDirectives(sel.directives); err != nil {
+					return err
+				}
+				frag, ok := e.doc.fragments[sel.name]
+				if !ok {
+					return fmt.Errorf("unknown fragment %q", sel.name)
+				}
+				if visiting[sel.name] {
+					return fmt.Errorf("fragment %q spreads itself", sel.name)
+				}
+				if frag.typeName != typeName {
+					return fmt.Errorf("fragment %q on %s cannot be spread in %s", sel.name, frag.typeName, typeName)
+				}
+				visiting[sel.name] = true
+				if err := walk(frag.selections, typeName, visiting); err != nil {
+					return err
+				}
+				delete(visiting, sel.name)
+			case *inlineFragment:
+				if err := checkDirectives(sel.directives); err != nil {
+					return err
+				}
+				if sel.typeName != "" && sel.typeName != typeName {
+					return fmt.Errorf("fragment on %s cannot be spread in %s", sel.typeName, typeName)
+				}
+				if err := walk(sel.selections, typeName, visiting); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := walk(e.op.selections, rootTypeName(e.op.kind), make(map[string]bool)); err != nil {
+		return err
+	}
+	for _, def := range e.op.variables {
+		if !used[def.name] {
+			return fmt.Errorf("variable $%s is not used", def.name)
+		}
+	}
+	return nil
+}
+
+// validateField checks a field selected on a type
+func (e *execution) validateField(field *fieldSelection, typeName string) error {
+	if field.name == "__typename" {
+		if len(field.arguments) > 0 || len(field.selections) > 0 {
+			return fmt.Errorf("field __typename takes no arguments or selections")
+		}
+		return nil
+	}
+
+	if typeName == "Record" {
+		if _, ok := e.h.byName[field.name]; !ok && field.name != keyField {
+			return fmt.Errorf("unknown field %q on Record", field.name)
+		}
+		if len(field.arguments) > 0 {
+			return fmt.Errorf("field %q takes no arguments", field.name)
+		}
+		if len(field.selections) > 0 {
+			return fmt.Errorf("field %q of type %s cannot have a selection", field.name, e.fieldType(field.name))
+		}
+		return nil
+	}
+
+	root, ok := findRootField(e.op.kind, field.name)
+	if !ok {
+		return fmt.Errorf("unknown field %q on %s", field.name, typeName)
+	}
+	for name := range field.arguments {
+		known := false
+		for _, arg := range root.arguments {
+			known = known || arg.name == name
+		}
+		if !known {
+			return fmt.Errorf("unknown argument %q on field %q", name, field.name)
+		}
+	}
+	for _, arg := range root.arguments {
+		if v, ok := field.arguments[arg.name]; arg.required && (!ok || v == nil) {
+			return fmt.Errorf("field %q requires the argument %q", field.name, arg.name)
+		}
+	}
+	if root.record && len(field.selections) == 0 {
+		return fmt.Errorf("field %q of type %s must have a selection", field.name, root.result)
+	}
+	if !root.record && len(field.selections) > 0 {
+		return fmt.Errorf("field %q of type %s cannot have a selection", field.name, root.result)
+	}
+	return nil
+}
+
+// fieldType returns the type of a Record field for messages
+func (e *execution) fieldType(name string) string {
+	if name == keyField {
+		return "Int!"
+	}
+	return e.h.byName[name].Type.String()
+}
+
+// coerceVariables applies the defaults and checks the required variables.
+// The values themselves are checked where they are used.
+func (e *execution) coerceVariables(given map[string]interface{}) (map[string]value, error) {
+	variables := make(map[string]value, len(e.op.variables))
+	for _, def := range e.op.variables {
+		raw, ok := given[def.name]
+		switch {
+		case ok:
+			v, err := fromJSON(raw)
+			if err != nil {
+				return nil, fmt.Errorf("variable $%s: %w", def.name, err)
+			}
+			variables[def.name] = v
+		case def.hasDefault:
+			variables[def.name] = def.defaultValue
+		}
+		if strings.HasSuffix(def.typ, "!") && variables[def.name] == nil {
+			return nil, fmt.Errorf("variable $%s of type %s is required", def.name, def.typ)
+		}
+	}
+	return variables, nil
+}
+
+// fromJSON converts a decoded JSON value, or a Go value of a caller, to a
+// literal
+func fromJSON(raw interface{}) (value, error) {
+	switch v := raw.(type) {
+	case nil, string, bool, int64, float64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case float32:
+		return float64(v), nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		return v.Float64()
+	case []interface{}:
+		list := make([]value, len(v))
+		for i, item := range v {
+			converted, err := fromJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = converted
+		}
+		return list, nil
+	case []string:
+		list := make([]value, len(v))
+		for i, item := range v {
+			list[i] = item
+		}
+		return list, nil
+	case map[string]interface{}:
+		object := make(map[string]value, len(v))
+		for key, item := range v {
+			converted, err := fromJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			object[key] = converted
+		}
+		return object, nil
+	}
+	return nil, fmt.Errorf("unsupported value %T", raw)
+}
+
+// resolve replaces the variables of a literal by their values
+func (e *execution) resolve(v value) value {
+	switch v := v.(type) {
+	case variableRef:
+		return e.variables[string(v)]
+	case []value:
+		list := make([]value, len(v))
+		for i, item := range v {
+			list[i] = e.resolve(item)
+		}
+		return list
+	case map[string]value:
+		object := make(map[string]value, len(v))
+		for key, item := range v {
+			object[key] = e.resolve(item)
+		}
+		return object
+	}
+	return v
+}
+
+// included evaluates the @skip and @include directives
+func (e *execution) included(directives []*directive) (bool, error) {
+	for _, d := range directives {
+		cond, ok := e.resolve(d.arguments["if"]).(bool)
+		if !ok {
+			return false, fmt.Errorf("argument \"if\" of @%s must be a Boolean", d.name)
+		}
+		if (d.name == "skip" && cond) || (d.name == "include" && !cond) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// collectFields flattens fragments and merges the fields selected under the
+// same response key
+func (e *execution) collectFields(selections []selection, typeName string, fields []*fieldSelection) ([]*fieldSelection, error) {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *fieldSelection:
+			ok, err := e.included(sel.directives)
+			if err != nil || !ok {
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+			merged := false
+			for _, f := range fields {
+				if f.responseKey() != sel.responseKey() {
+					continue
+				}
+				if f.name != sel.name {
+					return nil, fmt.Errorf("fields %q and %q conflict under the response key %q", f.name, sel.name, sel.responseKey())
+				}
+				f.selections = append(f.selections, sel.selections...)
+				merged = true
+			}
+			if !merged {
+				copied := *sel
+				copied.selections = append([]selection(nil), sel.selections...)
+				fields = append(fields, &copied)
+			}
+		case *fragmentSpread:
+			ok, err := e.included(sel.directives)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				if fields, err = e.collectFields(e.doc.fragments[sel.name].selections, typeName, fields); err != nil {
+					return nil, err
+				}
+			}
+		case *inlineFragment:
+			ok, err := e.included(sel.directives)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				if fields, err = e.collectFields(sel.selections, typeName, fields); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return fields, nil
+}
+
+// rootField resolves a field of Query or Mutation; errors are recorded and
+// leave the field null
+func (e *execution) rootField(field *fieldSelection) interface{} {
+	path := []interface{}{field.responseKey()}
+	if field.name == "__typename" {
+		return rootTypeName(e.op.kind)
+	}
+
+	result, err := e.resolveRoot(field)
+	if err != nil {
+		e.fieldError(path, err)
+		return nil
+	}
+
+	switch result := result.(type) {
+	case *sheetkv.Record:
+		obj, err := e.record(result, field.selections)
+		if err != nil {
+			e.fieldError(path, err)
+			return nil
+		}
+		return obj
+	case []*sheetkv.Record:
+		list := make([]interface{}, 0, len(result))
+		for i, record := range result {
+			obj, err := e.record(record, field.selections)
+			if err != nil {
+				e.fieldError(append(path, i), err)
+				return nil
+			}
+			list = append(list, obj)
+		}
+		return list
+	}
+	return result
+}
+
+// resolveRoot runs the client call behind a root field
+func (e *execution) resolveRoot(field *fieldSelection) (interface{}, error) {
+	args := make(map[string]value, len(field.arguments))
+	for name, v := range field.arguments {
+		args[name] = e.resolve(v)
+	}
+	client := e.h.client
+
+	switch field.name {
+	case "record":
+		key, err := intArgument(args, "key")
+		if err != nil {
+			return nil, err
+		}
+		record, err := client.Get(key)
+		if errors.Is(err, sheetkv.ErrKeyNotFound) {
+			return nil, nil
+		}
+		return record, err
+
+	case "records":
+		query, err := e.query(args)
+		if err != nil {
+			return nil, err
+		}
+		return client.Query(query)
+
+	case "append":
+		values, err := e.values(args["values"])
+		if err != nil {
+			return nil, err
+		}
+		record := &sheetkv.Record{Values: values}
+		if err := client.Append(record); err != nil {
+			return nil, err
+		}
+		return client.Get(record.Key)
+
+	case "set", "update":
+		key, err := intArgument(args, "key")
+		if err != nil {
+			return nil, err
+		}
+		values, err := e.values(args["values"])
+		if err != nil {
+			return nil, err
+		}
+		if field.name == "set" {
+			err = client.Set(key, &sheetkv.Record{Key: key, Values: values})
+		} else {
+			err = client.Update(key, values)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return client.Get(key)
+
+	case "delete":
+		key, err := intArgument(args, "key")
+		if err != nil {
+			return nil, err
+		}
+		if err := client.Delete(key); err != nil {
+			return nil, err
+		}
+		return true, nil
+	}
+	return nil, fmt.Errorf("unknown field %q", field.name)
+}
+
+// intArgument returns an Int argument, 0 when null
+func intArgument(args map[string]value, name string) (int, error) {
+	v := args[name]
+	if v == nil {
+		return 0, nil
+	}
+	n, err := coerceInt(v)
+	if err != nil {
+		return 0, fmt.Errorf("argument %q: %w", name, err)
+	}
+	return int(n), nil
+}
+
+// query converts the where, limit and offset arguments. Records come in row
+// order so that limit and offset page through them consistently.
+func (e *execution) query(args map[string]value) (sheetkv.Query, error) {
+	query := sheetkv.Query{
+		SortFunc: func(a, b *sheetkv.Record) bool { return a.Key < b.Key },
+	}
+	var err error
+	if query.Limit, err = intArgument(args, "limit"); err != nil {
+		return query, err
+	}
+	if query.Offset, err = intArgument(args, "offset"); err != nil {
+		return query, err
+	}
+
+	where := args["where"]
+	if where == nil {
+		return query, nil
+	}
+	filter, ok := where.(map[string]value)
+	if !ok {
+		return query, fmt.Errorf("argument \"where\" must be a RecordFilter object")
+	}
+
+	// Conditions in a stable order, so equal filters share query cache entries
+	names := make([]string, 0, len(filter))
+	for name := range filter {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		col, operator, ok := e.h.filterField(name)
+		if !ok {
+			return query, fmt.Errorf("unknown field %q on RecordFilter", name)
+		}
+
+		raw := filter[name]
+		var condValue interface{}
+		if operator == "in" {
+			list, ok := raw.([]value)
+			if !ok {
+				list = []value{raw}
+			}
+			values := make([]interface{}, len(list))
+			for i, item := range list {
+				if values[i], err = coerceInput(col, item); err != nil {
+					return query, fmt.Errorf("field %q on RecordFilter: %w", name, err)
+				}
+			}
+			condValue = values
+		} else if condValue, err = coerceInput(col, raw); err != nil {
+			return query, fmt.Errorf("field %q on RecordFilter: %w", name, err)
+		}
+
+		query.Conditions = append(query.Conditions, sheetkv.Condition{Column: col.Name, Operator: operator, Value: condValue})
+	}
+	return query, nil
+}
+
+// values converts a RecordValues argument; null clears a column
+func (e *execution) values(raw value) (map[string]interface{}, error) {
+	input, ok := raw.(map[string]value)
+	if !ok {
+		return nil, fmt.Errorf("argument \"values\" must be a RecordValues object")
+	}
+
+	values := make(map[string]interface{}, len(input))
+	for name, v := range input {
+		col, ok := e.h.byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q on RecordValues", name)
+		}
+		converted, err := coerceInput(col, v)
+		if err != nil {
+			return nil, fmt.Errorf("field %q on RecordValues: %w", name, err)
+		}
+		values[name] = converted
+	}
+	return values, nil
+}
+
+// coerceInput converts an input value to the cell value of a column
+func coerceInput(col Column, v value) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	switch col.Type {
+	case String:
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+	case Int:
+		return coerceInt(v)
+	case Float:
+		switch n := v.(type) {
+		case int64:
+			return float64(n), nil
+		case float64:
+			return n, nil
+		}
+	case Boolean:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+	case StringList:
+		list, ok := v.([]value)
+		if !ok {
+			list = []value{v}
+		}
+		items := make([]string, len(list))
+		for i, item := range list {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected [String], got %v", v)
+			}
+			items[i] = s
+		}
+		return strings.Join(items, ","), nil
+	}
+	return nil, fmt.Errorf("expected %s, got %v", col.Type, v)
+}
+
+// coerceInt accepts integers and integral floats in the 32-bit range of
+// GraphQL's Int
+func coerceInt(v value) (int64, error) {
+	var n int64
+	switch v := v.(type) {
+	case int64:
+		n = v
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("expected Int, got %v", v)
+		}
+		n = int64(v)
+	default:
+		return 0, fmt.Errorf("expected Int, got %v", v)
+	}
+	if n < math.MinInt32 || n > math.MaxInt32 {
+		return 0, fmt.Errorf("Int cannot represent %d", n)
+	}
+	return n, nil
+}
+
+// record converts a record to an object of the selected fields
+func (e *execution) record(record *sheetkv.Record, selections []selection) (*object, error) {
+	fields, err := e.collectFields(selections, "Record", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := newObject()
+	for _, field := range fields {
+		switch field.name {
+		case "__typename":
+			obj.set(field.responseKey(), "Record")
+		case keyField:
+			obj.set(field.responseKey(), record.Key)
+		default:
+			v, err := output(e.h.byName[field.name], record)
+			if err != nil {
+				return nil, err
+			}
+			obj.set(field.responseKey(), v)
+		}
+	}
+	return obj, nil
+}
+
+// output converts the value of a column to its GraphQL type; empty cells
+// are null
+func output(col Column, record *sheetkv.Record) (interface{}, error) {
+	raw, ok := record.Values[col.Name]
+	if !ok || raw == nil || raw == "" {
+		return nil, nil
+	}
+
+	switch col.Type {
+	case String:
+		return record.GetAsString(col.Name, ""), nil
+	case Int:
+		switch v := raw.(type) {
+		case int64:
+			return v, nil
+		case int:
+			return int64(v), nil
+		case float64:
+			if v == math.Trunc(v) {
+				return int64(v), nil
+			}
+		case string:
+			if n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil {
+				return n, nil
+			}
+		}
+	case Float:
+		switch v := raw.(type) {
+		case float64:
+			return v, nil
+		case int64:
+			return float64(v), nil
+		case int:
+			return float64(v), nil
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return f, nil
+			}
+		}
+	case Boolean:
+		return record.GetAsBool(col.Name, false), nil
+	case StringList:
+		return record.GetAsStrings(col.Name, nil), nil
+	}
+	return nil, fmt.Errorf("%s cannot represent %v of column %q in row %d", col.Type, raw, col.Name, record.Key)
+}
@@ -0,0 +1,71 @@
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// ServeHTTP answers GraphQL requests: POST with a JSON body of query,
+// operationName and variables, or GET with the same as URL parameters.
+// Mutations are refused over GET.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req Request
+	switch r.Method {
+	case http.MethodGet:
+		params := r.URL.Query()
+		req.Query = params.Get("query")
+		req.OperationName = params.Get("operationName")
+		if vars := params.Get("variables"); vars != "" {
+			if err := decodeVariables(strings.NewReader(vars), &req.Variables); err != nil {
+				writeResponse(w, http.StatusBadRequest, failed(err))
+				return
+			}
+		}
+		if isMutation(req) {
+			w.Header().Set("Allow", http.MethodPost)
+			writeResponse(w, http.StatusMethodNotAllowed, &Response{Errors: []*Error{{Message: "mutations require POST"}}})
+			return
+		}
+	case http.MethodPost:
+		decoder := json.NewDecoder(r.Body)
+		decoder.UseNumber()
+		if err := decoder.Decode(&req); err != nil {
+			writeResponse(w, http.StatusBadRequest, failed(err))
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeResponse(w, http.StatusMethodNotAllowed, &Response{Errors: []*Error{{Message: "method not allowed"}}})
+		return
+	}
+
+	if req.Query == "" {
+		writeResponse(w, http.StatusBadRequest, &Response{Errors: []*Error{{Message: "query is required"}}})
+		return
+	}
+	writeResponse(w, http.StatusOK, h.Execute(r.Context(), req))
+}
+
+// decodeVariables decodes variables keeping numbers exact
+func decodeVariables(r *strings.Reader, variables *map[string]interface{}) error {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	return decoder.Decode(variables)
+}
+
+// isMutation reports whether the operation a request would run is a mutation
+func isMutation(req Request) bool {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return false
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	return err == nil && op.kind == "mutation"
+}
+
+func writeResponse(w http.ResponseWriter, status int, resp *Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
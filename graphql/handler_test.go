@@ -0,0 +1,298 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+	"github.com/ideamans/go-sheetkv/graphql"
+	"github.com/ideamans/go-sheetkv/tests/common"
+)
+
+var columns = []graphql.Column{
+	{Name: "name", Type: graphql.String},
+	{Name: "age", Type: graphql.Int},
+	{Name: "score", Type: graphql.Float},
+	{Name: "active", Type: graphql.Boolean},
+	{Name: "tags", Type: graphql.StringList},
+}
+
+func newHandler(t *testing.T) (*graphql.Handler, *sheetkv.Client) {
+	t.Helper()
+
+	adapter := &common.StaticAdapter{
+		Schema: []string{"name", "age", "score", "active", "tags"},
+		Records: []*sheetkv.Record{
+			{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30), "score": 8.5, "active": true, "tags": "admin,dev"}},
+			{Key: 3, Values: map[string]interface{}{"name": "Jane", "age": int64(25), "score": int64(9), "active": false}},
+			{Key: 4, Values: map[string]interface{}{"name": "Bob", "age": int64(41)}},
+		},
+	}
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	handler, err := graphql.New(client, columns)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return handler, client
+}
+
+// execute runs a request and returns the data as JSON and the error messages
+func execute(t *testing.T, handler *graphql.Handler, query string, variables map[string]interface{}) (string, []string) {
+	t.Helper()
+
+	resp := handler.Execute(context.Background(), graphql.Request{Query: query, Variables: variables})
+	var messages []string
+	for _, err := range resp.Errors {
+		messages = append(messages, err.Message)
+	}
+	return string(resp.Data), messages
+}
+
+func TestNew(t *testing.T) {
+	invalid := [][]graphql.Column{
+		{{Name: "first name", Type: graphql.String}},
+		{{Name: "_private", Type: graphql.String}},
+		{{Name: "1st", Type: graphql.String}},
+		{{Name: "a", Type: graphql.String}, {Name: "a", Type: graphql.Int}},
+		{{Name: "a", Type: graphql.Type(99)}},
+	}
+	for _, cols := range invalid {
+		if _, err := graphql.New(nil, cols); err == nil {
+			t.Errorf("New(%v) error = nil, want an error", cols)
+		}
+	}
+}
+
+func TestHandler_Schema(t *testing.T) {
+	handler, _ := newHandler(t)
+	schema := handler.Schema()
+
+	for _, want := range []string{
+		"type Record {\n  _key: Int!\n  name: String\n  age: Int\n",
+		"  tags: [String]\n",
+		"  age_gte: Int\n",
+		"  name_in: [String]\n",
+		"  records(where: RecordFilter, limit: Int, offset: Int): [Record!]\n",
+		"  update(key: Int!, values: RecordValues!): Record\n",
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("Schema() does not contain %q:\n%s", want, schema)
+		}
+	}
+	for _, unwanted := range []string{"name_gt", "tags_in", "active_lt"} {
+		if strings.Contains(schema, unwanted) {
+			t.Errorf("Schema() contains %q", unwanted)
+		}
+	}
+}
+
+func TestHandler_Query(t *testing.T) {
+	handler, _ := newHandler(t)
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		want      string
+	}{
+		{
+			name:  "Record",
+			query: `{ record(key: 2) { _key name age score active tags } }`,
+			want:  `{"record":{"_key":2,"name":"John","age":30,"score":8.5,"active":true,"tags":["admin","dev"]}}`,
+		},
+		{
+			name:  "Missing record",
+			query: `{ record(key: 99) { name } }`,
+			want:  `{"record":null}`,
+		},
+		{
+			name:  "Filter",
+			query: `{ records(where: {age_gte: 26}) { name } }`,
+			want:  `{"records":[{"name":"John"},{"name":"Bob"}]}`,
+		},
+		{
+			name:  "In, limit and offset",
+			query: `{ records(where: {name_in: ["Bob", "Jane", "John"]}, limit: 1, offset: 1) { name } }`,
+			want:  `{"records":[{"name":"Jane"}]}`,
+		},
+		{
+			name:      "Variables and defaults",
+			query:     `query ($min: Int = 40, $active: Boolean) { records(where: {age_gte: $min, active_ne: $active}) { name } }`,
+			variables: map[string]interface{}{"active": true},
+			want:      `{"records":[{"name":"Bob"}]}`,
+		},
+		{
+			name:  "Aliases, fragments and directives",
+			query: `query { first: record(key: 3) { ...F  n: name @skip(if: true) __typename } } fragment F on Record { n: name score }`,
+			want:  `{"first":{"n":"Jane","score":9,"__typename":"Record"}}`,
+		},
+		{
+			name:  "Empty cells are null",
+			query: `{ record(key: 4) { score tags } }`,
+			want:  `{"record":{"score":null,"tags":null}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, errs := execute(t, handler, tt.query, tt.variables)
+			if len(errs) > 0 {
+				t.Fatalf("errors = %v", errs)
+			}
+			if data != tt.want {
+				t.Errorf("data = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_Mutation(t *testing.T) {
+	handler, client := newHandler(t)
+
+	data, errs := execute(t, handler, `mutation { append(values: {name: "Alice", age: 22, tags: ["new", "ops"]}) { _key name tags } }`, nil)
+	if len(errs) > 0 {
+		t.Fatalf("append errors = %v", errs)
+	}
+	if want := `{"append":{"_key":5,"name":"Alice","tags":["new","ops"]}}`; data != want {
+		t.Errorf("append data = %s, want %s", data, want)
+	}
+
+	data, errs = execute(t, handler, `mutation ($v: RecordValues!) { update(key: 2, values: $v) { age active } }`,
+		map[string]interface{}{"v": map[string]interface{}{"age": float64(31), "active": nil}})
+	if len(errs) > 0 {
+		t.Fatalf("update errors = %v", errs)
+	}
+	if want := `{"update":{"age":31,"active":null}}`; data != want {
+		t.Errorf("update data = %s, want %s", data, want)
+	}
+
+	data, errs = execute(t, handler, `mutation { delete(key: 3) }`, nil)
+	if len(errs) > 0 || data != `{"delete":true}` {
+		t.Errorf("delete = %s %v, want true", data, errs)
+	}
+	if _, err := client.Get(3); err == nil {
+		t.Error("record 3 still exists")
+	}
+
+	t.Run("Field error", func(t *testing.T) {
+		data, errs := execute(t, handler, `mutation { delete(key: 3) }`, nil)
+		if data != `{"delete":null}` || len(errs) != 1 {
+			t.Errorf("delete = %s %v, want null and one error", data, errs)
+		}
+	})
+}
+
+func TestHandler_Errors(t *testing.T) {
+	handler, _ := newHandler(t)
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"Syntax", `{ records { name }`},
+		{"Unknown field", `{ records { nmae } }`},
+		{"Unknown root field", `{ people { name } }`},
+		{"Missing selection", `{ records }`},
+		{"Selection on scalar", `{ records { name { x } } }`},
+		{"Missing argument", `{ record { name } }`},
+		{"Unknown argument", `{ records(sort: "name") { name } }`},
+		{"Undefined variable", `{ records(limit: $n) { name } }`},
+		{"Unused variable", `query ($n: Int) { records { name } }`},
+		{"Required variable", `query ($n: Int!) { records(limit: $n) { name } }`},
+		{"Unknown fragment", `{ records { ...F } }`},
+		{"Fragment cycle", `{ records { ...A } } fragment A on Record { ...B } fragment B on Record { ...A }`},
+		{"Unknown directive", `{ records @cached { name } }`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, errs := execute(t, handler, tt.query, nil)
+			if data != "" || len(errs) != 1 {
+				t.Errorf("response = %s %v, want no data and one error", data, errs)
+			}
+		})
+	}
+
+	fieldErrors := []struct {
+		name  string
+		query string
+	}{
+		{"Unknown filter", `{ records(where: {name_gt: "A"}) { name } }`},
+		{"Wrong value type", `{ records(where: {age: "thirty"}) { name } }`},
+		{"Int out of range", `{ records(limit: 3000000000) { name } }`},
+	}
+	for _, tt := range fieldErrors {
+		t.Run(tt.name, func(t *testing.T) {
+			data, errs := execute(t, handler, tt.query, nil)
+			if data != `{"records":null}` || len(errs) != 1 {
+				t.Errorf("response = %s %v, want null records and one error", data, errs)
+			}
+		})
+	}
+}
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	handler, _ := newHandler(t)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	t.Run("POST", func(t *testing.T) {
+		body := `{"query": "query ($k: Int!) { record(key: $k) { name } }", "variables": {"k": 2}}`
+		resp, err := http.Post(server.URL, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Post() error = %v", err)
+		}
+		defer resp.Body.Close()
+
+		var got struct {
+			Data map[string]map[string]string `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		if resp.StatusCode != http.StatusOK || got.Data["record"]["name"] != "John" {
+			t.Errorf("response = %d %v, want 200 and John", resp.StatusCode, got.Data)
+		}
+	})
+
+	t.Run("GET", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "?query=" + url.QueryEscape(`{ records(limit: 1) { name } }`))
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("status = %d, want 200", resp.StatusCode)
+		}
+	})
+
+	t.Run("GET mutation", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "?query=" + url.QueryEscape(`mutation { delete(key: 2) }`))
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusMethodNotAllowed {
+			t.Errorf("status = %d, want 405", resp.StatusCode)
+		}
+	})
+
+	t.Run("Invalid body", func(t *testing.T) {
+		resp, err := http.Post(server.URL, "application/json", strings.NewReader(`{`))
+		if err != nil {
+			t.Fatalf("Post() error = %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("status = %d, want 400", resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package graphql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	doc, err := parse(`
+		# Fetch adults
+		query Adults($min: Int = 20, $names: [String!]) {
+			people: records(where: {age_gte: $min, name_in: $names}, limit: 10) {
+				_key
+				...Person @include(if: true)
+				... on Record { note }
+			}
+		}
+
+		fragment Person on Record { name, age }
+	`)
+	if err != nil {
+		t.Fatalf("parse() error = %v", err)
+	}
+
+	if len(doc.operations) != 1 || doc.operations[0].name != "Adults" || doc.operations[0].kind != "query" {
+		t.Fatalf("operations = %+v, want the Adults query", doc.operations)
+	}
+	op := doc.operations[0]
+	if len(op.variables) != 2 || op.variables[0].defaultValue != int64(20) || op.variables[1].typ != "[String!]" {
+		t.Errorf("variables = %+v, want $min with default 20 and $names", op.variables)
+	}
+
+	field := op.selections[0].(*fieldSelection)
+	if field.alias != "people" || field.name != "records" {
+		t.Errorf("field = %s: %s, want people: records", field.alias, field.name)
+	}
+	wantWhere := map[string]value{"age_gte": variableRef("min"), "name_in": variableRef("names")}
+	if !reflect.DeepEqual(field.arguments["where"], wantWhere) {
+		t.Errorf("where = %v, want %v", field.arguments["where"], wantWhere)
+	}
+	if len(field.selections) != 3 {
+		t.Fatalf("selections = %d, want 3", len(field.selections))
+	}
+	if spread, ok := field.selections[1].(*fragmentSpread); !ok || spread.name != "Person" || len(spread.directives) != 1 {
+		t.Errorf("selection 1 = %+v, want ...Person @include", field.selections[1])
+	}
+	if inline, ok := field.selections[2].(*inlineFragment); !ok || inline.typeName != "Record" {
+		t.Errorf("selection 2 = %+v, want an inline fragment on Record", field.selections[2])
+	}
+	if frag := doc.fragments["Person"]; frag == nil || frag.typeName != "Record" || len(frag.selections) != 2 {
+		t.Errorf("fragment Person = %+v", frag)
+	}
+}
+
+func TestParse_Values(t *testing.T) {
+	tests := []struct {
+		src  string
+		want value
+	}{
+		{`1`, int64(1)},
+		{`-2.5e1`, float64(-25)},
+		{`"a\"bé"`, "a\"bé"},
+		{"\"\"\"\n    line 1\n      line 2\n    \"\"\"", "line 1\n  line 2"},
+		{`true`, true},
+		{`null`, nil},
+		{`ASC`, enumValue("ASC")},
+		{`[1, "x", [false]]`, []value{int64(1), "x", []value{false}}},
+		{`{a: 1, b: {c: null}}`, map[string]value{"a": int64(1), "b": map[string]value{"c": nil}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			doc, err := parse("{ f(v: " + tt.src + ") }")
+			if err != nil {
+				t.Fatalf("parse() error = %v", err)
+			}
+			got := doc.operations[0].selections[0].(*fieldSelection).arguments["v"]
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("value = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []string{
+		``,
+		`{`,
+		`{ }`,
+		`{ a(b: ) }`,
+		`{ a(b: "unterminated) }`,
+		`query ($x: Int = $y) { a }`,
+		`fragment on on Record { a }`,
+		`fragment F on Record { a } fragment F on Record { b }`,
+		`{ a(b: 1, b: 2) }`,
+		`{ a(b: 99999999999999999999) }`,
+		`subscription { a }`,
+	}
+
+	for _, src := range tests {
+		if _, err := parse(src); err == nil {
+			t.Errorf("parse(%q) error = nil, want an error", src)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+// Package graphql serves a sheetkv client as a GraphQL endpoint, for quick
+// internal tools and admin pages.
+//
+// The schema is generated from the declared columns: a Record type with one
+// field per column plus _key (the row number), a RecordFilter input whose
+// fields map to query conditions, and the record, records, append, set,
+// update and delete operations. Schema returns it in SDL.
+//
+//	handler, err := graphql.New(client, []graphql.Column{
+//		{Name: "name", Type: graphql.String},
+//		{Name: "age", Type: graphql.Int},
+//	})
+//	http.Handle("/graphql", handler)
+//
+// A filter such as
+//
+//	{ records(where: {age_gte: 20, name_in: ["John", "Jane"]}, limit: 10) { _key name } }
+//
+// runs Query{Conditions: [age >= 20, name in (John, Jane)], Limit: 10}.
+// Records are returned in row order.
+//
+// Introspection and subscriptions are not supported.
+package graphql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// Type is the GraphQL type of a column
+type Type int
+
+const (
+	String     Type = iota // String, stored as is
+	Int                    // Int, stored as int64
+	Float                  // Float, stored as float64
+	Boolean                // Boolean, stored as bool
+	StringList             // [String], stored comma-separated like Record.SetStrings
+)
+
+// String returns the GraphQL name of the type
+func (t Type) String() string {
+	switch t {
+	case String:
+		return "String"
+	case Int:
+		return "Int"
+	case Float:
+		return "Float"
+	case Boolean:
+		return "Boolean"
+	case StringList:
+		return "[String]"
+	default:
+		return fmt.Sprintf("Type(%d)", int(t))
+	}
+}
+
+// Column declares a column exposed by the schema
+type Column struct {
+	Name string
+	Type Type
+}
+
+// keyField is the field of Record holding the row number; it cannot clash
+// with a column because columns starting with an underscore are rejected
+const keyField = "_key"
+
+// filterOperator maps a suffix of RecordFilter fields to a query operator
+type filterOperator struct {
+	suffix   string
+	operator string
+	numeric  bool // Only offered on Int and Float columns
+}
+
+// offers reports whether a column has a filter field with the operator.
+// Lists are only compared as a whole.
+func (f filterOperator) offers(col Column) bool {
+	switch {
+	case f.numeric:
+		return col.Type == Int || col.Type == Float
+	case f.operator == "in":
+		return col.Type != StringList
+	}
+	return true
+}
+
+// Filter suffixes and the operators they map to. A filter field without a
+// suffix compares for equality.
+var filterOperators = []filterOperator{
+	{"_ne", "!=", false},
+	{"_gt", ">", true},
+	{"_gte", ">=", true},
+	{"_lt", "<", true},
+	{"_lte", "<=", true},
+	{"_in", "in", false},
+}
+
+// argument describes an argument of a root field
+type argument struct {
+	name     string
+	typ      string
+	required bool
+}
+
+// rootField describes a field of Query or Mutation
+type rootField struct {
+	name      string
+	arguments []argument
+	result    string // Type of the result in SDL
+	record    bool   // Whether the result is made of records and needs a selection
+}
+
+var queryFields = []rootField{
+	{"record", []argument{{"key", "Int", true}}, "Record", true},
+	{"records", []argument{{"where", "RecordFilter", false}, {"limit", "Int", false}, {"offset", "Int", false}}, "[Record!]", true},
+}
+
+var mutationFields = []rootField{
+	{"append", []argument{{"values", "RecordValues", true}}, "Record", true},
+	{"set", []argument{{"key", "Int", true}, {"values", "RecordValues", true}}, "Record", true},
+	{"update", []argument{{"key", "Int", true}, {"values", "RecordValues", true}}, "Record", true},
+	{"delete", []argument{{"key", "Int", true}}, "Boolean", false},
+}
+
+// findRootField returns the root field of an operation kind by name
+func findRootField(kind, name string) (rootField, bool) {
+	fields := queryFields
+	if kind == "mutation" {
+		fields = mutationFields
+	}
+	for _, f := range fields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return rootField{}, false
+}
+
+// Handler executes GraphQL requests against a client
+type Handler struct {
+	client  *sheetkv.Client
+	columns []Column
+	byName  map[string]Column
+}
+
+// New creates a handler exposing the given columns of client. Column names
+// must be valid GraphQL names that do not start with an underscore.
+func New(client *sheetkv.Client, columns []Column) (*Handler, error) {
+	h := &Handler{client: client, byName: make(map[string]Column, len(columns))}
+	for _, col := range columns {
+		if !validName(col.Name) || strings.HasPrefix(col.Name, "_") {
+			return nil, fmt.Errorf("column %q is not a valid GraphQL field name", col.Name)
+		}
+		if col.Type < String || col.Type > StringList {
+			return nil, fmt.Errorf("column %q has an unknown type %d", col.Name, int(col.Type))
+		}
+		if _, ok := h.byName[col.Name]; ok {
+			return nil, fmt.Errorf("column %q is declared more than once", col.Name)
+		}
+		h.columns = append(h.columns, col)
+		h.byName[col.Name] = col
+	}
+	return h, nil
+}
+
+// validName reports whether name matches /[_A-Za-z][_0-9A-Za-z]*/
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c != '_' && !isLetter(c) && (i == 0 || !isDigit(c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterField returns the column and operator of a RecordFilter field
+func (h *Handler) filterField(name string) (Column, string, bool) {
+	if col, ok := h.byName[name]; ok {
+		return col, "==", true
+	}
+	for _, f := range filterOperators {
+		base, found := strings.CutSuffix(name, f.suffix)
+		if !found {
+			continue
+		}
+		col, ok := h.byName[base]
+		if !ok || !f.offers(col) {
+			continue
+		}
+		return col, f.operator, true
+	}
+	return Column{}, "", false
+}
+
+// Schema returns the schema in the GraphQL schema definition language
+func (h *Handler) Schema() string {
+	var b strings.Builder
+
+	b.WriteString("type Record {\n")
+	fmt.Fprintf(&b, "  %s: Int!\n", keyField)
+	for _, col := range h.columns {
+		fmt.Fprintf(&b, "  %s: %s\n", col.Name, col.Type)
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("input RecordValues {\n")
+	for _, col := range h.columns {
+		fmt.Fprintf(&b, "  %s: %s\n", col.Name, col.Type)
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("input RecordFilter {\n")
+	for _, col := range h.columns {
+		fmt.Fprintf(&b, "  %s: %s\n", col.Name, col.Type)
+		for _, f := range filterOperators {
+			switch {
+			case !f.offers(col):
+			case f.operator == "in":
+				fmt.Fprintf(&b, "  %s%s: [%s]\n", col.Name, f.suffix, col.Type)
+			default:
+				fmt.Fprintf(&b, "  %s%s: %s\n", col.Name, f.suffix, col.Type)
+			}
+		}
+	}
+	b.WriteString("}\n\n")
+
+	writeRoot := func(name string, fields []rootField) {
+		fmt.Fprintf(&b, "type %s {\n", name)
+		for _, f := range fields {
+			args := make([]string, len(f.arguments))
+			for i, arg := range f.arguments {
+				args[i] = arg.name + ": " + arg.typ
+				if arg.required {
+					args[i] += "!"
+				}
+			}
+			fmt.Fprintf(&b, "  %s(%s): %s\n", f.name, strings.Join(args, ", "), f.result)
+		}
+		b.WriteString("}\n")
+	}
+	writeRoot("Query", queryFields)
+	b.WriteString("\n")
+	writeRoot("Mutation", mutationFields)
+	return b.String()
+}
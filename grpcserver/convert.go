@@ -0,0 +1,118 @@
+package grpcserver
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+	"github.com/ideamans/go-sheetkv/grpcserver/sheetkvpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toRecord converts a record of the client
+func toRecord(record *sheetkv.Record) (*sheetkvpb.Record, error) {
+	r := &sheetkvpb.Record{
+		Key:    int64(record.Key),
+		Values: make(map[string]*sheetkvpb.Value, len(record.Values)),
+	}
+	for col, value := range record.Values {
+		v, err := toValue(value)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "column %q: %v", col, err)
+		}
+		r.Values[col] = v
+	}
+	return r, nil
+}
+
+// toValue converts a cell value. Times are sent as RFC 3339 strings, the
+// way the sheet stores them.
+func toValue(value interface{}) (*sheetkvpb.Value, error) {
+	switch v := value.(type) {
+	case nil:
+		return &sheetkvpb.Value{}, nil
+	case string:
+		return &sheetkvpb.Value{Kind: &sheetkvpb.Value_StringValue{StringValue: v}}, nil
+	case int:
+		return &sheetkvpb.Value{Kind: &sheetkvpb.Value_IntValue{IntValue: int64(v)}}, nil
+	case int64:
+		return &sheetkvpb.Value{Kind: &sheetkvpb.Value_IntValue{IntValue: v}}, nil
+	case float64:
+		return &sheetkvpb.Value{Kind: &sheetkvpb.Value_FloatValue{FloatValue: v}}, nil
+	case bool:
+		return &sheetkvpb.Value{Kind: &sheetkvpb.Value_BoolValue{BoolValue: v}}, nil
+	case []string:
+		return &sheetkvpb.Value{Kind: &sheetkvpb.Value_StringsValue{StringsValue: &sheetkvpb.StringList{Values: v}}}, nil
+	case time.Time:
+		return &sheetkvpb.Value{Kind: &sheetkvpb.Value_StringValue{StringValue: v.Format(time.RFC3339)}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", value)
+	}
+}
+
+// fromValues converts the values of a request; unset values become nil
+func fromValues(values map[string]*sheetkvpb.Value) map[string]interface{} {
+	converted := make(map[string]interface{}, len(values))
+	for col, value := range values {
+		converted[col] = fromValue(value)
+	}
+	return converted
+}
+
+// fromValue converts a value of a request
+func fromValue(value *sheetkvpb.Value) interface{} {
+	switch v := value.GetKind().(type) {
+	case *sheetkvpb.Value_StringValue:
+		return v.StringValue
+	case *sheetkvpb.Value_IntValue:
+		return v.IntValue
+	case *sheetkvpb.Value_FloatValue:
+		return v.FloatValue
+	case *sheetkvpb.Value_BoolValue:
+		return v.BoolValue
+	case *sheetkvpb.Value_StringsValue:
+		return v.StringsValue.GetValues()
+	default:
+		return nil
+	}
+}
+
+// fromQuery converts a query request and validates it
+func fromQuery(req *sheetkvpb.QueryRequest) (sheetkv.Query, error) {
+	// Row order keeps limit and offset stable between calls
+	query := sheetkv.Query{
+		Limit:    int(req.GetLimit()),
+		Offset:   int(req.GetOffset()),
+		SortFunc: func(a, b *sheetkv.Record) bool { return a.Key < b.Key },
+	}
+
+	for i, cond := range req.GetConditions() {
+		values := make([]interface{}, 0, len(cond.GetValues()))
+		for _, v := range cond.GetValues() {
+			values = append(values, fromValue(v))
+		}
+
+		condition := sheetkv.Condition{Column: cond.GetColumn(), Operator: cond.GetOperator()}
+		switch cond.GetOperator() {
+		case "in":
+			condition.Value = values
+		case "between":
+			if len(values) != 2 {
+				return query, fmt.Errorf("operator 'between' requires 2 values in condition %d", i)
+			}
+			condition.Value = values
+		default:
+			if len(values) != 1 {
+				return query, fmt.Errorf("operator '%s' requires 1 value in condition %d", cond.GetOperator(), i)
+			}
+			condition.Value = values[0]
+		}
+		query.Conditions = append(query.Conditions, condition)
+	}
+
+	if err := sheetkv.ValidateQuery(query); err != nil {
+		return query, err
+	}
+	return query, nil
+}
@@ -0,0 +1,183 @@
+// Package grpcserver serves a sheetkv client over gRPC, so services written in
+// any language can share one sheet-backed store through the typed API defined
+// in sheetkvpb/sheetkv.proto.
+//
+//	server := grpc.NewServer()
+//	sheetkvpb.RegisterSheetKVServer(server, grpcserver.New(client))
+//	server.Serve(listener)
+package grpcserver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ideamans/go-sheetkv"
+	"github.com/ideamans/go-sheetkv/grpcserver/sheetkvpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements sheetkvpb.SheetKVServer on top of a client
+type Server struct {
+	sheetkvpb.UnimplementedSheetKVServer
+	client *sheetkv.Client
+}
+
+// New creates a server exposing client. The client stays owned by the
+// caller, who initializes and closes it.
+func New(client *sheetkv.Client) *Server {
+	return &Server{client: client}
+}
+
+// Get returns the record stored under a key
+func (s *Server) Get(ctx context.Context, req *sheetkvpb.GetRequest) (*sheetkvpb.Record, error) {
+	record, err := s.client.Get(int(req.GetKey()))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return toRecord(record)
+}
+
+// Set stores a record under a key
+func (s *Server) Set(ctx context.Context, req *sheetkvpb.SetRequest) (*sheetkvpb.SetResponse, error) {
+	key := int(req.GetKey())
+	record := &sheetkv.Record{Key: key, Values: fromValues(req.GetValues())}
+	if err := s.client.Set(key, record); err != nil {
+		return nil, statusError(err)
+	}
+	return &sheetkvpb.SetResponse{}, nil
+}
+
+// Append adds a record and returns its key
+func (s *Server) Append(ctx context.Context, req *sheetkvpb.AppendRequest) (*sheetkvpb.AppendResponse, error) {
+	record := &sheetkv.Record{Values: fromValues(req.GetValues())}
+	if err := s.client.Append(record); err != nil {
+		return nil, statusError(err)
+	}
+	return &sheetkvpb.AppendResponse{Key: int64(record.Key)}, nil
+}
+
+// Update changes some columns of a record
+func (s *Server) Update(ctx context.Context, req *sheetkvpb.UpdateRequest) (*sheetkvpb.UpdateResponse, error) {
+	if err := s.client.Update(int(req.GetKey()), fromValues(req.GetValues())); err != nil {
+		return nil, statusError(err)
+	}
+	return &sheetkvpb.UpdateResponse{}, nil
+}
+
+// Delete removes a record
+func (s *Server) Delete(ctx context.Context, req *sheetkvpb.DeleteRequest) (*sheetkvpb.DeleteResponse, error) {
+	if err := s.client.Delete(int(req.GetKey())); err != nil {
+		return nil, statusError(err)
+	}
+	return &sheetkvpb.DeleteResponse{}, nil
+}
+
+// Query returns the records matching every condition
+func (s *Server) Query(ctx context.Context, req *sheetkvpb.QueryRequest) (*sheetkvpb.QueryResponse, error) {
+	query, err := fromQuery(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	records, err := s.client.Query(query)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &sheetkvpb.QueryResponse{Records: make([]*sheetkvpb.Record, 0, len(records))}
+	for _, record := range records {
+		r, err := toRecord(record)
+		if err != nil {
+			return nil, err
+		}
+		resp.Records = append(resp.Records, r)
+	}
+	return resp, nil
+}
+
+// Watch streams the changes applied through the client until the caller
+// cancels. When the client is closed, or the caller cannot keep up, the
+// stream ends with codes.Unavailable and the caller should re-read the data
+// it depends on before watching again.
+func (s *Server) Watch(req *sheetkvpb.WatchRequest, stream grpc.ServerStreamingServer[sheetkvpb.Change]) error {
+	ctx := stream.Context()
+	changes := s.client.Watch(ctx)
+	// Send the headers right away so the caller knows the watch is registered
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	for change := range changes {
+		c, err := toChange(change)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(c); err != nil {
+			return err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.Unavailable, "watch ended: the client was closed or the receiver fell behind")
+}
+
+// toChange converts a change of the client
+func toChange(change sheetkv.Change) (*sheetkvpb.Change, error) {
+	c := &sheetkvpb.Change{
+		Operation: toOperation(change.Op),
+		Key:       int64(change.Key),
+		Columns:   change.Columns,
+		Time:      timestamppb.New(change.Time),
+	}
+
+	var err error
+	if change.Old != nil {
+		if c.Old, err = toRecord(change.Old); err != nil {
+			return nil, err
+		}
+	}
+	if change.New != nil {
+		if c.New, err = toRecord(change.New); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// toOperation converts an operation type
+func toOperation(op sheetkv.OperationType) sheetkvpb.Operation {
+	switch op {
+	case sheetkv.OpAdd:
+		return sheetkvpb.Operation_OPERATION_ADD
+	case sheetkv.OpUpdate:
+		return sheetkvpb.Operation_OPERATION_UPDATE
+	case sheetkv.OpDelete:
+		return sheetkvpb.Operation_OPERATION_DELETE
+	default:
+		return sheetkvpb.Operation_OPERATION_UNSPECIFIED
+	}
+}
+
+// statusError maps errors of the client to gRPC status codes
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, sheetkv.ErrKeyNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, sheetkv.ErrDuplicateKey):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, sheetkv.ErrInvalidValue), errors.Is(err, sheetkv.ErrUnknownColumn):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, sheetkv.ErrQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, sheetkv.ErrRemoteConflict):
+		return status.Error(codes.Aborted, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
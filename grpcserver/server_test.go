@@ -0,0 +1,239 @@
+package grpcserver_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+	"github.com/ideamans/go-sheetkv/grpcserver"
+	"github.com/ideamans/go-sheetkv/grpcserver/sheetkvpb"
+	"github.com/ideamans/go-sheetkv/tests/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestServer(t *testing.T, config *sheetkv.Config) (sheetkvpb.SheetKVClient, *sheetkv.Client) {
+	t.Helper()
+
+	adapter := &common.StaticAdapter{
+		Schema: []string{"name", "age", "tags"},
+		Records: []*sheetkv.Record{
+			{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30), "tags": []string{"a", "b"}}},
+			{Key: 3, Values: map[string]interface{}{"name": "Jane", "age": int64(25)}},
+		},
+	}
+	client := sheetkv.New(adapter, config)
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	sheetkvpb.RegisterSheetKVServer(server, grpcserver.New(client))
+	go server.Serve(listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+		client.Close()
+	})
+	return sheetkvpb.NewSheetKVClient(conn), client
+}
+
+func stringValue(s string) *sheetkvpb.Value {
+	return &sheetkvpb.Value{Kind: &sheetkvpb.Value_StringValue{StringValue: s}}
+}
+
+func intValue(i int64) *sheetkvpb.Value {
+	return &sheetkvpb.Value{Kind: &sheetkvpb.Value_IntValue{IntValue: i}}
+}
+
+func TestServer_CRUD(t *testing.T) {
+	ctx := context.Background()
+	kv, client := newTestServer(t, &sheetkv.Config{DisableAutoSync: true})
+
+	t.Run("Get", func(t *testing.T) {
+		record, err := kv.Get(ctx, &sheetkvpb.GetRequest{Key: 2})
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if got := record.GetValues()["name"].GetStringValue(); got != "John" {
+			t.Errorf("name = %q, want John", got)
+		}
+		if got := record.GetValues()["age"].GetIntValue(); got != 30 {
+			t.Errorf("age = %d, want 30", got)
+		}
+		if got := record.GetValues()["tags"].GetStringsValue().GetValues(); len(got) != 2 {
+			t.Errorf("tags = %v, want [a b]", got)
+		}
+	})
+
+	t.Run("Get missing key", func(t *testing.T) {
+		_, err := kv.Get(ctx, &sheetkvpb.GetRequest{Key: 99})
+		if got := status.Code(err); got != codes.NotFound {
+			t.Errorf("Get() code = %v, want NotFound", got)
+		}
+	})
+
+	t.Run("Append", func(t *testing.T) {
+		resp, err := kv.Append(ctx, &sheetkvpb.AppendRequest{Values: map[string]*sheetkvpb.Value{"name": stringValue("Bob")}})
+		if err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+		if resp.GetKey() != 4 {
+			t.Errorf("Append() key = %d, want 4", resp.GetKey())
+		}
+	})
+
+	t.Run("Set and Update", func(t *testing.T) {
+		if _, err := kv.Set(ctx, &sheetkvpb.SetRequest{Key: 10, Values: map[string]*sheetkvpb.Value{"name": stringValue("Alice")}}); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		if _, err := kv.Update(ctx, &sheetkvpb.UpdateRequest{Key: 10, Values: map[string]*sheetkvpb.Value{"age": intValue(40)}}); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+
+		record, err := client.Get(10)
+		if err != nil {
+			t.Fatalf("client.Get() error = %v", err)
+		}
+		if record.GetAsString("name", "") != "Alice" || record.GetAsInt64("age", 0) != 40 {
+			t.Errorf("record = %v, want name Alice and age 40", record.Values)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if _, err := kv.Delete(ctx, &sheetkvpb.DeleteRequest{Key: 10}); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		_, err := kv.Delete(ctx, &sheetkvpb.DeleteRequest{Key: 10})
+		if got := status.Code(err); got != codes.NotFound {
+			t.Errorf("Delete() code = %v, want NotFound", got)
+		}
+	})
+}
+
+func TestServer_Query(t *testing.T) {
+	ctx := context.Background()
+	kv, _ := newTestServer(t, &sheetkv.Config{DisableAutoSync: true})
+
+	tests := []struct {
+		name       string
+		conditions []*sheetkvpb.Condition
+		wantKeys   []int64
+		wantCode   codes.Code
+	}{
+		{
+			name:       "Comparison",
+			conditions: []*sheetkvpb.Condition{{Column: "age", Operator: ">", Values: []*sheetkvpb.Value{intValue(26)}}},
+			wantKeys:   []int64{2},
+		},
+		{
+			name:       "In",
+			conditions: []*sheetkvpb.Condition{{Column: "name", Operator: "in", Values: []*sheetkvpb.Value{stringValue("Jane"), stringValue("Bob")}}},
+			wantKeys:   []int64{3},
+		},
+		{
+			name:       "Between",
+			conditions: []*sheetkvpb.Condition{{Column: "age", Operator: "between", Values: []*sheetkvpb.Value{intValue(20), intValue(30)}}},
+			wantKeys:   []int64{2, 3},
+		},
+		{
+			name:       "Invalid operator",
+			conditions: []*sheetkvpb.Condition{{Column: "age", Operator: "~", Values: []*sheetkvpb.Value{intValue(1)}}},
+			wantCode:   codes.InvalidArgument,
+		},
+		{
+			name:       "Missing value",
+			conditions: []*sheetkvpb.Condition{{Column: "age", Operator: "=="}},
+			wantCode:   codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := kv.Query(ctx, &sheetkvpb.QueryRequest{Conditions: tt.conditions})
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("Query() code = %v, want %v (%v)", got, tt.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+
+			var keys []int64
+			for _, record := range resp.GetRecords() {
+				keys = append(keys, record.GetKey())
+			}
+			if len(keys) != len(tt.wantKeys) {
+				t.Fatalf("Query() keys = %v, want %v", keys, tt.wantKeys)
+			}
+			for i := range keys {
+				if keys[i] != tt.wantKeys[i] {
+					t.Errorf("Query() keys = %v, want %v", keys, tt.wantKeys)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestServer_InvalidArgument(t *testing.T) {
+	ctx := context.Background()
+	kv, _ := newTestServer(t, &sheetkv.Config{DisableAutoSync: true, StrictSchema: true})
+
+	_, err := kv.Update(ctx, &sheetkvpb.UpdateRequest{Key: 2, Values: map[string]*sheetkvpb.Value{"nmae": stringValue("x")}})
+	if got := status.Code(err); got != codes.InvalidArgument {
+		t.Errorf("Update() code = %v, want InvalidArgument", got)
+	}
+}
+
+func TestServer_Watch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kv, client := newTestServer(t, &sheetkv.Config{DisableAutoSync: true})
+
+	stream, err := kv.Watch(ctx, &sheetkvpb.WatchRequest{})
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	// The server sends the headers once the watch is registered
+	if _, err := stream.Header(); err != nil {
+		t.Fatalf("Header() error = %v", err)
+	}
+
+	if err := client.Update(2, map[string]interface{}{"age": int64(31)}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	change, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv() error = %v", err)
+	}
+	if change.GetOperation() != sheetkvpb.Operation_OPERATION_UPDATE || change.GetKey() != 2 {
+		t.Errorf("change = %v %d, want update 2", change.GetOperation(), change.GetKey())
+	}
+	if got := change.GetOld().GetValues()["age"].GetIntValue(); got != 30 {
+		t.Errorf("old age = %d, want 30", got)
+	}
+	if got := change.GetNew().GetValues()["age"].GetIntValue(); got != 31 {
+		t.Errorf("new age = %d, want 31", got)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.Unavailable {
+		t.Errorf("Recv() after Close error = %v, want Unavailable", err)
+	}
+}
@@ -0,0 +1,5 @@
+// Package sheetkvpb holds the protocol buffer messages and gRPC stubs of the
+// SheetKV service, generated from sheetkv.proto.
+package sheetkvpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative sheetkv.proto
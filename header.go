@@ -0,0 +1,138 @@
+package sheetkv
+
+import "strings"
+
+// DefaultHeaderSeparator joins the levels of a grouped header into a column
+// name, e.g. "address/city"
+const DefaultHeaderSeparator = "/"
+
+// FlattenHeader combines header rows spanning several lines into column
+// names. A group label applies to the columns on its right until the next
+// label, as with merged cells; a label with nothing below it is a column of
+// its own.
+func FlattenHeader(rows [][]string, separator string) []string {
+	if separator == "" {
+		separator = DefaultHeaderSeparator
+	}
+
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+
+	groups := make([]string, len(rows))
+	names := make([]string, width)
+	for j := 0; j < width; j++ {
+		leaf := -1
+		for l, row := range rows {
+			if j < len(row) && strings.TrimSpace(row[j]) != "" {
+				groups[l] = strings.TrimSpace(row[j])
+				clear(groups[l+1:])
+				leaf = l
+			}
+		}
+		if leaf == -1 {
+			continue // Blank column
+		}
+
+		parts := make([]string, 0, leaf+1)
+		for _, group := range groups[:leaf+1] {
+			if group != "" {
+				parts = append(parts, group)
+			}
+		}
+		names[j] = strings.Join(parts, separator)
+
+		// A label ending above the last row does not group the next columns
+		clear(groups[leaf:])
+	}
+	return names
+}
+
+// SplitHeader is the inverse of FlattenHeader: it spreads the column names
+// over count header rows, writing each group label once above its first
+// column.
+func SplitHeader(schema []string, count int, separator string) [][]string {
+	if separator == "" {
+		separator = DefaultHeaderSeparator
+	}
+	if count < 1 {
+		count = 1
+	}
+
+	rows := make([][]string, count)
+	for l := range rows {
+		rows[l] = make([]string, len(schema))
+	}
+
+	var previous []string
+	for j, name := range schema {
+		parts := strings.SplitN(name, separator, count)
+		for l, part := range parts {
+			if l < len(parts)-1 && l < len(previous)-1 && samePrefix(parts, previous, l) {
+				continue // Same group as the previous column
+			}
+			rows[l][j] = part
+		}
+		previous = parts
+	}
+	return rows
+}
+
+// samePrefix reports whether a and b share their first l+1 parts
+func samePrefix(a, b []string, l int) bool {
+	for i := 0; i <= l; i++ {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ColumnAliases maps sheet header names, such as "氏名" or "Join Date", to
+// the column names used in code. Headers without an alias are used as is.
+type ColumnAliases map[string]string
+
+// Column returns the column name for a sheet header
+func (a ColumnAliases) Column(header string) string {
+	if column, ok := a[header]; ok {
+		return column
+	}
+	return header
+}
+
+// Header returns the sheet header for a column name
+func (a ColumnAliases) Header(column string) string {
+	for header, col := range a {
+		if col == column {
+			return header
+		}
+	}
+	return column
+}
+
+// Columns maps sheet headers to column names
+func (a ColumnAliases) Columns(headers []string) []string {
+	if len(a) == 0 {
+		return headers
+	}
+	columns := make([]string, len(headers))
+	for i, header := range headers {
+		columns[i] = a.Column(header)
+	}
+	return columns
+}
+
+// Headers maps column names to sheet headers
+func (a ColumnAliases) Headers(columns []string) []string {
+	if len(a) == 0 {
+		return columns
+	}
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = a.Header(col)
+	}
+	return headers
+}
@@ -0,0 +1,81 @@
+package sheetkv_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestFlattenHeader(t *testing.T) {
+	tests := []struct {
+		name      string
+		rows      [][]string
+		separator string
+		want      []string
+	}{
+		{
+			name: "Single row",
+			rows: [][]string{{"id", "name"}},
+			want: []string{"id", "name"},
+		},
+		{
+			name: "Group spans the following columns",
+			rows: [][]string{
+				{"id", "address", "", "", "note"},
+				{"", "city", "zip", "", ""},
+			},
+			want: []string{"id", "address/city", "address/zip", "", "note"},
+		},
+		{
+			name: "Ungrouped field after a standalone column",
+			rows: [][]string{
+				{"id", ""},
+				{"", "name"},
+			},
+			want: []string{"id", "name"},
+		},
+		{
+			name:      "Custom separator",
+			rows:      [][]string{{"address", ""}, {"city", "zip"}},
+			separator: " / ",
+			want:      []string{"address / city", "address / zip"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sheetkv.FlattenHeader(tt.rows, tt.separator); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FlattenHeader() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitHeader(t *testing.T) {
+	schema := []string{"id", "address/city", "address/zip", "contact/email", "note"}
+
+	rows := sheetkv.SplitHeader(schema, 2, "")
+	want := [][]string{
+		{"id", "address", "", "contact", "note"},
+		{"", "city", "zip", "email", ""},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("SplitHeader() = %q, want %q", rows, want)
+	}
+
+	if got := sheetkv.FlattenHeader(rows, ""); !reflect.DeepEqual(got, schema) {
+		t.Errorf("FlattenHeader(SplitHeader()) = %q, want %q", got, schema)
+	}
+}
+
+func TestColumnAliases(t *testing.T) {
+	aliases := sheetkv.ColumnAliases{"氏名": "name", "Join Date": "joined_at"}
+
+	if got := aliases.Columns([]string{"氏名", "Join Date", "email"}); !reflect.DeepEqual(got, []string{"name", "joined_at", "email"}) {
+		t.Errorf("Columns() = %v", got)
+	}
+	if got := aliases.Headers([]string{"name", "joined_at", "email"}); !reflect.DeepEqual(got, []string{"氏名", "Join Date", "email"}) {
+		t.Errorf("Headers() = %v", got)
+	}
+}
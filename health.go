@@ -0,0 +1,48 @@
+package sheetkv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Ping checks that the backend is reachable and records the outcome in
+// Stats. It is tried once, so a failure reflects the current state of the
+// backend. It returns an error wrapping errors.ErrUnsupported when the
+// adapter does not implement Pinger.
+func (c *Client) Ping(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return fmt.Errorf("client is closed")
+	}
+
+	pinger, ok := c.adaptor.(Pinger)
+	if !ok {
+		return fmt.Errorf("adapter does not support ping: %w", errors.ErrUnsupported)
+	}
+
+	if c.config.RateLimiter != nil {
+		if err := c.config.RateLimiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	c.stats.apiCalls.Add(1)
+	err := pinger.Ping(ctx)
+	c.stats.recordPing(err)
+	return err
+}
+
+// HealthCheckJob returns a job pinging the backend, so Stats.Reachable
+// follows its reachability. Failed pings are reported to Config.OnJob.
+func HealthCheckJob(interval time.Duration) Job {
+	return Job{
+		Name:     "health check",
+		Interval: interval,
+		Run: func(ctx context.Context, client *Client) error {
+			return client.Ping(ctx)
+		},
+	}
+}
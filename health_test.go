@@ -0,0 +1,96 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// pingAdapter is a memory adapter whose Ping returns a settable error
+type pingAdapter struct {
+	*memoryAdapter
+	pingMu  sync.Mutex
+	pingErr error
+}
+
+func (a *pingAdapter) Ping(ctx context.Context) error {
+	a.pingMu.Lock()
+	defer a.pingMu.Unlock()
+	return a.pingErr
+}
+
+func (a *pingAdapter) setPingErr(err error) {
+	a.pingMu.Lock()
+	defer a.pingMu.Unlock()
+	a.pingErr = err
+}
+
+func TestClient_Ping(t *testing.T) {
+	ctx := context.Background()
+	adapter := &pingAdapter{memoryAdapter: newMemoryAdapter(nil)}
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+	defer client.Close()
+
+	if stats := client.Stats(); !stats.Reachable || !stats.LastPing.IsZero() {
+		t.Errorf("Stats() before Ping = %+v, want reachable and no ping", stats)
+	}
+
+	failure := errors.New("unreachable")
+	adapter.setPingErr(failure)
+	if err := client.Ping(ctx); err != failure {
+		t.Errorf("Ping() error = %v, want %v", err, failure)
+	}
+	stats := client.Stats()
+	if stats.Reachable || stats.LastPing.IsZero() || stats.LastPingError != failure {
+		t.Errorf("Stats() = %+v, want the failed ping recorded", stats)
+	}
+	if stats.APICalls != 1 {
+		t.Errorf("APICalls = %d, want 1 without retries", stats.APICalls)
+	}
+
+	adapter.setPingErr(nil)
+	if err := client.Ping(ctx); err != nil {
+		t.Errorf("Ping() error = %v", err)
+	}
+	if stats := client.Stats(); !stats.Reachable || stats.LastPingError != nil {
+		t.Errorf("Stats() = %+v, want reachable again", stats)
+	}
+
+	unsupported := sheetkv.New(newMemoryAdapter(nil), &sheetkv.Config{DisableAutoSync: true})
+	defer unsupported.Close()
+	if err := unsupported.Ping(ctx); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("Ping() error = %v, want errors.ErrUnsupported", err)
+	}
+}
+
+func TestHealthCheckJob(t *testing.T) {
+	adapter := &pingAdapter{memoryAdapter: newMemoryAdapter(nil), pingErr: errors.New("unreachable")}
+	results := make(chan sheetkv.JobResult, 10)
+	client := sheetkv.New(adapter, &sheetkv.Config{
+		DisableAutoSync: true,
+		Jobs:            []sheetkv.Job{sheetkv.HealthCheckJob(10 * time.Millisecond)},
+		OnJob: func(result sheetkv.JobResult) {
+			select {
+			case results <- result:
+			default:
+			}
+		},
+	})
+	defer client.Close()
+
+	select {
+	case result := <-results:
+		if result.Name != "health check" || result.Err == nil {
+			t.Errorf("JobResult = %+v, want the failed health check", result)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("health check did not run")
+	}
+	if client.Stats().Reachable {
+		t.Error("Stats().Reachable = true, want false after the failed health check")
+	}
+}
@@ -0,0 +1,195 @@
+package sheetkv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RecordVersion is a prior version of a record, captured before a change
+type RecordVersion struct {
+	Time   time.Time     // When the version was replaced
+	Op     OperationType // Operation that replaced it (OpUpdate or OpDelete)
+	Record *Record
+}
+
+// historySchema lists the columns of the history sheet
+var historySchema = []string{"time", "key", "op", "values"}
+
+// History returns the prior versions of a record, newest first. Versions are
+// served from memory when Config.HistoryLimit is set, otherwise they are read
+// from Config.HistoryAdapter. Restore a version with Set.
+func (c *Client) History(key int) ([]*RecordVersion, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, fmt.Errorf("client is closed")
+	}
+
+	if c.config.HistoryLimit > 0 {
+		c.historyMu.Lock()
+		defer c.historyMu.Unlock()
+
+		stored := c.history[key]
+		versions := make([]*RecordVersion, len(stored))
+		for i, v := range stored {
+			versions[len(stored)-1-i] = copyVersion(v)
+		}
+		return versions, nil
+	}
+
+	if c.config.HistoryAdapter != nil {
+		return c.loadHistory(context.Background(), key)
+	}
+
+	return nil, fmt.Errorf("history is not enabled")
+}
+
+// keepHistory stores the version replaced by a mutation
+func (c *Client) keepHistory(m mutation) {
+	if m.old == nil {
+		return // Nothing existed before an add
+	}
+	version := &RecordVersion{Time: m.time, Op: m.op, Record: m.old}
+
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+
+	if limit := c.config.HistoryLimit; limit > 0 {
+		if c.history == nil {
+			c.history = make(map[int][]*RecordVersion)
+		}
+		versions := append(c.history[m.key], version)
+		if len(versions) > limit {
+			versions = versions[len(versions)-limit:]
+		}
+		c.history[m.key] = versions
+	}
+
+	if c.config.HistoryAdapter != nil {
+		c.historyQueue = append(c.historyQueue, version)
+	}
+}
+
+// pendingHistory returns the number of versions waiting to be written
+func (c *Client) pendingHistory() int {
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+	return len(c.historyQueue)
+}
+
+// flushHistory writes the first n queued versions to the history adapter
+func (c *Client) flushHistory(ctx context.Context, n int) error {
+	if c.config.HistoryAdapter == nil || n == 0 {
+		return nil
+	}
+
+	c.historyMu.Lock()
+	versions := make([]*RecordVersion, n)
+	copy(versions, c.historyQueue[:n])
+	c.historyMu.Unlock()
+
+	records := make([]*Record, len(versions))
+	for i, v := range versions {
+		values, err := json.Marshal(v.Record.Values)
+		if err != nil {
+			return fmt.Errorf("failed to encode version of key %d: %w", v.Record.Key, err)
+		}
+		records[i] = &Record{Values: map[string]interface{}{
+			"time":   v.Time.Format(time.RFC3339Nano),
+			"key":    v.Record.Key,
+			"op":     v.Op.String(),
+			"values": string(values),
+		}}
+	}
+
+	err := c.withRetry(ctx, func() error {
+		return appendRecords(ctx, c.config.HistoryAdapter, records, historySchema)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+
+	c.historyMu.Lock()
+	c.historyQueue = c.historyQueue[n:]
+	c.historyMu.Unlock()
+	return nil
+}
+
+// loadHistory reads the versions of a record from the history adapter
+func (c *Client) loadHistory(ctx context.Context, key int) ([]*RecordVersion, error) {
+	var rows []*Record
+	err := c.withRetry(ctx, func() error {
+		var err error
+		rows, _, err = c.config.HistoryAdapter.Load(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load history: %w", err)
+	}
+
+	versions := make([]*RecordVersion, 0)
+	for _, row := range rows {
+		if fmt.Sprintf("%v", row.Values["key"]) != fmt.Sprintf("%d", key) {
+			continue
+		}
+
+		version := &RecordVersion{Record: &Record{Key: key, Values: make(map[string]interface{})}}
+		if s, ok := row.Values["values"].(string); ok {
+			values, err := decodeValues(s)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode version of key %d: %w", key, err)
+			}
+			version.Record.Values = values
+		}
+		if s, ok := row.Values["time"].(string); ok {
+			version.Time, _ = time.Parse(time.RFC3339Nano, s)
+		}
+		if row.Values["op"] == OpDelete.String() {
+			version.Op = OpDelete
+		} else {
+			version.Op = OpUpdate
+		}
+		versions = append(versions, version)
+	}
+
+	// Rows are appended in order, so reverse them to put the newest first
+	for i, j := 0, len(versions)-1; i < j; i, j = i+1, j-1 {
+		versions[i], versions[j] = versions[j], versions[i]
+	}
+	return versions, nil
+}
+
+// decodeValues parses JSON-encoded record values, restoring numbers as int64
+// or float64 like the adapters do
+func decodeValues(s string) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(strings.NewReader(s))
+	decoder.UseNumber()
+
+	var values map[string]interface{}
+	if err := decoder.Decode(&values); err != nil {
+		return nil, err
+	}
+	for col, v := range values {
+		if n, ok := v.(json.Number); ok {
+			if i, err := n.Int64(); err == nil {
+				values[col] = i
+			} else if f, err := n.Float64(); err == nil {
+				values[col] = f
+			}
+		}
+	}
+	return values, nil
+}
+
+// copyVersion returns a copy that callers may modify freely
+func copyVersion(v *RecordVersion) *RecordVersion {
+	return &RecordVersion{
+		Time:   v.Time,
+		Op:     v.Op,
+		Record: &Record{Key: v.Record.Key, Values: copyValues(v.Record.Values)},
+	}
+}
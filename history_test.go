@@ -0,0 +1,101 @@
+package sheetkv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_History(t *testing.T) {
+	setup := func(t *testing.T, config *sheetkv.Config) *sheetkv.Client {
+		data := newMemoryAdapter([]string{"name", "age"},
+			&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30)}},
+		)
+		client := sheetkv.New(data, config)
+		if err := client.Initialize(context.Background()); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		return client
+	}
+
+	t.Run("In memory, newest first and bounded", func(t *testing.T) {
+		client := setup(t, &sheetkv.Config{DisableAutoSync: true, HistoryLimit: 2})
+
+		client.Update(2, map[string]interface{}{"age": int64(31)})
+		client.Update(2, map[string]interface{}{"age": int64(32)})
+		client.Update(2, map[string]interface{}{"age": int64(32)}) // No change
+		client.Update(2, map[string]interface{}{"age": int64(33)})
+
+		versions, err := client.History(2)
+		if err != nil {
+			t.Fatalf("History() error = %v", err)
+		}
+		if len(versions) != 2 {
+			t.Fatalf("History() returned %d versions, want 2", len(versions))
+		}
+		if versions[0].Record.Values["age"] != int64(32) || versions[1].Record.Values["age"] != int64(31) {
+			t.Errorf("History() ages = %v, %v, want 32, 31", versions[0].Record.Values["age"], versions[1].Record.Values["age"])
+		}
+		if versions[0].Op != sheetkv.OpUpdate {
+			t.Errorf("Op = %v, want %v", versions[0].Op, sheetkv.OpUpdate)
+		}
+
+		// Reverting an overwrite
+		if err := client.Set(2, versions[1].Record); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		got, _ := client.Get(2)
+		if got.Values["age"] != int64(31) {
+			t.Errorf("age after revert = %v, want 31", got.Values["age"])
+		}
+	})
+
+	t.Run("Deleted records keep their last version", func(t *testing.T) {
+		client := setup(t, &sheetkv.Config{DisableAutoSync: true, HistoryLimit: 5})
+		client.Delete(2)
+
+		versions, _ := client.History(2)
+		if len(versions) != 1 || versions[0].Op != sheetkv.OpDelete || versions[0].Record.Values["name"] != "John" {
+			t.Errorf("History() = %+v, want the deleted version", versions)
+		}
+	})
+
+	t.Run("History tab", func(t *testing.T) {
+		history := newMemoryAdapter(nil)
+		client := setup(t, &sheetkv.Config{DisableAutoSync: true, HistoryAdapter: history})
+
+		client.Update(2, map[string]interface{}{"age": int64(31)})
+		client.Update(2, map[string]interface{}{"name": "Johnny"})
+		if len(history.records) != 0 {
+			t.Fatalf("history rows before sync = %d, want 0", len(history.records))
+		}
+		if err := client.Sync(); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+		if len(history.records) != 2 {
+			t.Fatalf("history rows = %d, want 2", len(history.records))
+		}
+
+		versions, err := client.History(2)
+		if err != nil {
+			t.Fatalf("History() error = %v", err)
+		}
+		if len(versions) != 2 {
+			t.Fatalf("History() returned %d versions, want 2", len(versions))
+		}
+		if versions[0].Record.Values["name"] != "John" || versions[0].Record.Values["age"] != int64(31) {
+			t.Errorf("newest version = %v, want John/31", versions[0].Record.Values)
+		}
+		if versions[1].Record.Values["age"] != int64(30) {
+			t.Errorf("oldest version age = %v, want 30", versions[1].Record.Values["age"])
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		client := setup(t, &sheetkv.Config{DisableAutoSync: true})
+		if _, err := client.History(2); err == nil {
+			t.Error("History() should fail when history is not enabled")
+		}
+	})
+}
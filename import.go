@@ -0,0 +1,148 @@
+package sheetkv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultImportBatchSize is the number of records imported per sync
+const DefaultImportBatchSize = 500
+
+// RecordSource yields the records to import. Next returns io.EOF once
+// every record has been read.
+type RecordSource interface {
+	Next(ctx context.Context) (*Record, error)
+}
+
+// sliceSource yields the records of a slice
+type sliceSource struct {
+	records []*Record
+}
+
+func (s *sliceSource) Next(ctx context.Context) (*Record, error) {
+	if len(s.records) == 0 {
+		return nil, io.EOF
+	}
+	record := s.records[0]
+	s.records = s.records[1:]
+	return record, nil
+}
+
+// SliceSource returns a source yielding records in order
+func SliceSource(records []*Record) RecordSource {
+	return &sliceSource{records: records}
+}
+
+// ImportProgress reports how far an import has gone
+type ImportProgress struct {
+	Records int // Records imported so far
+	Batches int // Batches saved so far
+}
+
+// ImportOptions configures Client.Import
+type ImportOptions struct {
+	BatchSize  int                  // Records per batch, 0 means DefaultImportBatchSize
+	RateLimit  float64              // Batches per second, 0 means no limit besides Config.RateLimiter
+	OnProgress func(ImportProgress) // Called after each batch is saved, may be nil
+}
+
+// Import appends the records of src in batches, saving each batch before
+// reading the next, so that only one batch is held beside the cache and the
+// backend sees one save per batch. Each batch is checked and appended as one
+// unit like Apply. Records get their keys like Append, and do not roll over
+// on Config.CellLimit. Config.OnProgress sees the whole import as
+// PhaseImport, with a PhaseSave for each batch.
+//
+// Import returns the number of records appended. When saving a batch fails
+// its records stay in the cache, like other unsaved changes, and are
+// counted, so the import can resume by skipping that many records.
+func (c *Client) Import(ctx context.Context, src RecordSource, opts ImportOptions) (int, error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultImportBatchSize
+	}
+	var limiter *RateLimiter
+	if opts.RateLimit > 0 {
+		limiter = NewRateLimiter(opts.RateLimit, 1)
+	}
+
+	tracker := c.trackProgress(PhaseImport, 0)
+	defer tracker.finish()
+
+	progress := ImportProgress{}
+	for done := false; !done; {
+		ops := make([]Operation, 0, batchSize)
+		for len(ops) < batchSize {
+			record, err := src.Next(ctx)
+			if errors.Is(err, io.EOF) {
+				done = true
+				break
+			}
+			if err != nil {
+				return progress.Records, fmt.Errorf("failed to read record %d: %w", progress.Records+len(ops), err)
+			}
+			ops = append(ops, Operation{Type: OpAdd, Record: record})
+		}
+		if len(ops) == 0 {
+			break
+		}
+
+		if limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				return progress.Records, err
+			}
+		}
+		appended, err := c.importBatch(ctx, ops)
+		if appended {
+			progress.Records += len(ops)
+			if tracker != nil {
+				records := make([]*Record, len(ops))
+				for i, op := range ops {
+					records[i] = op.Record
+				}
+				tracker.add(records)
+			}
+		}
+		if err != nil {
+			return progress.Records, fmt.Errorf("batch %d: %w", progress.Batches+1, err)
+		}
+
+		progress.Batches++
+		if opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+	}
+	return progress.Records, nil
+}
+
+// importBatch appends and saves one batch of Import, reporting whether the
+// records were appended
+func (c *Client) importBatch(ctx context.Context, ops []Operation) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return false, fmt.Errorf("client is closed")
+	}
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	if err := c.readThroughTable(ctx); err != nil {
+		return false, err
+	}
+	if err := c.checkOperations(ops); err != nil {
+		return false, err
+	}
+	for _, op := range ops {
+		if err := c.appendRecord(op.Record); err != nil {
+			return false, errors.Join(err, c.writeThrough(ctx))
+		}
+	}
+	if err := c.writeThrough(ctx); err != nil {
+		return false, err
+	}
+	return true, c.saveToAdapter(ctx, SyncStrategyGapPreserving)
+}
@@ -0,0 +1,96 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func importRecords(n int) []*sheetkv.Record {
+	records := make([]*sheetkv.Record, n)
+	for i := range records {
+		records[i] = &sheetkv.Record{Values: map[string]interface{}{"name": fmt.Sprintf("user%d", i)}}
+	}
+	return records
+}
+
+func TestClient_Import(t *testing.T) {
+	data := newMemoryAdapter([]string{"name"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John"}},
+	)
+	client := sheetkv.New(data, &sheetkv.Config{DisableAutoSync: true})
+	defer client.Close()
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	var progress []sheetkv.ImportProgress
+	n, err := client.Import(context.Background(), sheetkv.SliceSource(importRecords(25)), sheetkv.ImportOptions{
+		BatchSize:  10,
+		OnProgress: func(p sheetkv.ImportProgress) { progress = append(progress, p) },
+	})
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if n != 25 {
+		t.Errorf("imported = %d, want 25", n)
+	}
+
+	want := []sheetkv.ImportProgress{{Records: 10, Batches: 1}, {Records: 20, Batches: 2}, {Records: 25, Batches: 3}}
+	if fmt.Sprint(progress) != fmt.Sprint(want) {
+		t.Errorf("progress = %v, want %v", progress, want)
+	}
+	if saves := data.saveCount(); saves != 3 {
+		t.Errorf("saves = %d, want 3", saves)
+	}
+	if len(data.records) != 26 {
+		t.Errorf("saved records = %d, want 26", len(data.records))
+	}
+	if record, err := client.Get(27); err != nil || record.Values["name"] != "user24" {
+		t.Errorf("Get(27) = %v, %v", record, err)
+	}
+}
+
+func TestClient_ImportErrors(t *testing.T) {
+	data := newMemoryAdapter([]string{"name"})
+	client := sheetkv.New(data, &sheetkv.Config{DisableAutoSync: true, StrictSchema: true})
+	defer client.Close()
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	// A batch failing its checks is not appended
+	records := importRecords(5)
+	records[3].Values["email"] = "user3@example.com"
+	n, err := client.Import(context.Background(), sheetkv.SliceSource(records), sheetkv.ImportOptions{BatchSize: 2})
+	if err == nil {
+		t.Fatal("Import() should fail on the invalid record")
+	}
+	if n != 2 {
+		t.Errorf("imported = %d, want 2", n)
+	}
+	if all, _ := client.Query(sheetkv.Query{}); len(all) != 2 {
+		t.Errorf("records = %d, want 2", len(all))
+	}
+
+	// A batch failing to save stays in the cache and is counted
+	data.mu.Lock()
+	data.saveErr = errors.New("quota exceeded")
+	data.mu.Unlock()
+	n, err = client.Import(context.Background(), sheetkv.SliceSource(importRecords(3)), sheetkv.ImportOptions{BatchSize: 2})
+	if err == nil {
+		t.Fatal("Import() should fail when saving fails")
+	}
+	if n != 2 {
+		t.Errorf("imported = %d, want 2", n)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Import(ctx, sheetkv.SliceSource(importRecords(1)), sheetkv.ImportOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Import() error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,225 @@
+package sheetkv
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Index is a serializable snapshot of the secondary index, mapping the values
+// of indexed columns to the keys (row numbers) holding them
+type Index struct {
+	Columns []string     // Indexed columns
+	Entries []IndexEntry // Sorted by column, then value
+}
+
+// IndexEntry lists the keys holding one value of an indexed column
+type IndexEntry struct {
+	Column string
+	Value  string // Normalized value, see indexValueKeys
+	Keys   []int
+}
+
+// Keys returns the keys whose column may equal value, or nil when the column
+// is not indexed
+func (idx *Index) Keys(column string, value interface{}) []int {
+	if idx == nil || !containsString(idx.Columns, column) {
+		return nil
+	}
+
+	wanted := make(map[string]bool)
+	for _, k := range indexValueKeys(value) {
+		wanted[k] = true
+	}
+
+	seen := make(map[int]bool)
+	keys := make([]int, 0)
+	for _, entry := range idx.Entries {
+		if entry.Column != column || !wanted[entry.Value] {
+			continue
+		}
+		for _, key := range entry.Keys {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// valueIndex is the in-memory secondary index maintained by Cache
+type valueIndex struct {
+	columns   map[string]map[string]map[int]struct{} // column -> value key -> keys
+	normalize bool                                   // Keys are NFC-normalized, see Cache.SetUnicodeNormalization
+}
+
+func newValueIndex(columns []string) *valueIndex {
+	idx := &valueIndex{columns: make(map[string]map[string]map[int]struct{})}
+	for _, col := range columns {
+		idx.columns[col] = make(map[string]map[int]struct{})
+	}
+	return idx
+}
+
+// add indexes the values of a record
+func (idx *valueIndex) add(record *Record) {
+	for col, values := range idx.columns {
+		v, ok := record.Values[col]
+		if !ok || v == nil {
+			continue
+		}
+		for _, k := range idx.valueKeys(v) {
+			keys, exists := values[k]
+			if !exists {
+				keys = make(map[int]struct{})
+				values[k] = keys
+			}
+			keys[record.Key] = struct{}{}
+		}
+	}
+}
+
+// remove drops the values of a record from the index
+func (idx *valueIndex) remove(record *Record) {
+	for col, values := range idx.columns {
+		v, ok := record.Values[col]
+		if !ok || v == nil {
+			continue
+		}
+		for _, k := range idx.valueKeys(v) {
+			if keys, exists := values[k]; exists {
+				delete(keys, record.Key)
+				if len(keys) == 0 {
+					delete(values, k)
+				}
+			}
+		}
+	}
+}
+
+// lookup returns the keys that may satisfy an equality or "in" condition.
+// ok is false when the condition cannot be answered from the index.
+func (idx *valueIndex) lookup(cond Condition) (map[int]struct{}, bool) {
+	values, indexed := idx.columns[cond.Column]
+	if !indexed {
+		return nil, false
+	}
+
+	var wanted []interface{}
+	switch cond.Operator {
+	case "==":
+		wanted = []interface{}{cond.Value}
+	case "in":
+		list, ok := cond.Value.([]interface{})
+		if !ok {
+			return nil, false
+		}
+		wanted = list
+	default:
+		return nil, false
+	}
+
+	result := make(map[int]struct{})
+	for _, w := range wanted {
+		// nil matches records without the column, which are not indexed
+		if w == nil {
+			return nil, false
+		}
+		for _, k := range idx.valueKeys(w) {
+			for key := range values[k] {
+				result[key] = struct{}{}
+			}
+		}
+	}
+	return result, true
+}
+
+// valueKeys returns the keys of v in the index
+func (idx *valueIndex) valueKeys(v interface{}) []string {
+	if idx.normalize {
+		v = normalizeValue(v)
+	}
+	return indexValueKeys(v)
+}
+
+// candidates picks the most selective indexed condition of the query and
+// returns its candidate keys and column
+func (idx *valueIndex) candidates(conditions []Condition) (map[int]struct{}, string, bool) {
+	var best map[int]struct{}
+	bestColumn := ""
+	found := false
+
+	for _, cond := range conditions {
+		keys, ok := idx.lookup(cond)
+		if !ok {
+			continue
+		}
+		if !found || len(keys) < len(best) {
+			best, bestColumn, found = keys, cond.Column, true
+		}
+	}
+	return best, bestColumn, found
+}
+
+// snapshot converts the index to its serializable form
+func (idx *valueIndex) snapshot() *Index {
+	index := &Index{Columns: make([]string, 0, len(idx.columns))}
+	for col := range idx.columns {
+		index.Columns = append(index.Columns, col)
+	}
+	sort.Strings(index.Columns)
+
+	for _, col := range index.Columns {
+		values := idx.columns[col]
+		valueKeys := make([]string, 0, len(values))
+		for v := range values {
+			valueKeys = append(valueKeys, v)
+		}
+		sort.Strings(valueKeys)
+
+		for _, v := range valueKeys {
+			keys := make([]int, 0, len(values[v]))
+			for key := range values[v] {
+				keys = append(keys, key)
+			}
+			sort.Ints(keys)
+			index.Entries = append(index.Entries, IndexEntry{Column: col, Value: v, Keys: keys})
+		}
+	}
+	return index
+}
+
+// indexValueKeys returns the normalized representations of a value. Two values
+// considered equal by compareEqual always share at least one representation:
+// numbers compare as float64 and everything else by its %v form.
+func indexValueKeys(v interface{}) []string {
+	s := fmt.Sprintf("%v", v)
+	if !isNumeric(v) {
+		return []string{s}
+	}
+
+	keys := []string{s}
+	f := toFloat64(v)
+	if g := strconv.FormatFloat(f, 'g', -1, 64); g != s {
+		keys = append(keys, g)
+	}
+	if f == math.Trunc(f) && math.Abs(f) < math.MaxInt64 {
+		if i := strconv.FormatInt(int64(f), 10); i != s && i != keys[len(keys)-1] {
+			keys = append(keys, i)
+		}
+	}
+	return keys
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
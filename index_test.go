@@ -0,0 +1,128 @@
+package sheetkv_test
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestCache_IndexedQuery(t *testing.T) {
+	records := []*sheetkv.Record{
+		{Key: 2, Values: map[string]interface{}{"dept": "Sales", "code": int64(100)}},
+		{Key: 3, Values: map[string]interface{}{"dept": "Eng", "code": 100.0}},
+		{Key: 4, Values: map[string]interface{}{"dept": "Sales", "code": "100"}},
+		{Key: 5, Values: map[string]interface{}{"dept": "HR"}},
+	}
+
+	plain := sheetkv.NewCache()
+	plain.Load(records, []string{"dept", "code"})
+
+	indexed := sheetkv.NewCache()
+	indexed.SetIndexColumns([]string{"dept", "code"})
+	indexed.Load(records, []string{"dept", "code"})
+
+	queries := []sheetkv.Query{
+		{Conditions: []sheetkv.Condition{{Column: "dept", Operator: "==", Value: "Sales"}}},
+		{Conditions: []sheetkv.Condition{{Column: "code", Operator: "==", Value: 100}}},
+		{Conditions: []sheetkv.Condition{{Column: "code", Operator: "==", Value: "100"}}},
+		{Conditions: []sheetkv.Condition{{Column: "dept", Operator: "in", Value: []interface{}{"Eng", "HR"}}}},
+		{Conditions: []sheetkv.Condition{{Column: "code", Operator: "==", Value: nil}}},
+		{Conditions: []sheetkv.Condition{
+			{Column: "dept", Operator: "==", Value: "Sales"},
+			{Column: "code", Operator: "==", Value: 100.0},
+		}},
+	}
+
+	for _, query := range queries {
+		want, _ := plain.Query(query)
+		got, err := indexed.Query(query)
+		if err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+		if !reflect.DeepEqual(sortedKeys(got), sortedKeys(want)) {
+			t.Errorf("Query(%v) keys = %v, want %v", query.Conditions, sortedKeys(got), sortedKeys(want))
+		}
+	}
+
+	t.Run("Index follows writes", func(t *testing.T) {
+		indexed.Update(2, map[string]interface{}{"dept": "Eng"})
+		indexed.Delete(4)
+		indexed.Set(6, &sheetkv.Record{Values: map[string]interface{}{"dept": "Sales"}})
+
+		got, _ := indexed.Query(sheetkv.Query{Conditions: []sheetkv.Condition{{Column: "dept", Operator: "==", Value: "Sales"}}})
+		if keys := sortedKeys(got); !reflect.DeepEqual(keys, []int{6}) {
+			t.Errorf("Sales keys = %v, want [6]", keys)
+		}
+
+		index := indexed.Index()
+		if got := index.Keys("dept", "Eng"); !reflect.DeepEqual(got, []int{2, 3}) {
+			t.Errorf("Index().Keys(dept, Eng) = %v, want [2 3]", got)
+		}
+		if got := index.Keys("name", "x"); got != nil {
+			t.Errorf("Index().Keys() on unindexed column = %v, want nil", got)
+		}
+	})
+}
+
+func TestClient_Lookup(t *testing.T) {
+	source := &indexedAdapter{memoryAdapter: newMemoryAdapter([]string{"email", "name"})}
+	writer := sheetkv.New(source, &sheetkv.Config{
+		DisableAutoSync: true,
+		IndexColumns:    []string{"email"},
+		PersistIndex:    true,
+	})
+	if err := writer.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		writer.Append(&sheetkv.Record{Values: map[string]interface{}{"email": email, "name": email[:1]}})
+	}
+	if err := writer.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if source.indexSave != 1 {
+		t.Fatalf("index saved %d times, want 1", source.indexSave)
+	}
+
+	t.Run("Fresh client reads only matching rows", func(t *testing.T) {
+		reader := sheetkv.New(source, &sheetkv.Config{DisableAutoSync: true, IndexColumns: []string{"email"}})
+		loads := source.loads
+
+		got, err := reader.Lookup(context.Background(), "email", "b@example.com")
+		if err != nil {
+			t.Fatalf("Lookup() error = %v", err)
+		}
+		if len(got) != 1 || got[0].Key != 3 || got[0].Values["name"] != "b" {
+			t.Errorf("Lookup() = %v, want row 3", got)
+		}
+		if source.loads != loads {
+			t.Error("Lookup() should not load the whole sheet")
+		}
+		if source.rowLoads != 1 {
+			t.Errorf("rows loaded %d times, want 1", source.rowLoads)
+		}
+	})
+
+	t.Run("Unindexed column falls back to a full load", func(t *testing.T) {
+		reader := sheetkv.New(source, &sheetkv.Config{DisableAutoSync: true})
+		got, err := reader.Lookup(context.Background(), "name", "c")
+		if err != nil {
+			t.Fatalf("Lookup() error = %v", err)
+		}
+		if len(got) != 1 || got[0].Key != 4 {
+			t.Errorf("Lookup() = %v, want row 4", got)
+		}
+	})
+}
+
+func sortedKeys(records []*sheetkv.Record) []int {
+	keys := make([]int, 0, len(records))
+	for _, r := range records {
+		keys = append(keys, r.Key)
+	}
+	sort.Ints(keys)
+	return keys
+}
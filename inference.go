@@ -0,0 +1,224 @@
+package sheetkv
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TypeInference controls how adapters turn the text of cells into numbers
+// and booleans. The zero value infers the type of every cell.
+type TypeInference struct {
+	TextColumns  []string // Columns always loaded as strings, such as postal codes or phone numbers
+	Conservative bool     // Only infer values that are written back as the same text, keeping "00123" or "+8190" as strings
+}
+
+// Infers reports whether the text s of column may be loaded as a number or
+// a boolean
+func (t TypeInference) Infers(column, s string) bool {
+	if containsString(t.TextColumns, column) {
+		return false
+	}
+	return !t.Conservative || canonicalText(s)
+}
+
+// canonicalText reports whether s is the text its inferred value is written
+// as: the shortest form of a number, or TRUE and FALSE. Other text is not
+// inferred, so it is canonical too.
+func canonicalText(s string) bool {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return strconv.FormatInt(i, 10) == s
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return strconv.FormatFloat(f, 'g', -1, 64) == s
+	}
+	switch s {
+	case "true", "false":
+		return false
+	}
+	return true
+}
+
+// ValueType is the type of a cell value reported by InferTypes
+type ValueType int
+
+const (
+	TypeString ValueType = iota
+	TypeInteger
+	TypeFloat
+	TypeBoolean
+	TypeTime // Strings in Config.TimeFormat
+)
+
+// String returns the name of the type
+func (t ValueType) String() string {
+	switch t {
+	case TypeString:
+		return "string"
+	case TypeInteger:
+		return "integer"
+	case TypeFloat:
+		return "float"
+	case TypeBoolean:
+		return "boolean"
+	case TypeTime:
+		return "time"
+	default:
+		return fmt.Sprintf("ValueType(%d)", int(t))
+	}
+}
+
+// ColumnTypes reports the types of the values of one column, see InferTypes
+type ColumnTypes struct {
+	Column    string
+	Type      ValueType         // Dominant type of the non-empty values
+	Counts    map[ValueType]int // Non-empty values per type
+	Empty     int               // Records without a value in the column
+	Conflicts []TypeConflict    // Values of another type than Type, by key
+	Rule      *ColumnRule       // Suggested validation rule enforcing Type, nil when none applies
+}
+
+// TypeConflict is a value whose type differs from the dominant type of its
+// column, such as "N/A" in a numeric column
+type TypeConflict struct {
+	Key   int
+	Value interface{}
+	Type  ValueType
+}
+
+// InferTypes scans the values of every column of the schema and reports
+// their dominant type, the cells that do not match it and a suggested
+// ColumnRule, as a first step before enforcing types on a messy sheet.
+// Numeric and boolean text counts as numbers and booleans, and integers do
+// not conflict in a float column. The suggested rules of numeric columns
+// bound the values seen; those of boolean columns set Boolean. Fix or
+// clear the conflicts before adding the rules to Config.ValidationRules.
+func (c *Client) InferTypes() ([]ColumnTypes, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, fmt.Errorf("client is closed")
+	}
+	if err := c.readThroughTable(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return c.inferTypes(c.sortedRecords(), c.cache.GetSchema()), nil
+}
+
+// sortedRecords returns the cached records by key
+func (c *Client) sortedRecords() []*Record {
+	records := c.cache.GetAllRecords()
+	sort.Slice(records, func(i, j int) bool { return records[i].Key < records[j].Key })
+	return records
+}
+
+// inferTypes reports the types of the columns of schema in records, sorted
+// by key
+func (c *Client) inferTypes(records []*Record, schema []string) []ColumnTypes {
+	layout := c.timeFormat()
+	reports := make([]ColumnTypes, len(schema))
+	for i, col := range schema {
+		report := ColumnTypes{Column: col, Counts: make(map[ValueType]int)}
+		types := make([]ValueType, len(records))
+		for j, record := range records {
+			value, ok := record.Values[col]
+			if !ok || value == nil || value == "" {
+				report.Empty++
+				types[j] = -1
+				continue
+			}
+			types[j] = valueType(value, layout)
+			report.Counts[types[j]]++
+		}
+
+		report.Type = dominantType(report.Counts)
+		var min, max float64
+		numbers := 0
+		for j, record := range records {
+			t := types[j]
+			switch {
+			case t < 0:
+			case t == report.Type, t == TypeInteger && report.Type == TypeFloat:
+				if f, ok := ruleNumber(record.Values[col]); ok {
+					if numbers == 0 || f < min {
+						min = f
+					}
+					if numbers == 0 || f > max {
+						max = f
+					}
+					numbers++
+				}
+			default:
+				report.Conflicts = append(report.Conflicts, TypeConflict{Key: record.Key, Value: record.Values[col], Type: t})
+			}
+		}
+
+		switch {
+		case len(report.Counts) == 0:
+		case report.Type == TypeBoolean:
+			report.Rule = &ColumnRule{Column: col, Boolean: true}
+		case (report.Type == TypeInteger || report.Type == TypeFloat) && numbers > 0:
+			report.Rule = &ColumnRule{Column: col, Min: &min, Max: &max}
+		}
+		reports[i] = report
+	}
+	return reports
+}
+
+// valueType returns the type of a non-empty value
+func valueType(value interface{}, layout string) ValueType {
+	switch v := value.(type) {
+	case bool:
+		return TypeBoolean
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return TypeInteger
+	case float32, float64:
+		if f, _ := ruleNumber(v); f == float64(int64(f)) {
+			return TypeInteger
+		}
+		return TypeFloat
+	case time.Time:
+		return TypeTime
+	case string:
+		s := strings.TrimSpace(v)
+		if lower := strings.ToLower(s); lower == "true" || lower == "false" {
+			return TypeBoolean
+		}
+		if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return TypeInteger
+		}
+		if _, err := strconv.ParseFloat(s, 64); err == nil {
+			return TypeFloat
+		}
+		if _, err := time.Parse(layout, s); err == nil {
+			return TypeTime
+		}
+	}
+	return TypeString
+}
+
+// dominantType returns the most frequent type, numbers counting together
+// as floats when both integers and floats are present
+func dominantType(counts map[ValueType]int) ValueType {
+	weights := make(map[ValueType]int, len(counts))
+	for t, n := range counts {
+		weights[t] = n
+	}
+	if counts[TypeInteger] > 0 && counts[TypeFloat] > 0 {
+		weights[TypeFloat] += weights[TypeInteger]
+		delete(weights, TypeInteger)
+	}
+
+	dominant, most := TypeString, 0
+	for _, t := range []ValueType{TypeString, TypeInteger, TypeFloat, TypeBoolean, TypeTime} {
+		if weights[t] > most {
+			dominant, most = t, weights[t]
+		}
+	}
+	return dominant
+}
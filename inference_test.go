@@ -0,0 +1,86 @@
+package sheetkv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestTypeInference_Infers(t *testing.T) {
+	tests := []struct {
+		name      string
+		inference sheetkv.TypeInference
+		column, s string
+		want      bool
+	}{
+		{"default", sheetkv.TypeInference{}, "zip", "00123", true},
+		{"text column", sheetkv.TypeInference{TextColumns: []string{"zip"}}, "zip", "123", false},
+		{"other column", sheetkv.TypeInference{TextColumns: []string{"zip"}}, "age", "42", true},
+		{"conservative integer", sheetkv.TypeInference{Conservative: true}, "n", "42", true},
+		{"conservative negative", sheetkv.TypeInference{Conservative: true}, "n", "-7", true},
+		{"conservative float", sheetkv.TypeInference{Conservative: true}, "n", "1.5", true},
+		{"leading zeros", sheetkv.TypeInference{Conservative: true}, "n", "00123", false},
+		{"plus sign", sheetkv.TypeInference{Conservative: true}, "n", "+819012345678", false},
+		{"trailing zero", sheetkv.TypeInference{Conservative: true}, "n", "1.50", false},
+		{"exponent", sheetkv.TypeInference{Conservative: true}, "n", "1e3", false},
+		{"upper-case boolean", sheetkv.TypeInference{Conservative: true}, "n", "TRUE", true},
+		{"lower-case boolean", sheetkv.TypeInference{Conservative: true}, "n", "true", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.inference.Infers(tt.column, tt.s); got != tt.want {
+				t.Errorf("Infers(%q, %q) = %v, want %v", tt.column, tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_InferTypes(t *testing.T) {
+	ctx := context.Background()
+	adapter := newMemoryAdapter([]string{"age", "score", "active", "joined", "name"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"age": int64(30), "score": 1.5, "active": true, "joined": "2024-06-01T09:00:00Z", "name": "a"}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"age": "N/A", "score": int64(2), "active": "FALSE", "joined": "2024-06-02T09:00:00Z", "name": "b"}},
+		&sheetkv.Record{Key: 4, Values: map[string]interface{}{"age": int64(45), "score": "", "active": false, "name": "c"}},
+	)
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	defer client.Close()
+
+	reports, err := client.InferTypes()
+	if err != nil {
+		t.Fatalf("InferTypes() error = %v", err)
+	}
+	byColumn := make(map[string]sheetkv.ColumnTypes)
+	for _, report := range reports {
+		byColumn[report.Column] = report
+	}
+
+	age := byColumn["age"]
+	if age.Type != sheetkv.TypeInteger || len(age.Conflicts) != 1 || age.Conflicts[0].Key != 3 || age.Conflicts[0].Type != sheetkv.TypeString {
+		t.Errorf("age = %+v, want integers with N/A conflicting", age)
+	}
+	if age.Rule == nil || *age.Rule.Min != 30 || *age.Rule.Max != 45 {
+		t.Errorf("age rule = %+v, want the range 30 to 45", age.Rule)
+	}
+
+	score := byColumn["score"]
+	if score.Type != sheetkv.TypeFloat || len(score.Conflicts) != 0 || score.Empty != 1 {
+		t.Errorf("score = %+v, want floats without conflicts and one empty cell", score)
+	}
+
+	want := map[string]sheetkv.ValueType{"active": sheetkv.TypeBoolean, "joined": sheetkv.TypeTime, "name": sheetkv.TypeString}
+	for col, typ := range want {
+		if got := byColumn[col]; got.Type != typ || len(got.Conflicts) != 0 {
+			t.Errorf("%s = %+v, want %s", col, got, typ)
+		}
+	}
+	if rule := byColumn["active"].Rule; rule == nil || !rule.Boolean {
+		t.Errorf("active rule = %+v, want Boolean", rule)
+	}
+	if rule := byColumn["name"].Rule; rule != nil {
+		t.Errorf("name rule = %+v, want none", rule)
+	}
+}
@@ -0,0 +1,133 @@
+package sheetkv
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// KeyedClient addresses records by the value of a key column instead of
+// their row numbers. Row numbers are still available in Record.Key but are
+// handled internally.
+type KeyedClient struct {
+	client *Client
+	column string
+	mu     sync.Mutex // Serializes writes so concurrent Sets of a new key add one row
+}
+
+// NewKeyed creates a KeyedClient using column as the primary key
+func NewKeyed(client *Client, column string) *KeyedClient {
+	return &KeyedClient{client: client, column: column}
+}
+
+// Client returns the underlying row-keyed client
+func (k *KeyedClient) Client() *Client {
+	return k.client
+}
+
+// Column returns the name of the key column
+func (k *KeyedClient) Column() string {
+	return k.column
+}
+
+// Get retrieves the record whose key column equals key
+func (k *KeyedClient) Get(key string) (*Record, error) {
+	return k.find(key)
+}
+
+// Set stores the record under key, appending a row when the key is new.
+// The key column of the stored record is set to key.
+func (k *KeyedClient) Set(key string, record *Record) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	values := copyValues(record.Values)
+	values[k.column] = key
+
+	existing, err := k.find(key)
+	switch {
+	case err == nil:
+		return k.client.Set(existing.Key, &Record{Values: values})
+	case err == ErrKeyNotFound:
+		return k.client.Append(&Record{Values: values})
+	default:
+		return err
+	}
+}
+
+// Update partially updates the record stored under key. The key column
+// itself cannot be changed.
+func (k *KeyedClient) Update(key string, updates map[string]interface{}) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if value, ok := updates[k.column]; ok && fmt.Sprintf("%v", value) != key {
+		return fmt.Errorf("cannot change key column %q", k.column)
+	}
+
+	existing, err := k.find(key)
+	if err != nil {
+		return err
+	}
+	return k.client.Update(existing.Key, updates)
+}
+
+// Delete removes the record stored under key
+func (k *KeyedClient) Delete(key string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	existing, err := k.find(key)
+	if err != nil {
+		return err
+	}
+	return k.client.Delete(existing.Key)
+}
+
+// Query searches for records matching the given conditions
+func (k *KeyedClient) Query(query Query) ([]*Record, error) {
+	return k.client.Query(query)
+}
+
+// Keys returns the keys of all records in ascending order.
+// Rows with an empty key column are skipped.
+func (k *KeyedClient) Keys() ([]string, error) {
+	records, err := k.client.Query(Query{
+		Conditions: []Condition{{Column: k.column, Operator: "!=", Value: nil}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(records))
+	for _, record := range records {
+		if key := fmt.Sprintf("%v", record.Values[k.column]); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// find returns the only record whose key column equals key
+func (k *KeyedClient) find(key string) (*Record, error) {
+	if key == "" {
+		return nil, fmt.Errorf("key must not be empty")
+	}
+
+	records, err := k.client.Query(Query{
+		Conditions: []Condition{{Column: k.column, Operator: "==", Value: key}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(records) {
+	case 0:
+		return nil, ErrKeyNotFound
+	case 1:
+		return records[0], nil
+	default:
+		return nil, fmt.Errorf("%w: %d rows have %s %q", ErrDuplicateKey, len(records), k.column, key)
+	}
+}
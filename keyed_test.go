@@ -0,0 +1,92 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestKeyedClient(t *testing.T) {
+	adapter := newMemoryAdapter([]string{"id", "name"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"id": "u1", "name": "John"}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"id": "u2", "name": "Jane"}},
+	)
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	users := sheetkv.NewKeyed(client, "id")
+
+	t.Run("Get", func(t *testing.T) {
+		record, err := users.Get("u2")
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if record.Values["name"] != "Jane" || record.Key != 3 {
+			t.Errorf("Get(u2) = %+v, want Jane at row 3", record)
+		}
+		if _, err := users.Get("u9"); !errors.Is(err, sheetkv.ErrKeyNotFound) {
+			t.Errorf("Get(u9) error = %v, want %v", err, sheetkv.ErrKeyNotFound)
+		}
+	})
+
+	t.Run("Set updates an existing key", func(t *testing.T) {
+		if err := users.Set("u1", &sheetkv.Record{Values: map[string]interface{}{"name": "Johnny"}}); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		record, _ := client.Get(2)
+		if record.Values["name"] != "Johnny" || record.Values["id"] != "u1" {
+			t.Errorf("row 2 = %v, want Johnny with id u1", record.Values)
+		}
+	})
+
+	t.Run("Set appends a new key", func(t *testing.T) {
+		if err := users.Set("u3", &sheetkv.Record{Values: map[string]interface{}{"name": "Bob"}}); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		record, err := users.Get("u3")
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if record.Key != 4 {
+			t.Errorf("u3 stored at row %d, want 4", record.Key)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		if err := users.Update("u2", map[string]interface{}{"name": "Janet"}); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if record, _ := users.Get("u2"); record.Values["name"] != "Janet" {
+			t.Errorf("name = %v, want Janet", record.Values["name"])
+		}
+		if err := users.Update("u2", map[string]interface{}{"id": "u5"}); err == nil {
+			t.Error("Update() should not change the key column")
+		}
+	})
+
+	t.Run("Keys and Delete", func(t *testing.T) {
+		if err := users.Delete("u1"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		keys, err := users.Keys()
+		if err != nil {
+			t.Fatalf("Keys() error = %v", err)
+		}
+		if want := []string{"u2", "u3"}; !reflect.DeepEqual(keys, want) {
+			t.Errorf("Keys() = %v, want %v", keys, want)
+		}
+	})
+
+	t.Run("Duplicate keys", func(t *testing.T) {
+		if err := client.Append(&sheetkv.Record{Values: map[string]interface{}{"id": "u2"}}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+		if _, err := users.Get("u2"); !errors.Is(err, sheetkv.ErrDuplicateKey) {
+			t.Errorf("Get() error = %v, want %v", err, sheetkv.ErrDuplicateKey)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package sheetkv
+
+import "context"
+
+// IsLeader reports whether this client holds the lease of the sheet and
+// runs its background syncs, see Config.ElectLeader
+func (c *Client) IsLeader() bool {
+	return c.leader.Load()
+}
+
+// electedSync runs a background sync cycle with Config.ElectLeader: the
+// client holding the lease saves its changes, the others reload the sheet,
+// keeping their own changes unsaved
+func (c *Client) electedSync(ctx context.Context) {
+	if c.campaign(ctx) {
+		if c.cache.HasChanges() {
+			_ = c.saveToAdapter(ctx, SyncStrategyGapPreserving)
+		}
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		_ = c.reload(ctx)
+	}
+}
+
+// campaign takes or renews the lease and reports whether this client leads.
+// Clients whose adapter is no LeaseStore never lead.
+func (c *Client) campaign(ctx context.Context) bool {
+	// A lease that cannot be read or renewed may be taken by another
+	// client, so any failure ends the leadership
+	_, isStore := c.adaptor.(LeaseStore)
+	leader := isStore && c.acquireLease(ctx) == nil
+	c.setLeader(leader)
+	return leader
+}
+
+// setLeader records the leadership, reporting changes to
+// Config.OnLeaderChange
+func (c *Client) setLeader(leader bool) {
+	if c.leader.Swap(leader) != leader && c.config.OnLeaderChange != nil {
+		c.config.OnLeaderChange(leader)
+	}
+}
@@ -0,0 +1,79 @@
+package sheetkv_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// waitFor polls cond until it holds or the test times out
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestClient_ElectLeader(t *testing.T) {
+	data := &leaseAdapter{memoryAdapter: newMemoryAdapter([]string{"name"})}
+
+	var mu sync.Mutex
+	changes := map[string][]bool{}
+	open := func(owner string) *sheetkv.Client {
+		client := sheetkv.New(data, &sheetkv.Config{
+			SyncInterval:  10 * time.Millisecond,
+			LeaseDuration: time.Second,
+			LeaseOwner:    owner,
+			ElectLeader:   true,
+			OnLeaderChange: func(leader bool) {
+				mu.Lock()
+				defer mu.Unlock()
+				changes[owner] = append(changes[owner], leader)
+			},
+		})
+		if err := client.Initialize(context.Background()); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		return client
+	}
+
+	first := open("first")
+	waitFor(t, "first to lead", first.IsLeader)
+	second := open("second")
+	defer second.Close()
+	time.Sleep(50 * time.Millisecond)
+	if second.IsLeader() {
+		t.Fatal("second leads while first holds the lease")
+	}
+
+	// The leader saves in the background, the follower reloads
+	if err := first.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "John"}}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	waitFor(t, "the follower to see the leader's write", func() bool {
+		_, err := second.Get(2)
+		return err == nil
+	})
+
+	// Closing the leader hands the leadership over
+	if err := first.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	waitFor(t, "second to lead", second.IsLeader)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := changes["first"]; len(got) != 2 || !got[0] || got[1] {
+		t.Errorf("first leadership changes = %v, want [true false]", got)
+	}
+	if got := changes["second"]; len(got) != 1 || !got[0] {
+		t.Errorf("second leadership changes = %v, want [true]", got)
+	}
+}
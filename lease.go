@@ -0,0 +1,135 @@
+package sheetkv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// defaultLeaseOwner identifies this process by host name and process ID
+func defaultLeaseOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
+
+// lock takes Config.Locker, or the lease of the sheet, before a write and
+// returns the function releasing it. A held lease is kept after the write,
+// so frequent saves do not rewrite it.
+func (c *Client) lock(ctx context.Context) (func() error, error) {
+	locker := c.config.Locker
+	if locker == nil {
+		return func() error { return nil }, c.acquireLease(ctx)
+	}
+
+	if err := locker.Lock(ctx); err != nil {
+		return nil, fmt.Errorf("failed to lock: %w", err)
+	}
+	return func() error {
+		if err := locker.Unlock(ctx); err != nil {
+			return fmt.Errorf("failed to unlock: %w", err)
+		}
+		return nil
+	}, nil
+}
+
+// acquireLease takes or renews the lease of the sheet before a write when
+// Config.LeaseDuration is set and the adapter is a LeaseStore. A held lease
+// is renewed once half of it has elapsed. It returns an error wrapping
+// ErrLeaseHeld while another process holds an unexpired lease.
+func (c *Client) acquireLease(ctx context.Context) error {
+	store, isStore := c.adaptor.(LeaseStore)
+	if c.config.LeaseDuration <= 0 || !isStore {
+		return nil
+	}
+
+	c.leaseMu.Lock()
+	defer c.leaseMu.Unlock()
+
+	now := c.now()
+	if c.leaseExpires.Sub(now) > c.config.LeaseDuration/2 {
+		return nil
+	}
+
+	current, err := c.readLease(ctx, store)
+	if err != nil {
+		return err
+	}
+	if current != nil && current.Owner != c.config.LeaseOwner && current.Expires.After(now) {
+		return leaseHeld(current)
+	}
+
+	lease := &Lease{Owner: c.config.LeaseOwner, Expires: now.Add(c.config.LeaseDuration)}
+	err = c.withRetry(ctx, func() error {
+		return store.WriteLease(ctx, lease)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write lease: %w", err)
+	}
+
+	// A process writing its lease at the same time may have overwritten
+	// ours, so read it back
+	current, err = c.readLease(ctx, store)
+	if err != nil {
+		return err
+	}
+	if current == nil || current.Owner != c.config.LeaseOwner {
+		c.leaseExpires = time.Time{}
+		return leaseHeld(current)
+	}
+	c.leaseExpires = lease.Expires
+	return nil
+}
+
+// releaseLease clears the lease of the sheet when this client holds it
+func (c *Client) releaseLease(ctx context.Context) error {
+	store, isStore := c.adaptor.(LeaseStore)
+	if !isStore {
+		return nil
+	}
+
+	c.leaseMu.Lock()
+	defer c.leaseMu.Unlock()
+
+	if !c.leaseExpires.After(c.now()) {
+		return nil
+	}
+	c.leaseExpires = time.Time{}
+
+	current, err := c.readLease(ctx, store)
+	if err != nil || current == nil || current.Owner != c.config.LeaseOwner {
+		return err
+	}
+	err = c.withRetry(ctx, func() error {
+		return store.WriteLease(ctx, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release lease: %w", err)
+	}
+	return nil
+}
+
+// readLease returns the stored lease
+func (c *Client) readLease(ctx context.Context, store LeaseStore) (*Lease, error) {
+	var lease *Lease
+	err := c.withRetry(ctx, func() error {
+		var err error
+		lease, err = store.ReadLease(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lease: %w", err)
+	}
+	return lease, nil
+}
+
+// leaseHeld returns the error of a lease held by another process
+func leaseHeld(lease *Lease) error {
+	if lease == nil {
+		return ErrLeaseHeld
+	}
+	return fmt.Errorf("%w (%s until %s)", ErrLeaseHeld, lease.Owner, lease.Expires.Format(time.RFC3339))
+}
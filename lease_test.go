@@ -0,0 +1,162 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// leaseAdapter is a memoryAdapter keeping a lease shared by its clients
+type leaseAdapter struct {
+	*memoryAdapter
+	lease  *sheetkv.Lease
+	writes int
+}
+
+func (a *leaseAdapter) ReadLease(ctx context.Context) (*sheetkv.Lease, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.lease == nil {
+		return nil, nil
+	}
+	lease := *a.lease
+	return &lease, nil
+}
+
+func (a *leaseAdapter) WriteLease(ctx context.Context, lease *sheetkv.Lease) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.writes++
+	a.lease = lease
+	return nil
+}
+
+func TestClient_Lease(t *testing.T) {
+	data := &leaseAdapter{memoryAdapter: newMemoryAdapter([]string{"name"})}
+	open := func(owner string) *sheetkv.Client {
+		client := sheetkv.New(data, &sheetkv.Config{DisableAutoSync: true, LeaseDuration: time.Hour, LeaseOwner: owner})
+		if err := client.Initialize(context.Background()); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		return client
+	}
+	first, second := open("first"), open("second")
+	defer second.Close()
+
+	if err := first.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "John"}}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if err := first.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if data.lease == nil || data.lease.Owner != "first" {
+		t.Fatalf("lease = %+v, want held by first", data.lease)
+	}
+
+	// The held lease is renewed only once half of it has elapsed
+	if err := first.Update(2, map[string]interface{}{"name": "Johnny"}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := first.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if data.writes != 1 {
+		t.Errorf("lease writes = %d, want 1", data.writes)
+	}
+
+	if err := second.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "Jane"}}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	err := second.Sync()
+	if !errors.Is(err, sheetkv.ErrLeaseHeld) || !errors.Is(err, sheetkv.ErrSyncFailed) {
+		t.Fatalf("Sync() error = %v, want ErrLeaseHeld", err)
+	}
+	if saves := data.saveCount(); saves != 2 {
+		t.Errorf("saves = %d, want the refused save skipped", saves)
+	}
+
+	// Closing releases the lease for the other process
+	if err := first.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if data.lease != nil {
+		t.Errorf("lease = %+v, want released", data.lease)
+	}
+	if err := second.Sync(); err != nil {
+		t.Errorf("Sync() error = %v", err)
+	}
+}
+
+func TestClient_LeaseExpired(t *testing.T) {
+	data := &leaseAdapter{memoryAdapter: newMemoryAdapter([]string{"name"})}
+	data.lease = &sheetkv.Lease{Owner: "crashed", Expires: time.Now().Add(-time.Minute)}
+
+	client := sheetkv.New(data, &sheetkv.Config{DisableAutoSync: true, LeaseDuration: time.Minute, LeaseOwner: "next"})
+	defer client.Close()
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	if err := client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "John"}}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if err := client.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if data.lease.Owner != "next" || !data.lease.Expires.After(time.Now()) {
+		t.Errorf("lease = %+v, want taken over", data.lease)
+	}
+}
+
+// recordingLocker is a Locker recording its calls, failing while held is set
+type recordingLocker struct {
+	calls []string
+	held  bool
+}
+
+func (l *recordingLocker) Lock(ctx context.Context) error {
+	if l.held {
+		return sheetkv.ErrLocked
+	}
+	l.calls = append(l.calls, "lock")
+	return nil
+}
+
+func (l *recordingLocker) Unlock(ctx context.Context) error {
+	l.calls = append(l.calls, "unlock")
+	return nil
+}
+
+func TestClient_Locker(t *testing.T) {
+	data := newMemoryAdapter([]string{"name"})
+	locker := &recordingLocker{}
+	client := sheetkv.New(data, &sheetkv.Config{DisableAutoSync: true, Locker: locker})
+	defer client.Close()
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	if err := client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "John"}}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if err := client.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if len(locker.calls) != 2 || locker.calls[0] != "lock" || locker.calls[1] != "unlock" {
+		t.Errorf("locker calls = %v, want the save locked", locker.calls)
+	}
+
+	locker.held = true
+	if err := client.Update(2, map[string]interface{}{"name": "Johnny"}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := client.Sync(); !errors.Is(err, sheetkv.ErrLocked) || !errors.Is(err, sheetkv.ErrSyncFailed) {
+		t.Errorf("Sync() error = %v, want ErrLocked", err)
+	}
+	if saves := data.saveCount(); saves != 1 {
+		t.Errorf("saves = %d, want the locked save skipped", saves)
+	}
+	locker.held = false
+}
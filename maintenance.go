@@ -0,0 +1,169 @@
+package sheetkv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Job is a maintenance task run periodically by a client (see Config.Jobs)
+type Job struct {
+	Name     string                                          // Identifies the job in JobResult and RunJob
+	Interval time.Duration                                   // Time between runs, the first one an interval after New
+	Run      func(ctx context.Context, client *Client) error // Task; it may use any client method
+}
+
+// JobResult describes one run of a job, reported to Config.OnJob
+type JobResult struct {
+	Name     string
+	Started  time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// jobScheduler runs the jobs of a client until stopped
+type jobScheduler struct {
+	client *Client
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// startJobs starts one goroutine per job with a positive interval
+func startJobs(client *Client, jobs []Job) *jobScheduler {
+	s := &jobScheduler{client: client, done: make(chan struct{})}
+	for _, job := range jobs {
+		if job.Interval <= 0 || job.Run == nil {
+			continue
+		}
+		s.wg.Add(1)
+		go func(job Job) {
+			defer s.wg.Done()
+
+			ticker := client.config.Clock.NewTicker(job.Interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C():
+					client.runJob(context.Background(), job)
+				case <-s.done:
+					return
+				}
+			}
+		}(job)
+	}
+	return s
+}
+
+// stop ends the scheduling and waits for running jobs
+func (s *jobScheduler) stop() {
+	close(s.done)
+	s.wg.Wait()
+}
+
+// runJob runs job and reports the result to Config.OnJob
+func (c *Client) runJob(ctx context.Context, job Job) error {
+	started := c.now()
+	err := job.Run(ctx, c)
+	if c.config.OnJob != nil {
+		c.config.OnJob(JobResult{Name: job.Name, Started: started, Duration: c.now().Sub(started), Err: err})
+	}
+	return err
+}
+
+// RunJob runs the job of Config.Jobs named name now, independently of its
+// schedule
+func (c *Client) RunJob(ctx context.Context, name string) error {
+	for _, job := range c.config.Jobs {
+		if job.Name == name && job.Run != nil {
+			return c.runJob(ctx, job)
+		}
+	}
+	return fmt.Errorf("unknown job: %s", name)
+}
+
+// errNotExpired skips a record of ExpireJob that is no longer expired
+var errNotExpired = errors.New("record not expired")
+
+// ExpireJob returns a job deleting the records whose time in column is older
+// than ttl. Use it with Config.CreatedAtColumn or UpdatedAtColumn to expire
+// rows, or with the column stamped by a soft delete to prune them. Records
+// without a time in column are kept.
+func ExpireJob(column string, ttl, interval time.Duration) Job {
+	return Job{
+		Name:     "expire " + column,
+		Interval: interval,
+		Run: func(ctx context.Context, client *Client) error {
+			cutoff := client.now().Add(-ttl)
+			expired := func(record *Record) bool {
+				at := record.GetAsTime(column, time.Time{})
+				return !at.IsZero() && at.Before(cutoff)
+			}
+			// A compaction may renumber the keys of the view before a delete,
+			// so the record at the key is checked again before it is deleted
+			guard := func(existing *Record) error {
+				if existing == nil || !expired(existing) {
+					return errNotExpired
+				}
+				return nil
+			}
+			return client.ReadTx(func(view ReadView) error {
+				// The view stays consistent while the deletes below run
+				for _, record := range view.Records() {
+					if !expired(record) {
+						continue
+					}
+					err := client.deleteGuarded(record.Key, guard)
+					if err != nil && err != ErrKeyNotFound && err != errNotExpired {
+						return err
+					}
+				}
+				return nil
+			})
+		},
+	}
+}
+
+// CompactJob returns a job running Compact, which removes the gaps left by
+// deleted rows and renumbers the records below them
+func CompactJob(interval time.Duration) Job {
+	return Job{
+		Name:     "compact",
+		Interval: interval,
+		Run: func(ctx context.Context, client *Client) error {
+			_, err := client.Compact(ctx)
+			return err
+		},
+	}
+}
+
+// RefreshIndexJob returns a job reloading the sheet, which rebuilds the
+// index from edits made outside the client. With Config.PersistIndex, the
+// persisted index is rewritten too when no local change is pending;
+// otherwise the next sync writes it.
+func RefreshIndexJob(interval time.Duration) Job {
+	return Job{
+		Name:     "refresh index",
+		Interval: interval,
+		Run: func(ctx context.Context, client *Client) error {
+			if err := client.Reload(ctx); err != nil {
+				return err
+			}
+			if !client.config.PersistIndex {
+				return nil
+			}
+
+			client.mu.Lock()
+			defer client.mu.Unlock()
+
+			if client.closed || client.cache.HasChanges() {
+				return nil
+			}
+			if err := client.saveIndex(ctx); err != nil {
+				return fmt.Errorf("failed to save index: %w", err)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,133 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestExpireJob(t *testing.T) {
+	ctx := context.Background()
+	old := time.Now().Add(-48 * time.Hour).Format(time.RFC3339)
+	recent := time.Now().Format(time.RFC3339)
+
+	adapter := newMemoryAdapter([]string{"name", "updated_at"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "stale", "updated_at": old}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "fresh", "updated_at": recent}},
+		&sheetkv.Record{Key: 4, Values: map[string]interface{}{"name": "untimed"}},
+	)
+	var results []sheetkv.JobResult
+	client := sheetkv.New(adapter, &sheetkv.Config{
+		DisableAutoSync: true,
+		Jobs:            []sheetkv.Job{sheetkv.ExpireJob("updated_at", 24*time.Hour, 0)},
+		OnJob:           func(result sheetkv.JobResult) { results = append(results, result) },
+	})
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	defer client.Close()
+
+	if err := client.RunJob(ctx, "expire updated_at"); err != nil {
+		t.Fatalf("RunJob() error = %v", err)
+	}
+	if _, err := client.Get(2); err != sheetkv.ErrKeyNotFound {
+		t.Errorf("Get(2) error = %v, want the stale record expired", err)
+	}
+	for _, key := range []int{3, 4} {
+		if _, err := client.Get(key); err != nil {
+			t.Errorf("Get(%d) error = %v, want the record kept", key, err)
+		}
+	}
+	if len(results) != 1 || results[0].Name != "expire updated_at" || results[0].Err != nil {
+		t.Errorf("OnJob results = %+v, want one successful run", results)
+	}
+
+	if err := client.RunJob(ctx, "missing"); err == nil {
+		t.Error("RunJob() of an unknown job should fail")
+	}
+}
+
+// strategyAdapter records the strategy of each save
+type strategyAdapter struct {
+	*memoryAdapter
+	strategies []sheetkv.SyncStrategy
+}
+
+func (a *strategyAdapter) Save(ctx context.Context, records []*sheetkv.Record, schema []string, strategy sheetkv.SyncStrategy) error {
+	a.strategies = append(a.strategies, strategy)
+	return a.memoryAdapter.Save(ctx, records, schema, strategy)
+}
+
+func TestCompactJob(t *testing.T) {
+	ctx := context.Background()
+	adapter := &strategyAdapter{memoryAdapter: newMemoryAdapter([]string{"name"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "a"}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "b"}},
+	)}
+	client := sheetkv.New(adapter, &sheetkv.Config{
+		DisableAutoSync: true,
+		Jobs:            []sheetkv.Job{sheetkv.CompactJob(0)},
+	})
+	client.Initialize(ctx)
+	defer client.Close()
+
+	client.Delete(3)
+	if err := client.RunJob(ctx, "compact"); err != nil {
+		t.Fatalf("RunJob() error = %v", err)
+	}
+
+	if len(adapter.strategies) != 1 || adapter.strategies[0] != sheetkv.SyncStrategyCompacting {
+		t.Errorf("save strategies = %v, want one compacting save", adapter.strategies)
+	}
+}
+
+func TestClient_JobsScheduled(t *testing.T) {
+	var mu sync.Mutex
+	runs := 0
+	failure := errors.New("job failed")
+	results := make(chan sheetkv.JobResult, 10)
+
+	client := sheetkv.New(newMemoryAdapter([]string{"name"}), &sheetkv.Config{
+		DisableAutoSync: true,
+		Jobs: []sheetkv.Job{{
+			Name:     "tick",
+			Interval: 10 * time.Millisecond,
+			Run: func(ctx context.Context, client *sheetkv.Client) error {
+				mu.Lock()
+				defer mu.Unlock()
+				runs++
+				return failure
+			},
+		}},
+		OnJob: func(result sheetkv.JobResult) {
+			select {
+			case results <- result:
+			default:
+			}
+		},
+	})
+
+	select {
+	case result := <-results:
+		if result.Name != "tick" || result.Err != failure {
+			t.Errorf("JobResult = %+v, want the failure of tick", result)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("job did not run")
+	}
+
+	client.Close()
+	mu.Lock()
+	stopped := runs
+	mu.Unlock()
+	time.Sleep(30 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if runs != stopped {
+		t.Errorf("job ran %d times after Close", runs-stopped)
+	}
+}
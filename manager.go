@@ -0,0 +1,243 @@
+package sheetkv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Opener creates the adapter of one sheet of a spreadsheet. Openers usually
+// share one set of credentials between all the adapters they create.
+type Opener func(ctx context.Context, spreadsheetID, sheet string) (Adapter, error)
+
+// ManagerConfig represents configuration for a Manager
+type ManagerConfig struct {
+	Client      *Config       // Configuration of every client (default: New's defaults)
+	RateLimiter *RateLimiter  // Shared by every client, overriding Client.RateLimiter (default: none)
+	IdleTimeout time.Duration // Close clients unused for this long (0 keeps them open)
+}
+
+// Manager opens and caches one client per (spreadsheet, sheet), for
+// applications that keep a sheet per customer. Clients are initialized on
+// first use, share the rate limiter and are closed after IdleTimeout without
+// use or by Close.
+//
+// An evicted client is closed, so fetch the client from the manager for
+// each unit of work rather than keeping it beyond IdleTimeout.
+type Manager struct {
+	opener  Opener
+	config  ManagerConfig
+	mu      sync.Mutex
+	clients map[managedKey]*managedClient
+	closed  bool
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// managedKey identifies a client of a Manager
+type managedKey struct {
+	spreadsheetID string
+	sheet         string
+}
+
+// managedClient is a client being opened or open
+type managedClient struct {
+	client   *Client
+	ready    chan struct{} // Closed once the client is initialized or failed
+	err      error
+	lastUsed time.Time
+}
+
+// NewManager creates a manager opening adapters with opener
+func NewManager(opener Opener, config *ManagerConfig) *Manager {
+	if config == nil {
+		config = &ManagerConfig{}
+	}
+
+	m := &Manager{
+		opener:  opener,
+		config:  *config,
+		clients: make(map[managedKey]*managedClient),
+		done:    make(chan struct{}),
+	}
+	if m.config.IdleTimeout > 0 {
+		m.wg.Add(1)
+		go m.evictIdle()
+	}
+	return m
+}
+
+// Client returns the initialized client of a sheet, opening it on first use.
+// Concurrent calls for the same sheet share one client; a failed open is
+// retried by the next call.
+func (m *Manager) Client(ctx context.Context, spreadsheetID, sheet string) (*Client, error) {
+	key := managedKey{spreadsheetID, sheet}
+
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("manager is closed")
+	}
+	entry, exists := m.clients[key]
+	if !exists {
+		entry = &managedClient{ready: make(chan struct{})}
+		m.clients[key] = entry
+	}
+	entry.lastUsed = time.Now()
+	m.mu.Unlock()
+
+	if !exists {
+		entry.client, entry.err = m.open(ctx, key)
+		if entry.err != nil {
+			m.mu.Lock()
+			if m.clients[key] == entry {
+				delete(m.clients, key)
+			}
+			m.mu.Unlock()
+		}
+		close(entry.ready)
+	}
+
+	select {
+	case <-entry.ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if entry.err != nil {
+		return nil, entry.err
+	}
+	return entry.client, nil
+}
+
+// open creates and initializes the client of a sheet
+func (m *Manager) open(ctx context.Context, key managedKey) (*Client, error) {
+	adapter, err := m.opener(ctx, key.spreadsheetID, key.sheet)
+	if err != nil {
+		return nil, fmt.Errorf("sheet %q of %q: %w", key.sheet, key.spreadsheetID, err)
+	}
+
+	var config *Config
+	if m.config.Client != nil {
+		copied := *m.config.Client
+		config = &copied
+	}
+	if m.config.RateLimiter != nil {
+		if config == nil {
+			config = &Config{}
+		}
+		config.RateLimiter = m.config.RateLimiter
+	}
+
+	client := New(adapter, config)
+	if err := client.Initialize(ctx); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("sheet %q of %q: %w", key.sheet, key.spreadsheetID, err)
+	}
+	return client, nil
+}
+
+// Len returns the number of open clients
+func (m *Manager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.clients)
+}
+
+// Evict closes the client of a sheet, performing its final sync. It does
+// nothing when the sheet is not open.
+func (m *Manager) Evict(spreadsheetID, sheet string) error {
+	key := managedKey{spreadsheetID, sheet}
+
+	m.mu.Lock()
+	entry, exists := m.clients[key]
+	if exists {
+		delete(m.clients, key)
+	}
+	m.mu.Unlock()
+
+	if !exists {
+		return nil
+	}
+	return m.closeEntry(key, entry)
+}
+
+// closeEntry closes the client of an entry once it is opened
+func (m *Manager) closeEntry(key managedKey, entry *managedClient) error {
+	<-entry.ready
+	if entry.err != nil {
+		return nil
+	}
+	if err := entry.client.Close(); err != nil {
+		return fmt.Errorf("sheet %q of %q: %w", key.sheet, key.spreadsheetID, err)
+	}
+	return nil
+}
+
+// evictIdle periodically closes the clients unused for IdleTimeout
+func (m *Manager) evictIdle() {
+	defer m.wg.Done()
+
+	interval := m.config.IdleTimeout / 2
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			return
+		case now := <-ticker.C:
+			m.evictBefore(now.Add(-m.config.IdleTimeout))
+		}
+	}
+}
+
+// evictBefore closes the clients last used before deadline
+func (m *Manager) evictBefore(deadline time.Time) {
+	idle := make(map[managedKey]*managedClient)
+
+	m.mu.Lock()
+	for key, entry := range m.clients {
+		if entry.lastUsed.Before(deadline) {
+			idle[key] = entry
+			delete(m.clients, key)
+		}
+	}
+	m.mu.Unlock()
+
+	for key, entry := range idle {
+		// Errors of background evictions have no caller to report to; the
+		// client's Stats record the failed sync
+		m.closeEntry(key, entry)
+	}
+}
+
+// Close closes every client, performing their final syncs, and stops idle
+// eviction. Errors from every client are joined together.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	clients := m.clients
+	m.clients = make(map[managedKey]*managedClient)
+	m.mu.Unlock()
+
+	close(m.done)
+	m.wg.Wait()
+
+	var errs []error
+	for key, entry := range clients {
+		if err := m.closeEntry(key, entry); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,176 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// countingOpener opens memory adapters and counts the opens per sheet
+type countingOpener struct {
+	mu       sync.Mutex
+	opens    map[string]int
+	adapters map[string]*memoryAdapter
+	fail     atomic.Bool
+}
+
+func newCountingOpener() *countingOpener {
+	return &countingOpener{opens: make(map[string]int), adapters: make(map[string]*memoryAdapter)}
+}
+
+func (o *countingOpener) open(ctx context.Context, spreadsheetID, sheet string) (sheetkv.Adapter, error) {
+	if o.fail.Load() {
+		return nil, errors.New("no credentials")
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	id := spreadsheetID + "/" + sheet
+	o.opens[id]++
+	adapter := newMemoryAdapter([]string{"name"})
+	o.adapters[id] = adapter
+	return adapter, nil
+}
+
+func (o *countingOpener) count(id string) int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.opens[id]
+}
+
+func TestManager(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Caches clients", func(t *testing.T) {
+		opener := newCountingOpener()
+		limiter := sheetkv.NewRateLimiter(100, 10)
+		manager := sheetkv.NewManager(opener.open, &sheetkv.ManagerConfig{
+			Client:      &sheetkv.Config{DisableAutoSync: true},
+			RateLimiter: limiter,
+		})
+		defer manager.Close()
+
+		var wg sync.WaitGroup
+		clients := make([]*sheetkv.Client, 10)
+		for i := range clients {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				client, err := manager.Client(ctx, "customer-a", "orders")
+				if err != nil {
+					t.Errorf("Client() error = %v", err)
+				}
+				clients[i] = client
+			}(i)
+		}
+		wg.Wait()
+
+		for _, client := range clients {
+			if client != clients[0] {
+				t.Fatal("Client() returned different clients for the same sheet")
+			}
+		}
+		if got := opener.count("customer-a/orders"); got != 1 {
+			t.Errorf("opens = %d, want 1", got)
+		}
+
+		other, err := manager.Client(ctx, "customer-b", "orders")
+		if err != nil {
+			t.Fatalf("Client() error = %v", err)
+		}
+		if other == clients[0] {
+			t.Error("Client() returned the same client for another spreadsheet")
+		}
+		if got := manager.Len(); got != 2 {
+			t.Errorf("Len() = %d, want 2", got)
+		}
+	})
+
+	t.Run("Open error", func(t *testing.T) {
+		opener := newCountingOpener()
+		manager := sheetkv.NewManager(opener.open, &sheetkv.ManagerConfig{Client: &sheetkv.Config{DisableAutoSync: true}})
+		defer manager.Close()
+
+		opener.fail.Store(true)
+		if _, err := manager.Client(ctx, "customer-a", "orders"); err == nil {
+			t.Fatal("Client() error = nil, want open error")
+		}
+		if got := manager.Len(); got != 0 {
+			t.Errorf("Len() = %d, want 0", got)
+		}
+
+		opener.fail.Store(false)
+		if _, err := manager.Client(ctx, "customer-a", "orders"); err != nil {
+			t.Errorf("Client() after recovery error = %v", err)
+		}
+	})
+
+	t.Run("Evict", func(t *testing.T) {
+		opener := newCountingOpener()
+		manager := sheetkv.NewManager(opener.open, &sheetkv.ManagerConfig{Client: &sheetkv.Config{DisableAutoSync: true}})
+		defer manager.Close()
+
+		client, _ := manager.Client(ctx, "customer-a", "orders")
+		client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "John"}})
+
+		if err := manager.Evict("customer-a", "orders"); err != nil {
+			t.Fatalf("Evict() error = %v", err)
+		}
+		if got := opener.adapters["customer-a/orders"].saveCount(); got != 1 {
+			t.Errorf("saves = %d, want 1 final sync", got)
+		}
+		if _, err := client.Get(2); err == nil {
+			t.Error("Get() on an evicted client error = nil, want closed")
+		}
+
+		manager.Client(ctx, "customer-a", "orders")
+		if got := opener.count("customer-a/orders"); got != 2 {
+			t.Errorf("opens = %d, want 2", got)
+		}
+	})
+
+	t.Run("Idle eviction", func(t *testing.T) {
+		opener := newCountingOpener()
+		manager := sheetkv.NewManager(opener.open, &sheetkv.ManagerConfig{
+			Client:      &sheetkv.Config{DisableAutoSync: true},
+			IdleTimeout: 20 * time.Millisecond,
+		})
+		defer manager.Close()
+
+		manager.Client(ctx, "customer-a", "orders")
+		deadline := time.Now().Add(time.Second)
+		for manager.Len() != 0 && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		if got := manager.Len(); got != 0 {
+			t.Errorf("Len() = %d, want 0 after the idle timeout", got)
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		opener := newCountingOpener()
+		manager := sheetkv.NewManager(opener.open, &sheetkv.ManagerConfig{Client: &sheetkv.Config{DisableAutoSync: true}})
+
+		for _, id := range []string{"customer-a", "customer-b"} {
+			client, _ := manager.Client(ctx, id, "orders")
+			client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": id}})
+		}
+		if err := manager.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		for _, id := range []string{"customer-a/orders", "customer-b/orders"} {
+			if got := opener.adapters[id].saveCount(); got != 1 {
+				t.Errorf("saves of %s = %d, want 1", id, got)
+			}
+		}
+		if _, err := manager.Client(ctx, "customer-a", "orders"); err == nil {
+			t.Error("Client() after Close() error = nil, want closed")
+		}
+	})
+}
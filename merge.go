@@ -0,0 +1,109 @@
+package sheetkv
+
+import (
+	"fmt"
+	"time"
+)
+
+// MergeStrategy decides which of two values of a column MergeValues keeps
+type MergeStrategy int
+
+const (
+	// MergePreferSource takes the values of src
+	MergePreferSource MergeStrategy = iota
+	// MergePreferDestination keeps the values of dst, taking those of src
+	// only for columns dst lacks
+	MergePreferDestination
+	// MergePreferNonEmpty takes the values of src unless they are nil or
+	// empty, so blanks do not erase data
+	MergePreferNonEmpty
+	// MergePreferNewest takes the values of the side whose
+	// MergePolicy.TimestampColumn is later, src on ties and unreadable times
+	MergePreferNewest
+)
+
+// String returns the name of the strategy
+func (s MergeStrategy) String() string {
+	switch s {
+	case MergePreferSource:
+		return "prefer source"
+	case MergePreferDestination:
+		return "prefer destination"
+	case MergePreferNonEmpty:
+		return "prefer non-empty"
+	case MergePreferNewest:
+		return "prefer newest"
+	default:
+		return fmt.Sprintf("MergeStrategy(%d)", int(s))
+	}
+}
+
+// MergePolicy describes how MergeValues combines two versions of a record
+type MergePolicy struct {
+	Strategy        MergeStrategy            // Strategy of the columns not in Columns (default: MergePreferSource)
+	Columns         map[string]MergeStrategy // Per-column strategies overriding Strategy
+	TimestampColumn string                   // Column compared by MergePreferNewest, such as Config.UpdatedAtColumn
+	TimeFormat      string                   // Layout of the timestamps (default: time.RFC3339)
+}
+
+// MergeValues merges the values of src into dst, column by column, with the
+// strategies of policy. Columns only in dst are kept.
+//
+// With Config.MergePolicy, Reload merges the records modified locally into
+// their versions on the sheet this way, the local values being src.
+func MergeValues(dst, src map[string]interface{}, policy MergePolicy) {
+	srcNewer := policy.srcNewer(dst, src)
+	for col, value := range src {
+		strategy, ok := policy.Columns[col]
+		if !ok {
+			strategy = policy.Strategy
+		}
+
+		_, exists := dst[col]
+		switch strategy {
+		case MergePreferDestination:
+			if exists {
+				continue
+			}
+		case MergePreferNonEmpty:
+			if value == nil || value == "" {
+				continue
+			}
+		case MergePreferNewest:
+			if !srcNewer {
+				continue
+			}
+		}
+		dst[col] = value
+	}
+}
+
+// srcNewer reports whether the timestamp of src is not before that of dst
+func (p MergePolicy) srcNewer(dst, src map[string]interface{}) bool {
+	if p.TimestampColumn == "" {
+		return true
+	}
+	layout := p.TimeFormat
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	srcTime, srcOK := mergeTime(src[p.TimestampColumn], layout)
+	dstTime, dstOK := mergeTime(dst[p.TimestampColumn], layout)
+	if !srcOK || !dstOK {
+		return true
+	}
+	return !srcTime.Before(dstTime)
+}
+
+// mergeTime returns the time of a timestamp value
+func mergeTime(value interface{}, layout string) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		t, err := time.Parse(layout, v)
+		return t, err == nil
+	default:
+		return time.Time{}, false
+	}
+}
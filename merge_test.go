@@ -0,0 +1,114 @@
+package sheetkv_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestMergeValues(t *testing.T) {
+	dst := func() map[string]interface{} {
+		return map[string]interface{}{"name": "John", "email": "john@example.com", "note": "remote", "updated": "2024-05-02T00:00:00Z"}
+	}
+	src := map[string]interface{}{"name": "Johnny", "email": "", "note": nil, "phone": "555-0100", "updated": "2024-05-01T00:00:00Z"}
+
+	tests := []struct {
+		name   string
+		policy sheetkv.MergePolicy
+		want   map[string]interface{}
+	}{
+		{
+			"prefer source",
+			sheetkv.MergePolicy{},
+			map[string]interface{}{"name": "Johnny", "email": "", "note": nil, "phone": "555-0100", "updated": "2024-05-01T00:00:00Z"},
+		},
+		{
+			"prefer destination",
+			sheetkv.MergePolicy{Strategy: sheetkv.MergePreferDestination},
+			map[string]interface{}{"name": "John", "email": "john@example.com", "note": "remote", "phone": "555-0100", "updated": "2024-05-02T00:00:00Z"},
+		},
+		{
+			"prefer non-empty",
+			sheetkv.MergePolicy{Strategy: sheetkv.MergePreferNonEmpty},
+			map[string]interface{}{"name": "Johnny", "email": "john@example.com", "note": "remote", "phone": "555-0100", "updated": "2024-05-01T00:00:00Z"},
+		},
+		{
+			"prefer newest",
+			sheetkv.MergePolicy{Strategy: sheetkv.MergePreferNewest, TimestampColumn: "updated"},
+			dst(),
+		},
+		{
+			"per-column overrides",
+			sheetkv.MergePolicy{
+				Strategy:        sheetkv.MergePreferNewest,
+				TimestampColumn: "updated",
+				Columns:         map[string]sheetkv.MergeStrategy{"phone": sheetkv.MergePreferNonEmpty},
+			},
+			map[string]interface{}{"name": "John", "email": "john@example.com", "note": "remote", "phone": "555-0100", "updated": "2024-05-02T00:00:00Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dst()
+			sheetkv.MergeValues(got, src, tt.policy)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("newer source", func(t *testing.T) {
+		got := dst()
+		newer := map[string]interface{}{"name": "Johnny", "updated": "2024-05-03T00:00:00Z"}
+		sheetkv.MergeValues(got, newer, sheetkv.MergePolicy{Strategy: sheetkv.MergePreferNewest, TimestampColumn: "updated"})
+		if got["name"] != "Johnny" || got["updated"] != "2024-05-03T00:00:00Z" {
+			t.Errorf("MergeValues() = %v, want the newer values", got)
+		}
+	})
+}
+
+func TestClient_ReloadMergePolicy(t *testing.T) {
+	adapter := newMemoryAdapter([]string{"name", "email"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "email": ""}},
+	)
+	client := sheetkv.New(adapter, &sheetkv.Config{
+		DisableAutoSync: true,
+		MergePolicy:     &sheetkv.MergePolicy{Strategy: sheetkv.MergePreferNonEmpty},
+	})
+	ctx := context.Background()
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	// The local change renames, the remote edit fills the email
+	if err := client.Update(2, map[string]interface{}{"name": "Johnny"}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	adapter.mu.Lock()
+	adapter.records = []*sheetkv.Record{
+		{Key: 2, Values: map[string]interface{}{"name": "John", "email": "john@example.com"}},
+	}
+	adapter.mu.Unlock()
+
+	if err := client.Reload(ctx); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	got, err := client.Get(2)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Values["name"] != "Johnny" || got.Values["email"] != "john@example.com" {
+		t.Errorf("Get() = %v, want the local name and the remote email", got.Values)
+	}
+
+	// The merged record is written by the next sync
+	if err := client.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if got := adapter.saveCount(); got != 1 {
+		t.Errorf("saves = %d, want 1", got)
+	}
+}
@@ -0,0 +1,139 @@
+package sheetkv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// MultiClientConfig represents configuration for a MultiClient
+type MultiClientConfig struct {
+	Parallelism int // Maximum number of tables loaded concurrently (default: 4)
+}
+
+// MultiClient groups the clients of several tables (typically the tabs of one
+// workbook) so they can be initialized, synced and closed together.
+//
+// Tables are loaded concurrently by Initialize. To keep the combined traffic
+// within the backend quota, create the member clients with the same
+// Config.RateLimiter.
+type MultiClient struct {
+	config  MultiClientConfig
+	mu      sync.RWMutex
+	clients map[string]*Client
+	names   []string // Registration order
+}
+
+// NewMultiClient creates an empty MultiClient
+func NewMultiClient(config *MultiClientConfig) *MultiClient {
+	if config == nil {
+		config = &MultiClientConfig{}
+	}
+
+	m := &MultiClient{
+		config:  *config,
+		clients: make(map[string]*Client),
+	}
+	if m.config.Parallelism <= 0 {
+		m.config.Parallelism = 4
+	}
+	return m
+}
+
+// Add registers a client under the given table name
+func (m *MultiClient) Add(name string, client *Client) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.clients[name]; exists {
+		return fmt.Errorf("table %q: %w", name, ErrDuplicateKey)
+	}
+
+	m.clients[name] = client
+	m.names = append(m.names, name)
+	return nil
+}
+
+// Table returns the client registered under the given name
+func (m *MultiClient) Table(name string) (*Client, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	client, exists := m.clients[name]
+	if !exists {
+		return nil, fmt.Errorf("table %q: %w", name, ErrTableNotFound)
+	}
+	return client, nil
+}
+
+// Tables returns the registered table names in registration order
+func (m *MultiClient) Tables() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, len(m.names))
+	copy(names, m.names)
+	return names
+}
+
+// Initialize loads all tables concurrently, with at most Parallelism loads in
+// flight. Loading stops early when ctx is canceled; errors from every failed
+// table are joined together.
+func (m *MultiClient) Initialize(ctx context.Context) error {
+	names := m.Tables()
+
+	sem := make(chan struct{}, m.config.Parallelism)
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+
+	for i, name := range names {
+		client, _ := m.Table(name)
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = fmt.Errorf("table %q: %w", name, ctx.Err())
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, name string, client *Client) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := client.Initialize(ctx); err != nil {
+				errs[i] = fmt.Errorf("table %q: %w", name, err)
+			}
+		}(i, name, client)
+	}
+
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Sync forces synchronization of every table
+func (m *MultiClient) Sync() error {
+	return m.each(func(client *Client) error {
+		return client.Sync()
+	})
+}
+
+// Close closes every table, performing the final sync of each
+func (m *MultiClient) Close() error {
+	return m.each(func(client *Client) error {
+		return client.Close()
+	})
+}
+
+// each calls fn for every table in registration order and joins the errors
+func (m *MultiClient) each(fn func(client *Client) error) error {
+	var errs []error
+	for _, name := range m.Tables() {
+		client, _ := m.Table(name)
+		if err := fn(client); err != nil {
+			errs = append(errs, fmt.Errorf("table %q: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
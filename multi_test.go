@@ -0,0 +1,121 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestMultiClient_Initialize(t *testing.T) {
+	t.Run("Loads tables concurrently with bounded parallelism", func(t *testing.T) {
+		// All tables share one adapter so it can observe the overall parallelism
+		adapter := newMemoryAdapter([]string{"name"}, &sheetkv.Record{
+			Key:    2,
+			Values: map[string]interface{}{"name": "John"},
+		})
+		adapter.loadDelay = 20 * time.Millisecond
+
+		multi := sheetkv.NewMultiClient(&sheetkv.MultiClientConfig{Parallelism: 3})
+		for i := 0; i < 10; i++ {
+			client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+			if err := multi.Add(fmt.Sprintf("tab%d", i), client); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+		}
+
+		if err := multi.Initialize(context.Background()); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+
+		if adapter.loads != 10 {
+			t.Errorf("adapter loaded %d times, want 10", adapter.loads)
+		}
+		if adapter.maxFlight < 2 || adapter.maxFlight > 3 {
+			t.Errorf("max concurrent loads = %d, want 2..3", adapter.maxFlight)
+		}
+
+		for _, name := range multi.Tables() {
+			client, err := multi.Table(name)
+			if err != nil {
+				t.Fatalf("Table() error = %v", err)
+			}
+			got, err := client.Get(2)
+			if err != nil {
+				t.Fatalf("%s: Get() error = %v", name, err)
+			}
+			if got.Values["name"] != "John" {
+				t.Errorf("%s: name = %v, want John", name, got.Values["name"])
+			}
+		}
+	})
+
+	t.Run("Errors are reported per table", func(t *testing.T) {
+		multi := sheetkv.NewMultiClient(nil)
+
+		good := newMemoryAdapter([]string{"name"})
+		bad := newMemoryAdapter([]string{"name"})
+		bad.loadErr = errors.New("boom")
+
+		multi.Add("good", sheetkv.New(good, &sheetkv.Config{DisableAutoSync: true, MaxRetries: 1}))
+		multi.Add("bad", sheetkv.New(bad, &sheetkv.Config{DisableAutoSync: true, MaxRetries: 1}))
+
+		err := multi.Initialize(context.Background())
+		if err == nil {
+			t.Fatal("Initialize() should fail when a table fails to load")
+		}
+		if !contains(err.Error(), `table "bad"`) || contains(err.Error(), `table "good"`) {
+			t.Errorf("Initialize() error = %v, want only the bad table", err)
+		}
+	})
+
+	t.Run("Duplicate and unknown tables", func(t *testing.T) {
+		multi := sheetkv.NewMultiClient(nil)
+		client := sheetkv.New(newMemoryAdapter(nil), &sheetkv.Config{DisableAutoSync: true})
+
+		if err := multi.Add("users", client); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if err := multi.Add("users", client); !errors.Is(err, sheetkv.ErrDuplicateKey) {
+			t.Errorf("Add() error = %v, want %v", err, sheetkv.ErrDuplicateKey)
+		}
+		if _, err := multi.Table("orders"); !errors.Is(err, sheetkv.ErrTableNotFound) {
+			t.Errorf("Table() error = %v, want %v", err, sheetkv.ErrTableNotFound)
+		}
+		if got := multi.Tables(); len(got) != 1 || got[0] != "users" {
+			t.Errorf("Tables() = %v, want [users]", got)
+		}
+	})
+}
+
+func TestRateLimiter(t *testing.T) {
+	t.Run("Allows burst then throttles", func(t *testing.T) {
+		limiter := sheetkv.NewRateLimiter(50, 2)
+		ctx := context.Background()
+
+		start := time.Now()
+		for i := 0; i < 4; i++ {
+			if err := limiter.Wait(ctx); err != nil {
+				t.Fatalf("Wait() error = %v", err)
+			}
+		}
+		// Two calls come from the burst; the other two need ~20ms each
+		if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+			t.Errorf("4 calls took %v, want at least 30ms", elapsed)
+		}
+	})
+
+	t.Run("Wait honors context cancellation", func(t *testing.T) {
+		limiter := sheetkv.NewRateLimiter(0.1, 1)
+		limiter.Wait(context.Background())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Wait() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package sheetkv
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// String returns the lower-case name of the operation type
+func (t OperationType) String() string {
+	switch t {
+	case OpAdd:
+		return "add"
+	case OpUpdate:
+		return "update"
+	case OpDelete:
+		return "delete"
+	default:
+		return fmt.Sprintf("OperationType(%d)", int(t))
+	}
+}
+
+// parseOperationType returns the operation type named s by String
+func parseOperationType(s string) (OperationType, bool) {
+	for _, t := range []OperationType{OpAdd, OpUpdate, OpDelete} {
+		if t.String() == s {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// mutation describes one change applied through the client
+type mutation struct {
+	op      OperationType
+	key     int
+	old     *Record  // Record before the change, nil on add
+	updated *Record  // Record after the change, nil on delete
+	columns []string // Columns whose values changed
+	time    time.Time
+}
+
+// tracksMutations reports whether any feature consumes mutations, so the
+// previous version of a record only has to be captured when needed
+func (c *Client) tracksMutations() bool {
+	return c.config.AuditAdapter != nil || c.config.HistoryLimit > 0 || c.config.HistoryAdapter != nil ||
+		c.config.OperationLogSize > 0 || c.config.Publisher != nil || c.watching()
+}
+
+// beforeMutation returns the current version of a record, or nil when it
+// does not exist or neither mutations nor write-throughs are tracked
+func (c *Client) beforeMutation(key int) *Record {
+	if !c.tracksMutations() && !c.config.WriteThrough {
+		return nil
+	}
+	old, err := c.cache.Get(key)
+	if err != nil {
+		return nil
+	}
+	return old
+}
+
+// afterMutation records the change of a record from old (nil when it did not
+// exist) to its current version
+func (c *Client) afterMutation(key int, old *Record) {
+	if !c.tracksMutations() {
+		return
+	}
+
+	updated, err := c.cache.Get(key)
+	if err != nil {
+		updated = nil
+	}
+
+	switch {
+	case updated == nil:
+		c.recordMutation(OpDelete, key, old, nil)
+	case old == nil:
+		c.recordMutation(OpAdd, key, nil, updated)
+	default:
+		c.recordMutation(OpUpdate, key, old, updated)
+	}
+}
+
+// recordMutation dispatches a successful change to the features observing
+// mutations. Changes that leave every value as it was are ignored.
+func (c *Client) recordMutation(op OperationType, key int, old, updated *Record) {
+	m := mutation{
+		op:      op,
+		key:     key,
+		old:     old,
+		updated: updated,
+		columns: changedColumns(old, updated),
+		time:    c.now(),
+	}
+	if len(m.columns) == 0 && op != OpDelete {
+		return
+	}
+
+	if c.config.AuditAdapter != nil {
+		c.queueAudit(m)
+	}
+	if c.config.HistoryLimit > 0 || c.config.HistoryAdapter != nil {
+		c.keepHistory(m)
+	}
+	if c.config.OperationLogSize > 0 {
+		c.logOperation(m)
+	}
+	if c.config.Publisher != nil {
+		c.queuePublish(m)
+	}
+	c.notifyWatchers(m)
+}
+
+// changedColumns returns the sorted columns whose values differ between two
+// versions of a record; either version may be nil
+func changedColumns(old, updated *Record) []string {
+	var oldValues, newValues map[string]interface{}
+	if old != nil {
+		oldValues = old.Values
+	}
+	if updated != nil {
+		newValues = updated.Values
+	}
+
+	columns := make([]string, 0)
+	for col, v := range newValues {
+		if ov, ok := oldValues[col]; !ok || !sameValue(ov, v) {
+			columns = append(columns, col)
+		}
+	}
+	for col := range oldValues {
+		if _, ok := newValues[col]; !ok {
+			columns = append(columns, col)
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// sameValue reports whether two cell values are identical, including their type
+func sameValue(a, b interface{}) bool {
+	return fmt.Sprintf("%T\x00%v", a, a) == fmt.Sprintf("%T\x00%v", b, b)
+}
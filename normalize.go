@@ -0,0 +1,79 @@
+package sheetkv
+
+import "golang.org/x/text/unicode/norm"
+
+// normalizeValue returns v with its strings, including those of "in" and
+// "between" lists, in Unicode normalization form C
+func normalizeValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case string:
+		return norm.NFC.String(val)
+	case []interface{}:
+		normalized := make([]interface{}, len(val))
+		for i, item := range val {
+			normalized[i] = normalizeValue(item)
+		}
+		return normalized
+	case [2]interface{}:
+		return [2]interface{}{normalizeValue(val[0]), normalizeValue(val[1])}
+	default:
+		return v
+	}
+}
+
+// normalizeQuery returns query with NFC-normalized condition values
+func normalizeQuery(query Query) Query {
+	query.Conditions = normalizeConditions(query.Conditions)
+	query.Groups = normalizeGroups(query.Groups)
+	return query
+}
+
+// normalizeConditions returns conditions with NFC-normalized values
+func normalizeConditions(conditions []Condition) []Condition {
+	normalized := make([]Condition, len(conditions))
+	for i, cond := range conditions {
+		cond.Value = normalizeValue(cond.Value)
+		normalized[i] = cond
+	}
+	return normalized
+}
+
+// normalizeGroups returns groups with NFC-normalized condition values
+func normalizeGroups(groups []ConditionGroup) []ConditionGroup {
+	if groups == nil {
+		return nil
+	}
+	normalized := make([]ConditionGroup, len(groups))
+	for i, group := range groups {
+		group.Conditions = normalizeConditions(group.Conditions)
+		group.Groups = normalizeGroups(group.Groups)
+		normalized[i] = group
+	}
+	return normalized
+}
+
+// normalizeRecords NFC-normalizes the strings of records in place
+func normalizeRecords(records []*Record) {
+	for _, record := range records {
+		for col, value := range record.Values {
+			if s, ok := value.(string); ok && !norm.NFC.IsNormalString(s) {
+				record.Values[col] = norm.NFC.String(s)
+			}
+		}
+	}
+}
+
+// SetUnicodeNormalization makes queries compare strings in Unicode
+// normalization form C, so that text decomposed by macOS (NFD) equals its
+// composed form. Stored values are left as written.
+func (c *Cache) SetUnicodeNormalization(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cmp := comparison{normalize: enabled}
+	if c.comparison != nil {
+		cmp.fallback, cmp.columns = c.comparison.fallback, c.comparison.columns
+	}
+	c.setComparison(cmp)
+	c.rebuildIndex()
+}
@@ -0,0 +1,59 @@
+package sheetkv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_NormalizeUnicode(t *testing.T) {
+	ctx := context.Background()
+	nfd := "\u30ab\u3099\u30c3\u30b3\u30a6" // ガッコウ decomposed, as saved by macOS
+	nfc := "\u30ac\u30c3\u30b3\u30a6"
+
+	newClient := func(normalize bool) *sheetkv.Client {
+		adapter := newMemoryAdapter([]string{"name"},
+			&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": nfd}},
+		)
+		client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true, IndexColumns: []string{"name"}, NormalizeUnicode: normalize})
+		if err := client.Initialize(ctx); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		return client
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		client := newClient(false)
+		defer client.Close()
+		records, _ := client.Lookup(ctx, "name", nfc)
+		if len(records) != 0 {
+			t.Errorf("Lookup(NFC) = %d records, want byte comparison to miss NFD text", len(records))
+		}
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		client := newClient(true)
+		defer client.Close()
+
+		record, _ := client.Get(2)
+		if got := record.GetAsString("name", ""); got != nfc {
+			t.Errorf("loaded name = %q, want it NFC-normalized", got)
+		}
+
+		// Values written decomposed still match composed conditions
+		client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": nfd}})
+		for _, value := range []string{nfc, nfd} {
+			records, err := client.Lookup(ctx, "name", value)
+			if err != nil || len(records) != 2 {
+				t.Errorf("Lookup(%q) = %d records, %v, want 2", value, len(records), err)
+			}
+			records, _ = client.Query(sheetkv.Query{Conditions: []sheetkv.Condition{
+				{Column: "name", Operator: "in", Value: []interface{}{value}},
+			}})
+			if len(records) != 2 {
+				t.Errorf("Query(in %q) = %d records, want 2", value, len(records))
+			}
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package sheetkv
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// LoggedOperation is one mutation of the operation log. Values holds the
+// whole record after the change, nil on delete, so replaying an operation
+// twice leaves the same record.
+type LoggedOperation struct {
+	Seq    uint64                 // Position in the log, starting at 1
+	Op     OperationType          // OpAdd, OpUpdate or OpDelete
+	Key    int                    // Key of the record
+	Values map[string]interface{} // Values of the record after the change
+	Time   time.Time              // Time of the change
+}
+
+// logOperation appends a mutation to the operation log, dropping the oldest
+// operation beyond Config.OperationLogSize
+func (c *Client) logOperation(m mutation) {
+	c.oplogMu.Lock()
+	defer c.oplogMu.Unlock()
+
+	c.oplogSeq++
+	op := LoggedOperation{Seq: c.oplogSeq, Op: m.op, Key: m.key, Time: m.time}
+	if m.updated != nil {
+		op.Values = m.updated.Values
+	}
+	c.oplog = append(c.oplog, op)
+	if over := len(c.oplog) - c.config.OperationLogSize; over > 0 {
+		c.oplog = append(c.oplog[:0:0], c.oplog[over:]...)
+	}
+}
+
+// OperationsSince returns the operations applied through the client after
+// checkpoint, and the checkpoint to pass next time. Start with checkpoint 0.
+// It fails with ErrCheckpoint when operations after checkpoint were already
+// dropped from the log (see Config.OperationLogSize); the replica then has
+// to start over from a full copy.
+func (c *Client) OperationsSince(checkpoint uint64) ([]LoggedOperation, uint64, error) {
+	c.oplogMu.Lock()
+	defer c.oplogMu.Unlock()
+
+	if checkpoint > c.oplogSeq {
+		return nil, 0, fmt.Errorf("%w: %d is ahead of the log at %d", ErrCheckpoint, checkpoint, c.oplogSeq)
+	}
+	if len(c.oplog) > 0 && checkpoint+1 < c.oplog[0].Seq {
+		return nil, 0, fmt.Errorf("%w: operations from %d were dropped", ErrCheckpoint, checkpoint+1)
+	}
+
+	var ops []LoggedOperation
+	for _, op := range c.oplog {
+		if op.Seq > checkpoint {
+			op.Values = copyValues(op.Values)
+			ops = append(ops, op)
+		}
+	}
+	return ops, c.oplogSeq, nil
+}
+
+// ReplayOperations applies operations exported by another client with
+// OperationsSince, keeping their keys: added and updated records are stored
+// as logged, and deletes of missing records are ignored, so replaying an
+// overlapping range is harmless. Like Apply, every operation is checked
+// before the first is applied. Timestamp columns are not stamped again.
+func (c *Client) ReplayOperations(ops []LoggedOperation) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return fmt.Errorf("client is closed")
+	}
+
+	for _, op := range ops {
+		switch op.Op {
+		case OpAdd, OpUpdate:
+			if err := c.checkWrite(op.Key, op.Values); err != nil {
+				return fmt.Errorf("operation %d: %w", op.Seq, err)
+			}
+			if err := c.checkRecord(op.Key, op.Values, op.Op == OpUpdate); err != nil {
+				return fmt.Errorf("operation %d: %w", op.Seq, err)
+			}
+		case OpDelete:
+		default:
+			return fmt.Errorf("operation %d: unknown operation type %v", op.Seq, op.Op)
+		}
+	}
+
+	for _, op := range ops {
+		old := c.beforeMutation(op.Key)
+		if op.Op == OpDelete {
+			if err := c.cache.Delete(op.Key); err != nil {
+				if err == ErrKeyNotFound {
+					continue
+				}
+				return errors.Join(fmt.Errorf("operation %d: %w", op.Seq, err), c.writeThrough(context.Background()))
+			}
+		} else if err := c.cache.Set(op.Key, &Record{Key: op.Key, Values: copyValues(op.Values)}); err != nil {
+			return errors.Join(fmt.Errorf("operation %d: %w", op.Seq, err), c.writeThrough(context.Background()))
+		}
+		c.mutated(op.Key, old)
+	}
+	return c.writeThrough(context.Background())
+}
+
+// loggedLine is the JSON form of a LoggedOperation
+type loggedLine struct {
+	Seq    uint64          `json:"seq"`
+	Op     string          `json:"op"`
+	Key    int             `json:"key"`
+	Values json.RawMessage `json:"values,omitempty"`
+	Time   string          `json:"time"`
+}
+
+// WriteOperations writes operations to w as JSON Lines, one operation per
+// line, for ReadOperations on the other side
+func WriteOperations(w io.Writer, ops []LoggedOperation) error {
+	encoder := json.NewEncoder(w)
+	for _, op := range ops {
+		line := loggedLine{Seq: op.Seq, Op: op.Op.String(), Key: op.Key, Time: op.Time.Format(time.RFC3339Nano)}
+		if op.Values != nil {
+			values, err := json.Marshal(op.Values)
+			if err != nil {
+				return fmt.Errorf("failed to encode operation %d: %w", op.Seq, err)
+			}
+			line.Values = values
+		}
+		if err := encoder.Encode(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadOperations reads the operations written by WriteOperations. Numbers
+// are restored as int64 or float64 like the adapters do.
+func ReadOperations(r io.Reader) ([]LoggedOperation, error) {
+	var ops []LoggedOperation
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var line loggedLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, fmt.Errorf("failed to decode operation: %w", err)
+		}
+
+		opType, ok := parseOperationType(line.Op)
+		if !ok {
+			return nil, fmt.Errorf("operation %d: unknown operation type %q", line.Seq, line.Op)
+		}
+		op := LoggedOperation{Seq: line.Seq, Op: opType, Key: line.Key}
+		op.Time, _ = time.Parse(time.RFC3339Nano, line.Time)
+		if len(line.Values) > 0 {
+			values, err := decodeValues(string(line.Values))
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode values of operation %d: %w", line.Seq, err)
+			}
+			op.Values = values
+		}
+		ops = append(ops, op)
+	}
+	return ops, scanner.Err()
+}
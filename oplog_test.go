@@ -0,0 +1,88 @@
+package sheetkv_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_OperationLog(t *testing.T) {
+	ctx := context.Background()
+	primary := sheetkv.New(newMemoryAdapter([]string{"name", "age"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30)}},
+	), &sheetkv.Config{DisableAutoSync: true, OperationLogSize: 3})
+	defer primary.Close()
+	replica := sheetkv.New(newMemoryAdapter([]string{"name", "age"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30)}},
+	), &sheetkv.Config{DisableAutoSync: true})
+	defer replica.Close()
+	for _, client := range []*sheetkv.Client{primary, replica} {
+		if err := client.Initialize(ctx); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+	}
+
+	// replicate copies the operations after checkpoint through JSON Lines
+	replicate := func(checkpoint uint64) uint64 {
+		t.Helper()
+		ops, next, err := primary.OperationsSince(checkpoint)
+		if err != nil {
+			t.Fatalf("OperationsSince(%d) error = %v", checkpoint, err)
+		}
+		var buf bytes.Buffer
+		if err := sheetkv.WriteOperations(&buf, ops); err != nil {
+			t.Fatalf("WriteOperations() error = %v", err)
+		}
+		read, err := sheetkv.ReadOperations(&buf)
+		if err != nil {
+			t.Fatalf("ReadOperations() error = %v", err)
+		}
+		if err := replica.ReplayOperations(read); err != nil {
+			t.Fatalf("ReplayOperations() error = %v", err)
+		}
+		return next
+	}
+
+	primary.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "Jane", "age": int64(25)}})
+	primary.Update(2, map[string]interface{}{"age": 31.5})
+	checkpoint := replicate(0)
+	if checkpoint != 2 {
+		t.Errorf("checkpoint = %d, want 2", checkpoint)
+	}
+
+	primary.Delete(3)
+	if got := replicate(checkpoint); got != 3 {
+		t.Errorf("checkpoint = %d, want 3", got)
+	}
+	// Replaying an overlapping range changes nothing
+	replicate(0)
+
+	want, _ := primary.Query(sheetkv.Query{})
+	got, _ := replica.Query(sheetkv.Query{})
+	if len(got) != len(want) {
+		t.Fatalf("replica has %d records, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Key != want[i].Key || !reflect.DeepEqual(got[i].Values, want[i].Values) {
+			t.Errorf("replica record = %v, want %v", got[i], want[i])
+		}
+	}
+
+	t.Run("Dropped operations", func(t *testing.T) {
+		primary.Update(2, map[string]interface{}{"age": int64(32)})
+		primary.Update(2, map[string]interface{}{"age": int64(33)})
+		if _, _, err := primary.OperationsSince(0); !errors.Is(err, sheetkv.ErrCheckpoint) {
+			t.Errorf("OperationsSince(0) error = %v, want ErrCheckpoint", err)
+		}
+		if ops, next, err := primary.OperationsSince(3); err != nil || len(ops) != 2 || next != 5 {
+			t.Errorf("OperationsSince(3) = %d ops, %d, %v, want 2 ops up to 5", len(ops), next, err)
+		}
+		if _, _, err := primary.OperationsSince(6); !errors.Is(err, sheetkv.ErrCheckpoint) {
+			t.Errorf("OperationsSince(6) error = %v, want ErrCheckpoint", err)
+		}
+	})
+}
@@ -0,0 +1,269 @@
+package orm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// field maps a struct field to a column
+type field struct {
+	name   string
+	column string
+	index  []int // Index path for reflect.Value.FieldByIndex
+	typ    reflect.Type
+}
+
+// model is the column mapping of a struct type
+type model struct {
+	typ     reflect.Type
+	fields  []field
+	key     int // Position of the primary key in fields
+	columns []string
+}
+
+// newModel maps the exported fields of a struct type to columns.
+//
+// The column of a field is taken from its `sheetkv:"name"` tag, then from a
+// GORM `gorm:"column:name"` tag, and defaults to the snake-cased field name.
+// A "-" tag skips the field. The primary key is the field tagged
+// `sheetkv:",key"` or `gorm:"primaryKey"`, or the field named ID. Embedded
+// structs such as gorm.Model are flattened.
+func newModel(typ reflect.Type) (*model, error) {
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("model must be a struct, got %s", typ)
+	}
+
+	m := &model{typ: typ, key: -1}
+	named := -1
+	if err := m.collect(typ, nil, &named); err != nil {
+		return nil, err
+	}
+	if m.key < 0 {
+		m.key = named
+	}
+	if m.key < 0 {
+		return nil, fmt.Errorf("model %s has no primary key", typ)
+	}
+	return m, nil
+}
+
+// collect adds the fields of typ, recursing into embedded structs
+func (m *model) collect(typ reflect.Type, index []int, named *int) error {
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		path := append(append([]int(nil), index...), i)
+
+		column, key, skip := parseTags(sf)
+		if skip || !sf.IsExported() {
+			continue
+		}
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct && sf.Type != timeType {
+			if err := m.collect(sf.Type, path, named); err != nil {
+				return err
+			}
+			continue
+		}
+		if !supported(sf.Type) {
+			return fmt.Errorf("field %s has unsupported type %s", sf.Name, sf.Type)
+		}
+
+		if column == "" {
+			column = snakeCase(sf.Name)
+		}
+		for _, f := range m.fields {
+			if f.column == column {
+				return fmt.Errorf("fields %s and %s map to the same column %q", f.name, sf.Name, column)
+			}
+		}
+
+		if key {
+			if m.key >= 0 {
+				return fmt.Errorf("model %s has more than one primary key", m.typ)
+			}
+			m.key = len(m.fields)
+		}
+		if sf.Name == "ID" {
+			*named = len(m.fields)
+		}
+		m.fields = append(m.fields, field{name: sf.Name, column: column, index: path, typ: sf.Type})
+		m.columns = append(m.columns, column)
+	}
+	return nil
+}
+
+// parseTags reads the column name, primary key flag and skip flag of a field
+func parseTags(sf reflect.StructField) (column string, key, skip bool) {
+	if tag, ok := sf.Tag.Lookup("sheetkv"); ok {
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			return "", false, true
+		}
+		return name, options == "key", false
+	}
+
+	for _, option := range strings.Split(sf.Tag.Get("gorm"), ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(option), ":")
+		switch strings.ToLower(name) {
+		case "-":
+			skip = true
+		case "column":
+			column = value
+		case "primarykey", "primary_key":
+			key = true
+		}
+	}
+	return column, key, skip
+}
+
+// supported reports whether a field type can be stored in a cell
+func supported(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ == timeType {
+		return true
+	}
+	switch typ.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return typ.Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+// snakeCase converts a Go field name the way GORM names columns:
+// ID becomes id, UserID user_id and CreatedAt created_at
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) || nextLower) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// keyField returns the mapping of the primary key
+func (m *model) keyField() field {
+	return m.fields[m.key]
+}
+
+// keyOf returns the primary key of v as the string stored in the key column,
+// or "" when the key is the zero value
+func (m *model) keyOf(v reflect.Value) string {
+	value := v.FieldByIndex(m.keyField().index)
+	if value.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%v", value.Interface())
+}
+
+// values converts a struct to the values of a record
+func (m *model) values(v reflect.Value) map[string]interface{} {
+	values := make(map[string]interface{}, len(m.fields))
+	for _, f := range m.fields {
+		values[f.column] = cellValue(v.FieldByIndex(f.index))
+	}
+	return values
+}
+
+// cellValue converts a field to a cell value; nil pointers and zero times
+// become empty cells
+func cellValue(value reflect.Value) interface{} {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	if value.Type() == timeType {
+		t := value.Interface().(time.Time)
+		if t.IsZero() {
+			return nil
+		}
+		return t.Format(time.RFC3339)
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return value.String()
+	case reflect.Bool:
+		return value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		return value.Float()
+	case reflect.Slice:
+		return strings.Join(value.Interface().([]string), ",")
+	}
+	return nil
+}
+
+// load fills a struct from a record. Empty cells leave the zero value.
+func (m *model) load(record *sheetkv.Record, v reflect.Value) error {
+	for _, f := range m.fields {
+		raw, ok := record.Values[f.column]
+		if !ok || raw == nil || raw == "" {
+			continue
+		}
+
+		target := v.FieldByIndex(f.index)
+		if target.Kind() == reflect.Pointer {
+			target.Set(reflect.New(target.Type().Elem()))
+			target = target.Elem()
+		}
+		if err := setField(target, record, f.column); err != nil {
+			return fmt.Errorf("column %q: %w", f.column, err)
+		}
+	}
+	return nil
+}
+
+// setField converts the value of a column into a field
+func setField(target reflect.Value, record *sheetkv.Record, column string) error {
+	if target.Type() == timeType {
+		t := record.GetAsTime(column, time.Time{})
+		if t.IsZero() {
+			return fmt.Errorf("cannot parse %v as a time", record.Values[column])
+		}
+		target.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.String:
+		target.SetString(record.GetAsString(column, ""))
+	case reflect.Bool:
+		target.SetBool(record.GetAsBool(column, false))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		target.SetInt(record.GetAsInt64(column, 0))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		target.SetUint(uint64(record.GetAsInt64(column, 0)))
+	case reflect.Float32, reflect.Float64:
+		target.SetFloat(record.GetAsFloat64(column, 0))
+	case reflect.Slice:
+		target.Set(reflect.ValueOf(record.GetAsStrings(column, nil)))
+	}
+	return nil
+}
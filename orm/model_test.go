@@ -0,0 +1,95 @@
+package orm
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"ID":        "id",
+		"Name":      "name",
+		"UserID":    "user_id",
+		"CreatedAt": "created_at",
+		"HTTPPort":  "http_port",
+		"Price2Tax": "price2_tax",
+	}
+	for name, want := range tests {
+		if got := snakeCase(name); got != want {
+			t.Errorf("snakeCase(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestNewModel(t *testing.T) {
+	type Base struct {
+		ID        uint `gorm:"primaryKey"`
+		CreatedAt time.Time
+	}
+
+	t.Run("Tags and embedding", func(t *testing.T) {
+		type Product struct {
+			Base
+			Name     string   `gorm:"column:product_name;size:255"`
+			Price    float64  `sheetkv:"unit_price"`
+			Tags     []string `gorm:"type:text"`
+			Internal string   `gorm:"-"`
+			Skipped  string   `sheetkv:"-"`
+			hidden   string
+		}
+
+		m, err := newModel(reflect.TypeOf(Product{}))
+		if err != nil {
+			t.Fatalf("newModel() error = %v", err)
+		}
+		want := []string{"id", "created_at", "product_name", "unit_price", "tags"}
+		if !reflect.DeepEqual(m.columns, want) {
+			t.Errorf("columns = %v, want %v", m.columns, want)
+		}
+		if got := m.keyField().column; got != "id" {
+			t.Errorf("key column = %q, want id", got)
+		}
+	})
+
+	t.Run("Key tag", func(t *testing.T) {
+		type Flag struct {
+			Name    string `sheetkv:"flag,key"`
+			Enabled bool
+		}
+		m, err := newModel(reflect.TypeOf(Flag{}))
+		if err != nil {
+			t.Fatalf("newModel() error = %v", err)
+		}
+		if got := m.keyField().column; got != "flag" {
+			t.Errorf("key column = %q, want flag", got)
+		}
+	})
+
+	errorTests := []struct {
+		name  string
+		model interface{}
+	}{
+		{"No primary key", struct{ Name string }{}},
+		{"Two primary keys", struct {
+			A string `gorm:"primaryKey"`
+			B string `gorm:"primaryKey"`
+		}{}},
+		{"Duplicate column", struct {
+			ID   int
+			Name string
+			Alt  string `gorm:"column:name"`
+		}{}},
+		{"Unsupported type", struct {
+			ID    int
+			Attrs map[string]string
+		}{}},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newModel(reflect.TypeOf(tt.model)); err == nil {
+				t.Error("newModel() error = nil, want an error")
+			}
+		})
+	}
+}
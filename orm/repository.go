@@ -0,0 +1,269 @@
+// Package orm maps Go structs to the rows of a sheet, for low-volume tables
+// such as feature flags and price lists.
+//
+// Models follow GORM's conventions, so existing GORM models can usually be
+// pointed at a sheet unchanged: columns come from `gorm:"column:..."` tags
+// (or `sheetkv:"..."` tags) and default to the snake-cased field name, the
+// primary key is the ID field or the one tagged `gorm:"primaryKey"`, and the
+// GORM hook methods without the *gorm.DB argument are called around writes.
+//
+//	type Flag struct {
+//		ID      string
+//		Enabled bool
+//	}
+//
+//	flags, err := orm.Register[Flag](client)
+//	err = flags.Save(&Flag{ID: "new-ui", Enabled: true})
+//	flag, err := flags.Find("new-ui")
+package orm
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// BeforeSaver is implemented by models running code before Create and Save
+type BeforeSaver interface {
+	BeforeSave() error
+}
+
+// AfterSaver is implemented by models running code after Create and Save
+type AfterSaver interface {
+	AfterSave() error
+}
+
+// BeforeCreator is implemented by models running code before Create
+type BeforeCreator interface {
+	BeforeCreate() error
+}
+
+// AfterCreator is implemented by models running code after Create
+type AfterCreator interface {
+	AfterCreate() error
+}
+
+// BeforeDeleter is implemented by models running code before Delete
+type BeforeDeleter interface {
+	BeforeDelete() error
+}
+
+// AfterDeleter is implemented by models running code after Delete
+type AfterDeleter interface {
+	AfterDelete() error
+}
+
+// AfterFinder is implemented by models running code after being loaded
+type AfterFinder interface {
+	AfterFind() error
+}
+
+// Repository reads and writes the records of a client as values of type T,
+// addressed by their primary key
+type Repository[T any] struct {
+	keyed *sheetkv.KeyedClient
+	model *model
+	mu    sync.Mutex // Serializes writes so generated keys stay unique
+}
+
+// Register maps the struct type T to the sheet of client
+func Register[T any](client *sheetkv.Client) (*Repository[T], error) {
+	m, err := newModel(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+	return &Repository[T]{keyed: sheetkv.NewKeyed(client, m.keyField().column), model: m}, nil
+}
+
+// Columns returns the columns of T, for example to declare them with
+// Config.Columns and Config.StrictSchema
+func Columns[T any]() ([]string, error) {
+	m, err := newModel(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+	return m.columns, nil
+}
+
+// Keyed returns the underlying client addressed by primary key
+func (r *Repository[T]) Keyed() *sheetkv.KeyedClient {
+	return r.keyed
+}
+
+// Find returns the value stored under a primary key
+func (r *Repository[T]) Find(key interface{}) (*T, error) {
+	record, err := r.keyed.Get(fmt.Sprintf("%v", key))
+	if err != nil {
+		return nil, err
+	}
+	return r.decode(record)
+}
+
+// All returns every value in row order
+func (r *Repository[T]) All() ([]*T, error) {
+	return r.Where(sheetkv.Query{})
+}
+
+// Where returns the values of the records matching a query, in row order
+// unless the query has an OrderBy or a SortFunc
+func (r *Repository[T]) Where(query sheetkv.Query) ([]*T, error) {
+	if query.SortFunc == nil && len(query.OrderBy) == 0 {
+		query.SortFunc = func(a, b *sheetkv.Record) bool { return a.Key < b.Key }
+	}
+	records, err := r.keyed.Query(query)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]*T, 0, len(records))
+	for _, record := range records {
+		v, err := r.decode(record)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// Create stores a new value. An integer primary key left at zero is set to
+// the largest existing key plus one; an existing key fails with
+// sheetkv.ErrDuplicateKey.
+func (r *Repository[T]) Create(v *T) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.assignKey(v); err != nil {
+		return err
+	}
+	key := r.model.keyOf(reflect.ValueOf(v).Elem())
+	if _, err := r.keyed.Get(key); err == nil {
+		return fmt.Errorf("%w: %s %q", sheetkv.ErrDuplicateKey, r.keyed.Column(), key)
+	} else if err != sheetkv.ErrKeyNotFound {
+		return err
+	}
+
+	if hook, ok := any(v).(BeforeCreator); ok {
+		if err := hook.BeforeCreate(); err != nil {
+			return err
+		}
+	}
+	if err := r.write(v); err != nil {
+		return err
+	}
+	if hook, ok := any(v).(AfterCreator); ok {
+		return hook.AfterCreate()
+	}
+	return nil
+}
+
+// Save stores a value, replacing the one with the same primary key.
+// Like Create, a zero integer key is generated.
+func (r *Repository[T]) Save(v *T) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.assignKey(v); err != nil {
+		return err
+	}
+	return r.write(v)
+}
+
+// write stores a value between the save hooks
+func (r *Repository[T]) write(v *T) error {
+	if hook, ok := any(v).(BeforeSaver); ok {
+		if err := hook.BeforeSave(); err != nil {
+			return err
+		}
+	}
+
+	value := reflect.ValueOf(v).Elem()
+	key := r.model.keyOf(value)
+	if key == "" {
+		return fmt.Errorf("primary key %s must be set", r.model.keyField().name)
+	}
+	if err := r.keyed.Set(key, &sheetkv.Record{Values: r.model.values(value)}); err != nil {
+		return err
+	}
+
+	if hook, ok := any(v).(AfterSaver); ok {
+		return hook.AfterSave()
+	}
+	return nil
+}
+
+// Delete removes the value with the primary key of v
+func (r *Repository[T]) Delete(v *T) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if hook, ok := any(v).(BeforeDeleter); ok {
+		if err := hook.BeforeDelete(); err != nil {
+			return err
+		}
+	}
+
+	key := r.model.keyOf(reflect.ValueOf(v).Elem())
+	if key == "" {
+		return fmt.Errorf("primary key %s must be set", r.model.keyField().name)
+	}
+	if err := r.keyed.Delete(key); err != nil {
+		return err
+	}
+
+	if hook, ok := any(v).(AfterDeleter); ok {
+		return hook.AfterDelete()
+	}
+	return nil
+}
+
+// decode converts a record into a new value and runs AfterFind
+func (r *Repository[T]) decode(record *sheetkv.Record) (*T, error) {
+	v := new(T)
+	if err := r.model.load(record, reflect.ValueOf(v).Elem()); err != nil {
+		return nil, fmt.Errorf("row %d: %w", record.Key, err)
+	}
+	if hook, ok := any(v).(AfterFinder); ok {
+		if err := hook.AfterFind(); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// assignKey sets a zero integer primary key to the largest key plus one
+func (r *Repository[T]) assignKey(v *T) error {
+	f := r.model.keyField()
+	target := reflect.ValueOf(v).Elem().FieldByIndex(f.index)
+	if !target.IsZero() {
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return nil
+	}
+
+	keys, err := r.keyed.Keys()
+	if err != nil {
+		return err
+	}
+	var next int64 = 1
+	for _, key := range keys {
+		if n, err := strconv.ParseInt(key, 10, 64); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+
+	if target.CanInt() {
+		target.SetInt(next)
+	} else {
+		target.SetUint(uint64(next))
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package orm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+	"github.com/ideamans/go-sheetkv/orm"
+	"github.com/ideamans/go-sheetkv/tests/common"
+)
+
+type Price struct {
+	ID        uint `gorm:"primaryKey"`
+	Product   string
+	Amount    float64
+	Tags      []string
+	UpdatedAt time.Time
+	Note      *string
+
+	events []string `gorm:"-"`
+}
+
+func (p *Price) BeforeSave() error {
+	if p.Amount < 0 {
+		return errors.New("amount must not be negative")
+	}
+	p.events = append(p.events, "before save")
+	return nil
+}
+
+func (p *Price) AfterSave() error {
+	p.events = append(p.events, "after save")
+	return nil
+}
+
+func (p *Price) BeforeCreate() error {
+	p.events = append(p.events, "before create")
+	return nil
+}
+
+func (p *Price) AfterFind() error {
+	p.events = append(p.events, "after find")
+	return nil
+}
+
+func newRepository(t *testing.T) (*orm.Repository[Price], *sheetkv.Client) {
+	t.Helper()
+
+	adapter := &common.StaticAdapter{
+		Schema: []string{"id", "product", "amount", "tags", "updated_at", "note"},
+		Records: []*sheetkv.Record{
+			{Key: 2, Values: map[string]interface{}{"id": int64(1), "product": "Coffee", "amount": 3.5, "tags": "hot,drink", "updated_at": "2024-05-01T09:00:00Z"}},
+			{Key: 3, Values: map[string]interface{}{"id": int64(7), "product": "Tea", "amount": int64(3)}},
+		},
+	}
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	prices, err := orm.Register[Price](client)
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	return prices, client
+}
+
+func TestRepository_Find(t *testing.T) {
+	prices, _ := newRepository(t)
+
+	price, err := prices.Find(1)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if price.Product != "Coffee" || price.Amount != 3.5 {
+		t.Errorf("Find() = %+v, want Coffee at 3.5", price)
+	}
+	if len(price.Tags) != 2 || price.Tags[1] != "drink" {
+		t.Errorf("Tags = %v, want [hot drink]", price.Tags)
+	}
+	if want := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC); !price.UpdatedAt.Equal(want) {
+		t.Errorf("UpdatedAt = %v, want %v", price.UpdatedAt, want)
+	}
+	if price.Note != nil {
+		t.Errorf("Note = %v, want nil", *price.Note)
+	}
+
+	if _, err := prices.Find(99); !errors.Is(err, sheetkv.ErrKeyNotFound) {
+		t.Errorf("Find() error = %v, want ErrKeyNotFound", err)
+	}
+}
+
+func TestRepository_Create(t *testing.T) {
+	prices, client := newRepository(t)
+
+	price := &Price{Product: "Juice", Amount: 4}
+	if err := prices.Create(price); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if price.ID != 8 {
+		t.Errorf("ID = %d, want 8", price.ID)
+	}
+	if want := []string{"before create", "before save", "after save"}; len(price.events) != 3 || price.events[0] != want[0] || price.events[2] != want[2] {
+		t.Errorf("events = %v, want %v", price.events, want)
+	}
+
+	record, err := client.Get(4)
+	if err != nil {
+		t.Fatalf("client.Get() error = %v", err)
+	}
+	if record.GetAsString("product", "") != "Juice" || record.GetAsString("id", "") != "8" {
+		t.Errorf("record = %v, want Juice with id 8", record.Values)
+	}
+
+	if err := prices.Create(&Price{ID: 7, Product: "Cocoa"}); !errors.Is(err, sheetkv.ErrDuplicateKey) {
+		t.Errorf("Create() error = %v, want ErrDuplicateKey", err)
+	}
+}
+
+func TestRepository_Save(t *testing.T) {
+	prices, _ := newRepository(t)
+
+	price, err := prices.Find(7)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	note := "seasonal"
+	price.Amount = 3.8
+	price.Note = &note
+	if err := prices.Save(price); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	saved, err := prices.Find(7)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if saved.Amount != 3.8 || saved.Note == nil || *saved.Note != "seasonal" {
+		t.Errorf("Find() = %+v, want the saved amount and note", saved)
+	}
+
+	t.Run("Hook error", func(t *testing.T) {
+		if err := prices.Save(&Price{ID: 7, Amount: -1}); err == nil {
+			t.Error("Save() error = nil, want the BeforeSave error")
+		}
+		price, _ := prices.Find(7)
+		if price.Amount != 3.8 {
+			t.Errorf("Amount = %v, want 3.8 (unchanged)", price.Amount)
+		}
+	})
+}
+
+func TestRepository_DeleteAndWhere(t *testing.T) {
+	prices, _ := newRepository(t)
+
+	cheap, err := prices.Where(sheetkv.Query{Conditions: []sheetkv.Condition{{Column: "amount", Operator: "<", Value: 3.2}}})
+	if err != nil {
+		t.Fatalf("Where() error = %v", err)
+	}
+	if len(cheap) != 1 || cheap[0].Product != "Tea" {
+		t.Fatalf("Where() = %v, want Tea only", cheap)
+	}
+
+	if err := prices.Delete(cheap[0]); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	all, err := prices.All()
+	if err != nil {
+		t.Fatalf("All() error = %v", err)
+	}
+	if len(all) != 1 || all[0].Product != "Coffee" {
+		t.Errorf("All() = %v, want Coffee only", all)
+	}
+}
+
+func TestColumns(t *testing.T) {
+	columns, err := orm.Columns[Price]()
+	if err != nil {
+		t.Fatalf("Columns() error = %v", err)
+	}
+	want := []string{"id", "product", "amount", "tags", "updated_at", "note"}
+	if len(columns) != len(want) {
+		t.Fatalf("Columns() = %v, want %v", columns, want)
+	}
+	for i := range want {
+		if columns[i] != want[i] {
+			t.Errorf("Columns() = %v, want %v", columns, want)
+			break
+		}
+	}
+}
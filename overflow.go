@@ -0,0 +1,254 @@
+package sheetkv
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// DefaultOverflowThreshold is the length from which values are moved to the
+// overflow store, just under the 50,000 characters a Google Sheets cell holds
+const DefaultOverflowThreshold = 49000
+
+// overflowPrefix marks cells holding a reference to a value kept in the
+// overflow store (see Config.OverflowStore)
+const overflowPrefix = "ref:"
+
+// OverflowStore keeps values too large for a cell. Values are addressed by
+// a token derived from their content, so storing a value again is a no-op.
+type OverflowStore interface {
+	// Load returns the values of the given tokens; unknown tokens are omitted
+	Load(ctx context.Context, tokens []string) (map[string]string, error)
+
+	// Store saves the values of the given tokens
+	Store(ctx context.Context, values map[string]string) error
+}
+
+// overflowToken returns the token of a value
+func overflowToken(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// overflowThreshold returns the length from which values overflow
+func (c *Client) overflowThreshold() int {
+	if c.config.OverflowThreshold > 0 {
+		return c.config.OverflowThreshold
+	}
+	return DefaultOverflowThreshold
+}
+
+// overflowRecords moves the values of encoded records that are still too
+// long to the overflow store, replacing them by references. Encoded records
+// that are not copies of the originals are copied before being modified.
+func (c *Client) overflowRecords(ctx context.Context, encoded, originals []*Record) error {
+	pending := make(map[string]string)
+	threshold := c.overflowThreshold()
+	for i, record := range encoded {
+		for col, value := range record.Values {
+			s, ok := value.(string)
+			if !ok || len(s) < threshold {
+				continue
+			}
+			if encoded[i] == originals[i] {
+				encoded[i] = &Record{Key: record.Key, Revision: record.Revision, Values: copyValues(record.Values)}
+			}
+			token := overflowToken(s)
+			pending[token] = s
+			encoded[i].Values[col] = overflowPrefix + token
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	err := c.withRetry(ctx, func() error {
+		return c.config.OverflowStore.Store(ctx, pending)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store overflowed values: %w", err)
+	}
+	return nil
+}
+
+// resolveRecords replaces the references of loaded records by the values
+// kept in the overflow store. Unknown references are left as is.
+func (c *Client) resolveRecords(ctx context.Context, records []*Record) error {
+	var tokens []string
+	for _, record := range records {
+		for _, value := range record.Values {
+			if s, ok := value.(string); ok {
+				if token, found := strings.CutPrefix(s, overflowPrefix); found {
+					tokens = append(tokens, token)
+				}
+			}
+		}
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	var values map[string]string
+	err := c.withRetry(ctx, func() error {
+		var err error
+		values, err = c.config.OverflowStore.Load(ctx, tokens)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load overflowed values: %w", err)
+	}
+
+	for _, record := range records {
+		for col, value := range record.Values {
+			if s, ok := value.(string); ok {
+				if token, found := strings.CutPrefix(s, overflowPrefix); found {
+					if resolved, ok := values[token]; ok {
+						record.Values[col] = resolved
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// overflowChunkSize is the length of the parts a value is split into in the
+// rows of a TabOverflowStore
+const overflowChunkSize = 40000
+
+// overflowSchema is the schema of the tab of a TabOverflowStore
+var overflowSchema = []string{"token", "part", "data"}
+
+// TabOverflowStore is an OverflowStore keeping values in an auxiliary tab,
+// split into parts that fit in a cell: one row per part with the token, the
+// part number and the data. The tab is loaded once and new values are
+// appended.
+type TabOverflowStore struct {
+	adapter Adapter
+	mu      sync.Mutex
+	values  map[string]string // Nil until the tab is loaded
+}
+
+// NewTabOverflowStore creates a store using the tab of adapter
+func NewTabOverflowStore(adapter Adapter) *TabOverflowStore {
+	return &TabOverflowStore{adapter: adapter}
+}
+
+// load reads the tab on first use. Callers must hold s.mu.
+func (s *TabOverflowStore) load(ctx context.Context) error {
+	if s.values != nil {
+		return nil
+	}
+
+	records, _, err := s.adapter.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	parts := make(map[string]map[int]string)
+	for _, record := range records {
+		token := record.GetAsString("token", "")
+		if token == "" {
+			continue
+		}
+		if parts[token] == nil {
+			parts[token] = make(map[int]string)
+		}
+		parts[token][int(record.GetAsInt64("part", 0))] = record.GetAsString("data", "")
+	}
+
+	s.values = make(map[string]string, len(parts))
+	for token, byPart := range parts {
+		numbers := make([]int, 0, len(byPart))
+		for n := range byPart {
+			numbers = append(numbers, n)
+		}
+		sort.Ints(numbers)
+
+		var b strings.Builder
+		for _, n := range numbers {
+			b.WriteString(byPart[n])
+		}
+		s.values[token] = b.String()
+	}
+	return nil
+}
+
+// Load returns the values of the given tokens
+func (s *TabOverflowStore) Load(ctx context.Context, tokens []string) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(tokens))
+	for _, token := range tokens {
+		if value, ok := s.values[token]; ok {
+			values[token] = value
+		}
+	}
+	return values, nil
+}
+
+// Store appends the values not in the tab yet
+func (s *TabOverflowStore) Store(ctx context.Context, values map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(ctx); err != nil {
+		return err
+	}
+
+	tokens := make([]string, 0, len(values))
+	for token := range values {
+		if _, exists := s.values[token]; !exists {
+			tokens = append(tokens, token)
+		}
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+	sort.Strings(tokens)
+
+	var rows []*Record
+	for _, token := range tokens {
+		for part, data := range overflowChunks(values[token]) {
+			rows = append(rows, &Record{Values: map[string]interface{}{
+				"token": token,
+				"part":  int64(part),
+				"data":  data,
+			}})
+		}
+	}
+	if err := appendRecords(ctx, s.adapter, rows, overflowSchema); err != nil {
+		return err
+	}
+
+	for _, token := range tokens {
+		s.values[token] = values[token]
+	}
+	return nil
+}
+
+// overflowChunks splits value into parts of at most overflowChunkSize bytes,
+// at least one. Parts end on rune boundaries, as a sheet stores valid UTF-8
+// only.
+func overflowChunks(value string) []string {
+	var chunks []string
+	for len(value) > overflowChunkSize {
+		end := overflowChunkSize
+		for end > 0 && !utf8.RuneStart(value[end]) {
+			end--
+		}
+		chunks = append(chunks, value[:end])
+		value = value[end:]
+	}
+	return append(chunks, value)
+}
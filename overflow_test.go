@@ -0,0 +1,130 @@
+package sheetkv_test
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_OverflowStore(t *testing.T) {
+	ctx := context.Background()
+
+	// Random text hardly compresses, so it stays too long for a cell
+	data := make([]byte, 50000)
+	rand.Read(data)
+	body := hex.EncodeToString(data)
+
+	adapter := newMemoryAdapter([]string{"title", "body"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"title": "short", "body": "plain text"}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"title": "dangling", "body": "ref:unknown"}},
+	)
+	tab := newMemoryAdapter(nil)
+	config := &sheetkv.Config{
+		DisableAutoSync: true,
+		CompressColumns: []string{"body"},
+		OverflowStore:   sheetkv.NewTabOverflowStore(tab),
+	}
+	client := sheetkv.New(adapter, config)
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	t.Run("Overflowed on save", func(t *testing.T) {
+		client.Append(&sheetkv.Record{Values: map[string]interface{}{"title": "long", "body": body}})
+		if err := client.Sync(); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+
+		records, _, _ := adapter.Load(ctx)
+		for _, record := range records {
+			stored := record.GetAsString("body", "")
+			switch record.Key {
+			case 4:
+				if !strings.HasPrefix(stored, "ref:") {
+					t.Errorf("stored body = %.20q, want a reference", stored)
+				}
+			default:
+				if !strings.HasPrefix(stored, "gz:") {
+					t.Errorf("stored body of %d = %q, want compressed only", record.Key, stored)
+				}
+			}
+		}
+
+		parts, schema, _ := tab.Load(ctx)
+		if len(parts) < 2 || len(schema) != 3 {
+			t.Errorf("overflow tab = %d rows, schema %v, want the value split in parts", len(parts), schema)
+		}
+		for _, part := range parts {
+			if n := len(part.GetAsString("data", "")); n > 50000 {
+				t.Errorf("part of %d chars, want at most a cell", n)
+			}
+		}
+
+		record, _ := client.Get(4)
+		if got := record.GetAsString("body", ""); got != body {
+			t.Errorf("Get(4) body = %d chars, want the original %d", len(got), len(body))
+		}
+	})
+
+	t.Run("Stored once", func(t *testing.T) {
+		saves := tab.saveCount()
+		client.Update(2, map[string]interface{}{"title": "renamed"})
+		if err := client.Sync(); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+		if got := tab.saveCount(); got != saves {
+			t.Errorf("overflow tab saved %d times, want no new save", got-saves)
+		}
+	})
+
+	t.Run("Resolved on load", func(t *testing.T) {
+		reloaded := sheetkv.New(adapter, &sheetkv.Config{
+			DisableAutoSync: true,
+			CompressColumns: []string{"body"},
+			OverflowStore:   sheetkv.NewTabOverflowStore(tab),
+		})
+		if err := reloaded.Initialize(ctx); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		for key, want := range map[int]string{2: "plain text", 3: "ref:unknown", 4: body} {
+			record, err := reloaded.Get(key)
+			if err != nil || record.GetAsString("body", "") != want {
+				t.Errorf("Get(%d) body = %.20q, %v, want %.20q", key, record.GetAsString("body", ""), err, want)
+			}
+		}
+	})
+}
+
+func TestTabOverflowStore_MultiByte(t *testing.T) {
+	ctx := context.Background()
+	tab := newMemoryAdapter(nil)
+
+	// Three bytes per rune, so byte offsets of parts fall inside runes
+	value := strings.Repeat("日本語のテキスト", 5000)
+	if err := sheetkv.NewTabOverflowStore(tab).Store(ctx, map[string]string{"t": value}); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+
+	parts, _, _ := tab.Load(ctx)
+	if len(parts) < 2 {
+		t.Fatalf("overflow tab = %d rows, want the value split in parts", len(parts))
+	}
+	for _, part := range parts {
+		if data := part.GetAsString("data", ""); !utf8.ValidString(data) {
+			t.Errorf("part %v is not valid UTF-8", part.Values["part"])
+		}
+	}
+
+	values, err := sheetkv.NewTabOverflowStore(tab).Load(ctx, []string{"t"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if values["t"] != value {
+		t.Errorf("Load() = %d bytes, want the original %d", len(values["t"]), len(value))
+	}
+}
@@ -0,0 +1,165 @@
+package sheetkv
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DefaultPartitionSize is the number of records per partition used when
+// none is given. Google Sheets slows down well before its row limit.
+const DefaultPartitionSize = 10000
+
+// PartitionSource is implemented by backends that can spread one logical
+// table over several tabs (users_1, users_2, …)
+type PartitionSource interface {
+	// Partitions returns the number of existing partitions
+	Partitions(ctx context.Context) (int, error)
+
+	// CreatePartition creates partition i (0-based) when it does not exist
+	CreatePartition(ctx context.Context, i int) error
+
+	// Partition returns the adapter of partition i (0-based)
+	Partition(i int) Adapter
+}
+
+// PartitionedAdapter presents the partitions of a PartitionSource as a single
+// adapter. Each partition holds up to size records: logical key k lives in
+// partition (k-2)/size, so a client sees one sheet with one key space while
+// reads and writes are routed to the tabs. New partitions are created as
+// records spill over.
+//
+// A compacting save redistributes the records so every partition but the
+// last is full; a gap-preserving save keeps each record in its partition.
+type PartitionedAdapter struct {
+	source     PartitionSource
+	size       int
+	mu         sync.Mutex
+	partitions int // Partitions known to exist
+}
+
+// NewPartitionedAdapter creates an adapter spreading records over the
+// partitions of source, size records each (default: DefaultPartitionSize)
+func NewPartitionedAdapter(source PartitionSource, size int) *PartitionedAdapter {
+	if size <= 0 {
+		size = DefaultPartitionSize
+	}
+	return &PartitionedAdapter{source: source, size: size}
+}
+
+// partitionOf returns the partition and local key of a logical key
+func (a *PartitionedAdapter) partitionOf(key int) (int, int) {
+	partition := (key - 2) / a.size
+	return partition, key - partition*a.size
+}
+
+// Load retrieves the records of every partition, with logical keys, and the
+// union of their schemas
+func (a *PartitionedAdapter) Load(ctx context.Context) ([]*Record, []string, error) {
+	count, err := a.source.Partitions(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	records := make([]*Record, 0)
+	schema := make([]string, 0)
+	for i := 0; i < count; i++ {
+		loaded, partSchema, err := a.source.Partition(i).Load(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("partition %d: %w", i, err)
+		}
+		for _, record := range loaded {
+			if record.Key-2 >= a.size {
+				return nil, nil, fmt.Errorf("partition %d: row %d exceeds the partition size %d", i, record.Key, a.size)
+			}
+			records = append(records, &Record{Key: record.Key + i*a.size, Values: record.Values})
+		}
+		for _, col := range partSchema {
+			if !containsString(schema, col) {
+				schema = append(schema, col)
+			}
+		}
+	}
+
+	a.mu.Lock()
+	a.partitions = count
+	a.mu.Unlock()
+	return records, schema, nil
+}
+
+// Save replaces the data of every partition. Partitions left without
+// records are cleared rather than removed.
+func (a *PartitionedAdapter) Save(ctx context.Context, records []*Record, schema []string, strategy SyncStrategy) error {
+	sorted := make([]*Record, len(records))
+	copy(sorted, records)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Key < sorted[j].Key
+	})
+
+	// Route the records, with local keys
+	groups := make(map[int][]*Record)
+	needed := 0
+	for i, record := range sorted {
+		partition, key := a.partitionOf(record.Key)
+		if strategy == SyncStrategyCompacting {
+			partition, key = i/a.size, i%a.size+2
+		}
+		groups[partition] = append(groups[partition], &Record{Key: key, Values: record.Values})
+		needed = max(needed, partition+1)
+	}
+
+	count, err := a.ensurePartitions(ctx, needed)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < count; i++ {
+		if err := a.source.Partition(i).Save(ctx, groups[i], schema, strategy); err != nil {
+			return fmt.Errorf("partition %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// BatchUpdate routes each operation to the partition holding its key
+func (a *PartitionedAdapter) BatchUpdate(ctx context.Context, operations []Operation) error {
+	groups := make(map[int][]Operation)
+	var order []int
+	needed := 0
+	for _, op := range operations {
+		partition, key := a.partitionOf(op.Record.Key)
+		if _, ok := groups[partition]; !ok {
+			order = append(order, partition)
+		}
+		needed = max(needed, partition+1)
+		groups[partition] = append(groups[partition], Operation{
+			Type:   op.Type,
+			Record: &Record{Key: key, Values: op.Record.Values},
+		})
+	}
+
+	if _, err := a.ensurePartitions(ctx, needed); err != nil {
+		return err
+	}
+	for _, partition := range order {
+		if err := a.source.Partition(partition).BatchUpdate(ctx, groups[partition]); err != nil {
+			return fmt.Errorf("partition %d: %w", partition, err)
+		}
+	}
+	return nil
+}
+
+// ensurePartitions creates the partitions up to needed and returns the
+// number of partitions
+func (a *PartitionedAdapter) ensurePartitions(ctx context.Context, needed int) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for i := a.partitions; i < needed; i++ {
+		if err := a.source.CreatePartition(ctx, i); err != nil {
+			return 0, fmt.Errorf("partition %d: %w", i, err)
+		}
+		a.partitions = i + 1
+	}
+	return a.partitions, nil
+}
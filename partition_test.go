@@ -0,0 +1,119 @@
+package sheetkv_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// memoryPartitions is a PartitionSource made of memory adapters
+type memoryPartitions struct {
+	tabs []*memoryAdapter
+}
+
+func (p *memoryPartitions) Partitions(ctx context.Context) (int, error) {
+	return len(p.tabs), nil
+}
+
+func (p *memoryPartitions) CreatePartition(ctx context.Context, i int) error {
+	for len(p.tabs) <= i {
+		p.tabs = append(p.tabs, newMemoryAdapter(nil))
+	}
+	return nil
+}
+
+func (p *memoryPartitions) Partition(i int) sheetkv.Adapter {
+	return p.tabs[i]
+}
+
+// keys returns the keys and names stored in a tab
+func (p *memoryPartitions) keys(i int) []string {
+	records, _, _ := p.tabs[i].Load(context.Background())
+	keys := make([]string, 0, len(records))
+	for _, record := range records {
+		keys = append(keys, fmt.Sprintf("%d:%s", record.Key, record.GetAsString("name", "")))
+	}
+	return keys
+}
+
+func TestPartitionedAdapter(t *testing.T) {
+	ctx := context.Background()
+	source := &memoryPartitions{tabs: []*memoryAdapter{
+		newMemoryAdapter([]string{"name"},
+			&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "a"}},
+			&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "b"}},
+		),
+		newMemoryAdapter([]string{"name", "age"},
+			&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "c", "age": int64(30)}},
+		),
+	}}
+	adapter := sheetkv.NewPartitionedAdapter(source, 2)
+
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	t.Run("Combined view", func(t *testing.T) {
+		record, err := client.Get(4)
+		if err != nil || record.GetAsString("name", "") != "c" {
+			t.Errorf("Get(4) = %v, %v, want c", record, err)
+		}
+		results, err := client.Query(sheetkv.Query{})
+		if err != nil || len(results) != 3 {
+			t.Errorf("Query() = %d records, %v, want 3", len(results), err)
+		}
+		client.ReadTx(func(view sheetkv.ReadView) error {
+			if got := view.Schema(); len(got) != 2 {
+				t.Errorf("Schema() = %v, want [name age]", got)
+			}
+			return nil
+		})
+	})
+
+	t.Run("Spills over into new tabs", func(t *testing.T) {
+		for _, name := range []string{"d", "e"} {
+			if err := client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": name}}); err != nil {
+				t.Fatalf("Append() error = %v", err)
+			}
+		}
+		if err := client.Sync(); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+
+		if len(source.tabs) != 3 {
+			t.Fatalf("tabs = %d, want 3", len(source.tabs))
+		}
+		want := [][]string{{"2:a", "3:b"}, {"2:c", "3:d"}, {"2:e"}}
+		for i, keys := range want {
+			if got := source.keys(i); fmt.Sprint(got) != fmt.Sprint(keys) {
+				t.Errorf("tab %d = %v, want %v", i, got, keys)
+			}
+		}
+	})
+
+	t.Run("Compacting save redistributes", func(t *testing.T) {
+		client.Delete(3)
+		if err := client.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+
+		want := [][]string{{"2:a", "3:c"}, {"2:d", "3:e"}, {}}
+		for i, keys := range want {
+			if got := source.keys(i); fmt.Sprint(got) != fmt.Sprint(keys) {
+				t.Errorf("tab %d = %v, want %v", i, got, keys)
+			}
+		}
+	})
+
+	t.Run("Oversized tab", func(t *testing.T) {
+		source := &memoryPartitions{tabs: []*memoryAdapter{
+			newMemoryAdapter([]string{"name"}, &sheetkv.Record{Key: 4, Values: map[string]interface{}{"name": "x"}}),
+		}}
+		if _, _, err := sheetkv.NewPartitionedAdapter(source, 2).Load(ctx); err == nil {
+			t.Error("Load() error = nil, want row beyond the partition size")
+		}
+	})
+}
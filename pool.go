@@ -0,0 +1,41 @@
+package sheetkv
+
+import "sync"
+
+// recordPool recycles Record structs together with their Values maps.
+// Query-heavy workloads copy every matching record out of the cache, so
+// reusing the maps keeps allocation (and GC) pressure flat.
+var recordPool = sync.Pool{
+	New: func() interface{} {
+		return &Record{Values: make(map[string]interface{})}
+	},
+}
+
+// acquireRecord returns an empty record from the pool
+func acquireRecord() *Record {
+	record := recordPool.Get().(*Record)
+	if record.Values == nil {
+		record.Values = make(map[string]interface{})
+	}
+	return record
+}
+
+// releaseRecord clears a record and returns it to the pool
+func releaseRecord(record *Record) {
+	if record == nil {
+		return
+	}
+	record.Key = 0
+	record.Revision = 0
+	clear(record.Values)
+	recordPool.Put(record)
+}
+
+// ReleaseRecords returns records obtained from Get, Query or GetAllRecords
+// to the internal pool. It is optional: records that are never released are
+// simply garbage collected. Released records must not be used afterwards.
+func ReleaseRecords(records []*Record) {
+	for _, record := range records {
+		releaseRecord(record)
+	}
+}
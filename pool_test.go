@@ -0,0 +1,62 @@
+package sheetkv_test
+
+import (
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestReleaseRecords(t *testing.T) {
+	cache := sheetkv.NewCache()
+	for i := 2; i <= 101; i++ {
+		cache.Set(i, &sheetkv.Record{Values: map[string]interface{}{"n": i, "name": "row"}})
+	}
+
+	t.Run("Released record is cleared", func(t *testing.T) {
+		got, err := cache.Get(2)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		sheetkv.ReleaseRecords([]*sheetkv.Record{got})
+
+		if got.Key != 0 || len(got.Values) != 0 {
+			t.Errorf("released record = %+v, want zero value", got)
+		}
+	})
+
+	t.Run("Releasing query results does not affect cache", func(t *testing.T) {
+		query := sheetkv.Query{
+			Conditions: []sheetkv.Condition{
+				{Column: "n", Operator: "<=", Value: 51},
+			},
+		}
+
+		for round := 0; round < 3; round++ {
+			results, err := cache.Query(query)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if len(results) != 50 {
+				t.Fatalf("Query() returned %d records, want 50", len(results))
+			}
+			for _, r := range results {
+				if r.GetAsInt64("n", 0) != int64(r.Key) {
+					t.Errorf("record %d has n = %v", r.Key, r.Values["n"])
+				}
+			}
+			sheetkv.ReleaseRecords(results)
+		}
+
+		if cache.Size() != 100 {
+			t.Errorf("Size() = %v, want 100", cache.Size())
+		}
+		got, _ := cache.Get(50)
+		if got.Values["name"] != "row" {
+			t.Errorf("Get(50) name = %v, want row", got.Values["name"])
+		}
+	})
+
+	t.Run("Nil records are ignored", func(t *testing.T) {
+		sheetkv.ReleaseRecords([]*sheetkv.Record{nil})
+	})
+}
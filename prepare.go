@@ -0,0 +1,199 @@
+package sheetkv
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Param is a placeholder for a condition value supplied when a prepared
+// query is run. It may also appear inside the list of an "in" or "between"
+// condition.
+type Param string
+
+// PreparedQuery is a query validated and normalized once, to be run many
+// times with different parameters
+type PreparedQuery struct {
+	client *Client
+	query  Query
+	params []string   // Sorted names of the placeholders
+	plan   *indexPlan // Conditions answered by the secondary index
+}
+
+// Prepare validates query and normalizes its condition values so that runs
+// skip both steps. Numeric values are converted to float64 (as the
+// evaluator compares them) and [2]interface{} ranges to slices. The
+// conditions the secondary index answers (see Config.IndexColumns) are
+// resolved once too.
+func (c *Client) Prepare(query Query) (*PreparedQuery, error) {
+	if err := validateParamQuery(query); err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+
+	prepared := &PreparedQuery{client: c, query: query}
+	prepared.query.Conditions = make([]Condition, len(query.Conditions))
+
+	seen := make(map[string]bool)
+	for i, cond := range query.Conditions {
+		cond.Value = normalizeConditionValue(cond.Value)
+		for _, name := range conditionParams(cond.Value) {
+			if !seen[name] {
+				seen[name] = true
+				prepared.params = append(prepared.params, name)
+			}
+		}
+		prepared.query.Conditions[i] = cond
+	}
+	sort.Strings(prepared.params)
+	prepared.plan = c.cache.planIndex(prepared.query)
+
+	return prepared, nil
+}
+
+// validateParamQuery validates query like ValidateQuery. Placeholders
+// standing for a whole list are checked when bound; those of groups are
+// refused.
+func validateParamQuery(query Query) error {
+	for _, cond := range groupConditions(query.Groups) {
+		if len(conditionParams(cond.Value)) > 0 {
+			return fmt.Errorf("placeholders are not supported in condition groups")
+		}
+	}
+
+	stubbed := query
+	stubbed.Conditions = make([]Condition, len(query.Conditions))
+	for i, cond := range query.Conditions {
+		if _, ok := cond.Value.(Param); ok {
+			switch cond.Operator {
+			case "in":
+				cond.Value = []interface{}{}
+			case "between":
+				cond.Value = []interface{}{nil, nil}
+			}
+		}
+		stubbed.Conditions[i] = cond
+	}
+	return ValidateQuery(stubbed)
+}
+
+// Params returns the names of the placeholders, sorted
+func (p *PreparedQuery) Params() []string {
+	params := make([]string, len(p.params))
+	copy(params, p.params)
+	return params
+}
+
+// Run executes the query with the given placeholder values
+func (p *PreparedQuery) Run(params map[string]interface{}) ([]*Record, error) {
+	query, err := p.bind(params)
+	if err != nil {
+		return nil, err
+	}
+
+	p.client.mu.Lock()
+	defer p.client.mu.Unlock()
+
+	if p.client.closed {
+		return nil, fmt.Errorf("client is closed")
+	}
+	if err := p.client.readThroughTable(context.Background()); err != nil {
+		return nil, err
+	}
+
+	p.client.cache.mu.RLock()
+	defer p.client.cache.mu.RUnlock()
+	return p.client.cache.queryPlanned(context.Background(), query, p.plan)
+}
+
+// Explain returns the plan of the query. Placeholders must be bound as for Run.
+func (p *PreparedQuery) Explain(params map[string]interface{}) (*QueryPlan, error) {
+	query, err := p.bind(params)
+	if err != nil {
+		return nil, err
+	}
+	return p.client.Explain(query)
+}
+
+// bind substitutes the placeholders, validating only the bound conditions
+func (p *PreparedQuery) bind(params map[string]interface{}) (Query, error) {
+	if len(p.params) == 0 {
+		return p.query, nil
+	}
+
+	for _, name := range p.params {
+		if _, ok := params[name]; !ok {
+			return Query{}, fmt.Errorf("missing value for parameter %q", name)
+		}
+	}
+
+	query := p.query
+	query.Conditions = make([]Condition, len(p.query.Conditions))
+	for i, cond := range p.query.Conditions {
+		if len(conditionParams(cond.Value)) > 0 {
+			cond.Value = normalizeConditionValue(bindValue(cond.Value, params))
+			if err := ValidateQuery(Query{Conditions: []Condition{cond}}); err != nil {
+				return Query{}, fmt.Errorf("invalid parameter value: %w", err)
+			}
+		}
+		query.Conditions[i] = cond
+	}
+	return query, nil
+}
+
+// bindValue replaces placeholders in a condition value
+func bindValue(v interface{}, params map[string]interface{}) interface{} {
+	switch val := v.(type) {
+	case Param:
+		return params[string(val)]
+	case []interface{}:
+		bound := make([]interface{}, len(val))
+		for i, item := range val {
+			bound[i] = bindValue(item, params)
+		}
+		return bound
+	default:
+		return v
+	}
+}
+
+// conditionParams returns the placeholder names used by a condition value
+func conditionParams(v interface{}) []string {
+	switch val := v.(type) {
+	case Param:
+		return []string{string(val)}
+	case []interface{}:
+		var names []string
+		for _, item := range val {
+			names = append(names, conditionParams(item)...)
+		}
+		return names
+	default:
+		return nil
+	}
+}
+
+// normalizeConditionValue converts a condition value to the form the
+// evaluator works with
+func normalizeConditionValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case [2]interface{}:
+		return []interface{}{normalizeConditionValue(val[0]), normalizeConditionValue(val[1])}
+	case []interface{}:
+		normalized := make([]interface{}, len(val))
+		for i, item := range val {
+			normalized[i] = normalizeConditionValue(item)
+		}
+		return normalized
+	case Param:
+		return val
+	default:
+		// Only when the text form is unchanged, since non-numeric cells
+		// are compared by their text
+		if isNumeric(v) {
+			if f := toFloat64(v); fmt.Sprintf("%v", f) == fmt.Sprintf("%v", v) {
+				return f
+			}
+		}
+		return v
+	}
+}
@@ -0,0 +1,161 @@
+package sheetkv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_Prepare(t *testing.T) {
+	adapter := newMemoryAdapter([]string{"name", "dept", "age"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "dept": "Sales", "age": int64(30)}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane", "dept": "Dev", "age": int64(25)}},
+		&sheetkv.Record{Key: 4, Values: map[string]interface{}{"name": "Bob", "dept": "Sales", "age": int64(45)}},
+	)
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true, IndexColumns: []string{"dept"}})
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	t.Run("Invalid queries are rejected once", func(t *testing.T) {
+		_, err := client.Prepare(sheetkv.Query{Conditions: []sheetkv.Condition{{Column: "age", Operator: "~", Value: 1}}})
+		if err == nil {
+			t.Error("Prepare() should reject invalid operators")
+		}
+	})
+
+	t.Run("Without parameters", func(t *testing.T) {
+		prepared, err := client.Prepare(sheetkv.Query{Conditions: []sheetkv.Condition{
+			{Column: "age", Operator: "between", Value: [2]interface{}{20, 35}},
+		}})
+		if err != nil {
+			t.Fatalf("Prepare() error = %v", err)
+		}
+		results, err := prepared.Run(nil)
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if len(results) != 2 {
+			t.Errorf("Run() returned %d records, want 2", len(results))
+		}
+	})
+
+	t.Run("With parameters", func(t *testing.T) {
+		prepared, err := client.Prepare(sheetkv.Query{Conditions: []sheetkv.Condition{
+			{Column: "dept", Operator: "==", Value: sheetkv.Param("dept")},
+			{Column: "age", Operator: ">=", Value: sheetkv.Param("minAge")},
+		}})
+		if err != nil {
+			t.Fatalf("Prepare() error = %v", err)
+		}
+		if got := prepared.Params(); len(got) != 2 || got[0] != "dept" || got[1] != "minAge" {
+			t.Errorf("Params() = %v, want [dept minAge]", got)
+		}
+
+		for _, tt := range []struct {
+			dept   string
+			minAge int
+			want   int
+		}{
+			{"Sales", 0, 2},
+			{"Sales", 40, 1},
+			{"Dev", 30, 0},
+		} {
+			results, err := prepared.Run(map[string]interface{}{"dept": tt.dept, "minAge": tt.minAge})
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if len(results) != tt.want {
+				t.Errorf("Run(%s, %d) returned %d records, want %d", tt.dept, tt.minAge, len(results), tt.want)
+			}
+		}
+
+		plan, err := prepared.Explain(map[string]interface{}{"dept": "Sales", "minAge": 0})
+		if err != nil {
+			t.Fatalf("Explain() error = %v", err)
+		}
+		if plan.IndexColumn != "dept" {
+			t.Errorf("IndexColumn = %q, want dept", plan.IndexColumn)
+		}
+
+		if _, err := prepared.Run(map[string]interface{}{"dept": "Sales"}); err == nil {
+			t.Error("Run() should fail when a parameter is missing")
+		}
+	})
+
+	t.Run("Parameters inside lists are validated on bind", func(t *testing.T) {
+		prepared, err := client.Prepare(sheetkv.Query{Conditions: []sheetkv.Condition{
+			{Column: "name", Operator: "in", Value: []interface{}{"John", sheetkv.Param("other")}},
+		}})
+		if err != nil {
+			t.Fatalf("Prepare() error = %v", err)
+		}
+		results, err := prepared.Run(map[string]interface{}{"other": "Jane"})
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if len(results) != 2 {
+			t.Errorf("Run() returned %d records, want 2", len(results))
+		}
+
+		single, _ := client.Prepare(sheetkv.Query{Conditions: []sheetkv.Condition{
+			{Column: "name", Operator: "in", Value: sheetkv.Param("names")},
+		}})
+		if _, err := single.Run(map[string]interface{}{"names": "John"}); err == nil {
+			t.Error("Run() should reject a non-list value for in")
+		}
+	})
+
+	t.Run("Indexed conditions follow writes", func(t *testing.T) {
+		prepared, err := client.Prepare(sheetkv.Query{Conditions: []sheetkv.Condition{
+			{Column: "dept", Operator: "in", Value: sheetkv.Param("depts")},
+		}})
+		if err != nil {
+			t.Fatalf("Prepare() error = %v", err)
+		}
+		params := map[string]interface{}{"depts": []interface{}{"Dev"}}
+		if results, _ := prepared.Run(params); len(results) != 1 {
+			t.Fatalf("Run() returned %d records, want 1", len(results))
+		}
+
+		client.Update(2, map[string]interface{}{"dept": "Dev"})
+		defer client.Update(2, map[string]interface{}{"dept": "Sales"})
+		if results, _ := prepared.Run(params); len(results) != 2 {
+			t.Errorf("Run() after an update returned %d records, want 2", len(results))
+		}
+	})
+}
+
+func TestPreparedQuery_ReadThrough(t *testing.T) {
+	adapter := newMemoryAdapter([]string{"name", "dept"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "dept": "Sales"}},
+	)
+	clock := sheetkv.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true, ReadThroughTTL: time.Minute, Clock: clock})
+	defer client.Close()
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	prepared, err := client.Prepare(sheetkv.Query{Conditions: []sheetkv.Condition{
+		{Column: "dept", Operator: "==", Value: sheetkv.Param("dept")},
+	}})
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+
+	// Edit made outside the client
+	adapter.mu.Lock()
+	adapter.records = append(adapter.records, &sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane", "dept": "Sales"}})
+	adapter.mu.Unlock()
+
+	params := map[string]interface{}{"dept": "Sales"}
+	if results, _ := prepared.Run(params); len(results) != 1 {
+		t.Errorf("fresh Run() returned %d records, want the cached 1", len(results))
+	}
+	clock.Advance(2 * time.Minute)
+	if results, _ := prepared.Run(params); len(results) != 2 {
+		t.Errorf("stale Run() returned %d records, want the sheet's 2", len(results))
+	}
+}
@@ -0,0 +1,119 @@
+package sheetkv
+
+import (
+	"sync"
+	"time"
+)
+
+// ProgressPhase names the stage of a long operation
+type ProgressPhase string
+
+const (
+	PhaseLoad   ProgressPhase = "load"   // Reading the sheet, for Initialize and Reload
+	PhaseSave   ProgressPhase = "save"   // Writing the sheet, for Sync, Close and periodic syncs
+	PhaseImport ProgressPhase = "import" // Appending the records of Import, whose batches are saved in PhaseSave
+)
+
+// progressInterval is how often a running phase is reported
+const progressInterval = time.Second
+
+// Progress reports a phase of a long operation through Config.OnProgress.
+// A phase is reported when it starts, every second while it runs, and once
+// more with Done set when it ends, so a caller can tell a slow backend from
+// a hung one.
+type Progress struct {
+	Phase   ProgressPhase
+	Rows    int           // Rows processed so far
+	Total   int           // Rows of the phase, 0 while unknown (a load in progress, an import)
+	Bytes   int64         // Size of the processed values as text
+	Elapsed time.Duration // Time since the phase started
+	Done    bool          // The phase has ended, successfully or not
+}
+
+// progressTracker reports one phase, never calling OnProgress concurrently
+type progressTracker struct {
+	fn    func(Progress)
+	mu    sync.Mutex
+	p     Progress
+	start time.Time
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// trackProgress starts reporting phase, returning nil when the client has
+// no Config.OnProgress
+func (c *Client) trackProgress(phase ProgressPhase, total int) *progressTracker {
+	if c.config.OnProgress == nil {
+		return nil
+	}
+	t := &progressTracker{
+		fn:    c.config.OnProgress,
+		p:     Progress{Phase: phase, Total: total},
+		start: time.Now(),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	t.report()
+
+	go func() {
+		defer close(t.done)
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				t.report()
+			case <-t.stop:
+				return
+			}
+		}
+	}()
+	return t
+}
+
+// report calls OnProgress with the current state
+func (t *progressTracker) report() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.p.Elapsed = time.Since(t.start)
+	t.fn(t.p)
+}
+
+// add counts rows as processed
+func (t *progressTracker) add(rows []*Record) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.p.Rows += len(rows)
+	t.p.Bytes += recordBytes(rows)
+	t.mu.Unlock()
+}
+
+// finish stops the periodic reports and reports the end of the phase
+func (t *progressTracker) finish() {
+	if t == nil {
+		return
+	}
+	close(t.stop)
+	<-t.done
+
+	t.mu.Lock()
+	t.p.Done = true
+	if t.p.Total == 0 {
+		t.p.Total = t.p.Rows
+	}
+	t.mu.Unlock()
+	t.report()
+}
+
+// recordBytes returns the size of the values of records as text
+func recordBytes(records []*Record) int64 {
+	var n int64
+	for _, record := range records {
+		for col := range record.Values {
+			n += int64(len(cellText(record, col)))
+		}
+	}
+	return n
+}
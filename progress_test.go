@@ -0,0 +1,105 @@
+package sheetkv_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// slowAdapter takes delay to load
+type slowAdapter struct {
+	*memoryAdapter
+	delay time.Duration
+}
+
+func (a *slowAdapter) Load(ctx context.Context) ([]*sheetkv.Record, []string, error) {
+	time.Sleep(a.delay)
+	return a.memoryAdapter.Load(ctx)
+}
+
+// progressLog collects reported progress
+type progressLog struct {
+	mu      sync.Mutex
+	reports []sheetkv.Progress
+}
+
+func (l *progressLog) add(p sheetkv.Progress) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.reports = append(l.reports, p)
+}
+
+func (l *progressLog) phase(phase sheetkv.ProgressPhase) []sheetkv.Progress {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var reports []sheetkv.Progress
+	for _, p := range l.reports {
+		if p.Phase == phase {
+			reports = append(reports, p)
+		}
+	}
+	return reports
+}
+
+func TestClient_Progress(t *testing.T) {
+	data := newMemoryAdapter([]string{"name"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John"}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane"}},
+	)
+	log := &progressLog{}
+	client := sheetkv.New(data, &sheetkv.Config{DisableAutoSync: true, OnProgress: log.add})
+	defer client.Close()
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	load := log.phase(sheetkv.PhaseLoad)
+	if len(load) != 2 || load[0].Done || load[0].Total != 0 {
+		t.Fatalf("load reports = %+v, want a start and an end", load)
+	}
+	if end := load[1]; !end.Done || end.Rows != 2 || end.Total != 2 || end.Bytes != 8 {
+		t.Errorf("load end = %+v, want 2 rows of 8 bytes", end)
+	}
+
+	client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "Bob"}})
+	if err := client.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	save := log.phase(sheetkv.PhaseSave)
+	if len(save) != 2 || save[0].Total != 3 || save[0].Rows != 0 {
+		t.Fatalf("save reports = %+v, want a start of 3 rows and an end", save)
+	}
+	if end := save[1]; !end.Done || end.Rows != 3 || end.Bytes != 11 {
+		t.Errorf("save end = %+v, want 3 rows of 11 bytes", end)
+	}
+
+	if _, err := client.Import(context.Background(), sheetkv.SliceSource(importRecords(3)), sheetkv.ImportOptions{BatchSize: 2}); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	imported := log.phase(sheetkv.PhaseImport)
+	if end := imported[len(imported)-1]; !end.Done || end.Rows != 3 {
+		t.Errorf("import end = %+v, want 3 rows", end)
+	}
+	if saves := log.phase(sheetkv.PhaseSave); len(saves) != 6 {
+		t.Errorf("save reports = %d, want 6 with the two import batches", len(saves))
+	}
+}
+
+func TestClient_ProgressWhileRunning(t *testing.T) {
+	data := &slowAdapter{memoryAdapter: newMemoryAdapter([]string{"name"}), delay: 1200 * time.Millisecond}
+	log := &progressLog{}
+	client := sheetkv.New(data, &sheetkv.Config{DisableAutoSync: true, OnProgress: log.add})
+	defer client.Close()
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	// A start, at least one report while loading, and an end
+	load := log.phase(sheetkv.PhaseLoad)
+	if len(load) < 3 || load[1].Done || load[1].Elapsed < time.Second {
+		t.Errorf("load reports = %+v, want one while loading", load)
+	}
+}
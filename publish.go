@@ -0,0 +1,156 @@
+package sheetkv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DefaultPublishTopic is the topic of published change events unless
+// Config.PublishTopic is set
+const DefaultPublishTopic = "sheetkv.changes"
+
+// ChangeEventVersion is the version of the ChangeEvent envelope. It changes
+// only when a field changes meaning or is removed.
+const ChangeEventVersion = 1
+
+// Publisher delivers messages to a message bus such as Kafka or NATS. It
+// is implemented by a few lines around the client library of the bus.
+type Publisher interface {
+	// Publish sends one message to topic
+	Publish(ctx context.Context, topic string, message []byte) error
+}
+
+// ChangeEvent is the JSON envelope of a published change
+type ChangeEvent struct {
+	Version int                    `json:"version"`         // ChangeEventVersion
+	Op      string                 `json:"op"`              // "add", "update" or "delete"
+	Key     int                    `json:"key"`             // Key of the record
+	Columns []string               `json:"columns"`         // Columns whose values changed, sorted
+	Old     map[string]interface{} `json:"old,omitempty"`   // Values before the change, absent on add
+	New     map[string]interface{} `json:"new,omitempty"`   // Values after the change, absent on delete
+	Time    time.Time              `json:"time"`            // Time of the change
+	Actor   string                 `json:"actor,omitempty"` // Config.AuditActor
+}
+
+// queuePublish adds a mutation to the events published after the next sync
+func (c *Client) queuePublish(m mutation) {
+	event := ChangeEvent{
+		Version: ChangeEventVersion,
+		Op:      m.op.String(),
+		Key:     m.key,
+		Columns: m.columns,
+		Time:    m.time,
+		Actor:   c.config.AuditActor,
+	}
+	if m.old != nil {
+		event.Old = m.old.Values
+	}
+	if m.updated != nil {
+		event.New = m.updated.Values
+	}
+
+	c.publishMu.Lock()
+	defer c.publishMu.Unlock()
+	c.publishQueue = append(c.publishQueue, event)
+}
+
+// pendingPublish returns the number of events waiting to be published
+func (c *Client) pendingPublish() int {
+	c.publishMu.Lock()
+	defer c.publishMu.Unlock()
+	return len(c.publishQueue)
+}
+
+// flushPublish publishes the first n queued events in order. The event that
+// fails to be published and those after it stay queued for the next sync.
+func (c *Client) flushPublish(ctx context.Context, n int) error {
+	if c.config.Publisher == nil || n == 0 {
+		return nil
+	}
+
+	c.publishMu.Lock()
+	events := make([]ChangeEvent, n)
+	copy(events, c.publishQueue[:n])
+	c.publishMu.Unlock()
+
+	topic := c.config.PublishTopic
+	if topic == "" {
+		topic = DefaultPublishTopic
+	}
+
+	published := 0
+	var err error
+	for _, event := range events {
+		var message []byte
+		if message, err = json.Marshal(event); err != nil {
+			err = fmt.Errorf("failed to encode change event of key %d: %w", event.Key, err)
+			break
+		}
+		if err = c.withRetry(ctx, func() error {
+			return c.config.Publisher.Publish(ctx, topic, message)
+		}); err != nil {
+			err = fmt.Errorf("failed to publish change event: %w", err)
+			break
+		}
+		published++
+	}
+
+	c.publishMu.Lock()
+	c.publishQueue = c.publishQueue[published:]
+	c.publishMu.Unlock()
+	return err
+}
+
+// changeEventJSON is ChangeEvent with the values left undecoded
+type changeEventJSON struct {
+	ChangeEvent
+	Old json.RawMessage `json:"old,omitempty"`
+	New json.RawMessage `json:"new,omitempty"`
+}
+
+// DecodeChangeEvent parses a published change event, restoring numbers as
+// int64 or float64 like the adapters do
+func DecodeChangeEvent(message []byte) (*ChangeEvent, error) {
+	var raw changeEventJSON
+	if err := json.Unmarshal(message, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode change event: %w", err)
+	}
+	if raw.Version != ChangeEventVersion {
+		return nil, fmt.Errorf("unsupported change event version: %d", raw.Version)
+	}
+
+	event := raw.ChangeEvent
+	var err error
+	if len(raw.Old) > 0 {
+		if event.Old, err = decodeValues(string(raw.Old)); err != nil {
+			return nil, fmt.Errorf("failed to decode old values of key %d: %w", event.Key, err)
+		}
+	}
+	if len(raw.New) > 0 {
+		if event.New, err = decodeValues(string(raw.New)); err != nil {
+			return nil, fmt.Errorf("failed to decode new values of key %d: %w", event.Key, err)
+		}
+	}
+	return &event, nil
+}
+
+// ApplyChangeEvents applies the events published by another client, with
+// the semantics of ReplayOperations: records are stored as in the event
+// under the same key, and deletes of missing records are ignored
+func (c *Client) ApplyChangeEvents(events []*ChangeEvent) error {
+	ops := make([]LoggedOperation, 0, len(events))
+	for i, event := range events {
+		opType, ok := parseOperationType(event.Op)
+		if !ok {
+			return fmt.Errorf("event %d: unknown operation %q", i, event.Op)
+		}
+		op := LoggedOperation{Seq: uint64(i + 1), Op: opType, Key: event.Key, Time: event.Time}
+		if opType != OpDelete {
+			op.Values = event.New
+		}
+		ops = append(ops, op)
+	}
+	return c.ReplayOperations(ops)
+}
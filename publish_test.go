@@ -0,0 +1,196 @@
+package sheetkv_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// memoryPublisher collects published messages, failing while err is set
+type memoryPublisher struct {
+	mu       sync.Mutex
+	topics   []string
+	messages [][]byte
+	err      error
+}
+
+func (p *memoryPublisher) Publish(ctx context.Context, topic string, message []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	p.topics = append(p.topics, topic)
+	p.messages = append(p.messages, message)
+	return nil
+}
+
+func TestClient_Publisher(t *testing.T) {
+	data := newMemoryAdapter([]string{"name", "age"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30)}},
+	)
+	publisher := &memoryPublisher{err: errors.New("bus down")}
+	client := sheetkv.New(data, &sheetkv.Config{
+		DisableAutoSync: true,
+		Publisher:       publisher,
+		AuditActor:      "importer",
+	})
+	defer client.Close()
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	client.Update(2, map[string]interface{}{"age": int64(31)})
+	client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "Jane"}})
+	if len(publisher.messages) != 0 {
+		t.Fatalf("messages before sync = %d, want 0", len(publisher.messages))
+	}
+
+	// Events that fail to be published are kept for the next sync
+	if err := client.Sync(); err == nil {
+		t.Fatal("Sync() should fail while the bus is down")
+	}
+	publisher.mu.Lock()
+	publisher.err = nil
+	publisher.mu.Unlock()
+	client.Delete(2)
+	if err := client.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	if len(publisher.messages) != 3 {
+		t.Fatalf("messages = %d, want 3", len(publisher.messages))
+	}
+	if publisher.topics[0] != sheetkv.DefaultPublishTopic {
+		t.Errorf("topic = %q, want %q", publisher.topics[0], sheetkv.DefaultPublishTopic)
+	}
+
+	var events []map[string]interface{}
+	for _, message := range publisher.messages {
+		var event map[string]interface{}
+		if err := json.Unmarshal(message, &event); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		events = append(events, event)
+	}
+	want := []struct {
+		op  string
+		key float64
+	}{{"update", 2}, {"add", 3}, {"delete", 2}}
+	for i, w := range want {
+		if events[i]["op"] != w.op || events[i]["key"] != w.key || events[i]["version"] != float64(1) {
+			t.Errorf("event %d = %v, want %s of %v", i, events[i], w.op, w.key)
+		}
+	}
+	if old := events[0]["old"].(map[string]interface{}); old["age"] != float64(30) {
+		t.Errorf("old = %v, want age 30", old)
+	}
+	if _, ok := events[2]["new"]; ok || events[2]["actor"] != "importer" {
+		t.Errorf("delete event = %v, want no new values and the actor", events[2])
+	}
+}
+
+func TestClient_PublisherRollback(t *testing.T) {
+	data := newMemoryAdapter([]string{"name"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John"}},
+	)
+	publisher := &memoryPublisher{}
+	client := sheetkv.New(data, &sheetkv.Config{DisableAutoSync: true, Publisher: publisher, KeepSyncSnapshot: true})
+	defer client.Close()
+	ctx := context.Background()
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	// Changes that were rolled back are never published
+	client.Update(2, map[string]interface{}{"name": "Johnny"})
+	client.Delete(2)
+	if err := client.RollbackToLastSync(ctx, false); err != nil {
+		t.Fatalf("RollbackToLastSync() error = %v", err)
+	}
+	client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "Jane"}})
+	if err := client.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	if len(publisher.messages) != 1 {
+		t.Fatalf("messages = %d, want only the append", len(publisher.messages))
+	}
+	event, err := sheetkv.DecodeChangeEvent(publisher.messages[0])
+	if err != nil {
+		t.Fatalf("DecodeChangeEvent() error = %v", err)
+	}
+	if event.Op != "add" || event.Key != 3 {
+		t.Errorf("event = %+v, want the add of 3", event)
+	}
+}
+
+func TestApplyChangeEvents(t *testing.T) {
+	source := newMemoryAdapter([]string{"name", "age"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30)}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane", "age": int64(25)}},
+	)
+	publisher := &memoryPublisher{}
+	client := sheetkv.New(source, &sheetkv.Config{DisableAutoSync: true, Publisher: publisher})
+	defer client.Close()
+	replica := sheetkv.New(newMemoryAdapter([]string{"name", "age"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30)}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane", "age": int64(25)}},
+	), &sheetkv.Config{DisableAutoSync: true})
+	defer replica.Close()
+	for _, c := range []*sheetkv.Client{client, replica} {
+		if err := c.Initialize(context.Background()); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+	}
+
+	client.Update(2, map[string]interface{}{"age": int64(31)})
+	client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "Bob", "age": 1.5}})
+	client.Delete(3)
+	if err := client.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	var events []*sheetkv.ChangeEvent
+	for _, message := range publisher.messages {
+		event, err := sheetkv.DecodeChangeEvent(message)
+		if err != nil {
+			t.Fatalf("DecodeChangeEvent() error = %v", err)
+		}
+		events = append(events, event)
+	}
+	if events[0].Old["age"] != int64(30) {
+		t.Errorf("old age = %#v, want int64(30)", events[0].Old["age"])
+	}
+	if err := replica.ApplyChangeEvents(events); err != nil {
+		t.Fatalf("ApplyChangeEvents() error = %v", err)
+	}
+
+	if record, err := replica.Get(2); err != nil || record.Values["age"] != int64(31) {
+		t.Errorf("record 2 = %v, %v", record, err)
+	}
+	if record, err := replica.Get(4); err != nil || record.Values["age"] != 1.5 {
+		t.Errorf("record 4 = %v, %v", record, err)
+	}
+	if _, err := replica.Get(3); !errors.Is(err, sheetkv.ErrKeyNotFound) {
+		t.Errorf("record 3 error = %v, want ErrKeyNotFound", err)
+	}
+
+	// Applying the events again leaves the replica unchanged
+	if err := replica.ApplyChangeEvents(events); err != nil {
+		t.Errorf("second ApplyChangeEvents() error = %v", err)
+	}
+}
+
+func TestDecodeChangeEvent_Version(t *testing.T) {
+	if _, err := sheetkv.DecodeChangeEvent([]byte(`{"version":2,"op":"add","key":2}`)); err == nil {
+		t.Error("DecodeChangeEvent() should reject unknown versions")
+	}
+	if _, err := sheetkv.DecodeChangeEvent([]byte(`not json`)); err == nil {
+		t.Error("DecodeChangeEvent() should reject invalid JSON")
+	}
+}
@@ -0,0 +1,165 @@
+// Package pubsub carries the change events of a sheetkv client over Google
+// Cloud Pub/Sub. Publisher plugs into Config.Publisher, and Subscriber
+// applies the events of a subscription to another client.
+//
+//	publisher, _ := pubsub.NewPublisher(ctx, "my-project")
+//	client := sheetkv.New(adapter, &sheetkv.Config{Publisher: publisher})
+//
+//	subscriber, _ := pubsub.NewSubscriber(ctx, "my-project", "sheetkv-replica")
+//	subscriber.Receive(ctx, replica)
+package pubsub
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/ideamans/go-sheetkv"
+	"google.golang.org/api/option"
+	pubsubapi "google.golang.org/api/pubsub/v1"
+)
+
+// DefaultMaxMessages is the number of messages pulled at a time
+const DefaultMaxMessages = 100
+
+// Publisher publishes change events to Pub/Sub topics
+type Publisher struct {
+	service *pubsubapi.Service
+	project string
+
+	// OrderingKey is set on every message so that subscriptions with
+	// message ordering enabled receive the events in order. Empty means
+	// no ordering key.
+	OrderingKey string
+}
+
+// NewPublisher creates a publisher for the topics of the project
+func NewPublisher(ctx context.Context, project string, opts ...option.ClientOption) (*Publisher, error) {
+	if project == "" {
+		return nil, fmt.Errorf("project is required")
+	}
+	service, err := pubsubapi.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pubsub service: %w", err)
+	}
+	return &Publisher{service: service, project: project}, nil
+}
+
+// Publish implements sheetkv.Publisher. The topic is either a topic ID of
+// the project or a full "projects/.../topics/..." name.
+func (p *Publisher) Publish(ctx context.Context, topic string, message []byte) error {
+	name := resourceName(p.project, "topics", topic)
+	req := &pubsubapi.PublishRequest{
+		Messages: []*pubsubapi.PubsubMessage{{
+			Data:        base64.StdEncoding.EncodeToString(message),
+			OrderingKey: p.OrderingKey,
+		}},
+	}
+	if _, err := p.service.Projects.Topics.Publish(name, req).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", name, err)
+	}
+	return nil
+}
+
+// Subscriber applies the change events of a Pub/Sub subscription
+type Subscriber struct {
+	service      *pubsubapi.Service
+	subscription string
+
+	// MaxMessages is the number of messages pulled at a time, 0 means
+	// DefaultMaxMessages
+	MaxMessages int
+}
+
+// NewSubscriber creates a subscriber for the subscription, given as an ID
+// of the project or a full "projects/.../subscriptions/..." name
+func NewSubscriber(ctx context.Context, project, subscription string, opts ...option.ClientOption) (*Subscriber, error) {
+	if subscription == "" {
+		return nil, fmt.Errorf("subscription is required")
+	}
+	if project == "" && !strings.HasPrefix(subscription, "projects/") {
+		return nil, fmt.Errorf("project is required")
+	}
+	service, err := pubsubapi.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pubsub service: %w", err)
+	}
+	return &Subscriber{
+		service:      service,
+		subscription: resourceName(project, "subscriptions", subscription),
+	}, nil
+}
+
+// Receive pulls events and applies them to the client until ctx is done,
+// then returns nil. Messages are acknowledged once applied; when a batch
+// cannot be decoded or applied its messages are left for redelivery and
+// the error is returned, so subscriptions should set a dead-letter policy
+// for events that never apply.
+func (s *Subscriber) Receive(ctx context.Context, client *sheetkv.Client) error {
+	for {
+		if _, err := s.Pull(ctx, client); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// Pull pulls one batch of events, applies them to the client and
+// acknowledges them, returning the number of events applied
+func (s *Subscriber) Pull(ctx context.Context, client *sheetkv.Client) (int, error) {
+	maxMessages := s.MaxMessages
+	if maxMessages <= 0 {
+		maxMessages = DefaultMaxMessages
+	}
+	resp, err := s.service.Projects.Subscriptions.Pull(s.subscription, &pubsubapi.PullRequest{
+		MaxMessages: int64(maxMessages),
+	}).Context(ctx).Do()
+	if err != nil {
+		return 0, fmt.Errorf("failed to pull from %s: %w", s.subscription, err)
+	}
+	if len(resp.ReceivedMessages) == 0 {
+		return 0, nil
+	}
+
+	events := make([]*sheetkv.ChangeEvent, 0, len(resp.ReceivedMessages))
+	ackIDs := make([]string, 0, len(resp.ReceivedMessages))
+	for _, received := range resp.ReceivedMessages {
+		if received.Message == nil {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(received.Message.Data)
+		if err != nil {
+			return 0, fmt.Errorf("message %s: %w", received.Message.MessageId, err)
+		}
+		event, err := sheetkv.DecodeChangeEvent(data)
+		if err != nil {
+			return 0, fmt.Errorf("message %s: %w", received.Message.MessageId, err)
+		}
+		events = append(events, event)
+		ackIDs = append(ackIDs, received.AckId)
+	}
+
+	if err := client.ApplyChangeEvents(events); err != nil {
+		return 0, err
+	}
+	if _, err := s.service.Projects.Subscriptions.Acknowledge(s.subscription, &pubsubapi.AcknowledgeRequest{
+		AckIds: ackIDs,
+	}).Context(ctx).Do(); err != nil {
+		return len(events), fmt.Errorf("failed to acknowledge %d messages: %w", len(ackIDs), err)
+	}
+	return len(events), nil
+}
+
+// resourceName returns the full name of a topic or subscription
+func resourceName(project, kind, id string) string {
+	if strings.HasPrefix(id, "projects/") {
+		return id
+	}
+	return fmt.Sprintf("projects/%s/%s/%s", project, kind, id)
+}
@@ -0,0 +1,153 @@
+package pubsub_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+	"github.com/ideamans/go-sheetkv/pubsub"
+	"github.com/ideamans/go-sheetkv/tests/common"
+	"google.golang.org/api/option"
+	pubsubapi "google.golang.org/api/pubsub/v1"
+)
+
+// fakePubSub delivers the messages published to any topic to any
+// subscription, counting acknowledgements
+type fakePubSub struct {
+	mu       sync.Mutex
+	paths    []string
+	messages []*pubsubapi.PubsubMessage
+	acked    int
+}
+
+func (f *fakePubSub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.paths = append(f.paths, r.URL.Path)
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, ":publish"):
+		var req pubsubapi.PublishRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		f.messages = append(f.messages, req.Messages...)
+		json.NewEncoder(w).Encode(&pubsubapi.PublishResponse{MessageIds: []string{"1"}})
+	case strings.HasSuffix(r.URL.Path, ":pull"):
+		resp := &pubsubapi.PullResponse{}
+		for i, message := range f.messages[f.acked:] {
+			resp.ReceivedMessages = append(resp.ReceivedMessages, &pubsubapi.ReceivedMessage{
+				AckId:   string(rune('a' + i)),
+				Message: message,
+			})
+		}
+		json.NewEncoder(w).Encode(resp)
+	case strings.HasSuffix(r.URL.Path, ":acknowledge"):
+		var req pubsubapi.AcknowledgeRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		f.acked += len(req.AckIds)
+		w.Write([]byte("{}"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newClient(t *testing.T, config *sheetkv.Config, records ...*sheetkv.Record) *sheetkv.Client {
+	t.Helper()
+	client := sheetkv.New(&common.StaticAdapter{Schema: []string{"name", "age"}, Records: records}, config)
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestPublisherAndSubscriber(t *testing.T) {
+	fake := &fakePubSub{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	ctx := context.Background()
+	opts := []option.ClientOption{option.WithEndpoint(server.URL), option.WithoutAuthentication()}
+
+	publisher, err := pubsub.NewPublisher(ctx, "my-project", opts...)
+	if err != nil {
+		t.Fatalf("NewPublisher() error = %v", err)
+	}
+	john := &sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30)}}
+	source := newClient(t, &sheetkv.Config{Publisher: publisher, PublishTopic: "changes"}, john)
+	replica := newClient(t, &sheetkv.Config{}, john)
+
+	source.Update(2, map[string]interface{}{"age": int64(31)})
+	source.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "Jane", "age": int64(25)}})
+	if err := source.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if len(fake.messages) != 2 {
+		t.Fatalf("published messages = %d, want 2", len(fake.messages))
+	}
+	if fake.paths[0] != "/v1/projects/my-project/topics/changes:publish" {
+		t.Errorf("publish path = %q", fake.paths[0])
+	}
+
+	subscriber, err := pubsub.NewSubscriber(ctx, "my-project", "replica", opts...)
+	if err != nil {
+		t.Fatalf("NewSubscriber() error = %v", err)
+	}
+	n, err := subscriber.Pull(ctx, replica)
+	if err != nil {
+		t.Fatalf("Pull() error = %v", err)
+	}
+	if n != 2 || fake.acked != 2 {
+		t.Errorf("applied = %d, acked = %d, want 2 and 2", n, fake.acked)
+	}
+
+	record, err := replica.Get(2)
+	if err != nil || record.Values["age"] != int64(31) {
+		t.Errorf("replica record 2 = %v, %v", record, err)
+	}
+	record, err = replica.Get(3)
+	if err != nil || record.Values["name"] != "Jane" {
+		t.Errorf("replica record 3 = %v, %v", record, err)
+	}
+
+	// Nothing is left to pull
+	if n, err := subscriber.Pull(ctx, replica); err != nil || n != 0 {
+		t.Errorf("second Pull() = %d, %v, want 0", n, err)
+	}
+}
+
+func TestSubscriber_InvalidMessage(t *testing.T) {
+	fake := &fakePubSub{messages: []*pubsubapi.PubsubMessage{{MessageId: "1", Data: "bm90IGpzb24="}}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	ctx := context.Background()
+
+	subscriber, err := pubsub.NewSubscriber(ctx, "", "projects/other/subscriptions/replica",
+		option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewSubscriber() error = %v", err)
+	}
+	replica := newClient(t, &sheetkv.Config{})
+	if _, err := subscriber.Pull(ctx, replica); err == nil {
+		t.Fatal("Pull() should fail on a message that is not a change event")
+	}
+	if fake.acked != 0 {
+		t.Errorf("acked = %d, want 0", fake.acked)
+	}
+	if fake.paths[0] != "/v1/projects/other/subscriptions/replica:pull" {
+		t.Errorf("pull path = %q", fake.paths[0])
+	}
+}
+
+func TestNewSubscriber_Validation(t *testing.T) {
+	ctx := context.Background()
+	if _, err := pubsub.NewSubscriber(ctx, "", "replica", option.WithoutAuthentication()); err == nil {
+		t.Error("NewSubscriber() should require a project for a subscription ID")
+	}
+	if _, err := pubsub.NewPublisher(ctx, "", option.WithoutAuthentication()); err == nil {
+		t.Error("NewPublisher() should require a project")
+	}
+}
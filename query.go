@@ -1,7 +1,11 @@
 package sheetkv
 
 import (
+	"cmp"
+	"context"
 	"fmt"
+	"sort"
+	"strings"
 )
 
 // Condition represents a single query condition
@@ -11,38 +15,68 @@ type Condition struct {
 	Value    interface{} // 比較値（inの場合は[]interface{}, betweenの場合は[2]interface{}）
 }
 
+// Logic values of a ConditionGroup
+const (
+	LogicAnd = "and"
+	LogicOr  = "or"
+)
+
+// ConditionGroup combines conditions and nested groups with AND or OR, for
+// queries such as dept == "Sales" OR (age >= 30 AND active == true)
+type ConditionGroup struct {
+	Logic      string           // 結合方法: "and"（デフォルト）または "or"
+	Conditions []Condition      // グループの条件
+	Groups     []ConditionGroup // ネストしたグループ（条件と同様に結合）
+}
+
 // Query represents a query with multiple conditions
 type Query struct {
-	Conditions []Condition // AND条件として評価
+	Conditions []Condition      // AND条件として評価
+	Groups     []ConditionGroup // Conditionsとともに AND で評価するグループ（OR やネストした条件）
+	OrderBy    []SortKey        // 並び順（先頭のキーから比較、同順位はSortFuncまたはキーの昇順）、Limit/Offsetの前に適用
 	Limit      int
 	Offset     int
+	SortFunc   func(a, b *Record) bool // 並び順 (aがbより前ならtrue)、Limit/Offsetの前に適用
 }
 
-// evalCondition evaluates a single condition against a record
-func evalCondition(record *Record, condition Condition) bool {
+// SortKey orders query results by a column: numerically when both values
+// are numbers, by text otherwise (with the collation of the column, if
+// any), records without the column first
+type SortKey struct {
+	Column     string // カラム名
+	Descending bool   // 降順
+}
+
+// evalCondition evaluates a single condition against a record, comparing
+// strings as described by cmp (nil: byte comparison)
+func evalCondition(record *Record, condition Condition, cmp *comparison) bool {
 	value, exists := record.Values[condition.Column]
 	if !exists {
 		// カラムが存在しない場合、nullとして扱う
 		value = nil
 	}
+	if cmp.normalizes() {
+		value = normalizeValue(value)
+	}
+	coll := cmp.collation(condition.Column)
 
 	switch condition.Operator {
 	case "==":
-		return compareEqual(value, condition.Value)
+		return compareEqual(value, condition.Value, coll)
 	case "!=":
-		return !compareEqual(value, condition.Value)
+		return !compareEqual(value, condition.Value, coll)
 	case ">":
-		return compareGreater(value, condition.Value)
+		return compareGreater(value, condition.Value, coll)
 	case ">=":
-		return compareGreaterEqual(value, condition.Value)
+		return compareGreaterEqual(value, condition.Value, coll)
 	case "<":
-		return compareLess(value, condition.Value)
+		return compareLess(value, condition.Value, coll)
 	case "<=":
-		return compareLessEqual(value, condition.Value)
+		return compareLessEqual(value, condition.Value, coll)
 	case "in":
-		return compareIn(value, condition.Value)
+		return compareIn(value, condition.Value, coll)
 	case "between":
-		return compareBetween(value, condition.Value)
+		return compareBetween(value, condition.Value, coll)
 	default:
 		return false
 	}
@@ -50,17 +84,70 @@ func evalCondition(record *Record, condition Condition) bool {
 
 // MatchesQuery checks if a record matches all conditions in the query
 func (r *Record) MatchesQuery(query Query) bool {
+	return r.matchesQuery(query, nil)
+}
+
+// matchesQuery is MatchesQuery comparing strings as described by cmp
+func (r *Record) matchesQuery(query Query, cmp *comparison) bool {
 	// 全ての条件をANDで評価
 	for _, condition := range query.Conditions {
-		if !evalCondition(r, condition) {
+		if !evalCondition(r, condition, cmp) {
+			return false
+		}
+	}
+	for _, group := range query.Groups {
+		if !evalGroup(r, group, cmp) {
 			return false
 		}
 	}
 	return true
 }
 
+// evalGroup evaluates the conditions and nested groups of group against a
+// record. An empty group matches every record.
+func evalGroup(record *Record, group ConditionGroup, cmp *comparison) bool {
+	if group.Logic != LogicOr {
+		for _, condition := range group.Conditions {
+			if !evalCondition(record, condition, cmp) {
+				return false
+			}
+		}
+		for _, nested := range group.Groups {
+			if !evalGroup(record, nested, cmp) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if len(group.Conditions) == 0 && len(group.Groups) == 0 {
+		return true
+	}
+	for _, condition := range group.Conditions {
+		if evalCondition(record, condition, cmp) {
+			return true
+		}
+	}
+	for _, nested := range group.Groups {
+		if evalGroup(record, nested, cmp) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupConditions returns the conditions of groups and their nested groups
+func groupConditions(groups []ConditionGroup) []Condition {
+	var conditions []Condition
+	for _, group := range groups {
+		conditions = append(conditions, group.Conditions...)
+		conditions = append(conditions, groupConditions(group.Groups)...)
+	}
+	return conditions
+}
+
 // compareEqual compares two values for equality
-func compareEqual(a, b interface{}) bool {
+func compareEqual(a, b interface{}, coll *Collation) bool {
 	// 両方がnilの場合
 	if a == nil && b == nil {
 		return true
@@ -75,12 +162,20 @@ func compareEqual(a, b interface{}) bool {
 		return toFloat64(a) == toFloat64(b)
 	}
 
+	// 照合順序が指定されていれば言語の規則で比較
+	if sa, sb, ok := collatedStrings(coll, a, b); ok {
+		return coll.Compare(sa, sb) == 0
+	}
+
 	// その他は通常の比較
 	return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
 }
 
 // compareGreater compares if a > b
-func compareGreater(a, b interface{}) bool {
+func compareGreater(a, b interface{}, coll *Collation) bool {
+	if sa, sb, ok := collatedStrings(coll, a, b); ok {
+		return coll.Compare(sa, sb) > 0
+	}
 	if !isNumeric(a) || !isNumeric(b) {
 		return false
 	}
@@ -88,7 +183,10 @@ func compareGreater(a, b interface{}) bool {
 }
 
 // compareGreaterEqual compares if a >= b
-func compareGreaterEqual(a, b interface{}) bool {
+func compareGreaterEqual(a, b interface{}, coll *Collation) bool {
+	if sa, sb, ok := collatedStrings(coll, a, b); ok {
+		return coll.Compare(sa, sb) >= 0
+	}
 	if !isNumeric(a) || !isNumeric(b) {
 		return false
 	}
@@ -96,7 +194,10 @@ func compareGreaterEqual(a, b interface{}) bool {
 }
 
 // compareLess compares if a < b
-func compareLess(a, b interface{}) bool {
+func compareLess(a, b interface{}, coll *Collation) bool {
+	if sa, sb, ok := collatedStrings(coll, a, b); ok {
+		return coll.Compare(sa, sb) < 0
+	}
 	if !isNumeric(a) || !isNumeric(b) {
 		return false
 	}
@@ -104,7 +205,10 @@ func compareLess(a, b interface{}) bool {
 }
 
 // compareLessEqual compares if a <= b
-func compareLessEqual(a, b interface{}) bool {
+func compareLessEqual(a, b interface{}, coll *Collation) bool {
+	if sa, sb, ok := collatedStrings(coll, a, b); ok {
+		return coll.Compare(sa, sb) <= 0
+	}
 	if !isNumeric(a) || !isNumeric(b) {
 		return false
 	}
@@ -112,7 +216,7 @@ func compareLessEqual(a, b interface{}) bool {
 }
 
 // compareIn checks if a is in the list b
-func compareIn(a, b interface{}) bool {
+func compareIn(a, b interface{}, coll *Collation) bool {
 	// bは[]interface{}である必要がある
 	list, ok := b.([]interface{})
 	if !ok {
@@ -120,7 +224,7 @@ func compareIn(a, b interface{}) bool {
 	}
 
 	for _, item := range list {
-		if compareEqual(a, item) {
+		if compareEqual(a, item, coll) {
 			return true
 		}
 	}
@@ -128,7 +232,7 @@ func compareIn(a, b interface{}) bool {
 }
 
 // compareBetween checks if a is between b[0] and b[1]
-func compareBetween(a, b interface{}) bool {
+func compareBetween(a, b interface{}, coll *Collation) bool {
 	// bは[2]interface{}である必要がある
 	var min, max interface{}
 
@@ -144,6 +248,10 @@ func compareBetween(a, b interface{}) bool {
 		return false
 	}
 
+	if sa, smin, ok := collatedStrings(coll, a, min); ok {
+		_, smax, ok := collatedStrings(coll, a, max)
+		return ok && coll.Compare(sa, smin) >= 0 && coll.Compare(sa, smax) <= 0
+	}
 	if !isNumeric(a) || !isNumeric(min) || !isNumeric(max) {
 		return false
 	}
@@ -201,20 +309,64 @@ func toFloat64(v interface{}) float64 {
 
 // ApplyQuery filters records based on query conditions
 func ApplyQuery(records []*Record, query Query) []*Record {
+	results, _ := applyQuery(context.Background(), records, query, nil)
+	return results
+}
+
+// queryBatchSize is the number of records filtered between two checks of
+// the context in applyQuery
+const queryBatchSize = 1000
+
+// applyQuery filters, sorts and pages records like ApplyQuery, comparing
+// strings as described by cmp and returning ctx.Err() as soon as ctx
+// is done
+func applyQuery(ctx context.Context, records []*Record, query Query, cmp *comparison) ([]*Record, error) {
 	var results []*Record
+	if cmp.normalizes() {
+		query = normalizeQuery(query)
+	}
 
-	// フィルタリング
-	for _, record := range records {
-		if record.MatchesQuery(query) {
+	// フィルタリング (バッチごとにキャンセルを確認)
+	for i, record := range records {
+		if i%queryBatchSize == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		if record.matchesQuery(query, cmp) {
 			results = append(results, record)
 		}
 	}
 
+	// OrderBy適用 (同順位はSortFuncまたはキーの昇順)
+	if len(query.OrderBy) > 0 {
+		sort.SliceStable(results, func(i, j int) bool {
+			if order := compareSortKeys(results[i], results[j], query.OrderBy, cmp); order != 0 {
+				return order < 0
+			}
+			if query.SortFunc != nil {
+				return query.SortFunc(results[i], results[j])
+			}
+			return results[i].Key < results[j].Key
+		})
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	} else if query.SortFunc != nil {
+		// SortFunc適用 (同順位は元の順序を維持)
+		sort.SliceStable(results, func(i, j int) bool {
+			return query.SortFunc(results[i], results[j])
+		})
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+
 	// Offset適用
 	if query.Offset > 0 && query.Offset < len(results) {
 		results = results[query.Offset:]
 	} else if query.Offset >= len(results) {
-		return []*Record{}
+		return []*Record{}, nil
 	}
 
 	// Limit適用
@@ -222,51 +374,84 @@ func ApplyQuery(records []*Record, query Query) []*Record {
 		results = results[:query.Limit]
 	}
 
-	return results
+	return results, nil
 }
 
-// ValidateQuery validates query structure
-func ValidateQuery(query Query) error {
-	for i, cond := range query.Conditions {
-		// 演算子の検証
-		validOps := []string{"==", "!=", ">", ">=", "<", "<=", "in", "between"}
-		valid := false
-		for _, op := range validOps {
-			if cond.Operator == op {
-				valid = true
-				break
-			}
+// compareSortKeys returns -1, 0 or 1 depending on whether a sorts before,
+// with or after b by keys
+func compareSortKeys(a, b *Record, keys []SortKey, cmp *comparison) int {
+	for _, key := range keys {
+		order := compareSortValues(a.Values[key.Column], b.Values[key.Column], cmp.collation(key.Column))
+		if key.Descending {
+			order = -order
 		}
-		if !valid {
-			return fmt.Errorf("invalid operator '%s' in condition %d", cond.Operator, i)
+		if order != 0 {
+			return order
 		}
+	}
+	return 0
+}
 
-		// in演算子の値検証
-		if cond.Operator == "in" {
-			if _, ok := cond.Value.([]interface{}); !ok {
-				return fmt.Errorf("operator 'in' requires []interface{} value in condition %d", i)
-			}
+// compareSortValues compares two values of a sort key: nil first, then
+// numbers, text and booleans. Values are compared within their class only,
+// so columns mixing numbers and text still sort in a total order.
+func compareSortValues(a, b interface{}, coll *Collation) int {
+	if ac, bc := sortClass(a), sortClass(b); ac != bc {
+		return cmp.Compare(ac, bc)
+	}
+	switch {
+	case a == nil:
+		return 0
+	case isNumeric(a):
+		return cmp.Compare(toFloat64(a), toFloat64(b))
+	}
+	if ab, ok := a.(bool); ok {
+		bb := b.(bool)
+		switch {
+		case ab == bb:
+			return 0
+		case !ab:
+			return -1
 		}
+		return 1
+	}
+	as, bs := fmt.Sprintf("%v", a), fmt.Sprintf("%v", b)
+	if coll != nil {
+		return coll.Compare(as, bs)
+	}
+	return strings.Compare(as, bs)
+}
 
-		// between演算子の値検証
-		if cond.Operator == "between" {
-			valid := false
-			switch v := cond.Value.(type) {
-			case [2]interface{}:
-				valid = true
-			case []interface{}:
-				if len(v) == 2 {
-					valid = true
-				}
-			}
-			if !valid {
-				return fmt.Errorf("operator 'between' requires [2]interface{} or []interface{} with 2 elements in condition %d", i)
-			}
-		}
+// sortClass returns the rank of the class of a sort value: nil, numbers,
+// text (any other value, compared by its text) and booleans
+func sortClass(v interface{}) int {
+	switch {
+	case v == nil:
+		return 0
+	case isNumeric(v):
+		return 1
+	}
+	if _, ok := v.(bool); ok {
+		return 3
+	}
+	return 2
+}
 
-		// カラム名の検証
-		if cond.Column == "" {
-			return fmt.Errorf("empty column name in condition %d", i)
+// ValidateQuery validates query structure
+func ValidateQuery(query Query) error {
+	for i, cond := range query.Conditions {
+		if err := validateCondition(cond, i); err != nil {
+			return err
+		}
+	}
+	for i, group := range query.Groups {
+		if err := validateGroup(group); err != nil {
+			return fmt.Errorf("group %d: %w", i, err)
+		}
+	}
+	for i, key := range query.OrderBy {
+		if key.Column == "" {
+			return fmt.Errorf("empty column name in sort key %d", i)
 		}
 	}
 
@@ -280,3 +465,68 @@ func ValidateQuery(query Query) error {
 
 	return nil
 }
+
+// validateCondition validates the i-th condition of a query or group
+func validateCondition(cond Condition, i int) error {
+	// 演算子の検証
+	validOps := []string{"==", "!=", ">", ">=", "<", "<=", "in", "between"}
+	valid := false
+	for _, op := range validOps {
+		if cond.Operator == op {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid operator '%s' in condition %d", cond.Operator, i)
+	}
+
+	// in演算子の値検証
+	if cond.Operator == "in" {
+		if _, ok := cond.Value.([]interface{}); !ok {
+			return fmt.Errorf("operator 'in' requires []interface{} value in condition %d", i)
+		}
+	}
+
+	// between演算子の値検証
+	if cond.Operator == "between" {
+		valid := false
+		switch v := cond.Value.(type) {
+		case [2]interface{}:
+			valid = true
+		case []interface{}:
+			if len(v) == 2 {
+				valid = true
+			}
+		}
+		if !valid {
+			return fmt.Errorf("operator 'between' requires [2]interface{} or []interface{} with 2 elements in condition %d", i)
+		}
+	}
+
+	// カラム名の検証
+	if cond.Column == "" {
+		return fmt.Errorf("empty column name in condition %d", i)
+	}
+	return nil
+}
+
+// validateGroup validates the logic, conditions and nested groups of group
+func validateGroup(group ConditionGroup) error {
+	switch group.Logic {
+	case "", LogicAnd, LogicOr:
+	default:
+		return fmt.Errorf("invalid logic '%s'", group.Logic)
+	}
+	for i, cond := range group.Conditions {
+		if err := validateCondition(cond, i); err != nil {
+			return err
+		}
+	}
+	for i, nested := range group.Groups {
+		if err := validateGroup(nested); err != nil {
+			return fmt.Errorf("group %d: %w", i, err)
+		}
+	}
+	return nil
+}
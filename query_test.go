@@ -1,6 +1,10 @@
 package sheetkv_test
 
 import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/ideamans/go-sheetkv"
@@ -372,6 +376,29 @@ func TestApplyQuery(t *testing.T) {
 			},
 			want: []int{2, 3, 4},
 		},
+		{
+			name:    "sort func before offset and limit",
+			records: records,
+			query: sheetkv.Query{
+				SortFunc: func(a, b *sheetkv.Record) bool {
+					return a.GetAsInt64("age", 0) > b.GetAsInt64("age", 0)
+				},
+				Offset: 1,
+				Limit:  2,
+			},
+			want: []int{4, 3},
+		},
+		{
+			name:    "sort func with custom ranking keeps ties stable",
+			records: records,
+			query: sheetkv.Query{
+				SortFunc: func(a, b *sheetkv.Record) bool {
+					rank := map[string]int{"inactive": 0, "active": 1}
+					return rank[a.GetAsString("status", "")] < rank[b.GetAsString("status", "")]
+				},
+			},
+			want: []int{3, 6, 2, 4, 5},
+		},
 	}
 
 	for _, tt := range tests {
@@ -504,6 +531,25 @@ func TestValidateQuery(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "invalid logic of a group",
+			query: sheetkv.Query{
+				Groups: []sheetkv.ConditionGroup{{Logic: "xor"}},
+			},
+			wantErr: true,
+			errMsg:  "group 0: invalid logic 'xor'",
+		},
+		{
+			name: "invalid condition of a nested group",
+			query: sheetkv.Query{
+				Groups: []sheetkv.ConditionGroup{{
+					Logic:  sheetkv.LogicOr,
+					Groups: []sheetkv.ConditionGroup{{Conditions: []sheetkv.Condition{{Column: "age", Operator: "~", Value: 1}}}},
+				}},
+			},
+			wantErr: true,
+			errMsg:  "group 0: group 0: invalid operator",
+		},
 	}
 
 	for _, tt := range tests {
@@ -522,6 +568,156 @@ func TestValidateQuery(t *testing.T) {
 	}
 }
 
+func TestQuery_ConditionGroups(t *testing.T) {
+	records := []*sheetkv.Record{
+		{Key: 2, Values: map[string]interface{}{"dept": "Sales", "age": 25, "active": false}},
+		{Key: 3, Values: map[string]interface{}{"dept": "Eng", "age": 35, "active": true}},
+		{Key: 4, Values: map[string]interface{}{"dept": "Eng", "age": 35, "active": false}},
+		{Key: 5, Values: map[string]interface{}{"dept": "Eng", "age": 20, "active": true}},
+	}
+	// dept == "Sales" OR (age >= 30 AND active == true)
+	salesOrSenior := sheetkv.ConditionGroup{
+		Logic:      sheetkv.LogicOr,
+		Conditions: []sheetkv.Condition{{Column: "dept", Operator: "==", Value: "Sales"}},
+		Groups: []sheetkv.ConditionGroup{{Conditions: []sheetkv.Condition{
+			{Column: "age", Operator: ">=", Value: 30},
+			{Column: "active", Operator: "==", Value: true},
+		}}},
+	}
+
+	tests := []struct {
+		name  string
+		query sheetkv.Query
+		want  []int
+	}{
+		{"or with a nested and", sheetkv.Query{Groups: []sheetkv.ConditionGroup{salesOrSenior}}, []int{2, 3}},
+		{"anded with the conditions", sheetkv.Query{
+			Conditions: []sheetkv.Condition{{Column: "active", Operator: "==", Value: true}},
+			Groups:     []sheetkv.ConditionGroup{salesOrSenior},
+		}, []int{3}},
+		{"empty or group", sheetkv.Query{Groups: []sheetkv.ConditionGroup{{Logic: sheetkv.LogicOr}}}, []int{2, 3, 4, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys []int
+			for _, record := range sheetkv.ApplyQuery(records, tt.query) {
+				keys = append(keys, record.Key)
+			}
+			sort.Ints(keys)
+			if !reflect.DeepEqual(keys, tt.want) {
+				t.Errorf("ApplyQuery() keys = %v, want %v", keys, tt.want)
+			}
+		})
+	}
+
+	t.Run("Query cache", func(t *testing.T) {
+		client := sheetkv.New(newMemoryAdapter([]string{"dept", "age", "active"}, records...), &sheetkv.Config{
+			DisableAutoSync: true,
+			QueryCacheSize:  8,
+		})
+		if err := client.Initialize(context.Background()); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		defer client.Close()
+
+		query := sheetkv.Query{Groups: []sheetkv.ConditionGroup{salesOrSenior}}
+		if results, _ := client.Query(query); len(results) != 2 {
+			t.Fatalf("Query() = %v, want 2 records", results)
+		}
+		// Same conditions joined by and instead of or
+		anded := salesOrSenior
+		anded.Logic = sheetkv.LogicAnd
+		if results, _ := client.Query(sheetkv.Query{Groups: []sheetkv.ConditionGroup{anded}}); len(results) != 0 {
+			t.Errorf("Query(and) = %v, want none, not the cached or result", results)
+		}
+		// Writes to a column of a nested group invalidate the result
+		client.Update(5, map[string]interface{}{"age": 40})
+		if results, _ := client.Query(query); len(results) != 3 {
+			t.Errorf("Query() after Update = %v, want 3 records", results)
+		}
+	})
+}
+
+func TestQuery_OrderBy(t *testing.T) {
+	records := []*sheetkv.Record{
+		{Key: 2, Values: map[string]interface{}{"name": "Bob", "dept": "Sales", "age": int64(45)}},
+		{Key: 3, Values: map[string]interface{}{"name": "Jane", "dept": "Dev", "age": 25.0}},
+		{Key: 4, Values: map[string]interface{}{"name": "Amy", "dept": "Sales", "age": int64(38)}},
+		{Key: 5, Values: map[string]interface{}{"name": "Ken", "age": int64(38)}},
+		{Key: 6, Values: map[string]interface{}{"name": "Eve", "dept": "Dev", "age": int64(25)}},
+	}
+	names := func(results []*sheetkv.Record) []string {
+		var names []string
+		for _, record := range results {
+			names = append(names, record.Values["name"].(string))
+		}
+		return names
+	}
+
+	tests := []struct {
+		name  string
+		query sheetkv.Query
+		want  []string
+	}{
+		{"numbers of different types", sheetkv.Query{OrderBy: []sheetkv.SortKey{{Column: "age"}}}, []string{"Jane", "Eve", "Amy", "Ken", "Bob"}},
+		{"several keys, missing values first", sheetkv.Query{OrderBy: []sheetkv.SortKey{{Column: "dept"}, {Column: "age", Descending: true}}}, []string{"Ken", "Jane", "Eve", "Bob", "Amy"}},
+		{"pages", sheetkv.Query{OrderBy: []sheetkv.SortKey{{Column: "age", Descending: true}}, Offset: 1, Limit: 2}, []string{"Amy", "Ken"}},
+		{"ties broken by SortFunc", sheetkv.Query{
+			OrderBy:  []sheetkv.SortKey{{Column: "age"}},
+			SortFunc: func(a, b *sheetkv.Record) bool { return a.Key > b.Key },
+		}, []string{"Eve", "Jane", "Ken", "Amy", "Bob"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(sheetkv.ApplyQuery(records, tt.query)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("Mixed types sort by class", func(t *testing.T) {
+		var mixed []*sheetkv.Record
+		for i, value := range []interface{}{int64(10), "2", true, nil, 9.0, "abc"} {
+			record := &sheetkv.Record{Key: i + 2, Values: map[string]interface{}{"name": fmt.Sprint(value)}}
+			if value != nil {
+				record.Values["age"] = value
+			}
+			mixed = append(mixed, record)
+		}
+		want := []string{"<nil>", "9", "10", "2", "abc", "true"}
+		for i := 0; i < 3; i++ {
+			if got := names(sheetkv.ApplyQuery(mixed, sheetkv.Query{OrderBy: []sheetkv.SortKey{{Column: "age"}}})); !reflect.DeepEqual(got, want) {
+				t.Fatalf("ApplyQuery() = %v, want %v", got, want)
+			}
+			// The order does not depend on the input order
+			mixed = append(mixed[1:], mixed[0])
+		}
+	})
+
+	t.Run("Query cache", func(t *testing.T) {
+		client := sheetkv.New(newMemoryAdapter([]string{"name", "dept", "age"}, records...), &sheetkv.Config{
+			DisableAutoSync: true,
+			QueryCacheSize:  8,
+		})
+		if err := client.Initialize(context.Background()); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		defer client.Close()
+
+		query := sheetkv.Query{OrderBy: []sheetkv.SortKey{{Column: "age"}}, Limit: 2}
+		for i := 0; i < 2; i++ {
+			results, err := client.Query(query)
+			if got := names(results); err != nil || !reflect.DeepEqual(got, []string{"Jane", "Eve"}) {
+				t.Fatalf("Query() = %v, %v, want [Jane Eve]", got, err)
+			}
+		}
+		query.OrderBy[0].Descending = true
+		if got, _ := client.Query(query); !reflect.DeepEqual(names(got), []string{"Bob", "Amy"}) {
+			t.Errorf("Query(descending) = %v, want [Bob Amy], not the cached ascending result", names(got))
+		}
+	})
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && s[:len(substr)] == substr || len(s) > len(substr) && contains(s[1:], substr)
 }
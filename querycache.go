@@ -0,0 +1,162 @@
+package sheetkv
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// queryCache memoizes query results as lists of keys. Entries are dropped
+// as soon as a write could change their result.
+type queryCache struct {
+	mu      sync.Mutex // Queries run under the cache's read lock
+	size    int
+	entries map[string]*cachedQuery
+	order   []string // Insertion order, oldest first
+}
+
+// cachedQuery is the memoized result of one query
+type cachedQuery struct {
+	keys     []int
+	members  map[int]bool
+	columns  map[string]bool // Columns referenced by the conditions
+	windowed bool            // Limit or Offset set, so any write may shift the result
+}
+
+func newQueryCache(size int) *queryCache {
+	return &queryCache{size: size, entries: make(map[string]*cachedQuery)}
+}
+
+// queryCacheKey normalizes a query into a cache key. Values keep their type
+// so that 1 and "1" are cached separately.
+func queryCacheKey(query Query) string {
+	var b strings.Builder
+	for _, cond := range query.Conditions {
+		fmt.Fprintf(&b, "%s\x00%s\x00%T\x00%v\x01", cond.Column, cond.Operator, cond.Value, cond.Value)
+	}
+	writeGroupKeys(&b, query.Groups)
+	for _, key := range query.OrderBy {
+		fmt.Fprintf(&b, "order=%s\x00%t\x01", key.Column, key.Descending)
+	}
+	fmt.Fprintf(&b, "limit=%d offset=%d", query.Limit, query.Offset)
+	return b.String()
+}
+
+// writeGroupKeys writes the logic and conditions of groups, nested ones
+// between parentheses
+func writeGroupKeys(b *strings.Builder, groups []ConditionGroup) {
+	for _, group := range groups {
+		fmt.Fprintf(b, "(%s\x01", group.Logic)
+		for _, cond := range group.Conditions {
+			fmt.Fprintf(b, "%s\x00%s\x00%T\x00%v\x01", cond.Column, cond.Operator, cond.Value, cond.Value)
+		}
+		writeGroupKeys(b, group.Groups)
+		b.WriteString(")\x01")
+	}
+}
+
+// get returns the cached keys of a query
+func (qc *queryCache) get(query Query) ([]int, bool) {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	entry, ok := qc.entries[queryCacheKey(query)]
+	if !ok {
+		return nil, false
+	}
+	return entry.keys, true
+}
+
+// put stores the result of a query, evicting the oldest entry when full
+func (qc *queryCache) put(query Query, results []*Record) {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	key := queryCacheKey(query)
+	if _, exists := qc.entries[key]; !exists {
+		if len(qc.order) >= qc.size {
+			delete(qc.entries, qc.order[0])
+			qc.order = qc.order[1:]
+		}
+		qc.order = append(qc.order, key)
+	}
+
+	entry := &cachedQuery{
+		keys:     make([]int, len(results)),
+		members:  make(map[int]bool, len(results)),
+		columns:  make(map[string]bool),
+		windowed: query.Limit > 0 || query.Offset > 0,
+	}
+	for i, record := range results {
+		entry.keys[i] = record.Key
+		entry.members[record.Key] = true
+	}
+	for _, cond := range append(groupConditions(query.Groups), query.Conditions...) {
+		entry.columns[cond.Column] = true
+	}
+	for _, key := range query.OrderBy {
+		entry.columns[key.Column] = true
+	}
+	qc.entries[key] = entry
+}
+
+// invalidate drops the entries whose result may change when the record at
+// key changes from old to updated (either may be nil)
+func (qc *queryCache) invalidate(key int, old, updated *Record) {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	if len(qc.entries) == 0 {
+		return
+	}
+
+	// A new record may match any query, even through missing columns
+	if old == nil {
+		qc.clearLocked()
+		return
+	}
+
+	// A deleted record only affects the results it was part of
+	var changed []string
+	if updated != nil {
+		changed = changedColumns(old, updated)
+	}
+	for k, entry := range qc.entries {
+		if entry.windowed || entry.members[key] || touchesAny(entry.columns, changed) {
+			qc.remove(k)
+		}
+	}
+}
+
+// remove drops a single entry
+func (qc *queryCache) remove(key string) {
+	delete(qc.entries, key)
+	for i, k := range qc.order {
+		if k == key {
+			qc.order = append(qc.order[:i], qc.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// reset drops every entry
+func (qc *queryCache) reset() {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+	qc.clearLocked()
+}
+
+func (qc *queryCache) clearLocked() {
+	clear(qc.entries)
+	qc.order = qc.order[:0]
+}
+
+// touchesAny reports whether any of the columns is in set
+func touchesAny(set map[string]bool, columns []string) bool {
+	for _, col := range columns {
+		if set[col] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package sheetkv_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestCache_QueryCache(t *testing.T) {
+	setup := func() *sheetkv.Cache {
+		cache := sheetkv.NewCache()
+		cache.SetQueryCacheSize(2)
+		cache.Load([]*sheetkv.Record{
+			{Key: 2, Values: map[string]interface{}{"name": "John", "status": "active"}},
+			{Key: 3, Values: map[string]interface{}{"name": "Jane", "status": "inactive"}},
+			{Key: 4, Values: map[string]interface{}{"name": "Bob", "status": "active"}},
+		}, []string{"name", "status"})
+		return cache
+	}
+	active := sheetkv.Query{Conditions: []sheetkv.Condition{{Column: "status", Operator: "==", Value: "active"}}}
+
+	names := func(t *testing.T, cache *sheetkv.Cache, query sheetkv.Query) []string {
+		t.Helper()
+		results, err := cache.Query(query)
+		if err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+		var got []string
+		for _, r := range results {
+			got = append(got, r.Values["name"].(string))
+		}
+		sort.Strings(got)
+		return got
+	}
+	assertNames := func(t *testing.T, got []string, want ...string) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("names = %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("names = %v, want %v", got, want)
+			}
+		}
+	}
+
+	t.Run("Repeated queries return the same results", func(t *testing.T) {
+		cache := setup()
+		assertNames(t, names(t, cache, active), "Bob", "John")
+		assertNames(t, names(t, cache, active), "Bob", "John")
+	})
+
+	t.Run("Updating a matched record", func(t *testing.T) {
+		cache := setup()
+		names(t, cache, active)
+		cache.Update(2, map[string]interface{}{"name": "Johnny"})
+		assertNames(t, names(t, cache, active), "Bob", "Johnny")
+	})
+
+	t.Run("Updating a queried column of another record", func(t *testing.T) {
+		cache := setup()
+		names(t, cache, active)
+		cache.Update(3, map[string]interface{}{"status": "active"})
+		assertNames(t, names(t, cache, active), "Bob", "Jane", "John")
+	})
+
+	t.Run("Appends and deletes", func(t *testing.T) {
+		cache := setup()
+		names(t, cache, active)
+		cache.Append(&sheetkv.Record{Key: 5, Values: map[string]interface{}{"name": "Alice", "status": "active"}})
+		assertNames(t, names(t, cache, active), "Alice", "Bob", "John")
+
+		cache.Delete(4)
+		assertNames(t, names(t, cache, active), "Alice", "John")
+	})
+
+	t.Run("Records without the queried column", func(t *testing.T) {
+		cache := setup()
+		notActive := sheetkv.Query{Conditions: []sheetkv.Condition{{Column: "status", Operator: "!=", Value: "active"}}}
+		names(t, cache, notActive)
+		cache.Set(6, &sheetkv.Record{Values: map[string]interface{}{"name": "Carol"}})
+		assertNames(t, names(t, cache, notActive), "Carol", "Jane")
+	})
+
+	t.Run("Reload and eviction", func(t *testing.T) {
+		cache := setup()
+		names(t, cache, active)
+		names(t, cache, sheetkv.Query{Limit: 1})
+		names(t, cache, sheetkv.Query{Offset: 1}) // Evicts the first entry
+
+		cache.Load([]*sheetkv.Record{{Key: 2, Values: map[string]interface{}{"name": "Zed", "status": "active"}}}, []string{"name", "status"})
+		assertNames(t, names(t, cache, active), "Zed")
+	})
+}
+
+func TestCache_QueryCacheSkipsSortFunc(t *testing.T) {
+	cache := sheetkv.NewCache()
+	cache.SetQueryCacheSize(4)
+	cache.Load([]*sheetkv.Record{
+		{Key: 2, Values: map[string]interface{}{"age": int64(30)}},
+		{Key: 3, Values: map[string]interface{}{"age": int64(20)}},
+	}, []string{"age"})
+
+	descending := true
+	query := sheetkv.Query{SortFunc: func(a, b *sheetkv.Record) bool {
+		if descending {
+			return a.GetAsInt64("age", 0) > b.GetAsInt64("age", 0)
+		}
+		return a.GetAsInt64("age", 0) < b.GetAsInt64("age", 0)
+	}}
+
+	first, _ := cache.Query(query)
+	descending = false
+	second, _ := cache.Query(query)
+	if first[0].Key != 2 || second[0].Key != 3 {
+		t.Errorf("first keys = %d, %d, want 2 then 3", first[0].Key, second[0].Key)
+	}
+}
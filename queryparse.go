@@ -0,0 +1,421 @@
+package sheetkv
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// QuerySyntaxError is returned by ParseQuery for text that is not a query
+type QuerySyntaxError struct {
+	Offset  int    // Byte offset of the error in the text
+	Message string // What was expected
+}
+
+func (e *QuerySyntaxError) Error() string {
+	return fmt.Sprintf("query syntax error at offset %d: %s", e.Offset, e.Message)
+}
+
+// ParseQuery parses a query written as text, for queries coming from config
+// files, command-line flags or HTTP parameters:
+//
+//	age >= 30 AND department in ('Sales', 'Marketing') ORDER BY age DESC LIMIT 10
+//
+// Conditions are joined with AND and use the operators of Condition ("="
+// and "<>" are accepted for "==" and "!="), "in (...)" and "between x and
+// y". Values are numbers, quoted strings (doubling the quote escapes it),
+// true, false, null or ":name" placeholders for Prepare. Columns are bare
+// words or `backquoted`. The optional clauses ORDER BY column [ASC|DESC]
+// (several separated by commas), LIMIT n and OFFSET n follow the
+// conditions. Keywords are case-insensitive.
+//
+// Syntax errors are returned as a *QuerySyntaxError; the parsed query is
+// checked with ValidateQuery, leaving the lists of placeholders to Prepare.
+func ParseQuery(text string) (Query, error) {
+	p := &queryParser{text: text}
+	query, err := p.parse()
+	if err != nil {
+		return Query{}, err
+	}
+	if err := validateParamQuery(query); err != nil {
+		return Query{}, fmt.Errorf("invalid query: %w", err)
+	}
+	return query, nil
+}
+
+// queryToken kinds
+const (
+	tokenEnd = iota
+	tokenWord
+	tokenQuoted // `column`
+	tokenString
+	tokenNumber
+	tokenParam
+	tokenOperator
+	tokenPunct // ( ) , *
+)
+
+// queryToken is a lexical token of a query string
+type queryToken struct {
+	kind   int
+	text   string // Text of the token, unquoted for strings and columns
+	offset int
+}
+
+// queryParser parses a query string by recursive descent
+type queryParser struct {
+	text string
+	pos  int
+	tok  queryToken // Current token
+	err  error      // First lexical error
+}
+
+func (p *queryParser) parse() (Query, error) {
+	p.next()
+	return p.rest()
+}
+
+// rest parses the conditions and clauses from the current token to the end
+func (p *queryParser) rest() (Query, error) {
+	var query Query
+	if p.tok.kind != tokenEnd && !p.isClause() {
+		for {
+			cond, err := p.condition()
+			if err != nil {
+				return Query{}, err
+			}
+			query.Conditions = append(query.Conditions, cond)
+			if !p.keyword("and") {
+				break
+			}
+			p.next()
+		}
+	}
+
+	if p.keyword("order") {
+		p.next()
+		if !p.keyword("by") {
+			return Query{}, p.errorf("expected BY after ORDER")
+		}
+		for {
+			p.next()
+			column, err := p.column()
+			if err != nil {
+				return Query{}, err
+			}
+			descending := false
+			if p.keyword("desc") {
+				descending = true
+				p.next()
+			} else if p.keyword("asc") {
+				p.next()
+			}
+			query.OrderBy = append(query.OrderBy, SortKey{Column: column, Descending: descending})
+			if !p.punct(",") {
+				break
+			}
+		}
+	}
+	if p.keyword("limit") {
+		p.next()
+		n, err := p.count("LIMIT")
+		if err != nil {
+			return Query{}, err
+		}
+		query.Limit = n
+	}
+	if p.keyword("offset") {
+		p.next()
+		n, err := p.count("OFFSET")
+		if err != nil {
+			return Query{}, err
+		}
+		query.Offset = n
+	}
+
+	if p.err != nil {
+		return Query{}, p.err
+	}
+	if p.tok.kind != tokenEnd {
+		return Query{}, p.errorf("unexpected %q", p.tok.text)
+	}
+	return query, nil
+}
+
+// isClause reports whether the current token starts a clause following the
+// conditions
+func (p *queryParser) isClause() bool {
+	return p.keyword("order") || p.keyword("limit") || p.keyword("offset")
+}
+
+func (p *queryParser) condition() (Condition, error) {
+	column, err := p.column()
+	if err != nil {
+		return Condition{}, err
+	}
+	cond := Condition{Column: column}
+
+	switch {
+	case p.tok.kind == tokenOperator:
+		cond.Operator = p.tok.text
+		switch cond.Operator {
+		case "=":
+			cond.Operator = "=="
+		case "<>":
+			cond.Operator = "!="
+		}
+		p.next()
+		if cond.Value, err = p.value(); err != nil {
+			return Condition{}, err
+		}
+	case p.keyword("in"):
+		cond.Operator = "in"
+		p.next()
+		if p.tok.kind == tokenParam {
+			cond.Value = Param(p.tok.text)
+			p.next()
+			break
+		}
+		if cond.Value, err = p.list(); err != nil {
+			return Condition{}, err
+		}
+	case p.keyword("between"):
+		cond.Operator = "between"
+		p.next()
+		low, err := p.value()
+		if err != nil {
+			return Condition{}, err
+		}
+		if !p.keyword("and") {
+			return Condition{}, p.errorf("expected AND in BETWEEN")
+		}
+		p.next()
+		high, err := p.value()
+		if err != nil {
+			return Condition{}, err
+		}
+		cond.Value = []interface{}{low, high}
+	default:
+		return Condition{}, p.errorf("expected an operator after %q", column)
+	}
+	return cond, nil
+}
+
+// list parses the parenthesized values of "in"
+func (p *queryParser) list() ([]interface{}, error) {
+	if !p.punct("(") {
+		return nil, p.errorf("expected ( after IN")
+	}
+	p.next()
+
+	values := []interface{}{}
+	if p.punct(")") {
+		p.next()
+		return values, nil
+	}
+	for {
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if p.punct(")") {
+			p.next()
+			return values, nil
+		}
+		if !p.punct(",") {
+			return nil, p.errorf("expected , or ) in the IN list")
+		}
+		p.next()
+	}
+}
+
+func (p *queryParser) column() (string, error) {
+	if p.tok.kind != tokenWord && p.tok.kind != tokenQuoted {
+		return "", p.errorf("expected a column")
+	}
+	column := p.tok.text
+	p.next()
+	return column, nil
+}
+
+func (p *queryParser) value() (interface{}, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokenString:
+		p.next()
+		return tok.text, nil
+	case tokenParam:
+		p.next()
+		return Param(tok.text), nil
+	case tokenNumber:
+		p.next()
+		if n, err := strconv.ParseInt(tok.text, 10, 64); err == nil {
+			return n, nil
+		}
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, &QuerySyntaxError{Offset: tok.offset, Message: fmt.Sprintf("invalid number %q", tok.text)}
+		}
+		return f, nil
+	case tokenWord:
+		switch strings.ToLower(tok.text) {
+		case "true":
+			p.next()
+			return true, nil
+		case "false":
+			p.next()
+			return false, nil
+		case "null":
+			p.next()
+			return nil, nil
+		}
+	}
+	return nil, p.errorf("expected a value")
+}
+
+// count parses the non-negative integer of LIMIT or OFFSET
+func (p *queryParser) count(clause string) (int, error) {
+	if p.tok.kind != tokenNumber {
+		return 0, p.errorf("expected a number after %s", clause)
+	}
+	n, err := strconv.Atoi(p.tok.text)
+	if err != nil || n < 0 {
+		return 0, p.errorf("%s must be a non-negative integer", clause)
+	}
+	p.next()
+	return n, nil
+}
+
+// keyword reports whether the current token is the bare word kw
+func (p *queryParser) keyword(kw string) bool {
+	return p.tok.kind == tokenWord && strings.EqualFold(p.tok.text, kw)
+}
+
+func (p *queryParser) punct(s string) bool {
+	return p.tok.kind == tokenPunct && p.tok.text == s
+}
+
+// errorf returns a syntax error at the current token, or the lexical error
+// that ended the tokens
+func (p *queryParser) errorf(format string, args ...interface{}) error {
+	if p.err != nil {
+		return p.err
+	}
+	return &QuerySyntaxError{Offset: p.tok.offset, Message: fmt.Sprintf(format, args...)}
+}
+
+// next reads the following token into p.tok. Lexical errors end the tokens
+// and are kept in p.err.
+func (p *queryParser) next() {
+	for p.pos < len(p.text) && unicode.IsSpace(rune(p.text[p.pos])) {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(p.text) || p.err != nil {
+		p.tok = queryToken{kind: tokenEnd, offset: start}
+		return
+	}
+
+	c := p.text[p.pos]
+	switch {
+	case c == '\'' || c == '"' || c == '`':
+		text, ok := p.quoted(c)
+		if !ok {
+			p.err = &QuerySyntaxError{Offset: start, Message: "unterminated quote"}
+			p.tok = queryToken{kind: tokenEnd, offset: start}
+			return
+		}
+		kind := tokenString
+		if c == '`' {
+			kind = tokenQuoted
+		}
+		p.tok = queryToken{kind: kind, text: text, offset: start}
+	case c == ':':
+		p.pos++
+		name := p.word()
+		if name == "" {
+			p.err = &QuerySyntaxError{Offset: start, Message: "expected a parameter name after :"}
+			p.tok = queryToken{kind: tokenEnd, offset: start}
+			return
+		}
+		p.tok = queryToken{kind: tokenParam, text: name, offset: start}
+	case strings.IndexByte("(),*", c) >= 0:
+		p.pos++
+		p.tok = queryToken{kind: tokenPunct, text: string(c), offset: start}
+	case strings.IndexByte("=!<>", c) >= 0:
+		op := p.operator()
+		if op == "" {
+			p.err = &QuerySyntaxError{Offset: start, Message: fmt.Sprintf("invalid operator %q", p.text[start:p.pos+1])}
+			p.tok = queryToken{kind: tokenEnd, offset: start}
+			return
+		}
+		p.tok = queryToken{kind: tokenOperator, text: op, offset: start}
+	case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+		p.pos++
+		for p.pos < len(p.text) && strings.IndexByte("0123456789.eE+-", p.text[p.pos]) >= 0 {
+			// Signs only follow an exponent
+			if (p.text[p.pos] == '+' || p.text[p.pos] == '-') && !strings.ContainsAny(p.text[p.pos-1:p.pos], "eE") {
+				break
+			}
+			p.pos++
+		}
+		p.tok = queryToken{kind: tokenNumber, text: p.text[start:p.pos], offset: start}
+	default:
+		word := p.word()
+		if word == "" {
+			p.err = &QuerySyntaxError{Offset: start, Message: fmt.Sprintf("unexpected character %q", c)}
+			p.tok = queryToken{kind: tokenEnd, offset: start}
+			return
+		}
+		p.tok = queryToken{kind: tokenWord, text: word, offset: start}
+	}
+}
+
+// quoted reads a string delimited by quote, where a doubled quote stands
+// for itself
+func (p *queryParser) quoted(quote byte) (string, bool) {
+	var b strings.Builder
+	p.pos++
+	for p.pos < len(p.text) {
+		c := p.text[p.pos]
+		p.pos++
+		if c != quote {
+			b.WriteByte(c)
+			continue
+		}
+		if p.pos < len(p.text) && p.text[p.pos] == quote {
+			b.WriteByte(quote)
+			p.pos++
+			continue
+		}
+		return b.String(), true
+	}
+	return "", false
+}
+
+// word reads letters, digits, underscores and dots
+func (p *queryParser) word() string {
+	start := p.pos
+	for p.pos < len(p.text) {
+		r := rune(p.text[p.pos])
+		if r >= 0x80 || unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.text[start:p.pos]
+}
+
+// operator reads a comparison operator, empty when there is none
+func (p *queryParser) operator() string {
+	for _, op := range []string{"==", "!=", "<>", ">=", "<=", "=", ">", "<"} {
+		if strings.HasPrefix(p.text[p.pos:], op) {
+			p.pos += len(op)
+			return op
+		}
+	}
+	return ""
+}
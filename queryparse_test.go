@@ -0,0 +1,157 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		conditions []sheetkv.Condition
+		limit      int
+		offset     int
+	}{
+		{"empty", "", nil, 0, 0},
+		{
+			"comparisons and list",
+			"age >= 30 AND department in ('Sales','Marketing') LIMIT 10",
+			[]sheetkv.Condition{
+				{Column: "age", Operator: ">=", Value: int64(30)},
+				{Column: "department", Operator: "in", Value: []interface{}{"Sales", "Marketing"}},
+			},
+			10, 0,
+		},
+		{
+			"aliases and literals",
+			`name = "O""Brien" and active <> false and note == null and score < -1.5`,
+			[]sheetkv.Condition{
+				{Column: "name", Operator: "==", Value: `O"Brien`},
+				{Column: "active", Operator: "!=", Value: false},
+				{Column: "note", Operator: "==", Value: nil},
+				{Column: "score", Operator: "<", Value: -1.5},
+			},
+			0, 0,
+		},
+		{
+			"between and quoted column",
+			"`joined at` BETWEEN '2024-01-01' AND '2024-12-31' offset 5",
+			[]sheetkv.Condition{
+				{Column: "joined at", Operator: "between", Value: []interface{}{"2024-01-01", "2024-12-31"}},
+			},
+			0, 5,
+		},
+		{
+			"placeholders",
+			"dept in :depts AND age > :min",
+			[]sheetkv.Condition{
+				{Column: "dept", Operator: "in", Value: sheetkv.Param("depts")},
+				{Column: "age", Operator: ">", Value: sheetkv.Param("min")},
+			},
+			0, 0,
+		},
+		{"clauses only", "limit 3 offset 1", nil, 3, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := sheetkv.ParseQuery(tt.text)
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+			if !reflect.DeepEqual(query.Conditions, tt.conditions) {
+				t.Errorf("Conditions = %#v, want %#v", query.Conditions, tt.conditions)
+			}
+			if query.Limit != tt.limit || query.Offset != tt.offset {
+				t.Errorf("Limit, Offset = %d, %d, want %d, %d", query.Limit, query.Offset, tt.limit, tt.offset)
+			}
+		})
+	}
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	tests := []struct {
+		text   string
+		offset int
+	}{
+		{"age >=", 6},
+		{"age ~ 3", 4},
+		{"name = 'open", 7},
+		{"dept in ('a' 'b')", 13},
+		{"age between 1 or 2", 14},
+		{"age > 1 limit -1", 14},
+		{"age > 1 garbage", 8},
+		{"= 1", 0},
+		{"name = Sales", 7},
+	}
+
+	for _, tt := range tests {
+		_, err := sheetkv.ParseQuery(tt.text)
+		var syntaxErr *sheetkv.QuerySyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("ParseQuery(%q) error = %v, want a *QuerySyntaxError", tt.text, err)
+			continue
+		}
+		if syntaxErr.Offset != tt.offset {
+			t.Errorf("ParseQuery(%q) offset = %d, want %d (%v)", tt.text, syntaxErr.Offset, tt.offset, err)
+		}
+	}
+}
+
+func TestParseQuery_Run(t *testing.T) {
+	adapter := newMemoryAdapter([]string{"name", "dept", "age"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "dept": "Sales", "age": int64(30)}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane", "dept": "Dev", "age": int64(25)}},
+		&sheetkv.Record{Key: 4, Values: map[string]interface{}{"name": "Bob", "dept": "Sales", "age": int64(45)}},
+		&sheetkv.Record{Key: 5, Values: map[string]interface{}{"name": "Amy", "dept": "Marketing", "age": int64(38)}},
+	)
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	query, err := sheetkv.ParseQuery("age >= 30 AND dept in ('Sales', 'Marketing') ORDER BY age DESC LIMIT 2")
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+	results, err := client.Query(query)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	var names []string
+	for _, r := range results {
+		names = append(names, r.Values["name"].(string))
+	}
+	if !reflect.DeepEqual(names, []string{"Bob", "Amy"}) {
+		t.Errorf("Query() = %v, want [Bob Amy]", names)
+	}
+
+	query, err = sheetkv.ParseQuery("dept = :dept order by name")
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+	prepared, err := client.Prepare(query)
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	results, err = prepared.Run(map[string]interface{}{"dept": "Sales"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 2 || results[0].Values["name"] != "Bob" {
+		t.Errorf("Run() = %v, want Bob and John", results)
+	}
+	query, err = sheetkv.ParseQuery("order by dept, age desc")
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+	want := []sheetkv.SortKey{{Column: "dept"}, {Column: "age", Descending: true}}
+	if !reflect.DeepEqual(query.OrderBy, want) {
+		t.Errorf("OrderBy = %v, want %v", query.OrderBy, want)
+	}
+}
@@ -0,0 +1,175 @@
+package sheetkv
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RangeSource is a tabular backend reduced to reading and writing all of
+// its cells as text, such as a CSV file served over HTTP or embedded in the
+// binary. NewRangeAdapter turns it into an Adapter.
+type RangeSource interface {
+	// ReadAll returns the rows of the source, the header first
+	ReadAll(ctx context.Context) ([][]string, error)
+
+	// WriteAll replaces the rows of the source, the header first
+	WriteAll(ctx context.Context, rows [][]string) error
+}
+
+// RangeAdapter adapts a RangeSource to the Adapter interface. The first row
+// is the header and the row below it has key 2, like a sheet. Cells are
+// loaded as int64, float64 and booleans when their text is one, strings
+// otherwise. Every save and batch update rewrites the whole source.
+type RangeAdapter struct {
+	source    RangeSource
+	inference TypeInference
+}
+
+// NewRangeAdapter creates an adapter over source. The inference keeps the
+// text of the columns it names, see TypeInference.
+func NewRangeAdapter(source RangeSource, inference TypeInference) *RangeAdapter {
+	return &RangeAdapter{source: source, inference: inference}
+}
+
+// Load reads the records and schema of the source. Blank rows are skipped
+// but keep their key.
+func (a *RangeAdapter) Load(ctx context.Context) ([]*Record, []string, error) {
+	rows, err := a.source.ReadAll(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rows) == 0 {
+		return []*Record{}, []string{}, nil
+	}
+
+	columns := make([]string, len(rows[0]))
+	schema := make([]string, 0, len(rows[0]))
+	for i, name := range rows[0] {
+		columns[i] = strings.TrimSpace(name)
+		if columns[i] != "" && !containsString(schema, columns[i]) {
+			schema = append(schema, columns[i])
+		}
+	}
+
+	records := make([]*Record, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		record := &Record{Key: i + 2, Values: make(map[string]interface{})}
+		for j, cell := range row {
+			if j >= len(columns) || columns[j] == "" || cell == "" {
+				continue
+			}
+			record.Values[columns[j]] = a.parseCell(columns[j], cell)
+		}
+		if len(record.Values) > 0 {
+			records = append(records, record)
+		}
+	}
+	return records, schema, nil
+}
+
+// parseCell infers the value of the text of a cell
+func (a *RangeAdapter) parseCell(col, s string) interface{} {
+	if !a.inference.Infers(col, s) {
+		return s
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	switch s {
+	case "true", "TRUE":
+		return true
+	case "false", "FALSE":
+		return false
+	}
+	return s
+}
+
+// Save replaces the rows of the source with records. Gap-preserving saves
+// write blank rows for missing keys; compacting saves write the records one
+// after the other.
+func (a *RangeAdapter) Save(ctx context.Context, records []*Record, schema []string, strategy SyncStrategy) error {
+	sorted := make([]*Record, len(records))
+	copy(sorted, records)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Key < sorted[j].Key })
+
+	rows := make([][]string, 0, len(sorted)+1)
+	rows = append(rows, append([]string(nil), schema...))
+	for _, record := range sorted {
+		if strategy == SyncStrategyGapPreserving {
+			for len(rows)+1 < record.Key {
+				rows = append(rows, make([]string, len(schema)))
+			}
+		}
+		row := make([]string, len(schema))
+		for i, col := range schema {
+			row[i] = cellText(record, col)
+		}
+		rows = append(rows, row)
+	}
+	return a.source.WriteAll(ctx, rows)
+}
+
+// BatchUpdate loads the source, applies the operations and saves it back,
+// keeping the keys of the other rows
+func (a *RangeAdapter) BatchUpdate(ctx context.Context, operations []Operation) error {
+	records, schema, err := a.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	byKey := make(map[int]*Record, len(records))
+	maxKey := 1
+	for _, record := range records {
+		byKey[record.Key] = record
+		maxKey = max(maxKey, record.Key)
+	}
+	for _, op := range operations {
+		if op.Record == nil {
+			continue
+		}
+		key := op.Record.Key
+		switch op.Type {
+		case OpAdd:
+			if key == 0 {
+				key = maxKey + 1
+			}
+			byKey[key] = &Record{Key: key, Values: copyValues(op.Record.Values)}
+		case OpUpdate:
+			existing, ok := byKey[key]
+			if !ok {
+				existing = &Record{Key: key, Values: make(map[string]interface{})}
+				byKey[key] = existing
+			}
+			for col, value := range op.Record.Values {
+				if value == nil {
+					delete(existing.Values, col)
+				} else {
+					existing.Values[col] = value
+				}
+			}
+		case OpDelete:
+			delete(byKey, key)
+			continue
+		}
+		maxKey = max(maxKey, key)
+		var added []string
+		for col := range op.Record.Values {
+			if !containsString(schema, col) {
+				added = append(added, col)
+			}
+		}
+		sort.Strings(added)
+		schema = append(schema, added...)
+	}
+
+	updated := make([]*Record, 0, len(byKey))
+	for _, record := range byKey {
+		updated = append(updated, record)
+	}
+	return a.Save(ctx, updated, schema, SyncStrategyGapPreserving)
+}
@@ -0,0 +1,93 @@
+package sheetkv_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// memoryRange keeps the rows of a RangeSource
+type memoryRange struct {
+	rows [][]string
+}
+
+func (r *memoryRange) ReadAll(ctx context.Context) ([][]string, error) {
+	return r.rows, nil
+}
+
+func (r *memoryRange) WriteAll(ctx context.Context, rows [][]string) error {
+	r.rows = rows
+	return nil
+}
+
+func TestRangeAdapter(t *testing.T) {
+	ctx := context.Background()
+	source := &memoryRange{rows: [][]string{
+		{"name", "age", "zip", "active"},
+		{"John", "30", "00123", "TRUE"},
+		{"", "", "", ""},
+		{"Jane", "2.5", "", "false"},
+	}}
+	adapter := sheetkv.NewRangeAdapter(source, sheetkv.TypeInference{TextColumns: []string{"zip"}})
+
+	records, schema, err := adapter.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(schema, []string{"name", "age", "zip", "active"}) {
+		t.Errorf("Load() schema = %v", schema)
+	}
+	want := []*sheetkv.Record{
+		{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30), "zip": "00123", "active": true}},
+		{Key: 4, Values: map[string]interface{}{"name": "Jane", "age": 2.5, "active": false}},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("Load() = %v, want %v", records, want)
+	}
+
+	t.Run("Client round trip", func(t *testing.T) {
+		client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+		if err := client.Initialize(ctx); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		if err := client.Update(4, map[string]interface{}{"age": int64(3)}); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if err := client.Sync(); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+		client.Close()
+
+		want := [][]string{
+			{"name", "age", "zip", "active"},
+			{"John", "30", "00123", "true"},
+			{"", "", "", ""},
+			{"Jane", "3", "", "false"},
+		}
+		if !reflect.DeepEqual(source.rows, want) {
+			t.Errorf("rows = %v, want %v", source.rows, want)
+		}
+	})
+
+	t.Run("BatchUpdate", func(t *testing.T) {
+		err := adapter.BatchUpdate(ctx, []sheetkv.Operation{
+			{Type: sheetkv.OpAdd, Record: &sheetkv.Record{Values: map[string]interface{}{"name": "Bob", "email": "bob@example.com"}}},
+			{Type: sheetkv.OpDelete, Record: &sheetkv.Record{Key: 2}},
+		})
+		if err != nil {
+			t.Fatalf("BatchUpdate() error = %v", err)
+		}
+		want := [][]string{
+			{"name", "age", "zip", "active", "email"},
+			{"", "", "", "", ""},
+			{"", "", "", "", ""},
+			{"Jane", "3", "", "false", ""},
+			{"Bob", "", "", "", "bob@example.com"},
+		}
+		if !reflect.DeepEqual(source.rows, want) {
+			t.Errorf("rows = %v, want %v", source.rows, want)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package sheetkv
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// errPartialCache is returned by saves of a client holding only the rows
+// read through, which would overwrite the others
+var errPartialCache = errors.New("the cache holds only the rows read through; initialize the client or enable WriteThrough")
+
+// loadedNow records a full load, making every row fresh
+func (c *Client) loadedNow() {
+	c.loadedAt.Store(c.now().UnixNano())
+
+	c.readMu.Lock()
+	c.fetchedAt = nil
+	c.readMu.Unlock()
+}
+
+// tableFresh reports whether the whole table was loaded within
+// Config.ReadThroughTTL
+func (c *Client) tableFresh() bool {
+	if !c.loaded.Load() {
+		return false
+	}
+	return c.now().Sub(time.Unix(0, c.loadedAt.Load())) <= c.config.ReadThroughTTL
+}
+
+// readThroughTable reloads the table when Config.ReadThroughTTL is set and
+// the cache is cold or stale. Records modified or deleted since the last
+// sync are kept. Callers must hold c.mu.
+func (c *Client) readThroughTable(ctx context.Context) error {
+	if c.config.ReadThroughTTL <= 0 || c.tableFresh() {
+		return nil
+	}
+	return c.reload(ctx)
+}
+
+// readThroughKey refreshes the record at key when Config.ReadThroughTTL is
+// set and it is cold or stale. Adapters implementing RowLoader read the row
+// alone, others reload the table. Records modified or deleted since the
+// last sync are kept. Callers must hold c.mu.
+func (c *Client) readThroughKey(ctx context.Context, key int) error {
+	if c.config.ReadThroughTTL <= 0 {
+		return nil
+	}
+
+	c.readMu.Lock()
+	fetched, ok := c.fetchedAt[key]
+	c.readMu.Unlock()
+	if ok && c.now().Sub(fetched) <= c.config.ReadThroughTTL {
+		return nil
+	}
+	if !ok && c.tableFresh() {
+		return nil
+	}
+	if c.cache.isDirty(key) {
+		// Local changes win until they are saved
+		return nil
+	}
+
+	if _, isLoader := c.adaptor.(RowLoader); !isLoader {
+		return c.reload(ctx)
+	}
+	return c.fetchRow(ctx, key)
+}
+
+// fetchRow reads the record at key from the adapter when it implements
+// RowLoader, unless the record was modified or deleted since the last sync.
+// Callers must hold c.mu.
+func (c *Client) fetchRow(ctx context.Context, key int) error {
+	loader, isLoader := c.adaptor.(RowLoader)
+	if !isLoader || c.cache.isDirty(key) {
+		return nil
+	}
+
+	var records []*Record
+	var schema []string
+	err := c.withRetry(ctx, func() error {
+		var err error
+		records, schema, err = loader.LoadRows(ctx, []int{key})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.decodeRecords(ctx, records); err != nil {
+		return err
+	}
+
+	var record *Record
+	for _, r := range records {
+		if r.Key == key {
+			record = r
+		}
+	}
+	c.cache.refreshRecord(key, record, schema)
+
+	c.readMu.Lock()
+	if c.fetchedAt == nil {
+		c.fetchedAt = make(map[int]time.Time)
+	}
+	c.fetchedAt[key] = c.now()
+	c.readMu.Unlock()
+	return nil
+}
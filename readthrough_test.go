@@ -0,0 +1,256 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_ReadThroughCold(t *testing.T) {
+	data := &indexedAdapter{memoryAdapter: newMemoryAdapter([]string{"name"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John"}},
+	)}
+	client := sheetkv.New(data, &sheetkv.Config{DisableAutoSync: true, ReadThroughTTL: time.Hour})
+	defer client.Close()
+
+	// Without Initialize, rows are read one by one
+	record, err := client.Get(2)
+	if err != nil {
+		t.Fatalf("Get(2) error = %v", err)
+	}
+	if record.Values["name"] != "John" {
+		t.Errorf("Get(2) = %v", record.Values)
+	}
+	if _, err := client.Get(2); err != nil {
+		t.Fatalf("Get(2) error = %v", err)
+	}
+	if _, err := client.Get(3); !errors.Is(err, sheetkv.ErrKeyNotFound) {
+		t.Errorf("Get(3) error = %v, want ErrKeyNotFound", err)
+	}
+	if data.rowLoads != 2 || data.loads != 0 {
+		t.Errorf("row loads = %d, loads = %d, want 2 and 0", data.rowLoads, data.loads)
+	}
+
+	// A partial cache must not replace the sheet
+	if err := client.Set(4, &sheetkv.Record{Values: map[string]interface{}{"name": "Jane"}}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := client.Sync(); !errors.Is(err, sheetkv.ErrSyncFailed) {
+		t.Errorf("Sync() error = %v, want ErrSyncFailed", err)
+	}
+	if data.saveCount() != 0 {
+		t.Errorf("saves = %d, want 0", data.saveCount())
+	}
+
+	// A query loads the table, keeping the local change
+	records, err := client.Query(sheetkv.Query{})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(records) != 2 || data.loads != 1 {
+		t.Errorf("Query() = %d records with %d loads, want 2 and 1", len(records), data.loads)
+	}
+	if err := client.Sync(); err != nil {
+		t.Errorf("Sync() error = %v", err)
+	}
+}
+
+func TestClient_ReadThroughStale(t *testing.T) {
+	data := &indexedAdapter{memoryAdapter: newMemoryAdapter([]string{"name"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John"}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane"}},
+	)}
+	client := sheetkv.New(data, &sheetkv.Config{DisableAutoSync: true, ReadThroughTTL: 50 * time.Millisecond})
+	defer client.Close()
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	// Edits made outside the client
+	data.mu.Lock()
+	data.records[0].Values["name"] = "Johnny"
+	data.records[1].Values["name"] = "Janet"
+	data.mu.Unlock()
+	if err := client.Update(3, map[string]interface{}{"name": "Jenny"}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if record, _ := client.Get(2); record.Values["name"] != "John" {
+		t.Errorf("fresh Get(2) = %v, want the cached value", record.Values)
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	if record, _ := client.Get(2); record.Values["name"] != "Johnny" {
+		t.Errorf("stale Get(2) = %v, want the sheet's value", record.Values)
+	}
+	if record, _ := client.Get(3); record.Values["name"] != "Jenny" {
+		t.Errorf("stale Get(3) = %v, want the local change", record.Values)
+	}
+	if data.rowLoads != 1 || data.loads != 1 {
+		t.Errorf("row loads = %d, loads = %d, want 1 and 1", data.rowLoads, data.loads)
+	}
+
+	if _, err := client.Query(sheetkv.Query{}); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if data.loads != 2 {
+		t.Errorf("loads = %d, want the stale table reloaded", data.loads)
+	}
+}
+
+func TestClient_ReadThroughWithoutRowLoader(t *testing.T) {
+	data := newMemoryAdapter([]string{"name"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John"}},
+	)
+	client := sheetkv.New(data, &sheetkv.Config{DisableAutoSync: true, ReadThroughTTL: time.Hour})
+	defer client.Close()
+
+	if record, err := client.Get(2); err != nil || record.Values["name"] != "John" {
+		t.Fatalf("Get(2) = %v, %v", record, err)
+	}
+	if _, err := client.Get(2); err != nil {
+		t.Fatalf("Get(2) error = %v", err)
+	}
+	if data.loads != 1 {
+		t.Errorf("loads = %d, want the table loaded once", data.loads)
+	}
+}
+
+func TestClient_ReadThroughKeepsDeletions(t *testing.T) {
+	data := &indexedAdapter{memoryAdapter: newMemoryAdapter([]string{"name"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John"}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane"}},
+	)}
+	clock := sheetkv.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	ttl := time.Minute
+	client := sheetkv.New(data, &sheetkv.Config{DisableAutoSync: true, ReadThroughTTL: ttl, Clock: clock})
+	defer client.Close()
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	// Local deletion not synced yet
+	if err := client.Delete(2); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	clock.Advance(2 * ttl)
+
+	if _, err := client.Get(2); !errors.Is(err, sheetkv.ErrKeyNotFound) {
+		t.Errorf("stale Get(2) error = %v, want ErrKeyNotFound", err)
+	}
+	records, err := client.Query(sheetkv.Query{})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(records) != 1 || records[0].Key != 3 {
+		t.Errorf("stale Query() = %v, want only row 3", records)
+	}
+}
+
+func TestClient_ReadThroughAppend(t *testing.T) {
+	data := &indexedAdapter{memoryAdapter: newMemoryAdapter([]string{"name"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John"}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane"}},
+	)}
+	client := sheetkv.New(data, &sheetkv.Config{DisableAutoSync: true, WriteThrough: true, ReadThroughTTL: time.Hour})
+	defer client.Close()
+
+	// Without Initialize, appended keys still follow the last row of the sheet
+	record := &sheetkv.Record{Values: map[string]interface{}{"name": "Bob"}}
+	if err := client.Append(record); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if record.Key != 4 {
+		t.Errorf("Append() key = %d, want 4", record.Key)
+	}
+	ops := []sheetkv.Operation{{Type: sheetkv.OpAdd, Record: &sheetkv.Record{Values: map[string]interface{}{"name": "Alice"}}}}
+	if err := client.Apply(ops); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if ops[0].Record.Key != 5 {
+		t.Errorf("Apply() key = %d, want 5", ops[0].Record.Key)
+	}
+
+	got, err := client.Get(2)
+	if err != nil {
+		t.Fatalf("Get(2) error = %v", err)
+	}
+	if got.Values["name"] != "John" {
+		t.Errorf("Get(2) = %v, want John", got.Values)
+	}
+}
+
+func TestClient_ReadThroughGetCell(t *testing.T) {
+	data := &indexedAdapter{memoryAdapter: newMemoryAdapter([]string{"name"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John"}},
+	)}
+	clock := sheetkv.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	ttl := time.Minute
+	client := sheetkv.New(data, &sheetkv.Config{DisableAutoSync: true, ReadThroughTTL: ttl, Clock: clock})
+	defer client.Close()
+
+	// Without Initialize, the row is read on first access
+	if v, ok := client.GetCell(2, "name"); !ok || v != "John" {
+		t.Errorf("cold GetCell(2, name) = %v, %v, want John, true", v, ok)
+	}
+
+	data.mu.Lock()
+	data.records[0].Values["name"] = "Johnny"
+	data.mu.Unlock()
+	clock.Advance(2 * ttl)
+
+	if v, _ := client.GetCell(2, "name"); v != "Johnny" {
+		t.Errorf("stale GetCell(2, name) = %v, want the sheet's value", v)
+	}
+	if data.rowLoads != 2 {
+		t.Errorf("row loads = %d, want 2", data.rowLoads)
+	}
+}
+
+func TestClient_ReadThroughSnapshot(t *testing.T) {
+	data := &indexedAdapter{memoryAdapter: newMemoryAdapter([]string{"name"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John"}},
+	)}
+	client := sheetkv.New(data, &sheetkv.Config{DisableAutoSync: true, Consistency: sheetkv.Strong()})
+	defer client.Close()
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	// Edit made outside the client
+	data.mu.Lock()
+	data.records[0].Values["name"] = "Johnny"
+	data.mu.Unlock()
+	time.Sleep(time.Millisecond)
+
+	snapshot, err := client.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	defer snapshot.Release()
+	if record, err := snapshot.Get(2); err != nil || record.Values["name"] != "Johnny" {
+		t.Errorf("Snapshot().Get(2) = %v, %v, want the sheet's value", record, err)
+	}
+
+	data.mu.Lock()
+	data.records[0].Values["name"] = "Jo"
+	data.mu.Unlock()
+	time.Sleep(time.Millisecond)
+
+	err = client.ReadTx(func(view sheetkv.ReadView) error {
+		record, err := view.Get(2)
+		if err != nil {
+			return err
+		}
+		if record.Values["name"] != "Jo" {
+			t.Errorf("ReadTx Get(2) = %v, want the sheet's value", record.Values)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ReadTx() error = %v", err)
+	}
+}
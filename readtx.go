@@ -0,0 +1,190 @@
+package sheetkv
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync/atomic"
+)
+
+// ReadView is a consistent, read-only view of the records. Every call made
+// through one view sees the same data, whatever is written concurrently.
+type ReadView interface {
+	// Get retrieves a record by key
+	Get(key int) (*Record, error)
+
+	// Query searches for records matching the given conditions
+	Query(query Query) ([]*Record, error)
+
+	// QueryCtx is like Query but stops with ctx.Err() when ctx is done
+	QueryCtx(ctx context.Context, query Query) ([]*Record, error)
+
+	// Records returns all records sorted by key
+	Records() []*Record
+
+	// Schema returns the columns
+	Schema() []string
+
+	// Size returns the number of records
+	Size() int
+}
+
+// Snapshot is an immutable version of the cache. Writers never modify the
+// records or the map a snapshot refers to: the first write after a snapshot
+// is taken stores the new versions in a fresh map, so snapshots keep reading
+// the old ones without blocking writers (MVCC).
+//
+// Replaced records are recycled (see ReleaseRecords) only while no snapshot
+// is live, so call Release once a snapshot is no longer needed.
+type Snapshot struct {
+	cache      *Cache
+	data       map[int]*Record
+	schema     []string
+	comparison *comparison
+	released   atomic.Bool
+}
+
+// Snapshot returns the current version of the cache. Taking a snapshot is
+// cheap: nothing is copied until the next write.
+func (c *Cache) Snapshot() *Snapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	c.snapshots.Add(1)
+	c.shared.Store(true)
+
+	schema := make([]string, len(c.schema))
+	copy(schema, c.schema)
+	return &Snapshot{cache: c, data: c.data, schema: schema, comparison: c.comparison}
+}
+
+// own gives the cache a private copy of its map before a write when a
+// snapshot may still read the current one. Callers must hold the write lock.
+func (c *Cache) own() {
+	if !c.shared.Load() {
+		return
+	}
+	data := make(map[int]*Record, len(c.data))
+	for key, record := range c.data {
+		data[key] = record
+	}
+	c.data = data
+	c.shared.Store(false)
+}
+
+// release recycles a replaced record unless a snapshot may still read it.
+// Callers must hold the write lock.
+func (c *Cache) release(record *Record) {
+	if c.snapshots.Load() == 0 {
+		releaseRecord(record)
+	}
+}
+
+// Release ends the snapshot. It must not be used afterwards; calling
+// Release again does nothing.
+func (s *Snapshot) Release() {
+	if s.released.CompareAndSwap(false, true) {
+		s.cache.snapshots.Add(-1)
+	}
+}
+
+// Get retrieves a record by key
+func (s *Snapshot) Get(key int) (*Record, error) {
+	record, exists := s.data[key]
+	if !exists {
+		return nil, ErrKeyNotFound
+	}
+	return s.cache.copyRecord(record), nil
+}
+
+// Query searches for records matching the given conditions. Snapshots do
+// not use the secondary index or the query result cache, which only
+// describe the latest version.
+func (s *Snapshot) Query(query Query) ([]*Record, error) {
+	return s.QueryCtx(context.Background(), query)
+}
+
+// QueryCtx is like Query but stops with ctx.Err() when ctx is done before
+// the records are filtered
+func (s *Snapshot) QueryCtx(ctx context.Context, query Query) ([]*Record, error) {
+	if err := ValidateQuery(query); err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+
+	records := make([]*Record, 0, len(s.data))
+	for _, record := range s.data {
+		records = append(records, record)
+	}
+	results, err := applyQuery(ctx, records, query, s.comparison)
+	if err != nil {
+		return nil, err
+	}
+	for i, record := range results {
+		results[i] = s.cache.copyRecord(record)
+	}
+	return results, nil
+}
+
+// Records returns all records sorted by key
+func (s *Snapshot) Records() []*Record {
+	records := make([]*Record, 0, len(s.data))
+	for _, record := range s.data {
+		records = append(records, s.cache.copyRecord(record))
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Key < records[j].Key
+	})
+	return records
+}
+
+// Schema returns the columns
+func (s *Snapshot) Schema() []string {
+	schema := make([]string, len(s.schema))
+	copy(schema, s.schema)
+	return schema
+}
+
+// Size returns the number of records
+func (s *Snapshot) Size() int {
+	return len(s.data)
+}
+
+// ReadTx calls fn with a view of the cache that stays consistent until fn
+// returns, so a report made of several queries does not observe rows
+// changed halfway through. The view is a snapshot: concurrent writes
+// proceed without waiting for fn. The error of fn is returned.
+func (c *Cache) ReadTx(fn func(view ReadView) error) error {
+	snapshot := c.Snapshot()
+	defer snapshot.Release()
+
+	return fn(snapshot)
+}
+
+// Snapshot returns an immutable version of the records, see Cache.Snapshot.
+// Like Query, it reads the table again once Config.ReadThroughTTL has
+// passed. Release it once done.
+func (c *Client) Snapshot() (*Snapshot, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, fmt.Errorf("client is closed")
+	}
+	if err := c.readThroughTable(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return c.cache.Snapshot(), nil
+}
+
+// ReadTx calls fn with a consistent view of the records, see Cache.ReadTx.
+// The view must not be used after fn returns.
+func (c *Client) ReadTx(fn func(view ReadView) error) error {
+	snapshot, err := c.Snapshot()
+	if err != nil {
+		return err
+	}
+	defer snapshot.Release()
+
+	return fn(snapshot)
+}
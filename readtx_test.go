@@ -0,0 +1,181 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_ReadTx(t *testing.T) {
+	adapter := newMemoryAdapter([]string{"name", "age"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30)}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane", "age": int64(25)}},
+	)
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	t.Run("Consistent view", func(t *testing.T) {
+		written := make(chan struct{})
+		err := client.ReadTx(func(view sheetkv.ReadView) error {
+			before := view.Size()
+
+			go func() {
+				client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "Bob"}})
+				client.Update(2, map[string]interface{}{"name": "Johnny"})
+				close(written)
+			}()
+
+			select {
+			case <-written:
+			case <-time.After(time.Second):
+				t.Fatal("writes waited for ReadTx() to return")
+			}
+
+			if got := view.Size(); got != before {
+				t.Errorf("Size() = %d, want %d", got, before)
+			}
+			results, err := view.Query(sheetkv.Query{})
+			if err != nil {
+				return err
+			}
+			if len(results) != before {
+				t.Errorf("Query() = %d records, want %d", len(results), before)
+			}
+			records := view.Records()
+			if len(records) != 2 || records[0].Key != 2 || records[1].Key != 3 {
+				t.Errorf("Records() = %v, want keys [2 3]", records)
+			}
+			record, err := view.Get(2)
+			if err != nil || record.GetAsString("name", "") != "John" {
+				t.Errorf("Get(2) = %v, %v, want John", record, err)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("ReadTx() error = %v", err)
+		}
+
+		<-written
+		if err := client.ReadTx(func(view sheetkv.ReadView) error {
+			if got := view.Size(); got != 3 {
+				t.Errorf("Size() = %d, want 3", got)
+			}
+			return nil
+		}); err != nil {
+			t.Fatalf("ReadTx() error = %v", err)
+		}
+	})
+
+	t.Run("Callback error", func(t *testing.T) {
+		want := errors.New("report failed")
+		if err := client.ReadTx(func(view sheetkv.ReadView) error { return want }); err != want {
+			t.Errorf("ReadTx() error = %v, want %v", err, want)
+		}
+	})
+
+	t.Run("Missing key", func(t *testing.T) {
+		client.ReadTx(func(view sheetkv.ReadView) error {
+			if _, err := view.Get(100); err != sheetkv.ErrKeyNotFound {
+				t.Errorf("Get(100) error = %v, want ErrKeyNotFound", err)
+			}
+			return nil
+		})
+	})
+}
+
+func TestCache_Snapshot(t *testing.T) {
+	cache := sheetkv.NewCache()
+	cache.Load([]*sheetkv.Record{
+		{Key: 2, Values: map[string]interface{}{"name": "John", "note": ""}},
+		{Key: 3, Values: map[string]interface{}{"name": "Jane"}},
+	}, []string{"name", "note"})
+
+	snapshot := cache.Snapshot()
+	defer snapshot.Release()
+
+	cache.Set(4, &sheetkv.Record{Values: map[string]interface{}{"name": "Bob"}})
+	cache.Update(2, map[string]interface{}{"name": "Johnny"})
+	cache.Delete(3)
+	cache.PruneSchema()
+	cache.AddComputedColumn("upper", func(r *sheetkv.Record) interface{} { return r.GetAsString("name", "") + "!" }, false)
+
+	t.Run("Old version", func(t *testing.T) {
+		records := snapshot.Records()
+		if len(records) != 2 || records[0].Key != 2 || records[1].Key != 3 {
+			t.Fatalf("Records() = %v, want keys [2 3]", records)
+		}
+		if got := records[0].GetAsString("name", ""); got != "John" {
+			t.Errorf("name = %q, want John", got)
+		}
+		if _, ok := records[0].Values["upper"]; ok {
+			t.Errorf("Values = %v, want no computed column", records[0].Values)
+		}
+		if got := snapshot.Schema(); len(got) != 2 {
+			t.Errorf("Schema() = %v, want [name note]", got)
+		}
+		results, err := snapshot.Query(sheetkv.Query{Conditions: []sheetkv.Condition{{Column: "name", Operator: "==", Value: "Jane"}}})
+		if err != nil || len(results) != 1 {
+			t.Errorf("Query() = %d records, %v, want 1 record", len(results), err)
+		}
+	})
+
+	t.Run("New version", func(t *testing.T) {
+		if got := cache.Size(); got != 2 {
+			t.Errorf("Size() = %d, want 2", got)
+		}
+		record, err := cache.Get(2)
+		if err != nil || record.GetAsString("upper", "") != "Johnny!" {
+			t.Errorf("Get(2) = %v, %v, want Johnny!", record, err)
+		}
+		if _, err := cache.Get(3); err != sheetkv.ErrKeyNotFound {
+			t.Errorf("Get(3) error = %v, want ErrKeyNotFound", err)
+		}
+	})
+
+	t.Run("Later snapshot", func(t *testing.T) {
+		later := cache.Snapshot()
+		defer later.Release()
+
+		if got := later.Size(); got != 2 {
+			t.Errorf("Size() = %d, want 2", got)
+		}
+		if got := snapshot.Size(); got != 2 {
+			t.Errorf("Size() of the first snapshot = %d, want 2", got)
+		}
+	})
+}
+
+func TestCache_SnapshotConcurrentWrites(t *testing.T) {
+	cache := sheetkv.NewCache()
+	for key := 2; key < 102; key++ {
+		cache.Set(key, &sheetkv.Record{Values: map[string]interface{}{"n": int64(0)}})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := int64(1); i <= 200; i++ {
+			for key := 2; key < 102; key++ {
+				cache.Update(key, map[string]interface{}{"n": i})
+			}
+		}
+	}()
+
+	for i := 0; i < 50; i++ {
+		snapshot := cache.Snapshot()
+		records := snapshot.Records()
+		first := records[0].GetAsInt64("n", -1)
+		for _, record := range records {
+			if n := record.GetAsInt64("n", -1); n < first-1 || n > first {
+				t.Fatalf("snapshot mixes versions %d and %d", first, n)
+			}
+		}
+		snapshot.Release()
+	}
+	<-done
+}
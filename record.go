@@ -8,8 +8,9 @@ import (
 )
 
 type Record struct {
-	Key    int                    // 行番号 (2から始まる、1行目はカラム定義)
-	Values map[string]interface{} // カラム名と値のマップ
+	Key      int                    // 行番号 (2から始まる、1行目はカラム定義)
+	Values   map[string]interface{} // カラム名と値のマップ
+	Revision uint64                 // キャッシュ内での変更回数 (変更ごとに増加、保存はされない)
 }
 
 // GetAsString returns the value as string or defaultValue if not found
@@ -0,0 +1,116 @@
+package sheetkv
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultIDColumn is the column holding record IDs when Config.IDColumn is
+// empty
+const DefaultIDColumn = "_id"
+
+// GetByID retrieves the record whose ID, see Config.RecordIDs, is id. IDs
+// stay with their record when rows move, unlike keys: through compaction,
+// rows inserted by hand above it, or a copy of the data to another backend.
+// Add Config.IDColumn to IndexColumns to look IDs up without scanning.
+func (c *Client) GetByID(id string) (*Record, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, fmt.Errorf("client is closed")
+	}
+	if err := c.readThroughTable(context.Background()); err != nil {
+		return nil, err
+	}
+
+	records, err := c.cache.Query(Query{
+		Conditions: []Condition{{Column: c.config.IDColumn, Operator: "==", Value: id}},
+		Limit:      1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, ErrKeyNotFound
+	}
+	return records[0], nil
+}
+
+// newID returns a new record ID
+func newID() (string, error) {
+	id, err := GenerateUUID(time.Time{})
+	if err != nil {
+		return "", err
+	}
+	return id.(string), nil
+}
+
+// assignID returns record with an ID, keeping the one it has or the one of
+// the record at key it replaces, otherwise a new one
+func (c *Client) assignID(key int, record *Record) (*Record, error) {
+	col := c.config.IDColumn
+	if !c.config.RecordIDs || !emptyID(record.Values[col]) {
+		return record, nil
+	}
+
+	var id interface{}
+	if existing, err := c.cache.Get(key); err == nil && !emptyID(existing.Values[col]) {
+		id = existing.Values[col]
+	} else {
+		generated, err := newID()
+		if err != nil {
+			return nil, err
+		}
+		id = generated
+	}
+	assigned := &Record{Key: record.Key, Values: copyValues(record.Values)}
+	assigned.Values[col] = id
+	return assigned, nil
+}
+
+// checkID refuses writes of values changing the ID of the record at key.
+// New records may bring their ID, such as when data is migrated.
+func (c *Client) checkID(key int, values map[string]interface{}) error {
+	col := c.config.IDColumn
+	value, ok := values[col]
+	if !c.config.RecordIDs || !ok {
+		return nil
+	}
+	existing, err := c.cache.Get(key)
+	if err != nil || emptyID(existing.Values[col]) {
+		return nil
+	}
+	if fmt.Sprintf("%v", value) != fmt.Sprintf("%v", existing.Values[col]) {
+		return fmt.Errorf("%w: cannot change the ID of record %d", ErrInvalidValue, key)
+	}
+	return nil
+}
+
+// assignMissingIDs gives an ID to the loaded records without one, such as
+// rows added by hand, marking them dirty so the next sync writes the IDs
+func (c *Client) assignMissingIDs() error {
+	if !c.config.RecordIDs || c.config.TailAppends {
+		return nil
+	}
+	col := c.config.IDColumn
+	for _, record := range c.cache.GetAllRecords() {
+		if !emptyID(record.Values[col]) {
+			continue
+		}
+		id, err := newID()
+		if err != nil {
+			return err
+		}
+		if err := c.cache.Update(record.Key, map[string]interface{}{col: id}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// emptyID reports whether value is no ID
+func emptyID(value interface{}) bool {
+	return value == nil || value == ""
+}
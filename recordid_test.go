@@ -0,0 +1,71 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_RecordIDs(t *testing.T) {
+	ctx := context.Background()
+	adapter := newMemoryAdapter([]string{"name", "_id"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "a", "_id": "id-a"}},
+		&sheetkv.Record{Key: 4, Values: map[string]interface{}{"name": "manual"}},
+	)
+	client := sheetkv.New(adapter, &sheetkv.Config{
+		DisableAutoSync: true,
+		RecordIDs:       true,
+		IndexColumns:    []string{sheetkv.DefaultIDColumn},
+	})
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	defer client.Close()
+
+	manual, _ := client.Get(4)
+	manualID, _ := manual.Values["_id"].(string)
+	if len(manualID) != 36 {
+		t.Fatalf("ID of the row added by hand = %q, want a UUID", manualID)
+	}
+
+	record := &sheetkv.Record{Values: map[string]interface{}{"name": "b"}}
+	if err := client.Append(record); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	appended, _ := client.Get(record.Key)
+	id, _ := appended.Values["_id"].(string)
+	if len(id) != 36 || id == manualID {
+		t.Fatalf("ID of the appended record = %q, want a new UUID", id)
+	}
+
+	t.Run("Set keeps the ID", func(t *testing.T) {
+		if err := client.Set(2, &sheetkv.Record{Values: map[string]interface{}{"name": "a2"}}); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		if record, err := client.GetByID("id-a"); err != nil || record.Key != 2 || record.Values["name"] != "a2" {
+			t.Errorf("GetByID(id-a) = %v, %v, want the record at 2", record, err)
+		}
+	})
+
+	t.Run("IDs survive compaction", func(t *testing.T) {
+		client.Delete(2)
+		if _, err := client.Compact(ctx); err != nil {
+			t.Fatalf("Compact() error = %v", err)
+		}
+		if record, err := client.GetByID(id); err != nil || record.Values["name"] != "b" || record.Key != 3 {
+			t.Errorf("GetByID() = %v, %v, want b renumbered to 3", record, err)
+		}
+		if _, err := client.GetByID("id-a"); err != sheetkv.ErrKeyNotFound {
+			t.Errorf("GetByID(deleted) error = %v, want ErrKeyNotFound", err)
+		}
+	})
+
+	t.Run("IDs cannot change", func(t *testing.T) {
+		err := client.Update(2, map[string]interface{}{"_id": "other"})
+		if !errors.Is(err, sheetkv.ErrInvalidValue) {
+			t.Errorf("Update() error = %v, want ErrInvalidValue", err)
+		}
+	})
+}
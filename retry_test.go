@@ -0,0 +1,47 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_MaxElapsedRetryTime(t *testing.T) {
+	data := newMemoryAdapter([]string{"name"})
+	client := sheetkv.New(data, &sheetkv.Config{
+		DisableAutoSync:     true,
+		MaxRetries:          10,
+		MaxElapsedRetryTime: 250 * time.Millisecond,
+	})
+	defer func() {
+		data.mu.Lock()
+		data.saveErr = nil
+		data.mu.Unlock()
+		client.Close()
+	}()
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	saveErr := errors.New("backend unavailable")
+	data.mu.Lock()
+	data.saveErr = saveErr
+	data.mu.Unlock()
+	client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "John"}})
+
+	// Backoffs of 100ms then 200ms: the second would end past the budget
+	start := time.Now()
+	err := client.Sync()
+	if !errors.Is(err, sheetkv.ErrRetryTime) || !errors.Is(err, saveErr) {
+		t.Fatalf("Sync() error = %v, want ErrRetryTime wrapping the save error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("Sync() took %s, want under the budget", elapsed)
+	}
+	if saves := data.saveCount(); saves != 2 {
+		t.Errorf("saves = %d, want 2", saves)
+	}
+}
@@ -0,0 +1,166 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_DetectRemoteChanges(t *testing.T) {
+	setup := func(t *testing.T, config *sheetkv.Config) (*sheetkv.Client, *revisionAdapter) {
+		adapter := &revisionAdapter{memoryAdapter: newMemoryAdapter([]string{"name"}, &sheetkv.Record{
+			Key:    2,
+			Values: map[string]interface{}{"name": "John"},
+		})}
+		client := sheetkv.New(adapter, config)
+		if err := client.Initialize(context.Background()); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		return client, adapter
+	}
+
+	t.Run("Own saves are not conflicts", func(t *testing.T) {
+		client, adapter := setup(t, &sheetkv.Config{DisableAutoSync: true, DetectRemoteChanges: true})
+
+		for _, name := range []string{"Jane", "Bob"} {
+			client.Update(2, map[string]interface{}{"name": name})
+			if err := client.Sync(); err != nil {
+				t.Fatalf("Sync() error = %v", err)
+			}
+		}
+		if got := adapter.saveCount(); got != 2 {
+			t.Errorf("saves = %d, want 2", got)
+		}
+	})
+
+	t.Run("Remote edit refuses the save", func(t *testing.T) {
+		var conflicts []error
+		client, adapter := setup(t, &sheetkv.Config{
+			DisableAutoSync:     true,
+			MaxRetries:          1,
+			DetectRemoteChanges: true,
+			OnConflict:          func(err error) { conflicts = append(conflicts, err) },
+		})
+
+		adapter.edit()
+		client.Update(2, map[string]interface{}{"name": "Jane"})
+
+		err := client.Sync()
+		if !errors.Is(err, sheetkv.ErrSyncFailed) || !errors.Is(err, sheetkv.ErrRemoteConflict) {
+			t.Fatalf("Sync() error = %v, want %v and %v", err, sheetkv.ErrSyncFailed, sheetkv.ErrRemoteConflict)
+		}
+		if got := adapter.saveCount(); got != 0 {
+			t.Errorf("saves = %d, want 0", got)
+		}
+		if len(conflicts) != 1 || !errors.Is(conflicts[0], sheetkv.ErrRemoteConflict) {
+			t.Errorf("OnConflict calls = %v, want one with %v", conflicts, sheetkv.ErrRemoteConflict)
+		}
+		// Reloading adopts the remote revision so the next save goes through
+		if err := client.Initialize(context.Background()); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		client.Update(2, map[string]interface{}{"name": "Jane"})
+		if err := client.Sync(); err != nil {
+			t.Errorf("Sync() after reload error = %v", err)
+		}
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		client, adapter := setup(t, &sheetkv.Config{DisableAutoSync: true})
+
+		adapter.edit()
+		client.Update(2, map[string]interface{}{"name": "Jane"})
+		if err := client.Sync(); err != nil {
+			t.Errorf("Sync() error = %v", err)
+		}
+	})
+}
+
+func TestClient_Reload(t *testing.T) {
+	adapter := newMemoryAdapter([]string{"name"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John"}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane"}},
+	)
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+	ctx := context.Background()
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	// Local change not synced yet
+	client.Update(2, map[string]interface{}{"name": "Johnny"})
+
+	// Remote edits
+	adapter.mu.Lock()
+	adapter.records = []*sheetkv.Record{
+		{Key: 2, Values: map[string]interface{}{"name": "John"}},
+		{Key: 3, Values: map[string]interface{}{"name": "Janet"}},
+		{Key: 4, Values: map[string]interface{}{"name": "Bob"}},
+	}
+	adapter.mu.Unlock()
+
+	if err := client.Reload(ctx); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	for key, want := range map[int]string{2: "Johnny", 3: "Janet", 4: "Bob"} {
+		got, err := client.Get(key)
+		if err != nil {
+			t.Fatalf("Get(%d) error = %v", key, err)
+		}
+		if got.Values["name"] != want {
+			t.Errorf("Get(%d) name = %v, want %v", key, got.Values["name"], want)
+		}
+	}
+
+	// The local change is still written by the next sync
+	if err := client.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if got := adapter.saveCount(); got != 1 {
+		t.Errorf("saves = %d, want 1", got)
+	}
+}
+
+func TestClient_ReloadKeepsDeletions(t *testing.T) {
+	adapter := newMemoryAdapter([]string{"name"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John"}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane"}},
+	)
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+	ctx := context.Background()
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	// Local deletion not synced yet
+	if err := client.Delete(2); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if err := client.Reload(ctx); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if _, err := client.Get(2); !errors.Is(err, sheetkv.ErrKeyNotFound) {
+		t.Errorf("Get(2) error = %v, want ErrKeyNotFound", err)
+	}
+	if _, err := client.Get(3); err != nil {
+		t.Errorf("Get(3) error = %v", err)
+	}
+
+	// The deletion is still written by the next sync
+	if err := client.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	records, _, err := adapter.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	for _, record := range records {
+		if record.Key == 2 && len(record.Values) > 0 {
+			t.Errorf("row 2 = %v after sync, want it deleted", record.Values)
+		}
+	}
+}
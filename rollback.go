@@ -0,0 +1,83 @@
+package sheetkv
+
+import (
+	"context"
+	"fmt"
+)
+
+// syncSnapshot is the data as of the last load or successful save
+type syncSnapshot struct {
+	records []*Record
+	schema  []string
+}
+
+// keepSnapshot stores the synced state when Config.KeepSyncSnapshot is set.
+// The records must not be modified afterwards.
+func (c *Client) keepSnapshot(records []*Record, schema []string) {
+	if !c.config.KeepSyncSnapshot {
+		return
+	}
+
+	snapshot := &syncSnapshot{records: records, schema: make([]string, len(schema))}
+	copy(snapshot.schema, schema)
+
+	c.snapshotMu.Lock()
+	defer c.snapshotMu.Unlock()
+	c.snapshot = snapshot
+}
+
+// RollbackToLastSync discards every change made since the last load or
+// successful save, restoring the cache to that state. With persist, the
+// snapshot is also written back to the spreadsheet, which repairs a sheet
+// left inconsistent by a failed save or overwritten remotely.
+// Requires Config.KeepSyncSnapshot.
+func (c *Client) RollbackToLastSync(ctx context.Context, persist bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return fmt.Errorf("client is closed")
+	}
+
+	c.snapshotMu.Lock()
+	snapshot := c.snapshot
+	c.snapshotMu.Unlock()
+	if snapshot == nil {
+		return fmt.Errorf("no sync snapshot available")
+	}
+
+	c.cache.Load(snapshot.records, snapshot.schema)
+
+	// Changes that were rolled back are never synced
+	c.auditMu.Lock()
+	c.auditQueue = nil
+	c.auditMu.Unlock()
+	c.historyMu.Lock()
+	c.historyQueue = nil
+	c.historyMu.Unlock()
+	c.publishMu.Lock()
+	c.publishQueue = nil
+	c.publishMu.Unlock()
+
+	if !persist {
+		return nil
+	}
+
+	stored, err := c.encodeRecords(ctx, snapshot.records)
+	if err != nil {
+		return err
+	}
+	err = c.withRetry(ctx, func() error {
+		return c.adaptor.Save(ctx, stored, snapshot.schema, SyncStrategyGapPreserving)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+
+	revision, err := c.fetchRevision(ctx)
+	if err != nil {
+		return err
+	}
+	c.setRevision(revision)
+	return nil
+}
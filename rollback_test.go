@@ -0,0 +1,103 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_RollbackToLastSync(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T, config *sheetkv.Config) (*sheetkv.Client, *memoryAdapter) {
+		adapter := newMemoryAdapter([]string{"name", "age"},
+			&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30)}},
+			&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane", "age": int64(25)}},
+		)
+		client := sheetkv.New(adapter, config)
+		if err := client.Initialize(ctx); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		return client, adapter
+	}
+
+	t.Run("Restores the loaded state", func(t *testing.T) {
+		client, adapter := setup(t, &sheetkv.Config{DisableAutoSync: true, KeepSyncSnapshot: true})
+
+		client.Update(2, map[string]interface{}{"age": int64(99)})
+		client.Delete(3)
+		client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "Bob"}})
+
+		if err := client.RollbackToLastSync(ctx, false); err != nil {
+			t.Fatalf("RollbackToLastSync() error = %v", err)
+		}
+
+		records, _ := client.Query(sheetkv.Query{})
+		if len(records) != 2 {
+			t.Fatalf("records after rollback = %d, want 2", len(records))
+		}
+		got, _ := client.Get(2)
+		if got.Values["age"] != int64(30) {
+			t.Errorf("age after rollback = %v, want 30", got.Values["age"])
+		}
+
+		// Nothing left to save
+		if err := client.Sync(); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+		if got := adapter.saveCount(); got != 0 {
+			t.Errorf("saves = %d, want 0", got)
+		}
+	})
+
+	t.Run("Restores the last saved state", func(t *testing.T) {
+		client, _ := setup(t, &sheetkv.Config{DisableAutoSync: true, KeepSyncSnapshot: true})
+
+		client.Update(2, map[string]interface{}{"age": int64(31)})
+		if err := client.Sync(); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+		client.Update(2, map[string]interface{}{"age": int64(99)})
+
+		client.RollbackToLastSync(ctx, false)
+		got, _ := client.Get(2)
+		if got.Values["age"] != int64(31) {
+			t.Errorf("age after rollback = %v, want 31", got.Values["age"])
+		}
+	})
+
+	t.Run("Persist repairs the sheet after a failed save", func(t *testing.T) {
+		client, adapter := setup(t, &sheetkv.Config{DisableAutoSync: true, MaxRetries: 1, KeepSyncSnapshot: true})
+
+		// Simulate a save that failed after partially writing the sheet
+		adapter.mu.Lock()
+		adapter.records = adapter.records[:1]
+		adapter.saveErr = errors.New("boom")
+		adapter.mu.Unlock()
+		client.Update(2, map[string]interface{}{"age": int64(99)})
+		if err := client.Sync(); err == nil {
+			t.Fatal("Sync() should fail")
+		}
+
+		adapter.mu.Lock()
+		adapter.saveErr = nil
+		adapter.mu.Unlock()
+		if err := client.RollbackToLastSync(ctx, true); err != nil {
+			t.Fatalf("RollbackToLastSync() error = %v", err)
+		}
+		if len(adapter.records) != 2 {
+			t.Errorf("sheet rows after rollback = %d, want 2", len(adapter.records))
+		}
+		if adapter.records[0].Values["age"] != int64(30) {
+			t.Errorf("sheet age after rollback = %v, want 30", adapter.records[0].Values["age"])
+		}
+	})
+
+	t.Run("Requires KeepSyncSnapshot", func(t *testing.T) {
+		client, _ := setup(t, &sheetkv.Config{DisableAutoSync: true})
+		if err := client.RollbackToLastSync(ctx, false); err == nil {
+			t.Error("RollbackToLastSync() should fail without a snapshot")
+		}
+	})
+}
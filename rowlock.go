@@ -0,0 +1,171 @@
+package sheetkv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultLockColumn is the column holding row locks when Config.LockColumn
+// is not set
+const DefaultLockColumn = "_lock"
+
+// lockSeparator separates the owner and expiry of a lock cell
+const lockSeparator = " until "
+
+// RecordLockedError is returned for writes and locks of a record locked by
+// another owner. It matches ErrRecordLocked with errors.Is.
+type RecordLockedError struct {
+	Key     int
+	Owner   string
+	Expires time.Time
+}
+
+func (e *RecordLockedError) Error() string {
+	return fmt.Sprintf("record %d locked by %s until %s", e.Key, e.Owner, e.Expires.Format(time.RFC3339))
+}
+
+// Is reports whether target is ErrRecordLocked
+func (e *RecordLockedError) Is(target error) bool {
+	return target == ErrRecordLocked
+}
+
+// Lock locks the record at key for ttl, so other clients and operators do
+// not edit it concurrently. The lock is written to Config.LockColumn and
+// persisted before Lock returns; the row is read again first when the
+// adapter implements RowLoader, to see locks taken elsewhere. Locking a
+// record this client holds renews the lock. A record locked by another
+// owner returns a *RecordLockedError until its lock expires.
+//
+// While locked, Set, Update and Delete from other owners fail with the
+// same error, as far as their cache shows the lock.
+func (c *Client) Lock(key int, ttl time.Duration) error {
+	if ttl <= 0 {
+		return fmt.Errorf("%w: lock ttl must be positive, got %s", ErrInvalidValue, ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return fmt.Errorf("client is closed")
+	}
+
+	ctx := context.Background()
+	if err := c.fetchRow(ctx, key); err != nil {
+		return err
+	}
+	if err := c.checkRowLock(key); err != nil {
+		return err
+	}
+
+	value := c.config.LeaseOwner + lockSeparator + c.now().Add(ttl).UTC().Format(time.RFC3339Nano)
+	return c.persistRowLock(ctx, key, value)
+}
+
+// Unlock releases the lock of the record at key, persisting the change
+// before it returns. It does nothing when the record is not locked, and
+// returns a *RecordLockedError when another owner holds the lock.
+func (c *Client) Unlock(key int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return fmt.Errorf("client is closed")
+	}
+
+	ctx := context.Background()
+	if err := c.fetchRow(ctx, key); err != nil {
+		return err
+	}
+	if err := c.checkRowLock(key); err != nil {
+		return err
+	}
+
+	record, err := c.cache.Get(key)
+	if err != nil {
+		return err
+	}
+	if record.Values[c.config.LockColumn] == nil {
+		return nil
+	}
+	return c.persistRowLock(ctx, key, nil)
+}
+
+// checkRowLock returns a *RecordLockedError when the record at key holds an
+// unexpired lock of another owner. Callers must hold c.mu.
+func (c *Client) checkRowLock(key int) error {
+	record, err := c.cache.Get(key)
+	if err != nil {
+		return nil
+	}
+	owner, expires, ok := parseRowLock(record.Values[c.config.LockColumn])
+	if !ok || owner == c.config.LeaseOwner || !expires.After(c.now()) {
+		return nil
+	}
+	return &RecordLockedError{Key: key, Owner: owner, Expires: expires}
+}
+
+// keepRowLock returns record with the lock of the record it replaces, so
+// Set does not drop it
+func (c *Client) keepRowLock(key int, record *Record) *Record {
+	col := c.config.LockColumn
+	if _, ok := record.Values[col]; ok {
+		return record
+	}
+	existing, err := c.cache.Get(key)
+	if err != nil || existing.Values[col] == nil {
+		return record
+	}
+	kept := &Record{Key: record.Key, Values: copyValues(record.Values)}
+	kept.Values[col] = existing.Values[col]
+	return kept
+}
+
+// persistRowLock sets the lock cell of the record at key and writes the
+// record through the adapter. On failure the record is restored and the
+// error wraps ErrSyncFailed. Callers must hold c.mu.
+func (c *Client) persistRowLock(ctx context.Context, key int, value interface{}) error {
+	old, err := c.cache.Get(key)
+	if err != nil {
+		return err
+	}
+	if err := c.cache.Update(key, map[string]interface{}{c.config.LockColumn: value}); err != nil {
+		return err
+	}
+	current, err := c.cache.Get(key)
+	if err != nil {
+		return err
+	}
+
+	ops := []Operation{{Type: OpUpdate, Record: current}}
+	unlock, err := c.lock(ctx)
+	if err == nil {
+		err = errors.Join(c.persistOperations(ctx, ops, map[int]*Record{key: old}), unlock())
+	}
+	if err != nil {
+		_ = c.cache.Set(key, old)
+		return fmt.Errorf("%w: lock of record %d: %w", ErrSyncFailed, key, err)
+	}
+	c.cache.markRecordSaved(key)
+	return nil
+}
+
+// parseRowLock returns the owner and expiry of a lock cell
+func parseRowLock(value interface{}) (string, time.Time, bool) {
+	text, ok := value.(string)
+	if !ok {
+		return "", time.Time{}, false
+	}
+	i := strings.LastIndex(text, lockSeparator)
+	if i < 0 {
+		return "", time.Time{}, false
+	}
+	expires, err := time.Parse(time.RFC3339Nano, text[i+len(lockSeparator):])
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return text[:i], expires, true
+}
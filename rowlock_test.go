@@ -0,0 +1,97 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// sharedAdapter is an indexedAdapter applying BatchUpdate to its records,
+// so clients sharing it see each other's persisted writes
+type sharedAdapter struct {
+	*indexedAdapter
+}
+
+func (a *sharedAdapter) BatchUpdate(ctx context.Context, operations []sheetkv.Operation) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, op := range operations {
+		for _, r := range a.records {
+			if r.Key == op.Record.Key && op.Type == sheetkv.OpUpdate {
+				for k, v := range op.Record.Values {
+					r.Values[k] = v
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func TestClient_Lock(t *testing.T) {
+	data := &sharedAdapter{&indexedAdapter{memoryAdapter: newMemoryAdapter([]string{"name"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John"}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane"}},
+	)}}
+	open := func(owner string) *sheetkv.Client {
+		client := sheetkv.New(data, &sheetkv.Config{DisableAutoSync: true, LeaseOwner: owner})
+		if err := client.Initialize(context.Background()); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		return client
+	}
+	alice, bob := open("alice"), open("bob")
+
+	if err := alice.Lock(2, time.Minute); err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+
+	// The lock is persisted and seen by the other client
+	err := bob.Lock(2, time.Minute)
+	var locked *sheetkv.RecordLockedError
+	if !errors.Is(err, sheetkv.ErrRecordLocked) || !errors.As(err, &locked) || locked.Owner != "alice" || locked.Key != 2 {
+		t.Fatalf("Lock() error = %v, want locked by alice", err)
+	}
+	if err := bob.Update(2, map[string]interface{}{"name": "Bob"}); !errors.Is(err, sheetkv.ErrRecordLocked) {
+		t.Errorf("Update() error = %v, want ErrRecordLocked", err)
+	}
+	if err := bob.Delete(2); !errors.Is(err, sheetkv.ErrRecordLocked) {
+		t.Errorf("Delete() error = %v, want ErrRecordLocked", err)
+	}
+	if err := bob.Unlock(2); !errors.Is(err, sheetkv.ErrRecordLocked) {
+		t.Errorf("Unlock() error = %v, want ErrRecordLocked", err)
+	}
+	if err := bob.Update(3, map[string]interface{}{"name": "Janet"}); err != nil {
+		t.Errorf("Update() of an unlocked record error = %v", err)
+	}
+
+	// The holder writes freely, and Set keeps the lock
+	if err := alice.Set(2, &sheetkv.Record{Values: map[string]interface{}{"name": "Johnny"}}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if record, _ := alice.Get(2); record.Values[sheetkv.DefaultLockColumn] == nil {
+		t.Errorf("Get(2) = %v, want the lock kept", record.Values)
+	}
+
+	if err := alice.Unlock(2); err != nil {
+		t.Fatalf("Unlock() error = %v", err)
+	}
+	if err := bob.Lock(2, time.Minute); err != nil {
+		t.Errorf("Lock() after Unlock error = %v", err)
+	}
+
+	// An expired lock is taken over
+	if err := alice.Lock(3, 10*time.Millisecond); err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if err := bob.Lock(3, time.Minute); err != nil {
+		t.Errorf("Lock() of an expired lock error = %v", err)
+	}
+
+	if err := alice.Lock(2, 0); !errors.Is(err, sheetkv.ErrInvalidValue) {
+		t.Errorf("Lock() with no ttl error = %v, want ErrInvalidValue", err)
+	}
+}
@@ -0,0 +1,234 @@
+package sheetkv
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// ColumnOrder controls the order of the schema, and so of the sheet columns
+type ColumnOrder int
+
+const (
+	// ColumnOrderAppend keeps the existing columns in place and appends new
+	// ones, sorted by name when a single write introduces several
+	ColumnOrderAppend ColumnOrder = iota
+	// ColumnOrderAlphabetical sorts all columns by name
+	ColumnOrderAlphabetical
+	// ColumnOrderDeclared puts the columns of Config.Columns first, in that
+	// order, followed by the others as with ColumnOrderAppend
+	ColumnOrderDeclared
+)
+
+// SetColumnOrder sets how the schema is ordered, with the declared columns
+// used by ColumnOrderDeclared, and reorders the current schema
+func (c *Cache) SetColumnOrder(order ColumnOrder, declared []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order = order
+	c.declared = make([]string, len(declared))
+	copy(c.declared, declared)
+	c.arrangeSchema()
+}
+
+// arrangeSchema reorders the schema according to the column order.
+// Callers must hold the write lock.
+func (c *Cache) arrangeSchema() {
+	switch c.order {
+	case ColumnOrderAlphabetical:
+		sort.Strings(c.schema)
+	case ColumnOrderDeclared:
+		arranged := make([]string, 0, len(c.schema)+len(c.declared))
+		for _, col := range c.declared {
+			if !containsString(arranged, col) {
+				arranged = append(arranged, col)
+			}
+		}
+		for _, col := range c.schema {
+			if !containsString(arranged, col) {
+				arranged = append(arranged, col)
+			}
+		}
+		c.schema = arranged
+	}
+}
+
+// PruneSchema removes the columns no record has a value for and returns
+// them. Declared columns (see SetColumnOrder) and stored computed columns
+// are kept.
+func (c *Cache) PruneSchema() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	used := make(map[string]bool, len(c.schema))
+	for _, record := range c.data {
+		for col, value := range record.Values {
+			if value != nil && value != "" {
+				used[col] = true
+			}
+		}
+	}
+	for _, col := range c.declared {
+		used[col] = true
+	}
+	for _, col := range c.computed {
+		used[col.name] = true
+	}
+
+	var removed []string
+	kept := make([]string, 0, len(c.schema))
+	for _, col := range c.schema {
+		if used[col] {
+			kept = append(kept, col)
+		} else {
+			removed = append(removed, col)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	// Drop the leftover blank values so the records match the schema
+	c.own()
+	for key, record := range c.data {
+		var updated *Record
+		for _, col := range removed {
+			if _, ok := record.Values[col]; ok {
+				if updated == nil {
+					updated = c.copyRecord(record)
+				}
+				delete(updated.Values, col)
+			}
+		}
+		if updated != nil {
+			updated.Revision = c.nextRevision(key)
+			c.data[key] = updated
+			c.release(record)
+			c.dirty[key] = true
+		}
+	}
+	c.schema = kept
+	c.rebuildIndex()
+	c.resetQueries()
+	return removed
+}
+
+// PruneSchema removes the columns no record has a value for, so they are
+// dropped from the sheet on the next sync, and returns them
+func (c *Client) PruneSchema() ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, fmt.Errorf("client is closed")
+	}
+
+	return c.cache.PruneSchema(), nil
+}
+
+// checkColumns rejects values for columns outside the schema when
+// Config.StrictSchema is set. The schema is Config.Columns when declared,
+// otherwise the one loaded from the sheet.
+func (c *Client) checkColumns(values map[string]interface{}) error {
+	if !c.config.StrictSchema {
+		return nil
+	}
+
+	known := c.config.Columns
+	if len(known) == 0 {
+		known = c.cache.GetSchema()
+	}
+	for col, value := range values {
+		if value == nil || containsString(known, col) || c.cache.isComputed(col) {
+			continue
+		}
+		if col == c.config.CreatedAtColumn || col == c.config.UpdatedAtColumn {
+			continue
+		}
+		if c.config.RecordIDs && col == c.config.IDColumn {
+			continue
+		}
+		return fmt.Errorf("%w: %q", ErrUnknownColumn, col)
+	}
+	return nil
+}
+
+// schemaSamples is the number of distinct sample values of ColumnInfo
+const schemaSamples = 3
+
+// Schema describes the columns of a client for tooling, such as describe
+// commands, servers and generated GraphQL schemas, see Client.Schema
+type Schema struct {
+	Records int          // Number of records
+	Columns []ColumnInfo // Columns in the order of the sheet, then the declared columns without values
+}
+
+// ColumnInfo describes one column of a Schema
+type ColumnInfo struct {
+	Name     string
+	Type     ValueType     // Dominant type of the values, see InferTypes
+	Values   int           // Records with a value in the column
+	Nulls    int           // Records without a value in the column
+	Samples  []interface{} // Up to three distinct values, in key order
+	Declared bool          // Listed in Config.Columns; other columns were discovered in the sheet or in writes
+	Computed bool          // Derived by a computed column
+	Rules    []ColumnRule  // Validation rules enforced on the column
+}
+
+// Schema returns the columns with their detected type, null counts, sample
+// values and whether they are declared in Config.Columns or were
+// discovered, scanning the cached records
+func (c *Client) Schema() (*Schema, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, fmt.Errorf("client is closed")
+	}
+	if err := c.readThroughTable(context.Background()); err != nil {
+		return nil, err
+	}
+
+	records := c.sortedRecords()
+	columns := c.cache.GetSchema()
+	for _, col := range c.config.Columns {
+		if !containsString(columns, col) {
+			columns = append(columns, col)
+		}
+	}
+	types := c.inferTypes(records, columns)
+
+	c.rulesMu.RLock()
+	defer c.rulesMu.RUnlock()
+
+	schema := &Schema{Records: len(records), Columns: make([]ColumnInfo, len(columns))}
+	for i, col := range columns {
+		info := ColumnInfo{
+			Name:     col,
+			Type:     types[i].Type,
+			Values:   len(records) - types[i].Empty,
+			Nulls:    types[i].Empty,
+			Declared: containsString(c.config.Columns, col),
+			Computed: c.cache.isComputed(col),
+			Rules:    append([]ColumnRule(nil), c.rules[col]...),
+		}
+		seen := make(map[string]bool)
+		for _, record := range records {
+			value, ok := record.Values[col]
+			if !ok || value == nil || value == "" {
+				continue
+			}
+			text := fmt.Sprintf("%v", value)
+			if !seen[text] {
+				seen[text] = true
+				info.Samples = append(info.Samples, value)
+			}
+			if len(info.Samples) == schemaSamples {
+				break
+			}
+		}
+		schema.Columns[i] = info
+	}
+	return schema, nil
+}
@@ -0,0 +1,219 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_ColumnOrder(t *testing.T) {
+	newValues := map[string]interface{}{"zip": "100", "city": "Tokyo", "age": int64(30)}
+
+	tests := []struct {
+		name    string
+		order   sheetkv.ColumnOrder
+		columns []string
+		want    []string
+	}{
+		{
+			name:  "Append sorts the columns added together",
+			order: sheetkv.ColumnOrderAppend,
+			want:  []string{"name", "id", "age", "city", "zip"},
+		},
+		{
+			name:  "Alphabetical",
+			order: sheetkv.ColumnOrderAlphabetical,
+			want:  []string{"age", "city", "id", "name", "zip"},
+		},
+		{
+			name:    "Declared columns first",
+			order:   sheetkv.ColumnOrderDeclared,
+			columns: []string{"id", "name", "email"},
+			want:    []string{"id", "name", "email", "age", "city", "zip"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := newMemoryAdapter([]string{"name", "id"},
+				&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "id": int64(1)}},
+			)
+			client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true, ColumnOrder: tt.order, Columns: tt.columns})
+			if err := client.Initialize(context.Background()); err != nil {
+				t.Fatalf("Initialize() error = %v", err)
+			}
+
+			if err := client.Update(2, newValues); err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+			if err := client.Sync(); err != nil {
+				t.Fatalf("Sync() error = %v", err)
+			}
+
+			adapter.mu.Lock()
+			defer adapter.mu.Unlock()
+			if !reflect.DeepEqual(adapter.schema, tt.want) {
+				t.Errorf("schema = %v, want %v", adapter.schema, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_PruneSchema(t *testing.T) {
+	newClient := func(config *sheetkv.Config) (*sheetkv.Client, *memoryAdapter) {
+		adapter := newMemoryAdapter([]string{"name", "legacy", "blank", "note"},
+			&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "legacy": "x", "blank": ""}},
+			&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane", "note": "hi"}},
+		)
+		client := sheetkv.New(adapter, config)
+		if err := client.Initialize(context.Background()); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		return client, adapter
+	}
+
+	t.Run("PruneSchema", func(t *testing.T) {
+		client, adapter := newClient(&sheetkv.Config{DisableAutoSync: true})
+		if err := client.Update(2, map[string]interface{}{"legacy": nil}); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+
+		removed, err := client.PruneSchema()
+		if err != nil {
+			t.Fatalf("PruneSchema() error = %v", err)
+		}
+		if want := []string{"legacy", "blank"}; !reflect.DeepEqual(removed, want) {
+			t.Errorf("PruneSchema() = %v, want %v", removed, want)
+		}
+
+		if err := client.Sync(); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+		adapter.mu.Lock()
+		defer adapter.mu.Unlock()
+		if want := []string{"name", "note"}; !reflect.DeepEqual(adapter.schema, want) {
+			t.Errorf("schema = %v, want %v", adapter.schema, want)
+		}
+	})
+
+	t.Run("PruneOnCompact", func(t *testing.T) {
+		client, adapter := newClient(&sheetkv.Config{DisableAutoSync: true, PruneOnCompact: true})
+		if err := client.Sync(); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+		if got := adapter.saveCount(); got != 0 {
+			t.Errorf("saves = %d, want 0 (gap-preserving syncs do not prune)", got)
+		}
+
+		if err := client.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		adapter.mu.Lock()
+		defer adapter.mu.Unlock()
+		if want := []string{"name", "legacy", "note"}; !reflect.DeepEqual(adapter.schema, want) {
+			t.Errorf("schema = %v, want %v", adapter.schema, want)
+		}
+	})
+}
+
+func TestClient_StrictSchema(t *testing.T) {
+	newClient := func(config *sheetkv.Config) *sheetkv.Client {
+		adapter := newMemoryAdapter([]string{"name", "department"},
+			&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "department": "Sales"}},
+		)
+		client := sheetkv.New(adapter, config)
+		if err := client.Initialize(context.Background()); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		return client
+	}
+
+	t.Run("Loaded schema", func(t *testing.T) {
+		client := newClient(&sheetkv.Config{DisableAutoSync: true, StrictSchema: true, UpdatedAtColumn: "updated_at"})
+
+		err := client.Update(2, map[string]interface{}{"departmnet": "HR"})
+		if !errors.Is(err, sheetkv.ErrUnknownColumn) {
+			t.Errorf("Update() error = %v, want ErrUnknownColumn", err)
+		}
+		err = client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "Jane", "age": int64(25)}})
+		if !errors.Is(err, sheetkv.ErrUnknownColumn) {
+			t.Errorf("Append() error = %v, want ErrUnknownColumn", err)
+		}
+		record, _ := client.Get(2)
+		if _, ok := record.Values["departmnet"]; ok {
+			t.Error("rejected column was written")
+		}
+
+		if err := client.Update(2, map[string]interface{}{"department": "HR"}); err != nil {
+			t.Errorf("Update() error = %v", err)
+		}
+	})
+
+	t.Run("Declared columns", func(t *testing.T) {
+		client := newClient(&sheetkv.Config{DisableAutoSync: true, StrictSchema: true, Columns: []string{"name", "department", "email"}})
+		client.AddComputedColumn("label", func(r *sheetkv.Record) interface{} { return r.GetAsString("name", "") })
+
+		if err := client.Set(3, &sheetkv.Record{Values: map[string]interface{}{"name": "Jane", "email": "jane@example.com"}}); err != nil {
+			t.Errorf("Set() error = %v", err)
+		}
+		if err := client.Update(2, map[string]interface{}{"label": "x"}); err != nil {
+			t.Errorf("Update() of a computed column error = %v", err)
+		}
+		if err := client.Update(2, map[string]interface{}{"phone": "123"}); !errors.Is(err, sheetkv.ErrUnknownColumn) {
+			t.Errorf("Update() error = %v, want ErrUnknownColumn", err)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		client := newClient(&sheetkv.Config{DisableAutoSync: true})
+		if err := client.Update(2, map[string]interface{}{"departmnet": "HR"}); err != nil {
+			t.Errorf("Update() error = %v", err)
+		}
+	})
+}
+
+func TestClient_Schema(t *testing.T) {
+	ctx := context.Background()
+	adapter := newMemoryAdapter([]string{"name", "age"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "a", "age": int64(30)}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "a", "age": int64(41)}},
+		&sheetkv.Record{Key: 4, Values: map[string]interface{}{"name": "b"}},
+		&sheetkv.Record{Key: 5, Values: map[string]interface{}{"name": "c"}},
+		&sheetkv.Record{Key: 6, Values: map[string]interface{}{"name": "d"}},
+	)
+	min := 0.0
+	client := sheetkv.New(adapter, &sheetkv.Config{
+		DisableAutoSync: true,
+		Columns:         []string{"age", "email"},
+		ValidationRules: []sheetkv.ColumnRule{{Column: "age", Min: &min}},
+	})
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	defer client.Close()
+
+	schema, err := client.Schema()
+	if err != nil {
+		t.Fatalf("Schema() error = %v", err)
+	}
+	if schema.Records != 5 || len(schema.Columns) != 3 {
+		t.Fatalf("Schema() = %+v, want 5 records and 3 columns", schema)
+	}
+
+	name, age, email := schema.Columns[0], schema.Columns[1], schema.Columns[2]
+	if name.Name != "name" || name.Type != sheetkv.TypeString || name.Declared || name.Nulls != 0 {
+		t.Errorf("name = %+v, want a discovered string column", name)
+	}
+	if !reflect.DeepEqual(name.Samples, []interface{}{"a", "b", "c"}) {
+		t.Errorf("name samples = %v, want three distinct values", name.Samples)
+	}
+	if age.Name != "age" || age.Type != sheetkv.TypeInteger || !age.Declared || age.Values != 2 || age.Nulls != 3 || len(age.Rules) != 1 {
+		t.Errorf("age = %+v, want a declared integer column with 3 nulls and its rule", age)
+	}
+	if email.Name != "email" || !email.Declared || email.Values != 0 || email.Samples != nil {
+		t.Errorf("email = %+v, want a declared column without values", email)
+	}
+}
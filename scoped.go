@@ -0,0 +1,146 @@
+package sheetkv
+
+import (
+	"context"
+	"fmt"
+)
+
+// ScopedClient is a view of a client restricted to the records matching a
+// condition, such as those of one tenant of a shared sheet. Queries only
+// return those records and records outside the scope are not found. Writes
+// are stamped with the value of an == condition, and refused with
+// ErrOutOfScope when the written record would fall outside the scope.
+type ScopedClient struct {
+	client    *Client
+	condition Condition
+}
+
+// Scoped returns a view of the client restricted to the records matching
+// condition
+func (c *Client) Scoped(condition Condition) *ScopedClient {
+	return &ScopedClient{client: c, condition: condition}
+}
+
+// Client returns the underlying unrestricted client
+func (s *ScopedClient) Client() *Client {
+	return s.client
+}
+
+// Condition returns the condition of the scope
+func (s *ScopedClient) Condition() Condition {
+	return s.condition
+}
+
+// Get retrieves the record at key, ErrKeyNotFound when it is outside the
+// scope
+func (s *ScopedClient) Get(key int) (*Record, error) {
+	record, err := s.client.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if !s.client.cache.inScope(record, s.condition) {
+		return nil, ErrKeyNotFound
+	}
+	return record, nil
+}
+
+// Set stores the record at key, which must be empty or inside the scope
+func (s *ScopedClient) Set(key int, record *Record) error {
+	stamped := &Record{Key: record.Key, Values: s.stamp(record.Values)}
+	if err := s.checkValues(stamped.Values); err != nil {
+		return err
+	}
+	return s.client.setGuarded(key, stamped, func(existing *Record) error {
+		if existing != nil && !s.client.cache.inScope(existing, s.condition) {
+			return fmt.Errorf("%w: key %d", ErrOutOfScope, key)
+		}
+		return nil
+	})
+}
+
+// Append adds a new record inside the scope. The key of the new record is
+// set on record, as Client.Append does.
+func (s *ScopedClient) Append(record *Record) error {
+	stamped := &Record{Values: s.stamp(record.Values)}
+	if err := s.checkValues(stamped.Values); err != nil {
+		return err
+	}
+	if err := s.client.Append(stamped); err != nil {
+		return err
+	}
+	record.Key = stamped.Key
+	return nil
+}
+
+// Update partially updates the record at key, which must be inside the
+// scope and stay in it
+func (s *ScopedClient) Update(key int, updates map[string]interface{}) error {
+	return s.client.updateGuarded(key, updates, func(existing *Record) error {
+		if err := s.checkRecord(existing); err != nil {
+			return err
+		}
+		merged := copyValues(existing.Values)
+		for col, value := range updates {
+			merged[col] = value
+		}
+		return s.checkValues(merged)
+	})
+}
+
+// Delete removes the record at key, which must be inside the scope
+func (s *ScopedClient) Delete(key int) error {
+	return s.client.deleteGuarded(key, s.checkRecord)
+}
+
+// Query searches for the records of the scope matching query
+func (s *ScopedClient) Query(query Query) ([]*Record, error) {
+	return s.client.Query(s.scope(query))
+}
+
+// QueryCtx is Query stopping early when ctx is canceled
+func (s *ScopedClient) QueryCtx(ctx context.Context, query Query) ([]*Record, error) {
+	return s.client.QueryCtx(ctx, s.scope(query))
+}
+
+// scope returns query restricted to the scope
+func (s *ScopedClient) scope(query Query) Query {
+	conditions := make([]Condition, 0, len(query.Conditions)+1)
+	conditions = append(conditions, s.condition)
+	query.Conditions = append(conditions, query.Conditions...)
+	return query
+}
+
+// stamp returns values with the column of an == condition set to its value
+func (s *ScopedClient) stamp(values map[string]interface{}) map[string]interface{} {
+	stamped := copyValues(values)
+	if s.condition.Operator == "==" {
+		stamped[s.condition.Column] = s.condition.Value
+	}
+	return stamped
+}
+
+// checkRecord returns ErrKeyNotFound when record is missing or outside the
+// scope
+func (s *ScopedClient) checkRecord(record *Record) error {
+	if record == nil || !s.client.cache.inScope(record, s.condition) {
+		return ErrKeyNotFound
+	}
+	return nil
+}
+
+// checkValues refuses values outside the scope
+func (s *ScopedClient) checkValues(values map[string]interface{}) error {
+	if !s.client.cache.inScope(&Record{Values: values}, s.condition) {
+		return fmt.Errorf("%w %s %s %v", ErrOutOfScope, s.condition.Column, s.condition.Operator, s.condition.Value)
+	}
+	return nil
+}
+
+// inScope reports whether record matches condition, comparing strings as
+// queries do
+func (c *Cache) inScope(record *Record, condition Condition) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return evalCondition(record, condition, c.comparison)
+}
@@ -0,0 +1,100 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_Scoped(t *testing.T) {
+	ctx := context.Background()
+	adapter := newMemoryAdapter([]string{"tenant", "name"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"tenant": "acme", "name": "a"}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"tenant": "globex", "name": "b"}},
+	)
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	defer client.Close()
+
+	acme := client.Scoped(sheetkv.Condition{Column: "tenant", Operator: "==", Value: "acme"})
+
+	record := &sheetkv.Record{Values: map[string]interface{}{"name": "c"}}
+	if err := acme.Append(record); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if stored, _ := client.Get(record.Key); stored.Values["tenant"] != "acme" {
+		t.Errorf("appended record = %v, want stamped with the tenant", stored)
+	}
+
+	records, err := acme.Query(sheetkv.Query{})
+	if err != nil || len(records) != 2 {
+		t.Errorf("Query() = %v, %v, want the 2 acme records", records, err)
+	}
+
+	if _, err := acme.Get(3); err != sheetkv.ErrKeyNotFound {
+		t.Errorf("Get(other tenant) error = %v, want ErrKeyNotFound", err)
+	}
+	if err := acme.Update(3, map[string]interface{}{"name": "x"}); err != sheetkv.ErrKeyNotFound {
+		t.Errorf("Update(other tenant) error = %v, want ErrKeyNotFound", err)
+	}
+	if err := acme.Delete(3); err != sheetkv.ErrKeyNotFound {
+		t.Errorf("Delete(other tenant) error = %v, want ErrKeyNotFound", err)
+	}
+	if err := acme.Set(3, &sheetkv.Record{Values: map[string]interface{}{"name": "x"}}); !errors.Is(err, sheetkv.ErrOutOfScope) {
+		t.Errorf("Set(other tenant) error = %v, want ErrOutOfScope", err)
+	}
+
+	t.Run("Writes stay in the scope", func(t *testing.T) {
+		err := acme.Update(2, map[string]interface{}{"tenant": "globex"})
+		if !errors.Is(err, sheetkv.ErrOutOfScope) {
+			t.Errorf("Update() moving out of the scope error = %v, want ErrOutOfScope", err)
+		}
+		if stored, _ := client.Get(2); stored.Values["tenant"] != "acme" {
+			t.Errorf("record = %v, want unchanged", stored)
+		}
+	})
+}
+
+func TestClient_ScopedConcurrentSet(t *testing.T) {
+	client := sheetkv.New(newMemoryAdapter([]string{"tenant"}), &sheetkv.Config{DisableAutoSync: true})
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	defer client.Close()
+
+	// Each view is a new value, so only the client can serialize them
+	tenants := []string{"acme", "globex"}
+	for key := 2; key < 200; key++ {
+		var wg sync.WaitGroup
+		errs := make([]error, len(tenants))
+		for i, tenant := range tenants {
+			wg.Add(1)
+			go func(i int, tenant string) {
+				defer wg.Done()
+				view := client.Scoped(sheetkv.Condition{Column: "tenant", Operator: "==", Value: tenant})
+				errs[i] = view.Set(key, &sheetkv.Record{Values: map[string]interface{}{}})
+			}(i, tenant)
+		}
+		wg.Wait()
+
+		var written []string
+		for i, err := range errs {
+			if err == nil {
+				written = append(written, tenants[i])
+			} else if !errors.Is(err, sheetkv.ErrOutOfScope) {
+				t.Fatalf("Set(%d) error = %v", key, err)
+			}
+		}
+		if len(written) != 1 {
+			t.Fatalf("Set(%d) succeeded for %v, want exactly one tenant", key, written)
+		}
+		if stored, _ := client.Get(key); stored.Values["tenant"] != written[0] {
+			t.Fatalf("record %d = %v, want written by %s", key, stored.Values, written[0])
+		}
+	}
+}
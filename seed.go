@@ -0,0 +1,298 @@
+package sheetkv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Seed appends the records of the JSON and YAML fixture files of fsys, for
+// integration tests and demo environments. Files ending in .json, .yaml or
+// .yml are read in lexical order, and each holds a list of records written
+// as objects of column values:
+//
+//	# users.yaml
+//	- name: John Doe
+//	  age: 30
+//	  active: true
+//	- name: "Jane Doe"
+//	  note: null
+//
+// YAML fixtures are limited to such lists of plain mappings. Values are
+// coerced as the adapters load cells: whole numbers to int64, other numbers
+// to float64, and the text of columns with a Boolean validation rule to
+// bool. Nested lists and objects are rejected with ErrInvalidValue. The
+// records are appended with Import, and Seed returns how many were.
+func Seed(ctx context.Context, client *Client, fsys fs.FS) (int, error) {
+	var records []*Record
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		var parse func([]byte) ([]map[string]interface{}, error)
+		switch strings.ToLower(path.Ext(name)) {
+		case ".json":
+			parse = parseJSONFixture
+		case ".yaml", ".yml":
+			parse = parseYAMLFixture
+		default:
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		rows, err := parse(data)
+		if err != nil {
+			return fmt.Errorf("fixture %s: %w", name, err)
+		}
+		for i, row := range rows {
+			record, err := client.seedRecord(row)
+			if err != nil {
+				return fmt.Errorf("fixture %s: record %d: %w", name, i, err)
+			}
+			records = append(records, record)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return client.Import(ctx, SliceSource(records), ImportOptions{})
+}
+
+// seedRecord coerces the values of a fixture row
+func (c *Client) seedRecord(row map[string]interface{}) (*Record, error) {
+	record := &Record{Values: make(map[string]interface{}, len(row))}
+	for col, value := range row {
+		switch v := value.(type) {
+		case json.Number:
+			if i, err := v.Int64(); err == nil {
+				value = i
+			} else if f, err := v.Float64(); err == nil {
+				value = f
+			} else {
+				return nil, fmt.Errorf("%w: column %q: invalid number %s", ErrInvalidValue, col, v)
+			}
+		case string:
+			if c.booleanColumn(col) {
+				if b, err := strconv.ParseBool(v); err == nil {
+					value = b
+				}
+			}
+		case nil, bool, int64, float64:
+		default:
+			return nil, fmt.Errorf("%w: column %q: nested values are not supported", ErrInvalidValue, col)
+		}
+		record.Values[col] = value
+	}
+	return record, nil
+}
+
+// booleanColumn reports whether a validation rule limits col to booleans
+func (c *Client) booleanColumn(col string) bool {
+	for _, rule := range c.config.ValidationRules {
+		if rule.Column == col && rule.Boolean {
+			return true
+		}
+	}
+	return false
+}
+
+// parseJSONFixture reads a JSON array of objects
+func parseJSONFixture(data []byte) ([]map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var rows []map[string]interface{}
+	if err := decoder.Decode(&rows); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return rows, nil
+}
+
+// parseYAMLFixture reads a YAML list of plain mappings
+func parseYAMLFixture(data []byte) ([]map[string]interface{}, error) {
+	var rows []map[string]interface{}
+	var row map[string]interface{}
+	itemIndent, keyIndent := -1, -1
+
+	for n, line := range strings.Split(string(data), "\n") {
+		lineNo := n + 1
+		line = strings.TrimRight(stripYAMLComment(line), " \t\r")
+		content := strings.TrimLeft(line, " ")
+		if content == "" || content == "---" {
+			continue
+		}
+		if content == "[]" && rows == nil && row == nil {
+			continue
+		}
+		if strings.HasPrefix(content, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", lineNo)
+		}
+		indent := len(line) - len(content)
+
+		if content == "-" || strings.HasPrefix(content, "- ") {
+			if itemIndent >= 0 && indent != itemIndent {
+				return nil, fmt.Errorf("line %d: inconsistent indentation", lineNo)
+			}
+			itemIndent = indent
+			row = make(map[string]interface{})
+			rows = append(rows, row)
+			content = strings.TrimLeft(strings.TrimPrefix(content, "-"), " ")
+			keyIndent = len(line) - len(content)
+			if content == "" || content == "{}" {
+				keyIndent = -1
+				continue
+			}
+		} else if row == nil {
+			return nil, fmt.Errorf("line %d: expected a list of records", lineNo)
+		} else if keyIndent < 0 {
+			if indent <= itemIndent {
+				return nil, fmt.Errorf("line %d: inconsistent indentation", lineNo)
+			}
+			keyIndent = indent
+		} else if indent != keyIndent {
+			return nil, fmt.Errorf("line %d: inconsistent indentation", lineNo)
+		}
+
+		key, value, err := parseYAMLPair(content)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if _, exists := row[key]; exists {
+			return nil, fmt.Errorf("line %d: duplicate column %q", lineNo, key)
+		}
+		row[key] = value
+	}
+	return rows, nil
+}
+
+// parseYAMLPair reads a "key: value" line
+func parseYAMLPair(content string) (string, interface{}, error) {
+	var key, rest string
+	if content[0] == '"' || content[0] == '\'' {
+		end := closingQuote(content)
+		if end < 0 {
+			return "", nil, fmt.Errorf("unterminated quote")
+		}
+		k, err := parseYAMLScalar(content[:end+1])
+		if err != nil {
+			return "", nil, err
+		}
+		key, rest = fmt.Sprint(k), content[end+1:]
+		if !strings.HasPrefix(rest, ":") {
+			return "", nil, fmt.Errorf("expected : after the key")
+		}
+		rest = rest[1:]
+	} else {
+		i := strings.Index(content, ": ")
+		if i < 0 && strings.HasSuffix(content, ":") {
+			i = len(content) - 1
+		}
+		if i <= 0 {
+			return "", nil, fmt.Errorf("expected key: value")
+		}
+		key, rest = content[:i], content[i+1:]
+	}
+
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return "", nil, fmt.Errorf("column %q: nested values are not supported", key)
+	}
+	switch rest[0] {
+	case '[', '{', '|', '>', '&', '*', '!':
+		return "", nil, fmt.Errorf("column %q: only plain and quoted scalars are supported", key)
+	}
+	value, err := parseYAMLScalar(rest)
+	if err != nil {
+		return "", nil, fmt.Errorf("column %q: %w", key, err)
+	}
+	return key, value, nil
+}
+
+// parseYAMLScalar resolves a scalar like the YAML core schema: null, booleans,
+// whole numbers as int64, other numbers as float64 and the rest as strings
+func parseYAMLScalar(s string) (interface{}, error) {
+	switch s[0] {
+	case '"':
+		if closingQuote(s) != len(s)-1 {
+			return nil, fmt.Errorf("unexpected text after the quoted value")
+		}
+		value, err := strconv.Unquote(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quoted value %s", s)
+		}
+		return value, nil
+	case '\'':
+		if closingQuote(s) != len(s)-1 {
+			return nil, fmt.Errorf("unexpected text after the quoted value")
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	}
+
+	switch s {
+	case "~", "null", "Null", "NULL":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && strings.IndexFunc(s, isDigitRune) >= 0 {
+		return f, nil
+	}
+	return s, nil
+}
+
+// closingQuote returns the index of the quote closing the value starting
+// at s[0], -1 when it is not closed
+func closingQuote(s string) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote && quote == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// stripYAMLComment removes a comment starting outside quoted values
+func stripYAMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && (i == 0 || line[i-1] == ' '):
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func isDigitRune(r rune) bool {
+	return r >= '0' && r <= '9'
+}
@@ -0,0 +1,101 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestSeed(t *testing.T) {
+	fixtures := fstest.MapFS{
+		"01_users.yaml": {Data: []byte(`# Users of the demo
+- name: John Doe
+  age: 30
+  active: "true"
+  score: 4.5
+- name: 'O''Brien' # quoted
+  age: ~
+  note: "line\nbreak"
+-
+  name: Jane
+  zip: "00123"
+`)},
+		"02_more.json": {Data: []byte(`[{"name": "Bob", "age": 45, "score": 1.25, "active": false}]`)},
+		"README.md":    {Data: []byte("not a fixture")},
+	}
+
+	adapter := newMemoryAdapter([]string{"name"})
+	client := sheetkv.New(adapter, &sheetkv.Config{
+		DisableAutoSync: true,
+		ValidationRules: []sheetkv.ColumnRule{{Column: "active", Boolean: true}},
+	})
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	n, err := sheetkv.Seed(context.Background(), client, fixtures)
+	if err != nil {
+		t.Fatalf("Seed() error = %v", err)
+	}
+	if n != 4 {
+		t.Fatalf("Seed() = %d, want 4", n)
+	}
+
+	want := []map[string]interface{}{
+		{"name": "John Doe", "age": int64(30), "active": true, "score": 4.5},
+		{"name": "O'Brien", "age": nil, "note": "line\nbreak"},
+		{"name": "Jane", "zip": "00123"},
+		{"name": "Bob", "age": int64(45), "score": 1.25, "active": false},
+	}
+	for i, values := range want {
+		record, err := client.Get(i + 2)
+		if err != nil {
+			t.Fatalf("Get(%d) error = %v", i+2, err)
+		}
+		for col, value := range values {
+			if value == nil {
+				if record.Values[col] != nil {
+					t.Errorf("record %d: %s = %#v, want nil", i+2, col, record.Values[col])
+				}
+				continue
+			}
+			if !reflect.DeepEqual(record.Values[col], value) {
+				t.Errorf("record %d: %s = %#v, want %#v", i+2, col, record.Values[col], value)
+			}
+		}
+	}
+}
+
+func TestSeed_Errors(t *testing.T) {
+	tests := map[string]string{
+		"users.json":  `{"name": "not a list"}`,
+		"nested.json": `[{"tags": ["a", "b"]}]`,
+		"flow.yaml":   "- tags: [a, b]\n",
+		"indent.yaml": "- name: John\n    age: 30\n",
+		"map.yaml":    "name: John\n",
+		"quote.yaml":  "- name: \"open\n",
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := sheetkv.New(newMemoryAdapter(nil), &sheetkv.Config{DisableAutoSync: true})
+			if err := client.Initialize(context.Background()); err != nil {
+				t.Fatalf("Initialize() error = %v", err)
+			}
+			n, err := sheetkv.Seed(context.Background(), client, fstest.MapFS{name: {Data: []byte(data)}})
+			if err == nil {
+				t.Fatalf("Seed() should fail")
+			}
+			if n != 0 {
+				t.Errorf("Seed() = %d, want nothing appended", n)
+			}
+			if name == "nested.json" && !errors.Is(err, sheetkv.ErrInvalidValue) {
+				t.Errorf("Seed() error = %v, want ErrInvalidValue", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package sheetkv
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// SnapshotLayout is the layout of the labels of SnapshotJob, one snapshot a
+// day
+const SnapshotLayout = "2006-01-02"
+
+// SnapshotStore is implemented by backends keeping labeled copies of a
+// table, such as the dated tabs users_2024-06-01, users_2024-06-02, … of a
+// spreadsheet
+type SnapshotStore interface {
+	// Snapshots returns the labels of the existing snapshots
+	Snapshots(ctx context.Context) ([]string, error)
+
+	// CreateSnapshot returns the adapter of the snapshot labeled label,
+	// creating it when it does not exist
+	CreateSnapshot(ctx context.Context, label string) (Adapter, error)
+
+	// DeleteSnapshot removes the snapshot labeled label
+	DeleteSnapshot(ctx context.Context, label string) error
+}
+
+// SnapshotJob returns a job copying the data of the client into the
+// snapshot of store labeled with the current date (see SnapshotLayout),
+// then deleting the oldest snapshots beyond keep (0: all are kept). Later
+// runs on the same day replace that day's snapshot. Labels that are not
+// dates are left alone.
+//
+// The snapshot holds the cached data, unsaved changes included, with the
+// row numbers of the sheet.
+func SnapshotJob(store SnapshotStore, keep int, interval time.Duration) Job {
+	return Job{
+		Name:     "snapshot",
+		Interval: interval,
+		Run: func(ctx context.Context, client *Client) error {
+			return client.writeSnapshot(ctx, store, keep)
+		},
+	}
+}
+
+// writeSnapshot writes the snapshot of today and prunes the old ones
+func (c *Client) writeSnapshot(ctx context.Context, store SnapshotStore, keep int) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return fmt.Errorf("client is closed")
+	}
+	if err := c.readThroughTable(ctx); err != nil {
+		c.mu.Unlock()
+		return err
+	}
+	records := c.cache.GetAllRecords()
+	schema := c.cache.GetSchema()
+	c.mu.Unlock()
+
+	label := c.now().Format(SnapshotLayout)
+	stored, err := c.encodeRecords(ctx, records)
+	if err != nil {
+		return err
+	}
+	err = c.withRetry(ctx, func() error {
+		adapter, err := store.CreateSnapshot(ctx, label)
+		if err != nil {
+			return err
+		}
+		return adapter.Save(ctx, stored, schema, SyncStrategyGapPreserving)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot %s: %w", label, err)
+	}
+
+	if keep <= 0 {
+		return nil
+	}
+	var labels []string
+	err = c.withRetry(ctx, func() error {
+		var err error
+		labels, err = store.Snapshots(ctx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	dated := labels[:0]
+	for _, l := range labels {
+		if _, err := time.Parse(SnapshotLayout, l); err == nil {
+			dated = append(dated, l)
+		}
+	}
+	// The layout sorts chronologically
+	sort.Strings(dated)
+	for len(dated) > keep {
+		old := dated[0]
+		dated = dated[1:]
+		err := c.withRetry(ctx, func() error {
+			return store.DeleteSnapshot(ctx, old)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete snapshot %s: %w", old, err)
+		}
+	}
+	return nil
+}
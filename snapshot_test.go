@@ -0,0 +1,102 @@
+package sheetkv_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// memorySnapshots keeps snapshots as memory adapters
+type memorySnapshots struct {
+	tabs map[string]*memoryAdapter
+}
+
+func (s *memorySnapshots) Snapshots(ctx context.Context) ([]string, error) {
+	var labels []string
+	for label := range s.tabs {
+		labels = append(labels, label)
+	}
+	return labels, nil
+}
+
+func (s *memorySnapshots) CreateSnapshot(ctx context.Context, label string) (sheetkv.Adapter, error) {
+	if _, ok := s.tabs[label]; !ok {
+		s.tabs[label] = newMemoryAdapter(nil)
+	}
+	return s.tabs[label], nil
+}
+
+func (s *memorySnapshots) DeleteSnapshot(ctx context.Context, label string) error {
+	delete(s.tabs, label)
+	return nil
+}
+
+func (s *memorySnapshots) labels() []string {
+	labels, _ := s.Snapshots(context.Background())
+	sort.Strings(labels)
+	return labels
+}
+
+func TestSnapshotJob(t *testing.T) {
+	ctx := context.Background()
+	clock := sheetkv.NewFakeClock(time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC))
+	store := &memorySnapshots{tabs: map[string]*memoryAdapter{"manual": newMemoryAdapter(nil)}}
+
+	adapter := newMemoryAdapter([]string{"name"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "a"}},
+		&sheetkv.Record{Key: 4, Values: map[string]interface{}{"name": "b"}},
+	)
+	client := sheetkv.New(adapter, &sheetkv.Config{
+		DisableAutoSync: true,
+		Clock:           clock,
+		Jobs:            []sheetkv.Job{sheetkv.SnapshotJob(store, 2, 0)},
+	})
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	defer client.Close()
+
+	if err := client.RunJob(ctx, "snapshot"); err != nil {
+		t.Fatalf("RunJob() error = %v", err)
+	}
+	tab := store.tabs["2024-06-01"]
+	if tab == nil {
+		t.Fatalf("snapshots = %v, want 2024-06-01", store.labels())
+	}
+	if len(tab.records) != 2 || tab.records[1].Key != 4 || tab.records[1].Values["name"] != "b" {
+		t.Errorf("snapshot records = %v, want the rows of the sheet", tab.records)
+	}
+
+	t.Run("Replaces the snapshot of the day", func(t *testing.T) {
+		client.Set(5, &sheetkv.Record{Values: map[string]interface{}{"name": "c"}})
+		if err := client.RunJob(ctx, "snapshot"); err != nil {
+			t.Fatalf("RunJob() error = %v", err)
+		}
+		if got := len(store.tabs["2024-06-01"].records); got != 3 {
+			t.Errorf("snapshot records = %d, want 3", got)
+		}
+	})
+
+	t.Run("Keeps the newest dated snapshots", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			clock.Advance(24 * time.Hour)
+			if err := client.RunJob(ctx, "snapshot"); err != nil {
+				t.Fatalf("RunJob() error = %v", err)
+			}
+		}
+		got := store.labels()
+		want := []string{"2024-06-02", "2024-06-03", "manual"}
+		if len(got) != len(want) {
+			t.Fatalf("snapshots = %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("snapshots = %v, want %v", got, want)
+				break
+			}
+		}
+	})
+}
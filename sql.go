@@ -0,0 +1,234 @@
+package sheetkv
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SQLResult is the result of a statement run by Exec
+type SQLResult struct {
+	Columns      []string        // Columns of Rows, for SELECT
+	Rows         [][]interface{} // Selected values, nil for missing cells
+	Keys         []int           // Keys of the records of Rows
+	RowsAffected int             // Records updated or deleted, for UPDATE and DELETE
+}
+
+// sqlStatement is a parsed statement of Exec
+type sqlStatement struct {
+	verb    string // "select", "update" or "delete"
+	table   string
+	columns []string               // Selected columns, nil for *
+	set     map[string]interface{} // Assignments of UPDATE
+	query   Query
+}
+
+// Exec runs a statement of a small SQL subset against the client:
+//
+//	SELECT name, age FROM users WHERE age >= 30 ORDER BY age DESC LIMIT 10
+//	UPDATE users SET status = 'inactive', note = null WHERE last_login < '2024-01-01'
+//	DELETE FROM users WHERE status = 'deleted'
+//
+// WHERE takes the conditions of ParseQuery, and ORDER BY, LIMIT and OFFSET
+// may follow it in every statement. SELECT * selects the columns of the
+// schema. Setting a column to null removes it. The table name is not
+// checked; MultiClient.Exec runs statements on the table they name.
+//
+// UPDATE and DELETE apply their changes with Apply, so a statement failing
+// on one record changes none.
+func (c *Client) Exec(ctx context.Context, statement string) (*SQLResult, error) {
+	stmt, err := parseSQL(statement)
+	if err != nil {
+		return nil, err
+	}
+	return c.exec(ctx, stmt)
+}
+
+// Exec runs a statement of the SQL subset of Client.Exec on the table it
+// names
+func (m *MultiClient) Exec(ctx context.Context, statement string) (*SQLResult, error) {
+	stmt, err := parseSQL(statement)
+	if err != nil {
+		return nil, err
+	}
+	client, err := m.Table(stmt.table)
+	if err != nil {
+		return nil, err
+	}
+	return client.exec(ctx, stmt)
+}
+
+func (c *Client) exec(ctx context.Context, stmt *sqlStatement) (*SQLResult, error) {
+	records, err := c.QueryCtx(ctx, stmt.query)
+	if err != nil {
+		return nil, err
+	}
+
+	switch stmt.verb {
+	case "select":
+		columns := stmt.columns
+		if columns == nil {
+			columns = c.cache.GetSchema()
+		}
+		result := &SQLResult{
+			Columns: columns,
+			Rows:    make([][]interface{}, len(records)),
+			Keys:    make([]int, len(records)),
+		}
+		for i, record := range records {
+			row := make([]interface{}, len(columns))
+			for j, col := range columns {
+				row[j] = record.Values[col]
+			}
+			result.Rows[i] = row
+			result.Keys[i] = record.Key
+		}
+		return result, nil
+
+	case "update":
+		ops := make([]Operation, len(records))
+		for i, record := range records {
+			ops[i] = Operation{Type: OpUpdate, Record: &Record{Key: record.Key, Values: copyValues(stmt.set)}}
+		}
+		if err := c.Apply(ops); err != nil {
+			return nil, err
+		}
+		return &SQLResult{RowsAffected: len(ops)}, nil
+
+	default:
+		ops := make([]Operation, len(records))
+		for i, record := range records {
+			ops[i] = Operation{Type: OpDelete, Record: &Record{Key: record.Key}}
+		}
+		if err := c.Apply(ops); err != nil {
+			return nil, err
+		}
+		return &SQLResult{RowsAffected: len(ops)}, nil
+	}
+}
+
+// parseSQL parses a statement of Exec. Syntax errors are returned as a
+// *QuerySyntaxError.
+func parseSQL(text string) (*sqlStatement, error) {
+	p := &queryParser{text: text}
+	p.next()
+
+	stmt := &sqlStatement{}
+	var err error
+	switch {
+	case p.keyword("select"):
+		stmt.verb = "select"
+		p.next()
+		if stmt.columns, err = p.selectColumns(); err != nil {
+			return nil, err
+		}
+		if err = p.expect("from"); err != nil {
+			return nil, err
+		}
+		if stmt.table, err = p.column(); err != nil {
+			return nil, err
+		}
+	case p.keyword("update"):
+		stmt.verb = "update"
+		p.next()
+		if stmt.table, err = p.column(); err != nil {
+			return nil, err
+		}
+		if err = p.expect("set"); err != nil {
+			return nil, err
+		}
+		if stmt.set, err = p.assignments(); err != nil {
+			return nil, err
+		}
+	case p.keyword("delete"):
+		stmt.verb = "delete"
+		p.next()
+		if err = p.expect("from"); err != nil {
+			return nil, err
+		}
+		if stmt.table, err = p.column(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, p.errorf("expected SELECT, UPDATE or DELETE")
+	}
+
+	if p.keyword("where") {
+		p.next()
+		if p.tok.kind == tokenEnd || p.isClause() {
+			return nil, p.errorf("expected a condition after WHERE")
+		}
+	} else if p.tok.kind != tokenEnd && !p.isClause() {
+		return nil, p.errorf("expected WHERE")
+	}
+	if stmt.query, err = p.rest(); err != nil {
+		return nil, err
+	}
+
+	for _, value := range stmt.set {
+		if _, ok := value.(Param); ok {
+			return nil, fmt.Errorf("invalid query: placeholders are not supported by Exec")
+		}
+	}
+	for _, cond := range stmt.query.Conditions {
+		if len(conditionParams(cond.Value)) > 0 {
+			return nil, fmt.Errorf("invalid query: placeholders are not supported by Exec")
+		}
+	}
+	if err := ValidateQuery(stmt.query); err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+	return stmt, nil
+}
+
+// selectColumns parses the column list of SELECT, nil for *
+func (p *queryParser) selectColumns() ([]string, error) {
+	if p.punct("*") {
+		p.next()
+		return nil, nil
+	}
+
+	var columns []string
+	for {
+		column, err := p.column()
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+		if !p.punct(",") {
+			return columns, nil
+		}
+		p.next()
+	}
+}
+
+// assignments parses the column = value list of UPDATE
+func (p *queryParser) assignments() (map[string]interface{}, error) {
+	set := make(map[string]interface{})
+	for {
+		column, err := p.column()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokenOperator || (p.tok.text != "=" && p.tok.text != "==") {
+			return nil, p.errorf("expected = after %q", column)
+		}
+		p.next()
+		if set[column], err = p.value(); err != nil {
+			return nil, err
+		}
+		if !p.punct(",") {
+			return set, nil
+		}
+		p.next()
+	}
+}
+
+// expect consumes the keyword kw
+func (p *queryParser) expect(kw string) error {
+	if !p.keyword(kw) {
+		return p.errorf("expected %s", strings.ToUpper(kw))
+	}
+	p.next()
+	return nil
+}
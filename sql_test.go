@@ -0,0 +1,133 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func newSQLClient(t *testing.T) *sheetkv.Client {
+	t.Helper()
+	adapter := newMemoryAdapter([]string{"name", "dept", "age"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "dept": "Sales", "age": int64(30)}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane", "dept": "Dev", "age": int64(25)}},
+		&sheetkv.Record{Key: 4, Values: map[string]interface{}{"name": "Bob", "dept": "Sales", "age": int64(45)}},
+	)
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	return client
+}
+
+func TestClient_Exec(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Select", func(t *testing.T) {
+		client := newSQLClient(t)
+		result, err := client.Exec(ctx, "SELECT name, age FROM users WHERE dept = 'Sales' ORDER BY age DESC")
+		if err != nil {
+			t.Fatalf("Exec() error = %v", err)
+		}
+		want := &sheetkv.SQLResult{
+			Columns: []string{"name", "age"},
+			Rows:    [][]interface{}{{"Bob", int64(45)}, {"John", int64(30)}},
+			Keys:    []int{4, 2},
+		}
+		if !reflect.DeepEqual(result, want) {
+			t.Errorf("Exec() = %+v, want %+v", result, want)
+		}
+	})
+
+	t.Run("Select star", func(t *testing.T) {
+		client := newSQLClient(t)
+		result, err := client.Exec(ctx, "select * from users limit 1")
+		if err != nil {
+			t.Fatalf("Exec() error = %v", err)
+		}
+		if !reflect.DeepEqual(result.Columns, []string{"name", "dept", "age"}) || len(result.Rows) != 1 {
+			t.Errorf("Exec() = %+v, want one row of the schema", result)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		client := newSQLClient(t)
+		result, err := client.Exec(ctx, "UPDATE users SET dept = 'Field', age = null WHERE dept = 'Sales'")
+		if err != nil {
+			t.Fatalf("Exec() error = %v", err)
+		}
+		if result.RowsAffected != 2 {
+			t.Errorf("RowsAffected = %d, want 2", result.RowsAffected)
+		}
+		record, err := client.Get(4)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if record.Values["dept"] != "Field" {
+			t.Errorf("dept = %v, want Field", record.Values["dept"])
+		}
+		if _, ok := record.Values["age"]; ok {
+			t.Error("setting null should remove the column")
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		client := newSQLClient(t)
+		result, err := client.Exec(ctx, "DELETE FROM users WHERE age < 40")
+		if err != nil {
+			t.Fatalf("Exec() error = %v", err)
+		}
+		if result.RowsAffected != 2 {
+			t.Errorf("RowsAffected = %d, want 2", result.RowsAffected)
+		}
+		if _, err := client.Get(2); !errors.Is(err, sheetkv.ErrKeyNotFound) {
+			t.Errorf("Get() error = %v, want ErrKeyNotFound", err)
+		}
+		if _, err := client.Get(4); err != nil {
+			t.Errorf("Get() error = %v", err)
+		}
+	})
+
+	t.Run("Syntax errors", func(t *testing.T) {
+		client := newSQLClient(t)
+		for _, stmt := range []string{
+			"",
+			"INSERT INTO users VALUES (1)",
+			"SELECT name users",
+			"SELECT * FROM users age > 3",
+			"SELECT * FROM users WHERE",
+			"UPDATE users dept = 'x'",
+			"DELETE users",
+		} {
+			var syntaxErr *sheetkv.QuerySyntaxError
+			if _, err := client.Exec(ctx, stmt); !errors.As(err, &syntaxErr) {
+				t.Errorf("Exec(%q) error = %v, want a *QuerySyntaxError", stmt, err)
+			}
+		}
+		if _, err := client.Exec(ctx, "DELETE FROM users WHERE age > :min"); err == nil {
+			t.Error("Exec() should reject placeholders")
+		}
+	})
+}
+
+func TestMultiClient_Exec(t *testing.T) {
+	multi := sheetkv.NewMultiClient(nil)
+	if err := multi.Add("users", newSQLClient(t)); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	result, err := multi.Exec(context.Background(), "SELECT name FROM users WHERE age > 40")
+	if err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+	if !reflect.DeepEqual(result.Rows, [][]interface{}{{"Bob"}}) {
+		t.Errorf("Rows = %v, want [[Bob]]", result.Rows)
+	}
+
+	if _, err := multi.Exec(context.Background(), "SELECT * FROM orders"); !errors.Is(err, sheetkv.ErrTableNotFound) {
+		t.Errorf("Exec() error = %v, want ErrTableNotFound", err)
+	}
+}
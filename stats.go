@@ -0,0 +1,83 @@
+package sheetkv
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a point-in-time snapshot of a client's state, meant for health
+// and admin pages
+type Stats struct {
+	Records          int           // Records in the cache
+	Dirty            int           // Records modified since the last sync
+	SchemaSize       int           // Columns in the schema
+	LastSync         time.Time     // End of the last sync attempt (zero before the first)
+	LastSyncDuration time.Duration // Duration of the last sync attempt
+	LastSyncError    error         // Error of the last sync attempt, nil on success
+	APICalls         int64         // Adapter calls made, including retries
+	Retries          int64         // Adapter calls that were retries of a failed call
+	Cells            int           // Cells of the spreadsheet as counted for Config.CellLimit
+	CellLimit        int           // Config.CellLimit (0 when unchecked)
+	NearCellLimit    bool          // Cells reached Config.CellLimitWarning of the limit
+	Reachable        bool          // The last Ping succeeded, true before the first
+	LastPing         time.Time     // End of the last Ping (zero before the first)
+	LastPingError    error         // Error of the last Ping, nil on success
+}
+
+// clientStats holds the counters behind Stats
+type clientStats struct {
+	apiCalls atomic.Int64
+	retries  atomic.Int64
+
+	mu               sync.Mutex
+	lastSync         time.Time
+	lastSyncDuration time.Duration
+	lastSyncError    error
+	lastPing         time.Time
+	lastPingError    error
+}
+
+// recordSync stores the outcome of a sync that started at start
+func (s *clientStats) recordSync(start time.Time, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastSync = time.Now()
+	s.lastSyncDuration = s.lastSync.Sub(start)
+	s.lastSyncError = err
+}
+
+// recordPing stores the outcome of a Ping
+func (s *clientStats) recordPing(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastPing = time.Now()
+	s.lastPingError = err
+}
+
+// Stats returns a snapshot of the client's state
+func (c *Client) Stats() Stats {
+	stats := Stats{
+		Records:    c.cache.Size(),
+		Dirty:      len(c.cache.GetDirtyKeys()),
+		SchemaSize: len(c.cache.GetSchema()),
+		APICalls:   c.stats.apiCalls.Load(),
+		Retries:    c.stats.retries.Load(),
+		Cells:      c.cells(),
+		CellLimit:  c.config.CellLimit,
+	}
+	stats.NearCellLimit = c.nearCellLimit(stats.Cells)
+
+	c.stats.mu.Lock()
+	stats.LastSync = c.stats.lastSync
+	stats.LastSyncDuration = c.stats.lastSyncDuration
+	stats.LastSyncError = c.stats.lastSyncError
+	stats.LastPing = c.stats.lastPing
+	stats.LastPingError = c.stats.lastPingError
+	stats.Reachable = c.stats.lastPingError == nil
+	c.stats.mu.Unlock()
+
+	return stats
+}
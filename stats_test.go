@@ -0,0 +1,71 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_Stats(t *testing.T) {
+	adapter := newMemoryAdapter([]string{"name", "age"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30)}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane", "age": int64(25)}},
+	)
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true, MaxRetries: 2})
+
+	if stats := client.Stats(); !stats.LastSync.IsZero() || stats.APICalls != 0 {
+		t.Errorf("Stats() before use = %+v, want zero sync and no calls", stats)
+	}
+
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	client.Update(2, map[string]interface{}{"age": int64(31)})
+
+	stats := client.Stats()
+	if stats.Records != 2 || stats.Dirty != 1 || stats.SchemaSize != 2 {
+		t.Errorf("Stats() = %+v, want 2 records, 1 dirty, 2 columns", stats)
+	}
+	if stats.APICalls != 1 {
+		t.Errorf("APICalls = %d, want 1", stats.APICalls)
+	}
+
+	t.Run("Failed sync", func(t *testing.T) {
+		adapter.mu.Lock()
+		adapter.saveErr = errors.New("boom")
+		adapter.mu.Unlock()
+
+		if err := client.Sync(); err == nil {
+			t.Fatal("Sync() should fail")
+		}
+
+		stats := client.Stats()
+		if stats.LastSync.IsZero() || stats.LastSyncError == nil {
+			t.Errorf("Stats() = %+v, want failed sync recorded", stats)
+		}
+		// Load, then three save attempts
+		if stats.APICalls != 4 || stats.Retries != 2 {
+			t.Errorf("APICalls = %d, Retries = %d, want 4 and 2", stats.APICalls, stats.Retries)
+		}
+	})
+
+	t.Run("Successful sync", func(t *testing.T) {
+		adapter.mu.Lock()
+		adapter.saveErr = nil
+		adapter.mu.Unlock()
+
+		if err := client.Sync(); err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+
+		stats := client.Stats()
+		if stats.LastSyncError != nil || stats.Dirty != 0 {
+			t.Errorf("Stats() = %+v, want clean successful sync", stats)
+		}
+		if stats.LastSyncDuration < 0 {
+			t.Errorf("LastSyncDuration = %v, want >= 0", stats.LastSyncDuration)
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package sheetkv
+
+import (
+	"context"
+	"fmt"
+)
+
+// checkWritable refuses writes with Config.TailAppends
+func (c *Client) checkWritable() error {
+	if c.config.TailAppends {
+		return ErrReadOnly
+	}
+	return nil
+}
+
+// Tail reads the rows appended to the sheet after the last key seen, adds
+// them to the cache and reports them to Watch and the change feed as added
+// records, returning how many there were. It is the sync of
+// Config.TailAppends, for append-only sheets such as the responses of a
+// Google Form: only the new rows are read from adapters implementing
+// RowTailer, other adapters load the whole sheet. Rows edited or deleted
+// above the last key are not picked up; Reload reads them.
+//
+// Localized headers, such as the "タイムスタンプ" column of a form, are
+// mapped to the names used in code by the adapter's column aliases.
+func (c *Client) Tail(ctx context.Context) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return 0, fmt.Errorf("client is closed")
+	}
+
+	last := c.cache.maxKey()
+	var records []*Record
+	var schema []string
+	err := c.withRetry(ctx, func() error {
+		var err error
+		if tailer, ok := c.adaptor.(RowTailer); ok {
+			records, schema, err = tailer.LoadAfter(ctx, last)
+		} else {
+			records, schema, err = c.adaptor.Load(ctx)
+		}
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := c.decodeRecords(ctx, records); err != nil {
+		return 0, err
+	}
+
+	added := 0
+	for _, record := range records {
+		if record.Key <= last || len(record.Values) == 0 {
+			continue
+		}
+		c.cache.refreshRecord(record.Key, record, schema)
+		added++
+		if c.watching() {
+			if stored, err := c.cache.Get(record.Key); err == nil {
+				c.notifyWatchers(mutation{
+					op:      OpAdd,
+					key:     record.Key,
+					updated: stored,
+					columns: changedColumns(nil, stored),
+					time:    c.now(),
+				})
+			}
+		}
+	}
+	c.loaded.Store(true)
+	return added, nil
+}
@@ -0,0 +1,73 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_Tail(t *testing.T) {
+	ctx := context.Background()
+	adapter := newMemoryAdapter([]string{"timestamp", "email"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"timestamp": "2024/06/01 9:00:00", "email": "a@example.com"}},
+	)
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true, TailAppends: true})
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	_, since, _ := client.Changes(0)
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	changes := client.Watch(watchCtx)
+
+	adapter.mu.Lock()
+	adapter.records = append(adapter.records,
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"timestamp": "2024/06/01 9:05:00", "email": "b@example.com"}},
+		&sheetkv.Record{Key: 4, Values: map[string]interface{}{}},
+		&sheetkv.Record{Key: 5, Values: map[string]interface{}{"timestamp": "2024/06/01 9:10:00", "email": "c@example.com"}},
+	)
+	adapter.mu.Unlock()
+
+	added, err := client.Tail(ctx)
+	if err != nil || added != 2 {
+		t.Fatalf("Tail() = %d, %v, want 2", added, err)
+	}
+	for _, key := range []int{3, 5} {
+		select {
+		case change := <-changes:
+			if change.Op != sheetkv.OpAdd || change.Key != key || change.New.Values["email"] == nil {
+				t.Errorf("change = %+v, want the add of %d", change, key)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no change for %d", key)
+		}
+	}
+	if feed, _, _ := client.Changes(since); len(feed) != 2 {
+		t.Errorf("Changes() = %v, want the 2 new rows", feed)
+	}
+
+	if added, err := client.Tail(ctx); err != nil || added != 0 {
+		t.Errorf("second Tail() = %d, %v, want 0", added, err)
+	}
+
+	t.Run("Refuses writes", func(t *testing.T) {
+		err := client.Append(&sheetkv.Record{Values: map[string]interface{}{"email": "d@example.com"}})
+		if !errors.Is(err, sheetkv.ErrReadOnly) {
+			t.Errorf("Append() error = %v, want ErrReadOnly", err)
+		}
+		if err := client.Delete(2); !errors.Is(err, sheetkv.ErrReadOnly) {
+			t.Errorf("Delete() error = %v, want ErrReadOnly", err)
+		}
+	})
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if saves := adapter.saveCount(); saves != 0 {
+		t.Errorf("saves = %d, want none from a tailing client", saves)
+	}
+}
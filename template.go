@@ -0,0 +1,75 @@
+package sheetkv
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+)
+
+// RecordTemplate describes the records of one kind for
+// Client.NewRecordFromTemplate, see Config.Templates
+type RecordTemplate struct {
+	Defaults  map[string]interface{} // Values of the columns not given
+	Generated map[string]Generator   // Columns filled by a generator when not given
+	Required  []string               // Columns that must end up with a non-empty value
+}
+
+// Generator returns the value of a generated column. now is the time of the
+// client's Config.Clock.
+type Generator func(now time.Time) (interface{}, error)
+
+// GenerateUUID generates random (version 4) UUIDs
+func GenerateUUID(time.Time) (interface{}, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate UUID: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// GenerateTimestamp generates the current time formatted with layout
+func GenerateTimestamp(layout string) Generator {
+	return func(now time.Time) (interface{}, error) {
+		return now.Format(layout), nil
+	}
+}
+
+// NewRecordFromTemplate returns a new record built from the template
+// registered under name in Config.Templates: values, then generated columns,
+// then the template defaults. It fails with ErrInvalidValue when a required
+// column is missing or empty. The record has no key; pass it to Append, or
+// to Set with a key.
+func (c *Client) NewRecordFromTemplate(name string, values map[string]interface{}) (*Record, error) {
+	template, ok := c.config.Templates[name]
+	if !ok {
+		return nil, fmt.Errorf("template %q: %w", name, ErrTemplateNotFound)
+	}
+
+	record := &Record{Values: make(map[string]interface{}, len(template.Defaults)+len(template.Generated)+len(values))}
+	for col, value := range template.Defaults {
+		record.Values[col] = value
+	}
+	now := c.now()
+	for col, generate := range template.Generated {
+		if _, given := values[col]; given {
+			continue
+		}
+		value, err := generate(now)
+		if err != nil {
+			return nil, fmt.Errorf("template %q: column %q: %w", name, col, err)
+		}
+		record.Values[col] = value
+	}
+	for col, value := range values {
+		record.Values[col] = value
+	}
+
+	for _, col := range template.Required {
+		if value := record.Values[col]; value == nil || value == "" {
+			return nil, fmt.Errorf("%w: template %q requires column %q", ErrInvalidValue, name, col)
+		}
+	}
+	return record, nil
+}
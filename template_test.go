@@ -0,0 +1,91 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_NewRecordFromTemplate(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	client := sheetkv.New(newMemoryAdapter([]string{"id", "name"}), &sheetkv.Config{
+		DisableAutoSync: true,
+		Clock:           sheetkv.NewFakeClock(now),
+		Templates: map[string]sheetkv.RecordTemplate{
+			"user": {
+				Defaults: map[string]interface{}{"status": "active", "role": "member"},
+				Generated: map[string]sheetkv.Generator{
+					"id":         sheetkv.GenerateUUID,
+					"created_at": sheetkv.GenerateTimestamp(time.RFC3339),
+				},
+				Required: []string{"name"},
+			},
+		},
+	})
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	t.Run("Defaults, generated and given values", func(t *testing.T) {
+		record, err := client.NewRecordFromTemplate("user", map[string]interface{}{"name": "John", "role": "admin"})
+		if err != nil {
+			t.Fatalf("NewRecordFromTemplate() error = %v", err)
+		}
+		uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+		if id, _ := record.Values["id"].(string); !uuid.MatchString(id) {
+			t.Errorf("id = %v, want a UUID", record.Values["id"])
+		}
+		want := map[string]interface{}{"status": "active", "role": "admin", "name": "John", "created_at": "2024-05-01T09:00:00Z"}
+		for col, value := range want {
+			if record.Values[col] != value {
+				t.Errorf("%s = %v, want %v", col, record.Values[col], value)
+			}
+		}
+
+		if err := client.Append(record); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	})
+
+	t.Run("Given values skip generators", func(t *testing.T) {
+		record, err := client.NewRecordFromTemplate("user", map[string]interface{}{"name": "Jane", "id": "fixed"})
+		if err != nil {
+			t.Fatalf("NewRecordFromTemplate() error = %v", err)
+		}
+		if record.Values["id"] != "fixed" {
+			t.Errorf("id = %v, want fixed", record.Values["id"])
+		}
+	})
+
+	t.Run("Required columns", func(t *testing.T) {
+		_, err := client.NewRecordFromTemplate("user", map[string]interface{}{"name": ""})
+		if !errors.Is(err, sheetkv.ErrInvalidValue) {
+			t.Errorf("NewRecordFromTemplate() error = %v, want ErrInvalidValue", err)
+		}
+	})
+
+	t.Run("Unknown template", func(t *testing.T) {
+		_, err := client.NewRecordFromTemplate("order", nil)
+		if !errors.Is(err, sheetkv.ErrTemplateNotFound) {
+			t.Errorf("NewRecordFromTemplate() error = %v, want ErrTemplateNotFound", err)
+		}
+	})
+
+	t.Run("Generator errors", func(t *testing.T) {
+		failing := sheetkv.New(newMemoryAdapter(nil), &sheetkv.Config{
+			DisableAutoSync: true,
+			Templates: map[string]sheetkv.RecordTemplate{
+				"broken": {Generated: map[string]sheetkv.Generator{
+					"id": func(time.Time) (interface{}, error) { return nil, errors.New("no ids left") },
+				}},
+			},
+		})
+		if _, err := failing.NewRecordFromTemplate("broken", nil); err == nil {
+			t.Error("NewRecordFromTemplate() should return the generator error")
+		}
+	})
+}
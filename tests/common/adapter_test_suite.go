@@ -17,8 +17,8 @@ type AdapterTestCase struct {
 // CreateTestClient creates a test client with the given adapter
 func CreateTestClient(t *testing.T, adapter sheetkv.Adapter) *sheetkv.Client {
 	clientConfig := &sheetkv.Config{
-		SyncInterval: 0, // No auto-sync for tests
-		MaxRetries:   3,
+		DisableAutoSync: true, // No auto-sync for tests
+		MaxRetries:      3,
 	}
 
 	client := sheetkv.New(adapter, clientConfig)
@@ -0,0 +1,25 @@
+package common
+
+import (
+	"context"
+
+	sheetkv "github.com/ideamans/go-sheetkv"
+)
+
+// StaticAdapter loads fixed records and discards saves
+type StaticAdapter struct {
+	Records []*sheetkv.Record
+	Schema  []string
+}
+
+func (a *StaticAdapter) Load(ctx context.Context) ([]*sheetkv.Record, []string, error) {
+	return a.Records, a.Schema, nil
+}
+
+func (a *StaticAdapter) Save(ctx context.Context, records []*sheetkv.Record, schema []string, strategy sheetkv.SyncStrategy) error {
+	return nil
+}
+
+func (a *StaticAdapter) BatchUpdate(ctx context.Context, operations []sheetkv.Operation) error {
+	return nil
+}
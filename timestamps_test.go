@@ -0,0 +1,107 @@
+package sheetkv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_Timestamps(t *testing.T) {
+	const layout = "2006-01-02 15:04:05"
+
+	newClient := func(t *testing.T) *sheetkv.Client {
+		client := sheetkv.New(newMemoryAdapter(nil), &sheetkv.Config{
+			DisableAutoSync: true,
+			CreatedAtColumn: "created_at",
+			UpdatedAtColumn: "updated_at",
+			TimeFormat:      layout,
+		})
+		if err := client.Initialize(context.Background()); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		return client
+	}
+
+	parse := func(t *testing.T, v interface{}) time.Time {
+		s, ok := v.(string)
+		if !ok {
+			t.Fatalf("timestamp = %v (%T), want string", v, v)
+		}
+		ts, err := time.ParseInLocation(layout, s, time.Local)
+		if err != nil {
+			t.Fatalf("timestamp %q does not match layout: %v", s, err)
+		}
+		return ts
+	}
+
+	t.Run("Append stamps both columns", func(t *testing.T) {
+		client := newClient(t)
+		values := map[string]interface{}{"name": "John"}
+		record := &sheetkv.Record{Values: values}
+		if err := client.Append(record); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+
+		got, _ := client.Get(record.Key)
+		created := parse(t, got.Values["created_at"])
+		if got.Values["updated_at"] != got.Values["created_at"] {
+			t.Errorf("updated_at = %v, want %v", got.Values["updated_at"], got.Values["created_at"])
+		}
+		if time.Since(created) > time.Minute {
+			t.Errorf("created_at = %v, want about now", created)
+		}
+		if _, ok := values["created_at"]; ok {
+			t.Error("Append() modified the caller's values map")
+		}
+	})
+
+	t.Run("Update stamps updated_at only", func(t *testing.T) {
+		client := newClient(t)
+		record := &sheetkv.Record{Values: map[string]interface{}{"name": "John", "created_at": "2000-01-01 00:00:00"}}
+		client.Append(record)
+
+		updates := map[string]interface{}{"name": "Johnny"}
+		if err := client.Update(record.Key, updates); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+
+		got, _ := client.Get(record.Key)
+		if got.Values["created_at"] != "2000-01-01 00:00:00" {
+			t.Errorf("created_at = %v, want the value given on Append", got.Values["created_at"])
+		}
+		parse(t, got.Values["updated_at"])
+		if _, ok := updates["updated_at"]; ok {
+			t.Error("Update() modified the caller's updates map")
+		}
+	})
+
+	t.Run("Set preserves created_at of existing records", func(t *testing.T) {
+		client := newClient(t)
+		record := &sheetkv.Record{Values: map[string]interface{}{"name": "John"}}
+		client.Append(record)
+		before, _ := client.Get(record.Key)
+
+		client.Set(record.Key, &sheetkv.Record{Values: map[string]interface{}{"name": "Jane"}})
+		got, _ := client.Get(record.Key)
+		if got.Values["created_at"] != before.Values["created_at"] {
+			t.Errorf("created_at = %v, want %v", got.Values["created_at"], before.Values["created_at"])
+		}
+		parse(t, got.Values["updated_at"])
+
+		client.Set(100, &sheetkv.Record{Values: map[string]interface{}{"name": "Bob"}})
+		got, _ = client.Get(100)
+		parse(t, got.Values["created_at"])
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		client := sheetkv.New(newMemoryAdapter(nil), &sheetkv.Config{DisableAutoSync: true})
+		record := &sheetkv.Record{Values: map[string]interface{}{"name": "John"}}
+		client.Append(record)
+		got, _ := client.Get(record.Key)
+		if len(got.Values) != 1 {
+			t.Errorf("Values = %v, want only name", got.Values)
+		}
+	})
+}
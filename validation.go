@@ -0,0 +1,174 @@
+package sheetkv
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ColumnRule restricts the values written to a column, mirroring the
+// data-validation rules of the spreadsheet UI. Blank values are always
+// allowed.
+type ColumnRule struct {
+	Column       string
+	OneOf        []string // Allowed values, as offered by a dropdown
+	Min          *float64 // Lower numeric bound
+	Max          *float64 // Upper numeric bound
+	MinExclusive bool     // Min itself is not allowed
+	MaxExclusive bool     // Max itself is not allowed
+	Boolean      bool     // Only true or false (checkboxes)
+}
+
+// Check reports whether value satisfies the rule
+func (r ColumnRule) Check(value interface{}) error {
+	if value == nil || value == "" {
+		return nil
+	}
+
+	if len(r.OneOf) > 0 {
+		text := fmt.Sprintf("%v", value)
+		if !containsString(r.OneOf, text) {
+			return fmt.Errorf("%w: column %q must be one of %v, got %q", ErrInvalidValue, r.Column, r.OneOf, text)
+		}
+	}
+
+	if r.Boolean {
+		if _, ok := value.(bool); !ok {
+			if s := strings.ToLower(fmt.Sprintf("%v", value)); s != "true" && s != "false" {
+				return fmt.Errorf("%w: column %q must be a boolean, got %v", ErrInvalidValue, r.Column, value)
+			}
+		}
+	}
+
+	if r.Min != nil || r.Max != nil {
+		f, ok := ruleNumber(value)
+		if !ok {
+			return fmt.Errorf("%w: column %q must be a number, got %v", ErrInvalidValue, r.Column, value)
+		}
+		if r.Min != nil && (f < *r.Min || r.MinExclusive && f == *r.Min) {
+			return fmt.Errorf("%w: column %q must be above %v, got %v", ErrInvalidValue, r.Column, *r.Min, value)
+		}
+		if r.Max != nil && (f > *r.Max || r.MaxExclusive && f == *r.Max) {
+			return fmt.Errorf("%w: column %q must be below %v, got %v", ErrInvalidValue, r.Column, *r.Max, value)
+		}
+	}
+
+	return nil
+}
+
+// ruleNumber converts numbers and numeric strings to float64
+func ruleNumber(value interface{}) (float64, bool) {
+	if isNumeric(value) {
+		return toFloat64(value), true
+	}
+	if s, ok := value.(string); ok {
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// loadValidationRules combines Config.ValidationRules with the rules read
+// from the adapter when Config.EnforceSheetValidation is set
+func (c *Client) loadValidationRules(ctx context.Context) error {
+	rules := groupRules(nil, c.config.ValidationRules)
+
+	if source, ok := c.adaptor.(ValidationRuleSource); ok && c.config.EnforceSheetValidation {
+		var sheetRules []ColumnRule
+		err := c.withRetry(ctx, func() error {
+			var err error
+			sheetRules, err = source.ValidationRules(ctx)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to load validation rules: %w", err)
+		}
+		rules = groupRules(rules, sheetRules)
+	}
+
+	c.rulesMu.Lock()
+	defer c.rulesMu.Unlock()
+	c.rules = rules
+	return nil
+}
+
+// groupRules adds rules to the per-column map
+func groupRules(groups map[string][]ColumnRule, rules []ColumnRule) map[string][]ColumnRule {
+	if groups == nil {
+		groups = make(map[string][]ColumnRule)
+	}
+	for _, rule := range rules {
+		groups[rule.Column] = append(groups[rule.Column], rule)
+	}
+	return groups
+}
+
+// checkValues validates values against the column rules
+func (c *Client) checkValues(values map[string]interface{}) error {
+	c.rulesMu.RLock()
+	defer c.rulesMu.RUnlock()
+
+	for col, value := range values {
+		for _, rule := range c.rules[col] {
+			if err := rule.Check(value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Validator checks whole records before the client accepts a write, for
+// business rules spanning columns, see Config.Validators
+type Validator interface {
+	// Validate returns an error when record must not be written
+	Validate(r *Record) error
+}
+
+// ValidatorFunc adapts a function to Validator
+type ValidatorFunc func(r *Record) error
+
+// Validate calls f(r)
+func (f ValidatorFunc) Validate(r *Record) error {
+	return f(r)
+}
+
+// checkRecord runs Config.Validators on the record at key as a write of
+// values leaves it: values replace the record, or are merged into it when
+// merge is set (nil values removing columns). Errors wrap ErrInvalidValue.
+func (c *Client) checkRecord(key int, values map[string]interface{}, merge bool) error {
+	if len(c.config.Validators) == 0 {
+		return nil
+	}
+
+	record := &Record{Key: key, Values: copyValues(values)}
+	if merge {
+		existing, err := c.cache.Get(key)
+		if err != nil {
+			// The write fails with ErrKeyNotFound
+			return nil
+		}
+		record.Values = copyValues(existing.Values)
+		for col, value := range values {
+			if value == nil {
+				delete(record.Values, col)
+			} else {
+				record.Values[col] = value
+			}
+		}
+	}
+
+	return c.validateRecord(record)
+}
+
+// validateRecord runs Config.Validators on record. Errors wrap
+// ErrInvalidValue.
+func (c *Client) validateRecord(record *Record) error {
+	for _, v := range c.config.Validators {
+		if err := v.Validate(record); err != nil {
+			return fmt.Errorf("%w: record %d: %w", ErrInvalidValue, record.Key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestColumnRule_Check(t *testing.T) {
+	zero, hundred := 0.0, 100.0
+
+	tests := []struct {
+		name    string
+		rule    sheetkv.ColumnRule
+		value   interface{}
+		wantErr bool
+	}{
+		{"Listed value", sheetkv.ColumnRule{Column: "status", OneOf: []string{"open", "closed"}}, "open", false},
+		{"Unlisted value", sheetkv.ColumnRule{Column: "status", OneOf: []string{"open", "closed"}}, "pending", true},
+		{"Blank value", sheetkv.ColumnRule{Column: "status", OneOf: []string{"open"}}, "", false},
+		{"Number in range", sheetkv.ColumnRule{Column: "score", Min: &zero, Max: &hundred}, int64(50), false},
+		{"Numeric string in range", sheetkv.ColumnRule{Column: "score", Min: &zero, Max: &hundred}, "100", false},
+		{"Number above range", sheetkv.ColumnRule{Column: "score", Min: &zero, Max: &hundred}, 101.5, true},
+		{"Exclusive bound", sheetkv.ColumnRule{Column: "score", Min: &zero, MinExclusive: true}, 0, true},
+		{"Not a number", sheetkv.ColumnRule{Column: "score", Max: &hundred}, "many", true},
+		{"Boolean", sheetkv.ColumnRule{Column: "active", Boolean: true}, true, false},
+		{"Boolean string", sheetkv.ColumnRule{Column: "active", Boolean: true}, "FALSE", false},
+		{"Not a boolean", sheetkv.ColumnRule{Column: "active", Boolean: true}, "yes", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Check(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Check(%v) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, sheetkv.ErrInvalidValue) {
+				t.Errorf("Check(%v) error = %v, want %v", tt.value, err, sheetkv.ErrInvalidValue)
+			}
+		})
+	}
+}
+
+// ruleAdapter is a memoryAdapter reporting data-validation rules
+type ruleAdapter struct {
+	*memoryAdapter
+	rules []sheetkv.ColumnRule
+}
+
+func (a *ruleAdapter) ValidationRules(ctx context.Context) ([]sheetkv.ColumnRule, error) {
+	return a.rules, nil
+}
+
+func TestClient_ValidationRules(t *testing.T) {
+	hundred := 100.0
+	adapter := &ruleAdapter{
+		memoryAdapter: newMemoryAdapter([]string{"status", "score"},
+			&sheetkv.Record{Key: 2, Values: map[string]interface{}{"status": "open", "score": int64(10)}},
+		),
+		rules: []sheetkv.ColumnRule{{Column: "status", OneOf: []string{"open", "closed"}}},
+	}
+	client := sheetkv.New(adapter, &sheetkv.Config{
+		DisableAutoSync:        true,
+		ValidationRules:        []sheetkv.ColumnRule{{Column: "score", Max: &hundred}},
+		EnforceSheetValidation: true,
+	})
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	t.Run("Sheet rule", func(t *testing.T) {
+		err := client.Append(&sheetkv.Record{Values: map[string]interface{}{"status": "pending"}})
+		if !errors.Is(err, sheetkv.ErrInvalidValue) {
+			t.Errorf("Append() error = %v, want %v", err, sheetkv.ErrInvalidValue)
+		}
+		if err := client.Update(2, map[string]interface{}{"status": "closed"}); err != nil {
+			t.Errorf("Update() error = %v", err)
+		}
+	})
+
+	t.Run("Configured rule", func(t *testing.T) {
+		err := client.Set(2, &sheetkv.Record{Values: map[string]interface{}{"status": "open", "score": int64(150)}})
+		if !errors.Is(err, sheetkv.ErrInvalidValue) {
+			t.Errorf("Set() error = %v, want %v", err, sheetkv.ErrInvalidValue)
+		}
+		if err := client.Update(2, map[string]interface{}{"score": int64(150)}); !errors.Is(err, sheetkv.ErrInvalidValue) {
+			t.Errorf("Update() error = %v, want %v", err, sheetkv.ErrInvalidValue)
+		}
+	})
+
+	t.Run("Rejected writes leave the record unchanged", func(t *testing.T) {
+		record, err := client.Get(2)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if record.Values["status"] != "closed" || record.Values["score"] != int64(10) {
+			t.Errorf("record = %v, want status closed and score 10", record.Values)
+		}
+	})
+}
+
+func TestClient_Validators(t *testing.T) {
+	positiveSalary := sheetkv.ValidatorFunc(func(r *sheetkv.Record) error {
+		if salary, ok := r.Values["salary"].(int); ok && salary <= 0 {
+			return errors.New("salary must be positive")
+		}
+		if r.Values["role"] == "manager" && r.Values["team"] == nil {
+			return errors.New("managers need a team")
+		}
+		return nil
+	})
+	adapter := newMemoryAdapter([]string{"name", "salary", "role", "team"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "salary": 100, "role": "manager", "team": "Sales"}},
+	)
+	client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true, Validators: []sheetkv.Validator{positiveSalary}})
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	if err := client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "Jane", "salary": -5}}); !errors.Is(err, sheetkv.ErrInvalidValue) {
+		t.Errorf("Append() error = %v, want ErrInvalidValue", err)
+	}
+	if err := client.Set(3, &sheetkv.Record{Values: map[string]interface{}{"name": "Bob", "role": "manager"}}); !errors.Is(err, sheetkv.ErrInvalidValue) {
+		t.Errorf("Set() error = %v, want ErrInvalidValue", err)
+	}
+
+	// Updates are checked as merged into the record
+	if err := client.Update(2, map[string]interface{}{"salary": 200}); err != nil {
+		t.Errorf("Update() error = %v", err)
+	}
+	if err := client.Update(2, map[string]interface{}{"team": nil}); !errors.Is(err, sheetkv.ErrInvalidValue) {
+		t.Errorf("Update() error = %v, want ErrInvalidValue", err)
+	}
+	if err := client.Update(9, map[string]interface{}{"salary": -1}); !errors.Is(err, sheetkv.ErrKeyNotFound) {
+		t.Errorf("Update() error = %v, want ErrKeyNotFound", err)
+	}
+
+	err := client.Apply([]sheetkv.Operation{
+		{Type: sheetkv.OpAdd, Record: &sheetkv.Record{Values: map[string]interface{}{"name": "Amy", "salary": 50}}},
+		{Type: sheetkv.OpUpdate, Record: &sheetkv.Record{Key: 2, Values: map[string]interface{}{"salary": 0}}},
+	})
+	if !errors.Is(err, sheetkv.ErrInvalidValue) {
+		t.Errorf("Apply() error = %v, want ErrInvalidValue", err)
+	}
+
+	// Rejected writes leave the cache as it was
+	record, err := client.Get(2)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if record.Values["salary"] != 200 || record.Values["team"] != "Sales" {
+		t.Errorf("Get() = %v, want salary 200 in Sales", record.Values)
+	}
+	if _, err := client.Get(3); !errors.Is(err, sheetkv.ErrKeyNotFound) {
+		t.Errorf("Get(3) error = %v, want ErrKeyNotFound", err)
+	}
+}
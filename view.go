@@ -0,0 +1,70 @@
+package sheetkv
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+)
+
+// View is a derived table the client materializes into another tab after
+// each sync, such as an "Active Engineers" tab for managers, see
+// Config.Views
+type View struct {
+	Name    string   // Identifies the view in errors
+	Adapter Adapter  // Adapter of the tab receiving the view
+	Query   Query    // Records of the view, in the order of Query.OrderBy or Query.SortFunc
+	Columns []string // Columns of the view, in order (default: the schema)
+}
+
+// materializeViews saves each view of Config.Views whose content changed
+// since it was last saved, replacing the rows of its tab. Views that fail
+// are saved again on the next sync.
+func (c *Client) materializeViews(ctx context.Context) error {
+	for i, view := range c.config.Views {
+		columns := view.Columns
+		if len(columns) == 0 {
+			columns = c.cache.GetSchema()
+		}
+		records, err := c.cache.QueryCtx(ctx, view.Query)
+		if err != nil {
+			return fmt.Errorf("failed to materialize view %q: %w", view.Name, err)
+		}
+
+		// Rows of the view are numbered from 2 like a compacted sheet
+		projected := make([]*Record, len(records))
+		h := fnv.New64a()
+		for _, col := range columns {
+			fmt.Fprintf(h, "%s\x00", col)
+		}
+		for j, record := range records {
+			values := make(map[string]interface{}, len(columns))
+			for _, col := range columns {
+				if v, ok := record.Values[col]; ok {
+					values[col] = v
+				}
+			}
+			projected[j] = &Record{Key: j + 2, Values: values}
+			fmt.Fprintf(h, "%d\x01", hashRecord(projected[j]))
+		}
+		sum := h.Sum64()
+
+		c.viewMu.Lock()
+		fresh := c.viewSums[i] == sum && c.viewSaved[i]
+		c.viewMu.Unlock()
+		if fresh {
+			continue
+		}
+
+		err = c.withRetry(ctx, func() error {
+			return view.Adapter.Save(ctx, projected, columns, SyncStrategyCompacting)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to materialize view %q: %w", view.Name, err)
+		}
+
+		c.viewMu.Lock()
+		c.viewSums[i], c.viewSaved[i] = sum, true
+		c.viewMu.Unlock()
+	}
+	return nil
+}
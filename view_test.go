@@ -0,0 +1,127 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_Views(t *testing.T) {
+	adapter := newMemoryAdapter([]string{"name", "role", "active", "salary"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "role": "engineer", "active": true, "salary": 100}},
+		&sheetkv.Record{Key: 3, Values: map[string]interface{}{"name": "Jane", "role": "engineer", "active": false, "salary": 120}},
+		&sheetkv.Record{Key: 4, Values: map[string]interface{}{"name": "Amy", "role": "engineer", "active": true, "salary": 110}},
+	)
+	view := newMemoryAdapter(nil)
+	client := sheetkv.New(adapter, &sheetkv.Config{
+		DisableAutoSync: true,
+		Views: []sheetkv.View{{
+			Name:    "Active Engineers",
+			Adapter: view,
+			Query: sheetkv.Query{
+				Conditions: []sheetkv.Condition{
+					{Column: "role", Operator: "==", Value: "engineer"},
+					{Column: "active", Operator: "==", Value: true},
+				},
+				SortFunc: func(a, b *sheetkv.Record) bool { return a.GetAsString("name", "") < b.GetAsString("name", "") },
+			},
+			Columns: []string{"name", "role"},
+		}},
+	})
+	ctx := context.Background()
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	viewRows := func() []map[string]interface{} {
+		view.mu.Lock()
+		defer view.mu.Unlock()
+		rows := make([]map[string]interface{}, len(view.records))
+		for i, r := range view.records {
+			if r.Key != i+2 {
+				t.Errorf("view row %d has key %d, want %d", i, r.Key, i+2)
+			}
+			rows[i] = r.Values
+		}
+		return rows
+	}
+
+	// The first sync materializes the view even without changes
+	if err := client.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	want := []map[string]interface{}{{"name": "Amy", "role": "engineer"}, {"name": "John", "role": "engineer"}}
+	if got := viewRows(); !reflect.DeepEqual(got, want) {
+		t.Errorf("view = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(view.schema, []string{"name", "role"}) {
+		t.Errorf("view schema = %v, want [name role]", view.schema)
+	}
+
+	// Unchanged views are not saved again
+	if err := client.Update(2, map[string]interface{}{"salary": 105}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := client.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if got := view.saveCount(); got != 1 {
+		t.Errorf("view saves = %d, want 1", got)
+	}
+
+	// Changes to the view's rows follow on the next sync
+	if err := client.Update(3, map[string]interface{}{"active": true}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := client.Delete(4); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := client.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	want = []map[string]interface{}{{"name": "Jane", "role": "engineer"}, {"name": "John", "role": "engineer"}}
+	if got := viewRows(); !reflect.DeepEqual(got, want) {
+		t.Errorf("view = %v, want %v", got, want)
+	}
+}
+
+func TestClient_ViewsRetry(t *testing.T) {
+	adapter := newMemoryAdapter([]string{"name"}, &sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John"}})
+	view := newMemoryAdapter(nil)
+	view.saveErr = errors.New("tab unavailable")
+	client := sheetkv.New(adapter, &sheetkv.Config{
+		DisableAutoSync: true,
+		MaxRetries:      1,
+		Views:           []sheetkv.View{{Name: "all", Adapter: view}},
+	})
+	ctx := context.Background()
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	if err := client.Sync(); err == nil {
+		t.Fatal("Sync() should report the failed view")
+	}
+
+	view.mu.Lock()
+	view.saveErr = nil
+	view.mu.Unlock()
+	if err := client.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	view.mu.Lock()
+	defer view.mu.Unlock()
+	if len(view.records) != 1 || view.records[0].Values["name"] != "John" {
+		t.Errorf("view = %v, want John", view.records)
+	}
+}
+
+func TestConfig_ValidateViews(t *testing.T) {
+	config := sheetkv.Config{Views: []sheetkv.View{{Name: "broken"}}}
+	if err := config.Validate(); !errors.Is(err, sheetkv.ErrInvalidConfig) {
+		t.Errorf("Validate() error = %v, want ErrInvalidConfig", err)
+	}
+}
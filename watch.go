@@ -0,0 +1,104 @@
+package sheetkv
+
+import (
+	"context"
+	"time"
+)
+
+// watchBuffer is the number of changes a watcher may lag behind before it is
+// dropped
+const watchBuffer = 256
+
+// Change describes one mutation applied through the client
+type Change struct {
+	Op      OperationType
+	Key     int
+	Old     *Record   // Record before the change, nil on add
+	New     *Record   // Record after the change, nil on delete
+	Columns []string  // Columns whose values changed, sorted
+	Time    time.Time // Time of the change
+}
+
+// Watch returns a channel receiving the changes applied through the client
+// from now on. The channel is closed when ctx is done, when the client is
+// closed, or when the receiver falls more than watchBuffer changes behind;
+// in the last case nothing is lost silently, the caller sees the channel
+// close and can reload and watch again. Changes made by others to the
+// spreadsheet are not reported, except the rows read by Tail.
+func (c *Client) Watch(ctx context.Context) <-chan Change {
+	ch := make(chan Change, watchBuffer)
+
+	c.watchMu.Lock()
+	if c.watchClosed {
+		c.watchMu.Unlock()
+		close(ch)
+		return ch
+	}
+	if c.watchers == nil {
+		c.watchers = make(map[chan Change]struct{})
+	}
+	c.watchers[ch] = struct{}{}
+	c.watchMu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		c.unwatch(ch)
+	}()
+	return ch
+}
+
+// unwatch removes and closes a watcher channel unless it is already gone
+func (c *Client) unwatch(ch chan Change) {
+	c.watchMu.Lock()
+	defer c.watchMu.Unlock()
+
+	if _, ok := c.watchers[ch]; ok {
+		delete(c.watchers, ch)
+		close(ch)
+	}
+}
+
+// watching reports whether any watcher is registered
+func (c *Client) watching() bool {
+	c.watchMu.Lock()
+	defer c.watchMu.Unlock()
+
+	return len(c.watchers) > 0
+}
+
+// notifyWatchers sends a change to every watcher, dropping the ones whose
+// buffer is full
+func (c *Client) notifyWatchers(m mutation) {
+	change := Change{
+		Op:      m.op,
+		Key:     m.key,
+		Old:     m.old,
+		New:     m.updated,
+		Columns: m.columns,
+		Time:    m.time,
+	}
+
+	c.watchMu.Lock()
+	defer c.watchMu.Unlock()
+
+	for ch := range c.watchers {
+		select {
+		case ch <- change:
+		default:
+			delete(c.watchers, ch)
+			close(ch)
+		}
+	}
+}
+
+// closeWatchers closes every watcher channel and refuses new ones
+func (c *Client) closeWatchers() {
+	c.watchMu.Lock()
+	defer c.watchMu.Unlock()
+
+	c.watchClosed = true
+	for ch := range c.watchers {
+		delete(c.watchers, ch)
+		close(ch)
+	}
+}
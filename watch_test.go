@@ -0,0 +1,130 @@
+package sheetkv_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestClient_Watch(t *testing.T) {
+	newClient := func() *sheetkv.Client {
+		adapter := newMemoryAdapter([]string{"name", "age"},
+			&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "age": int64(30)}},
+		)
+		client := sheetkv.New(adapter, &sheetkv.Config{DisableAutoSync: true})
+		if err := client.Initialize(context.Background()); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+		return client
+	}
+
+	t.Run("Changes", func(t *testing.T) {
+		client := newClient()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		changes := client.Watch(ctx)
+
+		if err := client.Update(2, map[string]interface{}{"age": int64(31)}); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if err := client.Update(2, map[string]interface{}{"age": int64(31)}); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if err := client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "Jane"}}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+		if err := client.Delete(2); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+
+		want := []struct {
+			op      sheetkv.OperationType
+			key     int
+			columns []string
+		}{
+			{sheetkv.OpUpdate, 2, []string{"age"}},
+			{sheetkv.OpAdd, 3, []string{"name"}},
+			{sheetkv.OpDelete, 2, []string{"age", "name"}},
+		}
+		for _, w := range want {
+			change := <-changes
+			if change.Op != w.op || change.Key != w.key || !reflect.DeepEqual(change.Columns, w.columns) {
+				t.Errorf("change = %v %d %v, want %v %d %v", change.Op, change.Key, change.Columns, w.op, w.key, w.columns)
+			}
+		}
+
+		select {
+		case change := <-changes:
+			t.Errorf("unexpected change %v %d", change.Op, change.Key)
+		default:
+		}
+	})
+
+	t.Run("Old and new versions", func(t *testing.T) {
+		client := newClient()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		changes := client.Watch(ctx)
+
+		if err := client.Update(2, map[string]interface{}{"age": int64(31)}); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		change := <-changes
+		if got := change.Old.GetAsInt64("age", 0); got != 30 {
+			t.Errorf("Old age = %d, want 30", got)
+		}
+		if got := change.New.GetAsInt64("age", 0); got != 31 {
+			t.Errorf("New age = %d, want 31", got)
+		}
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		client := newClient()
+		ctx, cancel := context.WithCancel(context.Background())
+		changes := client.Watch(ctx)
+		cancel()
+
+		if _, ok := <-changes; ok {
+			t.Error("channel is open after cancel")
+		}
+		if err := client.Update(2, map[string]interface{}{"age": int64(31)}); err != nil {
+			t.Errorf("Update() error = %v", err)
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		client := newClient()
+		changes := client.Watch(context.Background())
+		if err := client.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+
+		if _, ok := <-changes; ok {
+			t.Error("channel is open after Close")
+		}
+		if _, ok := <-client.Watch(context.Background()); ok {
+			t.Error("Watch() after Close returned an open channel")
+		}
+	})
+
+	t.Run("Slow watcher", func(t *testing.T) {
+		client := newClient()
+		changes := client.Watch(context.Background())
+
+		for i := 0; i < 300; i++ {
+			if err := client.Update(2, map[string]interface{}{"age": int64(i)}); err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+		}
+
+		received := 0
+		for range changes {
+			received++
+		}
+		if received != 256 {
+			t.Errorf("received %d changes before close, want 256", received)
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package sheetkv
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Whitespace describes how adapters clean up the whitespace of the text
+// they load, since human-entered cells often carry stray spaces that break
+// equality queries. The zero value leaves text as is.
+type Whitespace struct {
+	Trim      bool // Remove leading and trailing whitespace
+	Collapse  bool // Replace runs of internal whitespace by a single space
+	FullWidth bool // Convert full-width spaces (U+3000) to ASCII spaces
+}
+
+// Clean returns s with its whitespace cleaned up as configured
+func (w Whitespace) Clean(s string) string {
+	if w.FullWidth {
+		s = strings.ReplaceAll(s, "　", " ")
+	}
+	if w.Collapse {
+		s = collapseSpaces(s)
+	}
+	if w.Trim {
+		s = strings.TrimSpace(s)
+	}
+	return s
+}
+
+// collapseSpaces replaces the runs of whitespace within s by a single
+// space, keeping the leading and trailing ones
+func collapseSpaces(s string) string {
+	start := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsSpace(r) })
+	if start < 0 {
+		return s
+	}
+	end := strings.LastIndexFunc(s, func(r rune) bool { return !unicode.IsSpace(r) })
+	_, size := utf8.DecodeRuneInString(s[end:])
+	end += size
+
+	return s[:start] + strings.Join(strings.Fields(s[start:end]), " ") + s[end:]
+}
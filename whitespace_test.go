@@ -0,0 +1,30 @@
+package sheetkv_test
+
+import (
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+func TestWhitespace_Clean(t *testing.T) {
+	tests := []struct {
+		name string
+		w    sheetkv.Whitespace
+		in   string
+		want string
+	}{
+		{"zero value", sheetkv.Whitespace{}, "  a  b　", "  a  b　"},
+		{"trim", sheetkv.Whitespace{Trim: true}, " \tAlice 　", "Alice"},
+		{"collapse keeps edges", sheetkv.Whitespace{Collapse: true}, " a \t b\n\nc ", " a b c "},
+		{"full width", sheetkv.Whitespace{FullWidth: true}, "山田　太郎", "山田 太郎"},
+		{"all", sheetkv.Whitespace{Trim: true, Collapse: true, FullWidth: true}, "　山田　　太郎 ", "山田 太郎"},
+		{"only spaces", sheetkv.Whitespace{Collapse: true}, "   ", "   "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.w.Clean(tt.in); got != tt.want {
+				t.Errorf("Clean(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
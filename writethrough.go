@@ -0,0 +1,118 @@
+package sheetkv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// pendingWrite is a mutation waiting for its write-through
+type pendingWrite struct {
+	key int
+	old *Record // Record before the mutation, nil when it did not exist
+}
+
+// mutated records the change of key from old. With Config.WriteThrough the
+// change is only recorded once writeThrough has persisted it. Callers must
+// hold c.mu.
+func (c *Client) mutated(key int, old *Record) {
+	if !c.config.WriteThrough {
+		c.afterMutation(key, old)
+		return
+	}
+	c.pendingWrites = append(c.pendingWrites, pendingWrite{key: key, old: old})
+}
+
+// writeThrough persists the pending mutations with one BatchUpdate of the
+// adapter, then records them. When the adapter fails, the cache is restored
+// as it was before the mutations and the error wraps ErrSyncFailed.
+// Callers must hold c.mu.
+func (c *Client) writeThrough(ctx context.Context) error {
+	pending := c.pendingWrites
+	c.pendingWrites = nil
+	if len(pending) == 0 {
+		return nil
+	}
+
+	// One operation per key, from the state before its first mutation to
+	// its current state
+	first := make(map[int]*Record, len(pending))
+	var keys []int
+	for _, p := range pending {
+		if _, ok := first[p.key]; !ok {
+			first[p.key] = p.old
+			keys = append(keys, p.key)
+		}
+	}
+
+	var ops []Operation
+	for _, key := range keys {
+		old := first[key]
+		current, err := c.cache.Get(key)
+		switch {
+		case err != nil && old == nil:
+			// Added then deleted
+		case err != nil:
+			ops = append(ops, Operation{Type: OpDelete, Record: &Record{Key: key}})
+		case old == nil:
+			ops = append(ops, Operation{Type: OpAdd, Record: current})
+		default:
+			ops = append(ops, Operation{Type: OpUpdate, Record: current})
+		}
+	}
+
+	unlock, err := c.lock(ctx)
+	if err == nil {
+		err = errors.Join(c.persistOperations(ctx, ops, first), unlock())
+	}
+	if err != nil {
+		// Restore the cache, latest keys first
+		for i := len(keys) - 1; i >= 0; i-- {
+			if old := first[keys[i]]; old != nil {
+				_ = c.cache.Set(keys[i], old)
+			} else {
+				_ = c.cache.Delete(keys[i])
+			}
+		}
+		return fmt.Errorf("%w: write-through: %w", ErrSyncFailed, err)
+	}
+
+	for _, key := range keys {
+		c.cache.markRecordSaved(key)
+	}
+	for _, p := range pending {
+		c.afterMutation(p.key, p.old)
+	}
+	return nil
+}
+
+// persistOperations encodes the records of ops and sends them to the
+// adapter. Updates replace the whole record: columns of the previous
+// version missing from the new one are cleared.
+func (c *Client) persistOperations(ctx context.Context, ops []Operation, previous map[int]*Record) error {
+	records := make([]*Record, len(ops))
+	for i, op := range ops {
+		records[i] = op.Record
+	}
+	stored, err := c.encodeRecords(ctx, records)
+	if err != nil {
+		return err
+	}
+
+	for i, op := range ops {
+		record := stored[i]
+		if op.Type == OpUpdate {
+			record = &Record{Key: record.Key, Revision: record.Revision, Values: copyValues(record.Values)}
+			for col := range previous[record.Key].Values {
+				if _, ok := record.Values[col]; !ok {
+					record.Values[col] = nil
+				}
+			}
+		}
+		ops[i].Record = record
+	}
+
+	return c.withRetry(ctx, func() error {
+		return c.adaptor.BatchUpdate(ctx, ops)
+	})
+}
@@ -0,0 +1,118 @@
+package sheetkv_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ideamans/go-sheetkv"
+)
+
+// batchAdapter records the operations of BatchUpdate, failing while err is set
+type batchAdapter struct {
+	*memoryAdapter
+	mu      sync.Mutex
+	batches [][]sheetkv.Operation
+	err     error
+}
+
+func (a *batchAdapter) BatchUpdate(ctx context.Context, operations []sheetkv.Operation) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.err != nil {
+		return a.err
+	}
+	a.batches = append(a.batches, operations)
+	return nil
+}
+
+func TestClient_WriteThrough(t *testing.T) {
+	data := &batchAdapter{memoryAdapter: newMemoryAdapter([]string{"name", "amount"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "amount": int64(100)}},
+	)}
+	client := sheetkv.New(data, &sheetkv.Config{DisableAutoSync: true, WriteThrough: true, MaxRetries: 1})
+	defer client.Close()
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	if err := client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "Jane", "amount": int64(50)}}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if err := client.Set(2, &sheetkv.Record{Values: map[string]interface{}{"name": "John"}}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := client.Delete(3); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if len(data.batches) != 3 {
+		t.Fatalf("batches = %d, want one per write", len(data.batches))
+	}
+	if op := data.batches[0][0]; op.Type != sheetkv.OpAdd || op.Record.Key != 3 || op.Record.Values["amount"] != int64(50) {
+		t.Errorf("append operation = %+v", op)
+	}
+	// Set replaces the record, clearing the columns it no longer has
+	if op := data.batches[1][0]; op.Type != sheetkv.OpUpdate || op.Record.Values["name"] != "John" {
+		t.Errorf("set operation = %+v", op)
+	} else if v, ok := op.Record.Values["amount"]; !ok || v != nil {
+		t.Errorf("set operation values = %v, want amount cleared", op.Record.Values)
+	}
+	if op := data.batches[2][0]; op.Type != sheetkv.OpDelete || op.Record.Key != 3 {
+		t.Errorf("delete operation = %+v", op)
+	}
+
+	// Written-through changes leave nothing for the next sync
+	if err := client.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if saves := data.saveCount(); saves != 0 {
+		t.Errorf("saves = %d, want 0", saves)
+	}
+
+	// Apply writes its operations in one batch
+	err := client.Apply([]sheetkv.Operation{
+		{Type: sheetkv.OpAdd, Record: &sheetkv.Record{Values: map[string]interface{}{"name": "Bob"}}},
+		{Type: sheetkv.OpUpdate, Record: &sheetkv.Record{Key: 2, Values: map[string]interface{}{"amount": int64(120)}}},
+	})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if len(data.batches) != 4 || len(data.batches[3]) != 2 {
+		t.Errorf("batches = %v, want the two operations of Apply together", data.batches)
+	}
+}
+
+func TestClient_WriteThroughFailure(t *testing.T) {
+	data := &batchAdapter{memoryAdapter: newMemoryAdapter([]string{"name", "amount"},
+		&sheetkv.Record{Key: 2, Values: map[string]interface{}{"name": "John", "amount": int64(100)}},
+	)}
+	client := sheetkv.New(data, &sheetkv.Config{DisableAutoSync: true, WriteThrough: true, MaxRetries: 1, OperationLogSize: 10})
+	defer client.Close()
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	data.err = errors.New("backend unavailable")
+	if err := client.Update(2, map[string]interface{}{"amount": int64(90)}); !errors.Is(err, sheetkv.ErrSyncFailed) {
+		t.Fatalf("Update() error = %v, want ErrSyncFailed", err)
+	}
+	if err := client.Append(&sheetkv.Record{Values: map[string]interface{}{"name": "Jane"}}); err == nil {
+		t.Fatal("Append() should fail")
+	}
+
+	// The cache is restored and the failed writes are not recorded
+	if record, err := client.Get(2); err != nil || record.Values["amount"] != int64(100) {
+		t.Errorf("Get(2) = %v, %v, want the amount unchanged", record, err)
+	}
+	if _, err := client.Get(3); !errors.Is(err, sheetkv.ErrKeyNotFound) {
+		t.Errorf("Get(3) error = %v, want ErrKeyNotFound", err)
+	}
+	if ops, _, err := client.OperationsSince(0); err != nil || len(ops) != 0 {
+		t.Errorf("OperationsSince() = %v, %v, want no operations", ops, err)
+	}
+	if err := client.Sync(); err != nil || data.saveCount() != 0 {
+		t.Errorf("Sync() = %v with %d saves, want nothing to save", err, data.saveCount())
+	}
+}